@@ -23,6 +23,7 @@ package api
 // API Layer
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -31,7 +32,10 @@ import (
 
 	// Load backends (make sure they register)
 	_ "github.com/v3io/frames/backends/csv"
+	_ "github.com/v3io/frames/backends/jsonl"
 	_ "github.com/v3io/frames/backends/kv"
+	_ "github.com/v3io/frames/backends/memory"
+	_ "github.com/v3io/frames/backends/parquet"
 	_ "github.com/v3io/frames/backends/stream"
 	_ "github.com/v3io/frames/backends/tsdb"
 
@@ -75,10 +79,14 @@ func New(logger logger.Logger, config *frames.Config) (*API, error) {
 	return api, nil
 }
 
-// Read reads from database, emitting results to wf
-func (api *API) Read(request *frames.ReadRequest, out chan frames.Frame) error {
+// Read reads from database, emitting results to out. Read stops when ctx is
+// done
+func (api *API) Read(ctx context.Context, request *frames.ReadRequest, out chan frames.Frame) error {
+	var query *frames.Query
 	if request.Query != "" {
-		if err := api.populateQuery(request); err != nil {
+		var err error
+		query, err = api.populateQuery(request)
+		if err != nil {
 			msg := "can't populate query"
 			api.logger.ErrorWith(msg, "request", request, "error", err)
 			return errors.Wrap(err, msg)
@@ -94,14 +102,56 @@ func (api *API) Read(request *frames.ReadRequest, out chan frames.Frame) error {
 		return fmt.Errorf("unknown backend - %q", request.Backend)
 	}
 
-	iter, err := backend.Read(request)
+	stage, err := newQueryStage(query, request)
+	if err != nil {
+		api.logger.ErrorWith("bad query", "request", request, "error", err)
+		return errors.Wrap(err, "bad query")
+	}
+
+	groupBy, err := newGroupBy(request, api.config.MaxGroups)
+	if err != nil {
+		api.logger.ErrorWith("bad group by", "request", request, "error", err)
+		return errors.Wrap(err, "bad group by")
+	}
+
+	if groupBy != nil {
+		request.Columns = groupBy.sourceColumns()
+	}
+
+	plan, err := newPlan(backend, request)
+	if err != nil {
+		api.logger.ErrorWith("can't plan query", "request", request, "error", err)
+		return errors.Wrap(err, "can't plan query")
+	}
+
+	ctx, cancel := withTimeout(ctx, request.Timeout)
+	defer cancel()
+
+	iter, err := backend.Read(ctx, request)
 	if err != nil {
 		api.logger.ErrorWith("can't query", "error", err)
 		return errors.Wrap(err, "can't query")
 	}
 
+	if plan != nil {
+		iter = plan.iterator(iter)
+	}
+
+	if groupBy != nil {
+		iter = groupBy.iterator(iter)
+	}
+
+	if stage != nil {
+		iter = stage.iterator(iter)
+	}
+
 	for iter.Next() {
-		out <- iter.At()
+		select {
+		case out <- iter.At():
+		case <-ctx.Done():
+			api.logger.WarnWith("read canceled", "request", request, "error", ctx.Err())
+			return ctx.Err()
+		}
 	}
 
 	if err := iter.Err(); err != nil {
@@ -113,25 +163,34 @@ func (api *API) Read(request *frames.ReadRequest, out chan frames.Frame) error {
 	return nil
 }
 
-// Write write data to backend, returns num_frames, num_rows, error
-func (api *API) Write(request *frames.WriteRequest, in chan frames.Frame) (int, int, error) {
+// Write write data to backend, returns num_frames, num_rows, the row report
+// (nil if the backend doesn't report rows), error. Write stops when ctx is done
+func (api *API) Write(ctx context.Context, request *frames.WriteRequest, in chan frames.Frame) (int, int, *frames.WriteReport, error) {
 	if request.Backend == "" || request.Table == "" {
 		api.logger.ErrorWith(missingMsg, "request", request)
-		return -1, -1, fmt.Errorf(missingMsg)
+		return -1, -1, nil, fmt.Errorf(missingMsg)
 	}
 
 	api.logger.InfoWith("write request", "request", request)
 	backend, ok := api.backends[request.Backend]
 	if !ok {
 		api.logger.ErrorWith("unkown backend", "name", request.Backend)
-		return -1, -1, fmt.Errorf("unknown backend - %s", request.Backend)
+		return -1, -1, nil, fmt.Errorf("unknown backend - %s", request.Backend)
 	}
 
-	appender, err := backend.Write(request)
+	if request.IsConditional() && !backend.Capabilities().ConditionalWrites {
+		api.logger.ErrorWith("conditional write", "backend", request.Backend)
+		return -1, -1, nil, fmt.Errorf("%s backend does not support conditional writes", request.Backend)
+	}
+
+	ctx, cancel := withTimeout(ctx, request.Timeout)
+	defer cancel()
+
+	appender, err := backend.Write(ctx, request)
 	if err != nil {
 		msg := "backend Write failed"
 		api.logger.ErrorWith(msg, "error", err)
-		return -1, -1, errors.Wrap(err, msg)
+		return -1, -1, nil, errors.Wrap(err, msg)
 	}
 
 	nFrames, nRows := 0, 0
@@ -139,12 +198,28 @@ func (api *API) Write(request *frames.WriteRequest, in chan frames.Frame) (int,
 		nFrames, nRows = 1, request.ImmidiateData.Len()
 	}
 
-	for frame := range in {
+	for {
+		var (
+			frame frames.Frame
+			ok    bool
+		)
+
+		select {
+		case frame, ok = <-in:
+		case <-ctx.Done():
+			api.logger.WarnWith("write canceled", "request", request, "error", ctx.Err())
+			return nFrames, nRows, nil, ctx.Err()
+		}
+
+		if !ok {
+			break
+		}
+
 		api.logger.DebugWith("frame to write", "size", frame.Len())
 		if err := appender.Add(frame); err != nil {
 			msg := "can't add frame"
 			api.logger.ErrorWith(msg, "error", err)
-			return nFrames, nRows, errors.Wrap(err, msg)
+			return nFrames, nRows, nil, errors.Wrap(err, msg)
 		}
 
 		nFrames++
@@ -154,22 +229,31 @@ func (api *API) Write(request *frames.WriteRequest, in chan frames.Frame) (int,
 
 	api.logger.Debug("write done")
 
-	// TODO: Specify timeout in request?
+	timeout := time.Duration(api.config.DefaultTimeout) * time.Second
+	if request.Timeout > 0 {
+		timeout = time.Duration(request.Timeout) * time.Second
+	}
+
 	if nRows > 0 {
-		if err := appender.WaitForComplete(time.Duration(api.config.DefaultTimeout) * time.Second); err != nil {
+		if err := appender.WaitForComplete(timeout); err != nil {
 			msg := "can't wait for completion"
 			api.logger.ErrorWith(msg, "error", err)
-			return nFrames, nRows, errors.Wrap(err, msg)
+			return nFrames, nRows, nil, errors.Wrap(err, msg)
 		}
 	} else {
 		api.logger.DebugWith("write request with zero rows", "frames", nFrames, "requst", request)
 	}
 
-	return nFrames, nRows, nil
+	var report *frames.WriteReport
+	if reporter, ok := appender.(frames.ReportingAppender); ok {
+		report = reporter.Report()
+	}
+
+	return nFrames, nRows, report, nil
 }
 
 // Create will create a new table
-func (api *API) Create(request *frames.CreateRequest) error {
+func (api *API) Create(ctx context.Context, request *frames.CreateRequest) error {
 	if request.Backend == "" || request.Table == "" {
 		api.logger.ErrorWith(missingMsg, "request", request)
 		return fmt.Errorf(missingMsg)
@@ -182,7 +266,10 @@ func (api *API) Create(request *frames.CreateRequest) error {
 		return fmt.Errorf("unknown backend - %s", request.Backend)
 	}
 
-	if err := backend.Create(request); err != nil {
+	ctx, cancel := withTimeout(ctx, request.Timeout)
+	defer cancel()
+
+	if err := backend.Create(ctx, request); err != nil {
 		api.logger.ErrorWith("error creating table", "error", err, "request", request)
 		return errors.Wrap(err, "error creating table")
 	}
@@ -191,7 +278,7 @@ func (api *API) Create(request *frames.CreateRequest) error {
 }
 
 // Delete deletes a table or part of it
-func (api *API) Delete(request *frames.DeleteRequest) error {
+func (api *API) Delete(ctx context.Context, request *frames.DeleteRequest) error {
 	if request.Backend == "" || request.Table == "" {
 		api.logger.ErrorWith(missingMsg, "request", request)
 		return fmt.Errorf(missingMsg)
@@ -204,7 +291,10 @@ func (api *API) Delete(request *frames.DeleteRequest) error {
 		return fmt.Errorf("unknown backend - %s", request.Backend)
 	}
 
-	if err := backend.Delete(request); err != nil {
+	ctx, cancel := withTimeout(ctx, request.Timeout)
+	defer cancel()
+
+	if err := backend.Delete(ctx, request); err != nil {
 		api.logger.ErrorWith("error deleting table", "error", err, "request", request)
 		return errors.Wrap(err, "can't delete")
 	}
@@ -212,55 +302,140 @@ func (api *API) Delete(request *frames.DeleteRequest) error {
 	return nil
 }
 
-// Exec executes a command on the backend
-func (api *API) Exec(request *frames.ExecRequest) error {
+// Exec executes a command on the backend, returns the command result (or nil)
+func (api *API) Exec(ctx context.Context, request *frames.ExecRequest) (frames.Frame, error) {
 	if request.Backend == "" || request.Table == "" {
 		api.logger.ErrorWith(missingMsg, "request", request)
-		return fmt.Errorf(missingMsg)
+		return nil, fmt.Errorf(missingMsg)
 	}
 
 	api.logger.DebugWith("exec", "request", request)
 	backend, ok := api.backends[request.Backend]
 	if !ok {
 		api.logger.ErrorWith("unkown backend", "name", request.Backend)
-		return fmt.Errorf("unknown backend - %s", request.Backend)
+		return nil, fmt.Errorf("unknown backend - %s", request.Backend)
 	}
 
-	if err := backend.Exec(request); err != nil {
-		api.logger.ErrorWith("error deleting table", "error", err, "request", request)
-		return errors.Wrap(err, "can't delete")
+	ctx, cancel := withTimeout(ctx, request.Timeout)
+	defer cancel()
+
+	frame, err := backend.Exec(ctx, request)
+	if err != nil {
+		api.logger.ErrorWith("error executing command", "error", err, "request", request)
+		return nil, errors.Wrap(err, "can't exec")
 	}
 
-	return nil
+	return frame, nil
 }
 
-func (api *API) populateQuery(request *frames.ReadRequest) error {
+// List lists the tables in a backend
+func (api *API) List(ctx context.Context, request *frames.ListRequest) ([]string, error) {
+	if request.Backend == "" {
+		api.logger.ErrorWith(missingMsg, "request", request)
+		return nil, fmt.Errorf(missingMsg)
+	}
+
+	api.logger.DebugWith("list", "request", request)
+	backend, ok := api.backends[request.Backend]
+	if !ok {
+		api.logger.ErrorWith("unkown backend", "name", request.Backend)
+		return nil, fmt.Errorf("unknown backend - %s", request.Backend)
+	}
+
+	ctx, cancel := withTimeout(ctx, request.Timeout)
+	defer cancel()
+
+	tables, err := backend.List(ctx, request)
+	if err != nil {
+		api.logger.ErrorWith("error listing tables", "error", err, "request", request)
+		return nil, errors.Wrap(err, "can't list")
+	}
+
+	return tables, nil
+}
+
+// Describe returns table schema and attributes
+func (api *API) Describe(ctx context.Context, request *frames.DescribeRequest) (*frames.TableInfo, error) {
+	if request.Backend == "" || request.Table == "" {
+		api.logger.ErrorWith(missingMsg, "request", request)
+		return nil, fmt.Errorf(missingMsg)
+	}
+
+	api.logger.DebugWith("describe", "request", request)
+	backend, ok := api.backends[request.Backend]
+	if !ok {
+		api.logger.ErrorWith("unkown backend", "name", request.Backend)
+		return nil, fmt.Errorf("unknown backend - %s", request.Backend)
+	}
+
+	ctx, cancel := withTimeout(ctx, request.Timeout)
+	defer cancel()
+
+	info, err := backend.Describe(ctx, request)
+	if err != nil {
+		api.logger.ErrorWith("error describing table", "error", err, "request", request)
+		return nil, errors.Wrap(err, "can't describe")
+	}
+
+	return info, nil
+}
+
+// Backends returns the configured backends and their capabilities
+func (api *API) Backends() []*frames.BackendInfo {
+	infos := make([]*frames.BackendInfo, 0, len(api.config.Backends))
+	for _, cfg := range api.config.Backends {
+		backend, ok := api.backends[cfg.Name]
+		if !ok {
+			continue
+		}
+
+		infos = append(infos, &frames.BackendInfo{
+			Name:         cfg.Name,
+			Type:         cfg.Type,
+			Capabilities: backend.Capabilities(),
+		})
+	}
+
+	return infos
+}
+
+// withTimeout returns ctx with the request timeout (in seconds, 0 for none),
+// cancel must be called when the request is done
+func withTimeout(ctx context.Context, timeout int64) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+}
+
+func (api *API) populateQuery(request *frames.ReadRequest) (*frames.Query, error) {
 	sqlQuery, err := frames.ParseSQL(request.Query)
 	if err != nil {
-		return errors.Wrap(err, "bad SQL query")
+		return nil, errors.Wrap(err, "bad SQL query")
 	}
 
 	if request.Table != "" {
-		return fmt.Errorf("both query AND table provided")
+		return nil, fmt.Errorf("both query AND table provided")
 	}
 	request.Table = sqlQuery.Table
 
 	if request.Columns != nil {
-		return fmt.Errorf("both query AND columns provided")
+		return nil, fmt.Errorf("both query AND columns provided")
 	}
 	request.Columns = sqlQuery.Columns
 
 	if request.Filter != "" {
-		return fmt.Errorf("both query AND filter provided")
+		return nil, fmt.Errorf("both query AND filter provided")
 	}
 	request.Filter = sqlQuery.Filter
 
 	if request.GroupBy != "" {
-		return fmt.Errorf("both query AND group_by provided")
+		return nil, fmt.Errorf("both query AND group_by provided")
 	}
 	request.GroupBy = sqlQuery.GroupBy
 
-	return nil
+	return sqlQuery, nil
 }
 
 func (api *API) createBackends(config *frames.Config) error {
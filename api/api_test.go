@@ -0,0 +1,231 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils/v3iotest"
+)
+
+func TestReadCancel(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "frames-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf strings.Builder
+	buf.WriteString("x\n")
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&buf, "%d\n", i)
+	}
+
+	if err := ioutil.WriteFile(path.Join(tmpDir, "t.csv"), []byte(buf.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &frames.Config{
+		Backends: []*frames.BackendConfig{
+			{Name: "csv", Type: "csv", RootDir: tmpDir},
+		},
+	}
+
+	api, err := New(nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := &frames.ReadRequest{Backend: "csv", Table: "t.csv", MessageLimit: 10}
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan frames.Frame) // No one reads, Read blocks until canceled
+	errc := make(chan error, 1)
+	go func() {
+		errc <- api.Read(ctx, request, out)
+	}()
+
+	cancel()
+	select {
+	case err := <-errc:
+		if errors.Cause(err) != context.Canceled {
+			t.Fatalf("bad error - %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("read not canceled")
+	}
+}
+
+func TestMemoryBackend(t *testing.T) {
+	cfg := &frames.Config{
+		Backends: []*frames.BackendConfig{
+			{
+				Name: "mem",
+				Type: "memory",
+				Options: map[string]interface{}{
+					"tables": map[string]interface{}{
+						"t": []interface{}{
+							map[string]interface{}{"x": 1.0, "y": "a"},
+							map[string]interface{}{"x": 2.0, "y": "b"},
+							map[string]interface{}{"x": 3.0, "y": "c"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	api, err := New(nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := &frames.ReadRequest{Backend: "mem", Table: "t", Filter: "x > 1", Columns: []string{"y"}}
+	out := make(chan frames.Frame, 10)
+	if err := api.Read(context.Background(), request, out); err != nil {
+		t.Fatal(err)
+	}
+	close(out)
+
+	nRows := 0
+	for frame := range out {
+		if names := frame.Names(); len(names) != 1 || names[0] != "y" {
+			t.Fatalf("bad names - %v", names)
+		}
+		nRows += frame.Len()
+	}
+
+	if nRows != 2 {
+		t.Fatalf("bad number of rows - %d", nRows)
+	}
+}
+
+func TestConditionalWriteUnsupported(t *testing.T) {
+	cfg := &frames.Config{
+		Backends: []*frames.BackendConfig{{Name: "mem", Type: "memory"}},
+	}
+
+	api, err := New(nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := &frames.WriteRequest{Backend: "mem", Table: "t", WriteMode: frames.InsertMode}
+	in := make(chan frames.Frame)
+	close(in)
+	if _, _, _, err := api.Write(context.Background(), request, in); err == nil {
+		t.Fatal("conditional write to memory backend")
+	}
+}
+
+func TestKVFilter(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+
+	cfg := &frames.Config{
+		WebAPIEndpoint: srv.Addr,
+		Container:      "bigdata",
+		Backends:       []*frames.BackendConfig{{Name: "kv", Type: "kv", Workers: 2}},
+	}
+
+	api, err := New(nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := frames.NewSliceColumn("key", []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flag, err := frames.NewSliceColumn("flag", []bool{true, false})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := frames.NewSliceColumn("x", []int64{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	y, err := frames.NewSliceColumn("y", []string{"b'q", `c"'d`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, err := frames.NewFrame([]frames.Column{flag, x, y}, []frames.Column{index}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := make(chan frames.Frame, 1)
+	in <- frame
+	close(in)
+	request := &frames.WriteRequest{Backend: "kv", Table: "t"}
+	if _, _, _, err := api.Write(context.Background(), request, in); err != nil {
+		t.Fatal(err)
+	}
+
+	// Booleans are stored as 0/1, conditions on them are evaluated in process
+	testCases := []struct {
+		filter string
+		keys   string
+	}{
+		{"flag = true", "[a]"},
+		{"flag", "[a]"},
+		{"not flag", "[b]"},
+		{"x > 0 and flag = false", "[b]"},
+		// v3io string literals have no escapes
+		{"y = 'b''q'", "[a]"},
+		{`y = 'c"''d'`, "[b]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filter, func(t *testing.T) {
+			request := &frames.ReadRequest{Backend: "kv", Table: "t", Filter: tc.filter}
+			out := make(chan frames.Frame, 10)
+			if err := api.Read(context.Background(), request, out); err != nil {
+				t.Fatal(err)
+			}
+			close(out)
+
+			var keys []string
+			for frame := range out {
+				col := frame.Indices()[0]
+				for i := 0; i < frame.Len(); i++ {
+					key, _ := col.StringAt(i)
+					keys = append(keys, key)
+				}
+			}
+
+			if fmt.Sprint(keys) != tc.keys {
+				t.Fatalf("bad keys: %v != %s", keys, tc.keys)
+			}
+		})
+	}
+}
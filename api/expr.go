@@ -0,0 +1,71 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package api
+
+// SQL expression helpers, expressions are evaluated by utils.Evaluator
+
+import (
+	"github.com/xwb1989/sqlparser"
+)
+
+// isAggregation returns true if expr is an aggregation function call
+func isAggregation(expr sqlparser.Expr) bool {
+	fn, ok := expr.(*sqlparser.FuncExpr)
+	if !ok {
+		return false
+	}
+
+	_, ok = aggTypes[fn.Name.Lowered()]
+	return ok
+}
+
+// hasAggregation returns true if expr contains an aggregation
+func hasAggregation(expr sqlparser.Expr) bool {
+	found := false
+	sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if expr, ok := node.(sqlparser.Expr); ok && isAggregation(expr) {
+			found = true
+		}
+		return !found, nil
+	}, expr)
+
+	return found
+}
+
+// exprColumns returns the column names expr uses, if aggregate is true then
+// aggregations are returned as a whole (e.g. "avg(cpu)")
+func exprColumns(expr sqlparser.Expr, aggregate bool) []string {
+	var columns []string
+	sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.ColName:
+			columns = append(columns, node.Name.String())
+		case *sqlparser.FuncExpr:
+			if aggregate && isAggregation(node) {
+				columns = append(columns, sqlparser.String(node))
+				return false, nil
+			}
+		}
+		return true, nil
+	}, expr)
+
+	return columns
+}
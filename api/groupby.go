@@ -0,0 +1,776 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package api
+
+// Group by and aggregation stage, runs over the output of any backend
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/ops"
+)
+
+const (
+	defaultFrameSize = 1024
+)
+
+var (
+	// avg(cpu), count(*), count(distinct host) ...
+	aggRe = regexp.MustCompile(`(?i)^\s*(\w+)\s*\(\s*(distinct\s+)?([^)]+?)\s*\)\s*$`)
+
+	groupByPrefixRe = regexp.MustCompile(`(?i)^\s*group\s+by\s+`)
+)
+
+// aggregation is an aggregation over a column
+type aggregation struct {
+	name     string // output column name (e.g. "avg(cpu)")
+	function string
+	column   string // "*" for count(*)
+}
+
+// selection is a column in the output, either a group key or an aggregation
+type selection struct {
+	key int // index in groupBy.keys, -1 for aggregations
+	agg int // index in groupBy.aggs, -1 for keys
+}
+
+// groupBy groups and aggregates frames
+type groupBy struct {
+	keys       []string
+	selections []selection
+	aggs       []*aggregation
+	maxGroups  int
+	frameSize  int
+
+	keyTypes []frames.DType
+	aggTypes []frames.DType // source column dtype
+	groups   []*group
+	byKey    map[string]*group
+}
+
+type group struct {
+	keys []interface{}
+	accs []accumulator
+}
+
+// newGroupBy returns a group by stage for request, nil if the request has no
+// group by or aggregations
+func newGroupBy(request *frames.ReadRequest, maxGroups int) (*groupBy, error) {
+	keys := parseGroupBy(request.GroupBy)
+
+	var aggs []*aggregation
+	for _, name := range request.Columns {
+		agg, err := parseAggregation(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if agg != nil {
+			aggs = append(aggs, agg)
+		}
+	}
+
+	if len(keys) == 0 && len(aggs) == 0 {
+		return nil, nil
+	}
+
+	if len(request.Columns) == 0 {
+		return nil, fmt.Errorf("group by without columns")
+	}
+
+	gb := &groupBy{
+		keys:      keys,
+		aggs:      aggs,
+		maxGroups: maxGroups,
+		frameSize: int(request.MessageLimit),
+		byKey:     make(map[string]*group),
+	}
+
+	if gb.frameSize <= 0 {
+		gb.frameSize = defaultFrameSize
+	}
+
+	aggIdx := 0
+	for _, name := range request.Columns {
+		if key := indexOf(name, keys); key != -1 {
+			gb.selections = append(gb.selections, selection{key: key, agg: -1})
+			continue
+		}
+
+		if agg, _ := parseAggregation(name); agg != nil {
+			gb.selections = append(gb.selections, selection{key: -1, agg: aggIdx})
+			aggIdx++
+			continue
+		}
+
+		return nil, fmt.Errorf("column %q must appear in group by or be aggregated", name)
+	}
+
+	return gb, nil
+}
+
+// sourceColumns returns the columns needed from the backend
+func (gb *groupBy) sourceColumns() []string {
+	var columns []string
+	for _, name := range gb.keys {
+		if indexOf(name, columns) == -1 {
+			columns = append(columns, name)
+		}
+	}
+
+	for _, agg := range gb.aggs {
+		if agg.column != "*" && indexOf(agg.column, columns) == -1 {
+			columns = append(columns, agg.column)
+		}
+	}
+
+	return columns
+}
+
+// iterator returns an iterator over the aggregation of frames from it
+func (gb *groupBy) iterator(it frames.FrameIterator) frames.FrameIterator {
+	return &groupByIterator{
+		gb: gb,
+		it: it,
+	}
+}
+
+// aggregate aggregates all frames from it
+func (gb *groupBy) aggregate(it frames.FrameIterator) error {
+	for it.Next() {
+		if err := gb.add(it.At()); err != nil {
+			return err
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	// A global aggregation (without group by keys) always has one row, even
+	// with no input (e.g. count(*) of 0)
+	if len(gb.keys) == 0 && len(gb.groups) == 0 {
+		if gb.aggTypes == nil {
+			gb.unknownTypes()
+		}
+
+		if _, err := gb.group(nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unknownTypes sets the column types when no frame was seen, the aggregations
+// of unknown columns are null floats
+func (gb *groupBy) unknownTypes() {
+	gb.keyTypes = make([]frames.DType, len(gb.keys))
+	gb.aggTypes = make([]frames.DType, len(gb.aggs))
+	for i, agg := range gb.aggs {
+		if agg.column == "*" {
+			gb.aggTypes[i] = frames.IntType
+			continue
+		}
+		gb.aggTypes[i] = frames.FloatType
+	}
+}
+
+// add adds a frame to the aggregation
+func (gb *groupBy) add(frame frames.Frame) error {
+	keyCols, err := lookupColumns(frame, gb.keys)
+	if err != nil {
+		return err
+	}
+
+	aggCols := make([]frames.Column, len(gb.aggs))
+	for i, agg := range gb.aggs {
+		if agg.column == "*" {
+			continue
+		}
+
+		aggCols[i], err = ops.LookupColumn(frame, agg.column)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := gb.checkTypes(keyCols, aggCols); err != nil {
+		return err
+	}
+
+	keys := make([]interface{}, len(keyCols))
+	for row := 0; row < frame.Len(); row++ {
+		for i, col := range keyCols {
+			keys[i], err = ops.ValueAt(col, row)
+			if err != nil {
+				return err
+			}
+		}
+
+		grp, err := gb.group(keys)
+		if err != nil {
+			return err
+		}
+
+		for i, col := range aggCols {
+			var value interface{} = true // count(*) counts rows
+			if col != nil {
+				value, err = ops.ValueAt(col, row)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Aggregations skip nulls
+			if value == nil {
+				continue
+			}
+
+			if err := grp.accs[i].add(value); err != nil {
+				return errors.Wrapf(err, "%s", gb.aggs[i].name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkTypes checks that column types didn't change between frames
+func (gb *groupBy) checkTypes(keyCols, aggCols []frames.Column) error {
+	if gb.keyTypes == nil {
+		gb.keyTypes = make([]frames.DType, len(keyCols))
+		for i, col := range keyCols {
+			gb.keyTypes[i] = col.DType()
+		}
+
+		gb.aggTypes = make([]frames.DType, len(aggCols))
+		for i, col := range aggCols {
+			if col == nil {
+				gb.aggTypes[i] = frames.IntType
+				continue
+			}
+
+			gb.aggTypes[i] = col.DType()
+			if err := checkAggType(gb.aggs[i].function, col.DType()); err != nil {
+				return errors.Wrapf(err, "%s", gb.aggs[i].name)
+			}
+		}
+
+		return nil
+	}
+
+	for i, col := range keyCols {
+		if col.DType() != gb.keyTypes[i] {
+			return fmt.Errorf("%q - dtype changed (%d != %d)", col.Name(), col.DType(), gb.keyTypes[i])
+		}
+	}
+
+	for i, col := range aggCols {
+		if col != nil && col.DType() != gb.aggTypes[i] {
+			return fmt.Errorf("%q - dtype changed (%d != %d)", col.Name(), col.DType(), gb.aggTypes[i])
+		}
+	}
+
+	return nil
+}
+
+// group returns the group for keys, creating it if needed
+func (gb *groupBy) group(keys []interface{}) (*group, error) {
+	key := groupKey(keys)
+	grp, ok := gb.byKey[key]
+	if ok {
+		return grp, nil
+	}
+
+	if gb.maxGroups > 0 && len(gb.groups) >= gb.maxGroups {
+		return nil, fmt.Errorf("too many groups (limit is %d)", gb.maxGroups)
+	}
+
+	grp = &group{
+		keys: make([]interface{}, len(keys)),
+		accs: make([]accumulator, len(gb.aggs)),
+	}
+	copy(grp.keys, keys)
+	for i, agg := range gb.aggs {
+		grp.accs[i] = newAccumulator(agg.function, gb.aggTypes[i])
+	}
+
+	gb.byKey[key] = grp
+	gb.groups = append(gb.groups, grp)
+	return grp, nil
+}
+
+// frame returns a frame from groups
+func (gb *groupBy) frame(groups []*group) (frames.Frame, error) {
+	columns := make([]frames.Column, len(gb.selections))
+	for i, sel := range gb.selections {
+		var name string
+		var dtype frames.DType
+		if sel.agg == -1 {
+			name, dtype = gb.keys[sel.key], gb.keyTypes[sel.key]
+		} else {
+			agg := gb.aggs[sel.agg]
+			name, dtype = agg.name, aggDType(agg.function, gb.aggTypes[sel.agg])
+		}
+
+		col, err := frames.NewSliceColumn(name, utils.EmptyData(dtype))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, grp := range groups {
+			var value interface{}
+			if sel.agg == -1 {
+				value = grp.keys[sel.key]
+			} else {
+				value = grp.accs[sel.agg].result()
+			}
+
+			if err := utils.AppendColumn(col, value); err != nil {
+				return nil, errors.Wrapf(err, "%s", name)
+			}
+		}
+
+		columns[i] = col
+	}
+
+	return frames.NewFrame(columns, nil, nil)
+}
+
+// groupByIterator emits the groups, it reads all the input on the first call to
+// Next
+type groupByIterator struct {
+	gb    *groupBy
+	it    frames.FrameIterator
+	done  bool // input was aggregated
+	start int  // first group in next frame
+	frame frames.Frame
+	err   error
+}
+
+func (gi *groupByIterator) Next() bool {
+	if gi.err != nil {
+		return false
+	}
+
+	if !gi.done {
+		gi.done = true
+		if gi.err = gi.gb.aggregate(gi.it); gi.err != nil {
+			return false
+		}
+	}
+
+	groups := gi.gb.groups
+	if gi.start >= len(groups) {
+		return false
+	}
+
+	end := gi.start + gi.gb.frameSize
+	if end > len(groups) {
+		end = len(groups)
+	}
+
+	gi.frame, gi.err = gi.gb.frame(groups[gi.start:end])
+	if gi.err != nil {
+		return false
+	}
+
+	gi.start = end
+	return true
+}
+
+func (gi *groupByIterator) Err() error {
+	return gi.err
+}
+
+func (gi *groupByIterator) At() frames.Frame {
+	return gi.frame
+}
+
+// parseGroupBy parses group by clause ("group by a, b" or "a, b")
+func parseGroupBy(groupBy string) []string {
+	groupBy = groupByPrefixRe.ReplaceAllString(groupBy, "")
+	var keys []string
+	for _, key := range strings.Split(groupBy, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// parseAggregation parses an aggregation, returns nil if name is not an
+// aggregation
+func parseAggregation(name string) (*aggregation, error) {
+	match := aggRe.FindStringSubmatch(name)
+	if match == nil {
+		return nil, nil
+	}
+
+	function := strings.ToLower(match[1])
+	if match[2] != "" { // distinct
+		if function != "count" {
+			return nil, fmt.Errorf("%s - distinct is supported only in count", name)
+		}
+		function = "count_distinct"
+	}
+
+	if _, ok := aggTypes[function]; !ok {
+		return nil, fmt.Errorf("%s - unknown aggregation %q", name, function)
+	}
+
+	column := match[3]
+	if column == "*" && function != "count" {
+		return nil, fmt.Errorf("%s - * is supported only in count", name)
+	}
+
+	agg := &aggregation{
+		name:     strings.TrimSpace(name),
+		function: function,
+		column:   column,
+	}
+	return agg, nil
+}
+
+// groupKey returns the map key of keys, values are length prefixed so keys
+// with different values can't have the same map key
+func groupKey(keys []interface{}) string {
+	var buf strings.Builder
+	for _, key := range keys {
+		if t, ok := key.(time.Time); ok {
+			key = t.UnixNano()
+		}
+		value := fmt.Sprintf("%v", key)
+		fmt.Fprintf(&buf, "%T:%d:%s", key, len(value), value)
+	}
+
+	return buf.String()
+}
+
+func lookupColumns(frame frames.Frame, names []string) ([]frames.Column, error) {
+	columns := make([]frames.Column, len(names))
+	for i, name := range names {
+		col, err := ops.LookupColumn(frame, name)
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = col
+	}
+
+	return columns, nil
+}
+
+func indexOf(name string, names []string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Aggregation functions
+
+// accumulator accumulates values of a group, values are never nil
+type accumulator interface {
+	add(value interface{}) error
+	result() interface{} // nil if there's no result
+}
+
+const (
+	sameType = -1 // output type is the same as the input type
+)
+
+// aggTypes is the output type of each aggregation function
+var aggTypes = map[string]frames.DType{
+	"avg":            frames.FloatType,
+	"count":          frames.IntType,
+	"count_distinct": frames.IntType,
+	"first":          sameType,
+	"last":           sameType,
+	"max":            sameType,
+	"min":            sameType,
+	"stddev":         frames.FloatType,
+	"sum":            sameType,
+}
+
+func aggDType(function string, dtype frames.DType) frames.DType {
+	if out := aggTypes[function]; out != sameType {
+		return out
+	}
+
+	return dtype
+}
+
+func checkAggType(function string, dtype frames.DType) error {
+	switch function {
+	case "avg", "stddev", "sum":
+		if dtype != frames.IntType && dtype != frames.FloatType {
+			return fmt.Errorf("%s of non numeric column", function)
+		}
+	case "min", "max":
+		if dtype == frames.BoolType {
+			return fmt.Errorf("%s of bool column", function)
+		}
+	}
+
+	return nil
+}
+
+func newAccumulator(function string, dtype frames.DType) accumulator {
+	switch function {
+	case "avg":
+		return &avgAcc{}
+	case "count":
+		return &countAcc{}
+	case "count_distinct":
+		return &distinctAcc{values: make(map[interface{}]bool)}
+	case "first":
+		return &firstAcc{}
+	case "last":
+		return &lastAcc{}
+	case "max":
+		return &minMaxAcc{isMax: true}
+	case "min":
+		return &minMaxAcc{}
+	case "stddev":
+		return &stddevAcc{}
+	case "sum":
+		return &sumAcc{isInt: dtype == frames.IntType}
+	}
+
+	return nil
+}
+
+type countAcc struct {
+	n int64
+}
+
+func (a *countAcc) add(value interface{}) error {
+	a.n++
+	return nil
+}
+
+func (a *countAcc) result() interface{} {
+	return a.n
+}
+
+type distinctAcc struct {
+	values map[interface{}]bool
+}
+
+func (a *distinctAcc) add(value interface{}) error {
+	if t, ok := value.(time.Time); ok {
+		value = t.UnixNano()
+	}
+
+	a.values[value] = true
+	return nil
+}
+
+func (a *distinctAcc) result() interface{} {
+	return int64(len(a.values))
+}
+
+type sumAcc struct {
+	isInt bool
+	n     int
+	ival  int64
+	fval  float64
+}
+
+func (a *sumAcc) add(value interface{}) error {
+	a.n++
+	if a.isInt {
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("bad value for sum - %T", value)
+		}
+		a.ival += v
+		return nil
+	}
+
+	v, err := utils.AsFloat(value)
+	if err != nil {
+		return err
+	}
+	a.fval += v
+	return nil
+}
+
+func (a *sumAcc) result() interface{} {
+	switch {
+	case a.n == 0:
+		return nil
+	case a.isInt:
+		return a.ival
+	}
+
+	return a.fval
+}
+
+type avgAcc struct {
+	n   int
+	sum float64
+}
+
+func (a *avgAcc) add(value interface{}) error {
+	v, err := utils.AsFloat(value)
+	if err != nil {
+		return err
+	}
+
+	a.n++
+	a.sum += v
+	return nil
+}
+
+func (a *avgAcc) result() interface{} {
+	if a.n == 0 {
+		return nil
+	}
+
+	return a.sum / float64(a.n)
+}
+
+// stddevAcc is sample standard deviation (using Welford's algorithm)
+type stddevAcc struct {
+	n    int
+	mean float64
+	m2   float64
+}
+
+func (a *stddevAcc) add(value interface{}) error {
+	v, err := utils.AsFloat(value)
+	if err != nil {
+		return err
+	}
+
+	a.n++
+	delta := v - a.mean
+	a.mean += delta / float64(a.n)
+	a.m2 += delta * (v - a.mean)
+	return nil
+}
+
+func (a *stddevAcc) result() interface{} {
+	if a.n < 2 {
+		return nil
+	}
+
+	return math.Sqrt(a.m2 / float64(a.n-1))
+}
+
+type minMaxAcc struct {
+	isMax bool
+	value interface{}
+}
+
+func (a *minMaxAcc) add(value interface{}) error {
+	if a.value == nil {
+		a.value = value
+		return nil
+	}
+
+	var replace bool
+	var err error
+	if a.isMax {
+		replace, err = lessValue(a.value, value)
+	} else {
+		replace, err = lessValue(value, a.value)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if replace {
+		a.value = value
+	}
+	return nil
+}
+
+func (a *minMaxAcc) result() interface{} {
+	return a.value
+}
+
+type firstAcc struct {
+	value interface{}
+}
+
+func (a *firstAcc) add(value interface{}) error {
+	if a.value == nil {
+		a.value = value
+	}
+	return nil
+}
+
+func (a *firstAcc) result() interface{} {
+	return a.value
+}
+
+type lastAcc struct {
+	value interface{}
+}
+
+func (a *lastAcc) add(value interface{}) error {
+	a.value = value
+	return nil
+}
+
+func (a *lastAcc) result() interface{} {
+	return a.value
+}
+
+func lessValue(a, b interface{}) (bool, error) {
+	switch a.(type) {
+	case int64:
+		if bv, ok := b.(int64); ok {
+			return a.(int64) < bv, nil
+		}
+	case float64:
+		if bv, ok := b.(float64); ok {
+			return a.(float64) < bv, nil
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return a.(string) < bv, nil
+		}
+	case time.Time:
+		if bv, ok := b.(time.Time); ok {
+			return a.(time.Time).Before(bv), nil
+		}
+	}
+
+	return false, fmt.Errorf("can't compare %T and %T", a, b)
+}
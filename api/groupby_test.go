@@ -0,0 +1,255 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package api
+
+import (
+	"math"
+	"testing"
+
+	"github.com/v3io/frames"
+)
+
+type sliceIterator struct {
+	frames []frames.Frame
+	i      int
+}
+
+func (it *sliceIterator) Next() bool {
+	if it.i >= len(it.frames) {
+		return false
+	}
+
+	it.i++
+	return true
+}
+
+func (it *sliceIterator) Err() error {
+	return nil
+}
+
+func (it *sliceIterator) At() frames.Frame {
+	return it.frames[it.i-1]
+}
+
+func TestGroupBy(t *testing.T) {
+	request := &frames.ReadRequest{
+		Columns: []string{
+			"host", "count(*)", "sum(cpu)", "avg(cpu)", "min(cpu)", "max(cpu)",
+			"first(cpu)", "last(cpu)", "stddev(cpu)", "count(distinct user)",
+		},
+		GroupBy:      "group by host",
+		MessageLimit: 1,
+	}
+
+	gb, err := newGroupBy(request, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cols := gb.sourceColumns(); len(cols) != 3 {
+		t.Fatalf("bad source columns - %v", cols)
+	}
+
+	frame1 := groupByFrame(t, []string{"a", "b", "a"}, []int64{1, 2, 3}, []string{"x", "y", "x"})
+	frame2 := groupByFrame(t, []string{"b", "a"}, []int64{4, 5}, []string{"x", "z"})
+	it := &sliceIterator{frames: []frames.Frame{frame1, frame2}}
+
+	var results []map[string]interface{}
+	out := gb.iterator(it)
+	for out.Next() {
+		frame := out.At()
+		if frame.Len() != 1 {
+			t.Fatalf("bad frame size - %d", frame.Len())
+		}
+
+		rows := frame.IterRows(false)
+		for rows.Next() {
+			results = append(results, rows.Row())
+		}
+	}
+
+	if err := out.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("bad number of groups - %d", len(results))
+	}
+
+	expected := map[string]interface{}{
+		"host":                 "a",
+		"count(*)":             int64(3),
+		"sum(cpu)":             int64(9),
+		"avg(cpu)":             3.0,
+		"min(cpu)":             int64(1),
+		"max(cpu)":             int64(5),
+		"first(cpu)":           int64(1),
+		"last(cpu)":            int64(5),
+		"stddev(cpu)":          2.0,
+		"count(distinct user)": int64(2),
+	}
+
+	for name, value := range expected {
+		if results[0][name] != value {
+			t.Fatalf("%s: %v != %v", name, results[0][name], value)
+		}
+	}
+
+	if host := results[1]["host"]; host != "b" {
+		t.Fatalf("bad second group - %v", host)
+	}
+
+	stddev := results[1]["stddev(cpu)"].(float64)
+	if math.Abs(stddev-math.Sqrt(2)) > 0.0001 {
+		t.Fatalf("bad stddev - %v", stddev)
+	}
+}
+
+func TestGroupByErrors(t *testing.T) {
+	requests := []*frames.ReadRequest{
+		{Columns: []string{"host", "cpu"}, GroupBy: "host"},
+		{Columns: []string{"median(cpu)"}},
+		{Columns: []string{"sum(*)"}},
+	}
+
+	for _, request := range requests {
+		if _, err := newGroupBy(request, 0); err == nil {
+			t.Fatalf("no error for %v", request.Columns)
+		}
+	}
+
+	gb, err := newGroupBy(&frames.ReadRequest{Columns: []string{"cpu"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gb != nil {
+		t.Fatal("group by for request without aggregations")
+	}
+
+	gb, err = newGroupBy(&frames.ReadRequest{Columns: []string{"host"}, GroupBy: "host"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame := groupByFrame(t, []string{"a", "b"}, []int64{1, 2}, []string{"x", "y"})
+	it := &sliceIterator{frames: []frames.Frame{frame}}
+	if out := gb.iterator(it); out.Next() || out.Err() == nil {
+		t.Fatal("no error on too many groups")
+	}
+}
+
+func TestGroupByEmpty(t *testing.T) {
+	request := &frames.ReadRequest{
+		Columns: []string{"count(*)", "sum(cpu)", "count(distinct user)"},
+	}
+
+	empty := groupByFrame(t, []string{}, []int64{}, []string{})
+	inputs := [][]frames.Frame{nil, {empty}}
+	for _, input := range inputs {
+		gb, err := newGroupBy(request, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := gb.iterator(&sliceIterator{frames: input})
+		if !out.Next() {
+			t.Fatalf("no frame for %d input frames (%v)", len(input), out.Err())
+		}
+
+		frame := out.At()
+		if frame.Len() != 1 {
+			t.Fatalf("bad frame size - %d", frame.Len())
+		}
+
+		rows := frame.IterRows(false)
+		if !rows.Next() {
+			t.Fatal("no rows")
+		}
+
+		row := rows.Row()
+		if count := row["count(*)"]; count != int64(0) {
+			t.Fatalf("bad count(*) - %v", count)
+		}
+
+		if count := row["count(distinct user)"]; count != int64(0) {
+			t.Fatalf("bad count(distinct user) - %v", count)
+		}
+
+		if sum, ok := row["sum(cpu)"]; ok && sum != nil {
+			t.Fatalf("sum(cpu) of no rows is not null - %v", sum)
+		}
+
+		if out.Next() {
+			t.Fatal("more than one frame")
+		}
+	}
+
+	// Group by over no input has no groups
+	gb, err := newGroupBy(&frames.ReadRequest{Columns: []string{"host", "count(*)"}, GroupBy: "host"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out := gb.iterator(&sliceIterator{}); out.Next() {
+		t.Fatal("frame for group by over no input")
+	}
+}
+
+func TestGroupKey(t *testing.T) {
+	keys1 := []interface{}{"a\x00string:b", "c"}
+	keys2 := []interface{}{"a", "b\x00string:c"}
+	if groupKey(keys1) == groupKey(keys2) {
+		t.Fatalf("same key for %q and %q", keys1, keys2)
+	}
+
+	if groupKey([]interface{}{"1"}) == groupKey([]interface{}{int64(1)}) {
+		t.Fatal("same key for different types")
+	}
+
+	if groupKey(keys1) != groupKey([]interface{}{"a\x00string:b", "c"}) {
+		t.Fatal("different keys for equal values")
+	}
+}
+
+func groupByFrame(t *testing.T, hosts []string, cpus []int64, users []string) frames.Frame {
+	host, err := frames.NewSliceColumn("host", hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cpu, err := frames.NewSliceColumn("cpu", cpus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := frames.NewSliceColumn("user", users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, err := frames.NewFrame([]frames.Column{host, cpu, user}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return frame
+}
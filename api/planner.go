@@ -0,0 +1,391 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package api
+
+// Query planner, splits a read request between the backend and in process
+// evaluation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xwb1989/sqlparser"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/ops"
+)
+
+// plan is the in process part of a read request
+type plan struct {
+	filter  sqlparser.Expr // nil if there's no filter
+	columns []string       // nil for all columns
+	limit   int            // 0 for no limit
+}
+
+// newPlan updates request to what backend can handle natively and returns the
+// plan for the rest, nil if there's nothing to do in process.
+// Backends that don't implement frames.PushdownBackend get the request as is
+func newPlan(backend frames.DataBackend, request *frames.ReadRequest) (*plan, error) {
+	pdBackend, ok := backend.(frames.PushdownBackend)
+	if !ok {
+		return nil, nil
+	}
+
+	pushdown := pdBackend.Pushdown()
+	operators := make(map[string]bool)
+	for _, op := range pushdown.FilterOperators {
+		operators[strings.ToLower(op)] = true
+	}
+
+	p := &plan{}
+	if request.Filter != "" {
+		expr, err := parseFilter(request.Filter)
+		switch {
+		case err != nil && len(operators) == 0:
+			return nil, err
+		case err != nil:
+			// Not SQL, assume it's in the backend native syntax
+		default:
+			var pushed, residual []sqlparser.Expr
+			for _, cond := range splitAnd(expr) {
+				if canPush(cond, operators) {
+					pushed = append(pushed, cond)
+				} else {
+					residual = append(residual, cond)
+				}
+			}
+
+			if len(pushed) > 1 && !operators["and"] {
+				residual = append(residual, pushed[1:]...)
+				pushed = pushed[:1]
+			}
+
+			if pushdown.FilterHint && len(pushed) == 0 {
+				request.Filter = sqlparser.String(expr)
+			} else {
+				request.Filter = formatFilter(pushed, pushdown.OperatorNames)
+			}
+			p.filter = joinAnd(residual)
+		}
+	}
+
+	if p.filter != nil {
+		if len(request.Columns) > 0 {
+			// Filter columns are needed even if not selected
+			p.columns = request.Columns
+			columns := append([]string{}, request.Columns...)
+			for _, name := range exprColumns(p.filter, false) {
+				if indexOf(name, columns) == -1 {
+					columns = append(columns, name)
+				}
+			}
+			request.Columns = columns
+		}
+
+		// Backend limit is before filtering
+		p.limit = int(request.Limit)
+		request.Limit = 0
+	}
+
+	if !pushdown.Columns && len(request.Columns) > 0 {
+		p.columns = request.Columns
+	}
+
+	if p.filter == nil && p.columns == nil {
+		return nil, nil
+	}
+
+	return p, nil
+}
+
+// iterator returns an iterator over the frames from it after applying the
+// plan
+func (p *plan) iterator(it frames.FrameIterator) frames.FrameIterator {
+	return &planIterator{
+		p:    p,
+		it:   it,
+		left: p.limit,
+	}
+}
+
+// apply applies filter and column projection on frame
+func (p *plan) apply(frame frames.Frame) (frames.Frame, error) {
+	if p.filter != nil {
+		ev := utils.NewEvaluator(frame)
+		var indices []int
+		for i := 0; i < frame.Len(); i++ {
+			value, err := ev.Eval(p.filter, i)
+			if err != nil {
+				return nil, errors.Wrap(err, "can't evaluate filter")
+			}
+
+			// nil (unknown) is filtered out
+			if keep, ok := value.(bool); ok && keep {
+				indices = append(indices, i)
+			}
+		}
+
+		if len(indices) < frame.Len() {
+			var err error
+			frame, err = ops.Take(frame, indices)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if p.columns == nil {
+		return frame, nil
+	}
+
+	var names []string
+	for _, name := range p.columns {
+		if _, err := frame.Column(name); err == nil {
+			names = append(names, name)
+			continue
+		}
+
+		if !isIndex(frame, name) {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+	}
+
+	return ops.Select(frame, names...)
+}
+
+type planIterator struct {
+	p     *plan
+	it    frames.FrameIterator
+	left  int // rows left to emit, 0 for no limit
+	done  bool
+	frame frames.Frame
+	err   error
+}
+
+func (pi *planIterator) Next() bool {
+	for !pi.done && pi.err == nil && pi.it.Next() {
+		pi.frame, pi.err = pi.p.apply(pi.it.At())
+		if pi.err != nil || pi.frame.Len() == 0 {
+			continue
+		}
+
+		if pi.left > 0 {
+			if pi.frame.Len() >= pi.left {
+				pi.frame, pi.err = ops.Head(pi.frame, pi.left)
+				pi.done = true
+			}
+			pi.left -= pi.frame.Len()
+		}
+
+		return pi.err == nil
+	}
+
+	if pi.err == nil && !pi.done {
+		pi.err = pi.it.Err()
+	}
+
+	return false
+}
+
+func (pi *planIterator) Err() error {
+	return pi.err
+}
+
+func (pi *planIterator) At() frames.Frame {
+	return pi.frame
+}
+
+// parseFilter parses a filter ("where a > 1" or "a > 1"), the same way
+// backends that filter in process do
+func parseFilter(filter string) (sqlparser.Expr, error) {
+	parsed, err := utils.ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.Expr(), nil
+}
+
+// splitAnd splits expr to conditions joined by AND
+func splitAnd(expr sqlparser.Expr) []sqlparser.Expr {
+	switch e := expr.(type) {
+	case *sqlparser.AndExpr:
+		return append(splitAnd(e.Left), splitAnd(e.Right)...)
+	case *sqlparser.ParenExpr:
+		if _, ok := e.Expr.(*sqlparser.AndExpr); ok {
+			return splitAnd(e.Expr)
+		}
+	}
+
+	return []sqlparser.Expr{expr}
+}
+
+// joinAnd joins conditions with AND, returns nil if there are no conditions
+func joinAnd(conds []sqlparser.Expr) sqlparser.Expr {
+	var expr sqlparser.Expr
+	for _, cond := range conds {
+		if expr == nil {
+			expr = cond
+			continue
+		}
+		expr = &sqlparser.AndExpr{Left: expr, Right: cond}
+	}
+
+	return expr
+}
+
+// canPush returns true if cond, a condition, can be pushed to the backend: all
+// its operators are in operators. Conditions on boolean values (bare columns or
+// boolean literals) are evaluated in process, backends can store booleans as
+// something else (e.g. KV stores them as 0/1)
+func canPush(cond sqlparser.Expr, operators map[string]bool) bool {
+	switch e := cond.(type) {
+	case *sqlparser.ParenExpr:
+		return canPush(e.Expr, operators)
+	case *sqlparser.ComparisonExpr:
+		return operators[e.Operator] && e.Escape == nil &&
+			canPushValue(e.Left, operators) && canPushValue(e.Right, operators)
+	case *sqlparser.AndExpr:
+		return operators["and"] && canPush(e.Left, operators) && canPush(e.Right, operators)
+	case *sqlparser.OrExpr:
+		return operators["or"] && canPush(e.Left, operators) && canPush(e.Right, operators)
+	case *sqlparser.NotExpr:
+		return operators["not"] && canPush(e.Expr, operators)
+	case *sqlparser.FuncExpr:
+		return canPushValue(e, operators)
+	}
+
+	return false
+}
+
+// canPushValue returns true if expr, an operand, can be pushed to the backend
+func canPushValue(expr sqlparser.Expr, operators map[string]bool) bool {
+	switch e := expr.(type) {
+	case *sqlparser.ColName:
+		return true
+	case *sqlparser.SQLVal:
+		// Backend filters have no string escapes (see formatExpr)
+		return e.Type != sqlparser.StrVal || !strings.Contains(string(e.Val), "'") || !strings.Contains(string(e.Val), `"`)
+	case *sqlparser.ParenExpr:
+		return canPushValue(e.Expr, operators)
+	case sqlparser.ValTuple:
+		for _, value := range e {
+			if !canPushValue(value, operators) {
+				return false
+			}
+		}
+		return true
+	case *sqlparser.FuncExpr:
+		if !operators[e.Name.Lowered()] || e.Distinct {
+			return false
+		}
+
+		for _, arg := range e.Exprs {
+			aliased, ok := arg.(*sqlparser.AliasedExpr)
+			if !ok || !canPushValue(aliased.Expr, operators) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// formatFilter formats conditions (joined by AND) for the backend, names maps
+// SQL operators to backend operators
+func formatFilter(conds []sqlparser.Expr, names map[string]string) string {
+	parts := make([]string, len(conds))
+	for i, cond := range conds {
+		parts[i] = formatExpr(cond, names)
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	for i, part := range parts {
+		parts[i] = "(" + part + ")"
+	}
+	return strings.Join(parts, " "+opName("and", names)+" ")
+}
+
+// formatExpr formats an expression that passed canPush
+func formatExpr(expr sqlparser.Expr, names map[string]string) string {
+	switch e := expr.(type) {
+	case *sqlparser.ColName:
+		return e.Name.String()
+	case *sqlparser.SQLVal:
+		// v3io string literals have no escapes, strings with single quotes are
+		// double quoted (strings with both quote characters are not pushed)
+		if e.Type == sqlparser.StrVal {
+			if strings.Contains(string(e.Val), "'") {
+				return `"` + string(e.Val) + `"`
+			}
+			return "'" + string(e.Val) + "'"
+		}
+		return string(e.Val)
+	case *sqlparser.ParenExpr:
+		return "(" + formatExpr(e.Expr, names) + ")"
+	case sqlparser.ValTuple:
+		values := make([]string, len(e))
+		for i, value := range e {
+			values[i] = formatExpr(value, names)
+		}
+		return "(" + strings.Join(values, ", ") + ")"
+	case *sqlparser.ComparisonExpr:
+		return fmt.Sprintf("%s %s %s", formatExpr(e.Left, names), opName(e.Operator, names), formatExpr(e.Right, names))
+	case *sqlparser.AndExpr:
+		return fmt.Sprintf("%s %s %s", formatExpr(e.Left, names), opName("and", names), formatExpr(e.Right, names))
+	case *sqlparser.OrExpr:
+		return fmt.Sprintf("%s %s %s", formatExpr(e.Left, names), opName("or", names), formatExpr(e.Right, names))
+	case *sqlparser.NotExpr:
+		return fmt.Sprintf("%s (%s)", opName("not", names), formatExpr(e.Expr, names))
+	case *sqlparser.FuncExpr:
+		args := make([]string, len(e.Exprs))
+		for i, arg := range e.Exprs {
+			args[i] = formatExpr(arg.(*sqlparser.AliasedExpr).Expr, names)
+		}
+		return fmt.Sprintf("%s(%s)", opName(e.Name.Lowered(), names), strings.Join(args, ", "))
+	}
+
+	return sqlparser.String(expr)
+}
+
+func opName(op string, names map[string]string) string {
+	if name, ok := names[op]; ok {
+		return name
+	}
+
+	return op
+}
+
+func isIndex(frame frames.Frame, name string) bool {
+	for _, col := range frame.Indices() {
+		if col.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/v3io/frames"
+)
+
+type pushdownBackend struct {
+	frames.DataBackend
+	pushdown *frames.Pushdown
+}
+
+func (b *pushdownBackend) Pushdown() *frames.Pushdown {
+	return b.pushdown
+}
+
+func TestPlanPushdown(t *testing.T) {
+	backend := &pushdownBackend{
+		pushdown: &frames.Pushdown{
+			Columns:         true,
+			FilterOperators: []string{"=", ">", "and"},
+			OperatorNames:   map[string]string{"=": "=="},
+		},
+	}
+
+	request := &frames.ReadRequest{
+		Columns: []string{"host"},
+		Filter:  "where host = 'a' and cpu > 1 and user like 'x%'",
+		Limit:   10,
+	}
+
+	p, err := newPlan(backend, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if request.Filter != "(host == 'a') and (cpu > 1)" {
+		t.Fatalf("bad pushed filter - %q", request.Filter)
+	}
+
+	if !reflect.DeepEqual(request.Columns, []string{"host", "user"}) {
+		t.Fatalf("bad backend columns - %v", request.Columns)
+	}
+
+	if request.Limit != 0 || p.limit != 10 {
+		t.Fatalf("limit pushed down with in process filter")
+	}
+
+	frame := groupByFrame(t, []string{"a", "a", "a"}, []int64{2, 3, 4}, []string{"x", "y", "xx"})
+	out, err := p.apply(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Len() != 2 || !reflect.DeepEqual(out.Names(), []string{"host"}) {
+		t.Fatalf("bad result - %d rows, columns %v", out.Len(), out.Names())
+	}
+
+	// Boolean conditions are evaluated in process
+	request = &frames.ReadRequest{Filter: "cpu > 1 and flag and host = true"}
+	p, err = newPlan(backend, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if request.Filter != "cpu > 1" || p.filter == nil {
+		t.Fatalf("bad boolean pushdown - %q", request.Filter)
+	}
+
+	// Strings with single quotes are double quoted, strings with both quote
+	// characters are evaluated in process
+	request = &frames.ReadRequest{Filter: `host = 'it''s' and cpu = 'a"''b'`}
+	p, err = newPlan(backend, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if request.Filter != `host == "it's"` || p.filter == nil {
+		t.Fatalf("bad quoted pushdown - %q", request.Filter)
+	}
+}
+
+func TestPlanInProcess(t *testing.T) {
+	backend := &pushdownBackend{pushdown: &frames.Pushdown{}}
+	request := &frames.ReadRequest{
+		Columns: []string{"cpu"},
+		Filter:  "host = 'b' or cpu > 4",
+		Limit:   2,
+	}
+
+	p, err := newPlan(backend, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if request.Filter != "" {
+		t.Fatalf("filter pushed to backend - %q", request.Filter)
+	}
+
+	frame1 := groupByFrame(t, []string{"a", "b", "a"}, []int64{1, 2, 3}, []string{"x", "y", "x"})
+	frame2 := groupByFrame(t, []string{"b", "a"}, []int64{4, 5}, []string{"x", "z"})
+	it := p.iterator(&sliceIterator{frames: []frames.Frame{frame1, frame2}})
+
+	var cpus []int64
+	for it.Next() {
+		col, err := it.At().Column("cpu")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		values, err := col.Ints()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cpus = append(cpus, values...)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(cpus, []int64{2, 4}) {
+		t.Fatalf("bad result - %v", cpus)
+	}
+
+	if _, err := newPlan(backend, &frames.ReadRequest{Filter: "a >"}); err == nil {
+		t.Fatal("no error on bad filter")
+	}
+}
+
+func TestPlanFilterHint(t *testing.T) {
+	backend := &pushdownBackend{
+		pushdown: &frames.Pushdown{
+			Columns:    true,
+			FilterHint: true,
+		},
+	}
+
+	request := &frames.ReadRequest{
+		Columns: []string{"host"},
+		Filter:  "where cpu > 1 and host = 'a'",
+	}
+
+	p, err := newPlan(backend, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if request.Filter != "cpu > 1 and host = 'a'" {
+		t.Fatalf("bad filter hint - %q", request.Filter)
+	}
+
+	if p.filter == nil {
+		t.Fatal("filter not evaluated in process")
+	}
+
+	if !reflect.DeepEqual(request.Columns, []string{"host", "cpu"}) {
+		t.Fatalf("bad backend columns - %v", request.Columns)
+	}
+}
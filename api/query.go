@@ -0,0 +1,377 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package api
+
+// Post processing of SQL queries: expressions, aliases, ORDER BY, LIMIT and
+// OFFSET
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xwb1989/sqlparser"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/ops"
+)
+
+const (
+	hiddenPrefix = "__order_"
+)
+
+// queryStage applies the parts of a query the backends don't handle
+type queryStage struct {
+	fields    []*field // nil for SELECT *
+	orderBy   []*orderKey
+	limit     int // -1 for no limit
+	offset    int
+	frameSize int
+	aggregate bool // query has group by or aggregations
+}
+
+// field is an output column
+type field struct {
+	name string
+	expr sqlparser.Expr
+}
+
+type orderKey struct {
+	name       string         // column name in projected frame
+	expr       sqlparser.Expr // nil if the key is an output field
+	descending bool
+}
+
+// newQueryStage returns a query stage for query, nil if there's nothing to
+// do. It updates request columns and limit to what the backend should read
+func newQueryStage(query *frames.Query, request *frames.ReadRequest) (*queryStage, error) {
+	if query == nil {
+		return nil, nil
+	}
+
+	if len(query.Columns) == 0 && len(query.OrderBy) == 0 && !query.HasLimit && query.Offset == 0 {
+		return nil, nil
+	}
+
+	qs := &queryStage{
+		limit:     -1,
+		offset:    int(query.Offset),
+		frameSize: int(request.MessageLimit),
+		aggregate: query.GroupBy != "",
+	}
+
+	if query.HasLimit {
+		qs.limit = int(query.Limit)
+	}
+
+	if qs.frameSize <= 0 {
+		qs.frameSize = defaultFrameSize
+	}
+
+	names := make(map[string]bool)
+	for i, text := range query.Columns {
+		expr, err := utils.ParseExpr(text)
+		if err != nil {
+			return nil, err
+		}
+
+		name := text
+		if i < len(query.Aliases) && query.Aliases[i] != "" {
+			name = query.Aliases[i]
+		}
+
+		qs.fields = append(qs.fields, &field{name: name, expr: expr})
+		names[name] = true
+		qs.aggregate = qs.aggregate || hasAggregation(expr)
+	}
+
+	for i, key := range query.OrderBy {
+		okey := &orderKey{
+			name:       key.Column,
+			descending: key.Descending,
+		}
+
+		if !names[key.Column] {
+			expr, err := utils.ParseExpr(key.Column)
+			if err != nil {
+				return nil, errors.Wrap(err, "bad ORDER BY")
+			}
+
+			okey.name = fmt.Sprintf("%s%d", hiddenPrefix, i)
+			okey.expr = expr
+			qs.aggregate = qs.aggregate || hasAggregation(expr)
+		}
+
+		qs.orderBy = append(qs.orderBy, okey)
+	}
+
+	request.Columns = qs.requestColumns()
+	if !qs.aggregate && len(qs.orderBy) == 0 && qs.limit > 0 && request.Limit == 0 {
+		request.Limit = int64(qs.offset + qs.limit)
+	}
+
+	return qs, nil
+}
+
+// requestColumns returns the columns the backend (or the group by stage)
+// should return
+func (qs *queryStage) requestColumns() []string {
+	if qs.fields == nil {
+		return nil
+	}
+
+	var columns []string
+	add := func(expr sqlparser.Expr) {
+		for _, name := range exprColumns(expr, qs.aggregate) {
+			if indexOf(name, columns) == -1 {
+				columns = append(columns, name)
+			}
+		}
+	}
+
+	for _, field := range qs.fields {
+		add(field.expr)
+	}
+
+	for _, key := range qs.orderBy {
+		if key.expr != nil {
+			add(key.expr)
+		}
+	}
+
+	return columns
+}
+
+// iterator returns an iterator over the result of the query stage on frames
+// from it
+func (qs *queryStage) iterator(it frames.FrameIterator) frames.FrameIterator {
+	return &queryIterator{
+		qs:   qs,
+		it:   it,
+		skip: qs.offset,
+		left: qs.limit,
+	}
+}
+
+// project returns a frame with the output fields and the hidden order by
+// columns
+func (qs *queryStage) project(frame frames.Frame) (frames.Frame, error) {
+	ev := utils.NewEvaluator(frame)
+	var columns []frames.Column
+	if qs.fields == nil {
+		for _, name := range frame.Names() {
+			col, err := frame.Column(name)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, col)
+		}
+	}
+
+	for _, field := range qs.fields {
+		col, err := ev.Column(field.expr, field.name, frame.Len())
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't evaluate %q", field.name)
+		}
+		columns = append(columns, col)
+	}
+
+	for _, key := range qs.orderBy {
+		if key.expr == nil {
+			continue
+		}
+
+		col, err := ev.Column(key.expr, key.name, frame.Len())
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't evaluate ORDER BY %q", sqlparser.String(key.expr))
+		}
+		columns = append(columns, col)
+	}
+
+	return frames.NewFrame(columns, frame.Indices(), frame.Labels())
+}
+
+// sort collects all frames from it, sorts them and returns the result frames
+func (qs *queryStage) sort(it frames.FrameIterator) ([]frames.Frame, error) {
+	var projected []frames.Frame
+	for it.Next() {
+		frame, err := qs.project(it.At())
+		if err != nil {
+			return nil, err
+		}
+		projected = append(projected, frame)
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(projected) == 0 {
+		return nil, nil
+	}
+
+	frame, err := ops.Concat(projected...)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]ops.SortKey, len(qs.orderBy))
+	for i, key := range qs.orderBy {
+		keys[i] = ops.SortKey{Column: key.name, Descending: key.descending}
+	}
+
+	frame, err = ops.SortBy(frame, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, name := range frame.Names() {
+		if !strings.HasPrefix(name, hiddenPrefix) {
+			names = append(names, name)
+		}
+	}
+
+	frame, err = ops.Select(frame, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []frames.Frame
+	for start := 0; start < frame.Len(); start += qs.frameSize {
+		end := start + qs.frameSize
+		if end > frame.Len() {
+			end = frame.Len()
+		}
+
+		chunk, err := ops.Slice(frame, start, end)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk)
+	}
+
+	return out, nil
+}
+
+// queryIterator applies the query stage on frames. Without ORDER BY frames are
+// processed as they arrive, otherwise all of them are read on the first call
+// to Next
+type queryIterator struct {
+	qs     *queryStage
+	it     frames.FrameIterator
+	skip   int // rows left to skip (OFFSET)
+	left   int // rows left to emit (LIMIT), -1 for no limit
+	sorted []frames.Frame
+	done   bool
+	frame  frames.Frame
+	err    error
+}
+
+func (qi *queryIterator) Next() bool {
+	if qi.err != nil || qi.left == 0 {
+		return false
+	}
+
+	for {
+		frame, ok := qi.nextFrame()
+		if !ok {
+			return false
+		}
+
+		if qi.frame, qi.err = qi.limit(frame); qi.err != nil {
+			return false
+		}
+
+		if qi.frame.Len() > 0 {
+			return true
+		}
+
+		if qi.left == 0 {
+			return false
+		}
+	}
+}
+
+func (qi *queryIterator) nextFrame() (frames.Frame, bool) {
+	if len(qi.qs.orderBy) == 0 {
+		if !qi.it.Next() {
+			qi.err = qi.it.Err()
+			return nil, false
+		}
+
+		frame, err := qi.qs.project(qi.it.At())
+		if err != nil {
+			qi.err = err
+			return nil, false
+		}
+		return frame, true
+	}
+
+	if !qi.done {
+		qi.done = true
+		qi.sorted, qi.err = qi.qs.sort(qi.it)
+		if qi.err != nil {
+			return nil, false
+		}
+	}
+
+	if len(qi.sorted) == 0 {
+		return nil, false
+	}
+
+	frame := qi.sorted[0]
+	qi.sorted = qi.sorted[1:]
+	return frame, true
+}
+
+// limit applies OFFSET & LIMIT on frame
+func (qi *queryIterator) limit(frame frames.Frame) (frames.Frame, error) {
+	start := qi.skip
+	if start > frame.Len() {
+		start = frame.Len()
+	}
+	qi.skip -= start
+
+	end := frame.Len()
+	if qi.left != -1 && end-start > qi.left {
+		end = start + qi.left
+	}
+
+	if qi.left != -1 {
+		qi.left -= end - start
+	}
+
+	if start == 0 && end == frame.Len() {
+		return frame, nil
+	}
+
+	return ops.Slice(frame, start, end)
+}
+
+func (qi *queryIterator) Err() error {
+	return qi.err
+}
+
+func (qi *queryIterator) At() frames.Frame {
+	return qi.frame
+}
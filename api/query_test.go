@@ -0,0 +1,136 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/v3io/frames"
+)
+
+func TestQueryStage(t *testing.T) {
+	rows := runQuery(t, `
+	SELECT host AS h, cpu * 2 AS c2, upper(user)
+	FROM t
+	ORDER BY c2 DESC
+	LIMIT 2 OFFSET 1`)
+
+	expected := []map[string]interface{}{
+		{"h": "b", "c2": int64(8), "upper(user)": "X"},
+		{"h": "a", "c2": int64(6), "upper(user)": "X"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("bad result - %v", rows)
+	}
+}
+
+func TestQueryStageGroupBy(t *testing.T) {
+	rows := runQuery(t, `
+	SELECT host, sum(cpu) * 10 AS total
+	FROM t
+	GROUP BY host
+	ORDER BY max(cpu)`)
+
+	expected := []map[string]interface{}{
+		{"host": "b", "total": int64(60)},
+		{"host": "a", "total": int64(90)},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("bad result - %v", rows)
+	}
+}
+
+func TestQueryStageLimit(t *testing.T) {
+	rows := runQuery(t, "SELECT * FROM t LIMIT 3 OFFSET 1")
+	if len(rows) != 3 {
+		t.Fatalf("bad number of rows - %d", len(rows))
+	}
+
+	if cpu := rows[2]["cpu"]; cpu != int64(4) {
+		t.Fatalf("bad value - %v", cpu)
+	}
+}
+
+func TestQueryStageLimitZero(t *testing.T) {
+	for _, sql := range []string{
+		"SELECT * FROM t LIMIT 0",
+		"SELECT host, count(*) FROM t GROUP BY host ORDER BY host LIMIT 0",
+	} {
+		if rows := runQuery(t, sql); len(rows) != 0 {
+			t.Fatalf("%s: %d rows returned", sql, len(rows))
+		}
+	}
+}
+
+// runQuery runs query on two frames made by groupByFrame
+func runQuery(t *testing.T, sql string) []map[string]interface{} {
+	query, err := frames.ParseSQL(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := &frames.ReadRequest{
+		Columns: query.Columns,
+		GroupBy: query.GroupBy,
+	}
+
+	stage, err := newQueryStage(query, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groupBy, err := newGroupBy(request, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame1 := groupByFrame(t, []string{"a", "b", "a"}, []int64{1, 2, 3}, []string{"x", "y", "x"})
+	frame2 := groupByFrame(t, []string{"b", "a"}, []int64{4, 5}, []string{"x", "z"})
+	var it frames.FrameIterator = &sliceIterator{frames: []frames.Frame{frame1, frame2}}
+	if groupBy != nil {
+		it = groupBy.iterator(it)
+	}
+
+	if stage != nil {
+		it = stage.iterator(it)
+	}
+
+	var rows []map[string]interface{}
+	for it.Next() {
+		iter := it.At().IterRows(false)
+		for iter.Next() {
+			rows = append(rows, iter.Row())
+		}
+
+		if err := iter.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return rows
+}
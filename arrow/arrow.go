@@ -0,0 +1,172 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+/*
+Package arrow converts frames to and from Apache Arrow record batches and
+encodes them in the Arrow IPC streaming format.
+
+See https://arrow.apache.org/docs/format/Columnar.html for the format
+specification. Only flat (non nested) columns of the frames data types are
+supported, without compression or dictionaries.
+
+Index columns and frame labels are stored in the Arrow schema metadata so a
+frame survives a round trip. Other Arrow implementations (e.g. pyarrow) see
+index columns as regular columns.
+*/
+package arrow
+
+import (
+	"encoding/binary"
+)
+
+// ContentType is the HTTP content type of Arrow IPC streams
+const ContentType = "application/vnd.apache.arrow.stream"
+
+// Metadata keys
+const (
+	// Field metadata, value is one of the *Kind constants
+	kindKey = "frames.kind"
+	// Schema metadata, frame labels as JSON
+	labelsKey = "frames.labels"
+	// Schema metadata, error message (in error streams)
+	errorKey = "frames.error"
+
+	indexKind = "index"
+	labelKind = "label"
+)
+
+var byteOrder = binary.LittleEndian
+
+// TypeID is an Arrow logical type
+type TypeID int
+
+// Supported type IDs (values are the Arrow flatbuffers Type union tags)
+const (
+	IntType       TypeID = 2
+	FloatType     TypeID = 3
+	Utf8Type      TypeID = 5
+	BoolType      TypeID = 6
+	TimestampType TypeID = 10
+	LargeUtf8Type TypeID = 20
+)
+
+// Floating point precisions
+const (
+	HalfPrecision   = 0
+	SinglePrecision = 1
+	DoublePrecision = 2
+)
+
+// TimeUnit is a timestamp unit
+type TimeUnit int
+
+// Time units
+const (
+	Second TimeUnit = iota
+	Millisecond
+	Microsecond
+	Nanosecond
+)
+
+// DataType is an Arrow data type
+type DataType struct {
+	ID        TypeID
+	BitWidth  int      // IntType
+	Signed    bool     // IntType
+	Precision int      // FloatType
+	Unit      TimeUnit // TimestampType
+	Timezone  string   // TimestampType
+}
+
+// Field is a schema field
+type Field struct {
+	Name     string
+	Type     DataType
+	Nullable bool
+	Metadata map[string]string
+}
+
+// Schema is a record schema
+type Schema struct {
+	Fields   []Field
+	Metadata map[string]string
+}
+
+// Array is column data in Arrow memory layout, Buffers are validity bitmap
+// followed by data buffers (offsets & values for strings)
+type Array struct {
+	Len       int
+	NullCount int
+	Buffers   [][]byte
+}
+
+// Record is a record batch
+type Record struct {
+	Schema  *Schema
+	NumRows int
+	Columns []*Array
+}
+
+// numBuffers returns the number of buffers an array of type has
+func (dt DataType) numBuffers() int {
+	switch dt.ID {
+	case Utf8Type, LargeUtf8Type:
+		return 3
+	}
+	return 2
+}
+
+// equal returns true if both schemas are the same
+func (s *Schema) equal(other *Schema) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+
+	if len(s.Fields) != len(other.Fields) || !equalMaps(s.Metadata, other.Metadata) {
+		return false
+	}
+
+	for i, field := range s.Fields {
+		o := other.Fields[i]
+		if field.Name != o.Name || field.Type != o.Type || field.Nullable != o.Nullable {
+			return false
+		}
+
+		if !equalMaps(field.Metadata, o.Metadata) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalMaps(m1, m2 map[string]string) bool {
+	if len(m1) != len(m2) {
+		return false
+	}
+
+	for key, val := range m1 {
+		if oval, ok := m2[key]; !ok || oval != val {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package arrow
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+)
+
+func makeFrame(t *testing.T, size int, labels map[string]interface{}) frames.Frame {
+	var (
+		ints    = make([]int64, size)
+		floats  = make([]float64, size)
+		strs    = make([]string, size)
+		times   = make([]time.Time, size)
+		bools   = make([]bool, size)
+		nulls   = make([]bool, size)
+		indices = make([]string, size)
+	)
+
+	start := time.Date(2018, 10, 17, 13, 14, 15, 16, time.UTC)
+	for i := 0; i < size; i++ {
+		ints[i] = int64(i * 7)
+		floats[i] = float64(i) / 3
+		strs[i] = strings.Repeat("s", i%5)
+		times[i] = start.Add(time.Duration(i) * time.Minute)
+		bools[i] = i%3 == 0
+		nulls[i] = i%4 == 1
+		indices[i] = fmt.Sprintf("idx%d", i)
+	}
+
+	var cols []frames.Column
+	add := func(name string, data interface{}, nulls []bool) {
+		col, err := frames.NewNullableSliceColumn(name, data, nulls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cols = append(cols, col)
+	}
+
+	add("ints", ints, nil)
+	add("floats", floats, nulls)
+	add("strings", strs, nulls)
+	add("times", times, nil)
+	add("bools", bools, nulls)
+
+	label, err := frames.NewLabelColumn("label", "l1", size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cols = append(cols, label)
+
+	index, err := frames.NewSliceColumn("idx", indices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, err := frames.NewFrame(cols, []frames.Column{index}, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return frame
+}
+
+func checkFrames(t *testing.T, expected, actual frames.Frame) {
+	if !reflect.DeepEqual(expected.Names(), actual.Names()) {
+		t.Fatalf("names mismatch: %v != %v", expected.Names(), actual.Names())
+	}
+
+	if !reflect.DeepEqual(expected.Labels(), actual.Labels()) {
+		t.Fatalf("labels mismatch: %v != %v", expected.Labels(), actual.Labels())
+	}
+
+	if len(expected.Indices()) != len(actual.Indices()) {
+		t.Fatalf("indices mismatch: %d != %d", len(expected.Indices()), len(actual.Indices()))
+	}
+
+	for _, name := range expected.Names() {
+		col, _ := expected.Column(name)
+		acol, err := actual.Column(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Empty label columns have no value and are decoded as slice columns
+		if col.Len() > 0 && frames.IsLabelColumn(col) != frames.IsLabelColumn(acol) {
+			t.Fatalf("%q: label column mismatch", name)
+		}
+
+		if col.NullCount() != acol.NullCount() {
+			t.Fatalf("%q: null count mismatch: %d != %d", name, col.NullCount(), acol.NullCount())
+		}
+	}
+
+	eit, ait := expected.IterRows(true), actual.IterRows(true)
+	for eit.Next() {
+		if !ait.Next() {
+			t.Fatalf("%d: missing row", eit.RowNum())
+		}
+
+		if !reflect.DeepEqual(eit.Row(), ait.Row()) {
+			t.Fatalf("%d: row mismatch:\n%v\n%v", eit.RowNum(), eit.Row(), ait.Row())
+		}
+	}
+
+	if ait.Next() {
+		t.Fatal("extra rows")
+	}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	labels := map[string]interface{}{
+		"i": int64(1),
+		"s": "two",
+		"t": time.Date(2018, 1, 2, 3, 4, 5, 6, time.UTC),
+	}
+	frame := makeFrame(t, 17, labels)
+
+	rec, err := FrameToRecord(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Schema.Fields[0].Name != "idx" {
+		t.Fatalf("index is not first field: %q", rec.Schema.Fields[0].Name)
+	}
+
+	frame2, err := RecordToFrame(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkFrames(t, frame, frame2)
+}
+
+func TestIPCRoundTrip(t *testing.T) {
+	frs := []frames.Frame{
+		makeFrame(t, 10, nil),
+		makeFrame(t, 3, nil),
+		makeFrame(t, 0, nil),
+		makeFrame(t, 5, map[string]interface{}{"x": 1.5}), // new schema
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, frame := range frs {
+		if err := w.Write(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewReader(&buf)
+	i := 0
+	for r.Next() {
+		if i >= len(frs) {
+			t.Fatalf("too many frames")
+		}
+		checkFrames(t, frs[i], r.At())
+		i++
+	}
+
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if i != len(frs) {
+		t.Fatalf("frames mismatch: %d != %d", i, len(frs))
+	}
+}
+
+func TestIPCError(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.Write(makeFrame(t, 3, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.WriteError(fmt.Errorf("oops")); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewReader(&buf)
+	if !r.Next() {
+		t.Fatalf("no frame: %v", r.Err())
+	}
+
+	if r.Next() {
+		t.Fatal("frame after error")
+	}
+
+	if err := r.Err(); err == nil || err.Error() != "oops" {
+		t.Fatalf("bad error: %v", err)
+	}
+}
+
+func TestIPCEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWriter(&buf).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewReader(&buf)
+	if r.Next() {
+		t.Fatal("frame in empty stream")
+	}
+
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIPCMalformed(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.Write(makeFrame(t, 3, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	for _, size := range []int{7, 20, len(data) / 2, len(data) - 1} {
+		r := NewReader(bytes.NewReader(data[:size]))
+		for r.Next() {
+		}
+
+		if r.Err() == nil {
+			t.Fatalf("%d: no error on truncated stream", size)
+		}
+	}
+
+	// Garbage metadata
+	garbage := append([]byte{}, data...)
+	for i := 8; i < 40; i++ {
+		garbage[i] = 0xFF
+	}
+
+	r := NewReader(bytes.NewReader(garbage))
+	for r.Next() {
+	}
+
+	if r.Err() == nil {
+		t.Fatal("no error on malformed stream")
+	}
+}
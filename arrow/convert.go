@@ -0,0 +1,485 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package arrow
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+)
+
+// labelValue is a typed label value in schema metadata
+type labelValue struct {
+	DType string      `json:"dtype"`
+	Value interface{} `json:"value"`
+}
+
+// Label dtypes in schema metadata
+const (
+	boolLabel   = "bool"
+	floatLabel  = "float"
+	intLabel    = "int"
+	stringLabel = "string"
+	timeLabel   = "time"
+)
+
+// FrameToRecord converts a frame to a record batch. Index columns come first in
+// the record, label columns are materialized and marked in field metadata
+func FrameToRecord(frame frames.Frame) (*Record, error) {
+	rec := &Record{
+		Schema:  &Schema{},
+		NumRows: frame.Len(),
+	}
+
+	if len(frame.Labels()) > 0 {
+		labels, err := encodeLabels(frame.Labels())
+		if err != nil {
+			return nil, err
+		}
+		rec.Schema.Metadata = map[string]string{labelsKey: labels}
+	}
+
+	for _, col := range frame.Indices() {
+		if err := addColumn(rec, col, indexKind); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range frame.Names() {
+		col, err := frame.Column(name)
+		if err != nil {
+			return nil, err
+		}
+
+		kind := ""
+		if frames.IsLabelColumn(col) {
+			kind = labelKind
+		}
+
+		if err := addColumn(rec, col, kind); err != nil {
+			return nil, err
+		}
+	}
+
+	return rec, nil
+}
+
+func addColumn(rec *Record, col frames.Column, kind string) error {
+	field := Field{
+		Name:     col.Name(),
+		Nullable: true,
+	}
+
+	if kind != "" {
+		field.Metadata = map[string]string{kindKey: kind}
+	}
+
+	size := col.Len()
+	arr := &Array{
+		Len:       size,
+		NullCount: col.NullCount(),
+	}
+
+	var validity []byte
+	if arr.NullCount > 0 {
+		validity = make([]byte, (size+7)/8)
+		for i := 0; i < size; i++ {
+			if !col.IsNull(i) {
+				setBit(validity, i)
+			}
+		}
+	}
+
+	switch col.DType() {
+	case frames.IntType:
+		field.Type = DataType{ID: IntType, BitWidth: 64, Signed: true}
+		values, err := col.Ints()
+		if err != nil {
+			return err
+		}
+		data := make([]byte, 8*size)
+		for i, v := range values {
+			byteOrder.PutUint64(data[8*i:], uint64(v))
+		}
+		arr.Buffers = [][]byte{validity, data}
+	case frames.FloatType:
+		field.Type = DataType{ID: FloatType, Precision: DoublePrecision}
+		values, err := col.Floats()
+		if err != nil {
+			return err
+		}
+		data := make([]byte, 8*size)
+		for i, v := range values {
+			byteOrder.PutUint64(data[8*i:], math.Float64bits(v))
+		}
+		arr.Buffers = [][]byte{validity, data}
+	case frames.StringType:
+		field.Type = DataType{ID: Utf8Type}
+		values := col.Strings()
+		offsets := make([]byte, 4*(size+1))
+		var data []byte
+		for i, v := range values {
+			data = append(data, v...)
+			if len(data) > math.MaxInt32 {
+				return fmt.Errorf("%q - string data too big", col.Name())
+			}
+			byteOrder.PutUint32(offsets[4*(i+1):], uint32(len(data)))
+		}
+		arr.Buffers = [][]byte{validity, offsets, data}
+	case frames.TimeType:
+		field.Type = DataType{ID: TimestampType, Unit: Nanosecond, Timezone: "UTC"}
+		values, err := col.Times()
+		if err != nil {
+			return err
+		}
+		data := make([]byte, 8*size)
+		for i, v := range values {
+			byteOrder.PutUint64(data[8*i:], uint64(v.UnixNano()))
+		}
+		arr.Buffers = [][]byte{validity, data}
+	case frames.BoolType:
+		field.Type = DataType{ID: BoolType}
+		values, err := col.Bools()
+		if err != nil {
+			return err
+		}
+		data := make([]byte, (size+7)/8)
+		for i, v := range values {
+			if v {
+				setBit(data, i)
+			}
+		}
+		arr.Buffers = [][]byte{validity, data}
+	default:
+		return fmt.Errorf("%q - unsupported dtype - %v", col.Name(), col.DType())
+	}
+
+	rec.Schema.Fields = append(rec.Schema.Fields, field)
+	rec.Columns = append(rec.Columns, arr)
+	return nil
+}
+
+// RecordToFrame converts a record batch to a frame
+func RecordToFrame(rec *Record) (frames.Frame, error) {
+	if len(rec.Columns) != len(rec.Schema.Fields) {
+		return nil, fmt.Errorf("record has %d columns but schema has %d fields", len(rec.Columns), len(rec.Schema.Fields))
+	}
+
+	var columns, indices []frames.Column
+	for i, field := range rec.Schema.Fields {
+		col, err := arrayToColumn(field, rec.Columns[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "%q - can't convert", field.Name)
+		}
+
+		if field.Metadata[kindKey] == indexKind {
+			indices = append(indices, col)
+		} else {
+			columns = append(columns, col)
+		}
+	}
+
+	var labels map[string]interface{}
+	if data, ok := rec.Schema.Metadata[labelsKey]; ok {
+		var err error
+		labels, err = decodeLabels(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return frames.NewFrame(columns, indices, labels)
+}
+
+func arrayToColumn(field Field, arr *Array) (frames.Column, error) {
+	if len(arr.Buffers) != field.Type.numBuffers() {
+		return nil, fmt.Errorf("wrong number of buffers - %d", len(arr.Buffers))
+	}
+
+	size := arr.Len
+	var data interface{}
+	switch field.Type.ID {
+	case IntType:
+		values, err := decodeInts(arr.Buffers[1], size, field.Type.BitWidth, field.Type.Signed)
+		if err != nil {
+			return nil, err
+		}
+		data = values
+	case FloatType:
+		values := make([]float64, size)
+		switch field.Type.Precision {
+		case SinglePrecision:
+			if err := checkSize(arr.Buffers[1], 4*size); err != nil {
+				return nil, err
+			}
+			for i := range values {
+				values[i] = float64(math.Float32frombits(byteOrder.Uint32(arr.Buffers[1][4*i:])))
+			}
+		case DoublePrecision:
+			if err := checkSize(arr.Buffers[1], 8*size); err != nil {
+				return nil, err
+			}
+			for i := range values {
+				values[i] = math.Float64frombits(byteOrder.Uint64(arr.Buffers[1][8*i:]))
+			}
+		default:
+			return nil, fmt.Errorf("unsupported float precision - %d", field.Type.Precision)
+		}
+		data = values
+	case Utf8Type, LargeUtf8Type:
+		values, err := decodeStrings(arr.Buffers[1], arr.Buffers[2], size, field.Type.ID == LargeUtf8Type)
+		if err != nil {
+			return nil, err
+		}
+		data = values
+	case TimestampType:
+		ints, err := decodeInts(arr.Buffers[1], size, 64, true)
+		if err != nil {
+			return nil, err
+		}
+		var unit time.Duration
+		switch field.Type.Unit {
+		case Second:
+			unit = time.Second
+		case Millisecond:
+			unit = time.Millisecond
+		case Microsecond:
+			unit = time.Microsecond
+		default:
+			unit = time.Nanosecond
+		}
+		values := make([]time.Time, size)
+		for i, v := range ints {
+			values[i] = time.Unix(0, v*int64(unit)).UTC()
+		}
+		data = values
+	case BoolType:
+		if err := checkSize(arr.Buffers[1], (size+7)/8); err != nil {
+			return nil, err
+		}
+		values := make([]bool, size)
+		for i := range values {
+			values[i] = getBit(arr.Buffers[1], i)
+		}
+		data = values
+	default:
+		return nil, fmt.Errorf("unsupported type - %d", field.Type.ID)
+	}
+
+	var nulls []bool
+	if arr.NullCount > 0 {
+		validity := arr.Buffers[0]
+		if err := checkSize(validity, (size+7)/8); err != nil {
+			return nil, errors.Wrap(err, "validity bitmap")
+		}
+		nulls = make([]bool, size)
+		for i := range nulls {
+			nulls[i] = !getBit(validity, i)
+		}
+	}
+
+	if field.Metadata[kindKey] == labelKind && size > 0 && nulls == nil {
+		return frames.NewLabelColumn(field.Name, firstValue(data), size)
+	}
+
+	return frames.NewNullableSliceColumn(field.Name, data, nulls)
+}
+
+func firstValue(data interface{}) interface{} {
+	switch data := data.(type) {
+	case []int64:
+		return data[0]
+	case []float64:
+		return data[0]
+	case []string:
+		return data[0]
+	case []time.Time:
+		return data[0]
+	case []bool:
+		return data[0]
+	}
+
+	return nil
+}
+
+func decodeInts(buf []byte, size int, bitWidth int, signed bool) ([]int64, error) {
+	width := bitWidth / 8
+	switch width {
+	case 1, 2, 4, 8:
+	default:
+		return nil, fmt.Errorf("unsupported int bit width - %d", bitWidth)
+	}
+
+	if err := checkSize(buf, width*size); err != nil {
+		return nil, err
+	}
+
+	values := make([]int64, size)
+	for i := range values {
+		b := buf[width*i:]
+		switch {
+		case width == 1 && signed:
+			values[i] = int64(int8(b[0]))
+		case width == 1:
+			values[i] = int64(b[0])
+		case width == 2 && signed:
+			values[i] = int64(int16(byteOrder.Uint16(b)))
+		case width == 2:
+			values[i] = int64(byteOrder.Uint16(b))
+		case width == 4 && signed:
+			values[i] = int64(int32(byteOrder.Uint32(b)))
+		case width == 4:
+			values[i] = int64(byteOrder.Uint32(b))
+		default:
+			// uint64 values above math.MaxInt64 wrap around
+			values[i] = int64(byteOrder.Uint64(b))
+		}
+	}
+
+	return values, nil
+}
+
+func decodeStrings(offsetsBuf, data []byte, size int, large bool) ([]string, error) {
+	width := 4
+	if large {
+		width = 8
+	}
+
+	if err := checkSize(offsetsBuf, width*(size+1)); err != nil {
+		return nil, errors.Wrap(err, "offsets")
+	}
+
+	offsetAt := func(i int) int64 {
+		if large {
+			return int64(byteOrder.Uint64(offsetsBuf[8*i:]))
+		}
+		return int64(int32(byteOrder.Uint32(offsetsBuf[4*i:])))
+	}
+
+	values := make([]string, size)
+	for i := range values {
+		start, end := offsetAt(i), offsetAt(i+1)
+		if start < 0 || end < start || end > int64(len(data)) {
+			return nil, fmt.Errorf("bad string offsets at %d", i)
+		}
+		values[i] = string(data[start:end])
+	}
+
+	return values, nil
+}
+
+func checkSize(buf []byte, size int) error {
+	if len(buf) < size {
+		return fmt.Errorf("buffer too small (%d < %d)", len(buf), size)
+	}
+
+	return nil
+}
+
+func setBit(bitmap []byte, i int) {
+	bitmap[i/8] |= 1 << uint(i%8)
+}
+
+func getBit(bitmap []byte, i int) bool {
+	return bitmap[i/8]&(1<<uint(i%8)) != 0
+}
+
+func encodeLabels(labels map[string]interface{}) (string, error) {
+	values := make(map[string]labelValue, len(labels))
+	for name, value := range labels {
+		var lv labelValue
+		switch value := value.(type) {
+		case bool:
+			lv = labelValue{boolLabel, value}
+		case float64:
+			lv = labelValue{floatLabel, value}
+		case int:
+			lv = labelValue{intLabel, value}
+		case int64:
+			lv = labelValue{intLabel, value}
+		case string:
+			lv = labelValue{stringLabel, value}
+		case time.Time:
+			lv = labelValue{timeLabel, value.Format(time.RFC3339Nano)}
+		default:
+			return "", fmt.Errorf("label %q - unsupported type %T", name, value)
+		}
+		values[name] = lv
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", errors.Wrap(err, "can't encode labels")
+	}
+
+	return string(data), nil
+}
+
+func decodeLabels(data string) (map[string]interface{}, error) {
+	var values map[string]struct {
+		DType string          `json:"dtype"`
+		Value json.RawMessage `json:"value"`
+	}
+
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return nil, errors.Wrap(err, "can't decode labels")
+	}
+
+	labels := make(map[string]interface{}, len(values))
+	for name, lv := range values {
+		var err error
+		switch lv.DType {
+		case boolLabel:
+			var v bool
+			err = json.Unmarshal(lv.Value, &v)
+			labels[name] = v
+		case floatLabel:
+			var v float64
+			err = json.Unmarshal(lv.Value, &v)
+			labels[name] = v
+		case intLabel:
+			var v int64
+			err = json.Unmarshal(lv.Value, &v)
+			labels[name] = v
+		case stringLabel:
+			var v string
+			err = json.Unmarshal(lv.Value, &v)
+			labels[name] = v
+		case timeLabel:
+			var v time.Time
+			err = json.Unmarshal(lv.Value, &v)
+			labels[name] = v
+		default:
+			err = fmt.Errorf("unknown dtype - %q", lv.DType)
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "label %q", name)
+		}
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package arrow
+
+// Minimal flatbuffers encoding/decoding, enough for Arrow IPC metadata
+// (see https://google.github.io/flatbuffers/flatbuffers_internals.html)
+//
+// The builder writes front to back: a table is written before its children
+// so all offsets point forward, vtables are written before their tables.
+// Alignment is relative to the start of the buffer.
+
+import (
+	"fmt"
+)
+
+type fbKind int
+
+const (
+	fbBool fbKind = iota
+	fbUint8
+	fbInt16
+	fbInt32
+	fbInt64
+	fbRef
+)
+
+// fbField is a table field, ref is used only in fbRef fields
+type fbField struct {
+	id   int
+	kind fbKind
+	val  int64
+	ref  interface{} // *fbTable, fbString, fbVector or fbStructs
+}
+
+func (f fbField) size() int {
+	switch f.kind {
+	case fbBool, fbUint8:
+		return 1
+	case fbInt16:
+		return 2
+	case fbInt32, fbRef:
+		return 4
+	}
+	return 8
+}
+
+type fbTable struct {
+	fields []fbField
+}
+
+func (t *fbTable) addBool(id int, val bool) {
+	var i int64
+	if val {
+		i = 1
+	}
+	t.fields = append(t.fields, fbField{id: id, kind: fbBool, val: i})
+}
+
+func (t *fbTable) addUint8(id int, val uint8) {
+	t.fields = append(t.fields, fbField{id: id, kind: fbUint8, val: int64(val)})
+}
+
+func (t *fbTable) addInt16(id int, val int16) {
+	t.fields = append(t.fields, fbField{id: id, kind: fbInt16, val: int64(val)})
+}
+
+func (t *fbTable) addInt32(id int, val int32) {
+	t.fields = append(t.fields, fbField{id: id, kind: fbInt32, val: int64(val)})
+}
+
+func (t *fbTable) addInt64(id int, val int64) {
+	t.fields = append(t.fields, fbField{id: id, kind: fbInt64, val: val})
+}
+
+func (t *fbTable) addRef(id int, ref interface{}) {
+	t.fields = append(t.fields, fbField{id: id, kind: fbRef, ref: ref})
+}
+
+// fbString is a string object
+type fbString string
+
+// fbVector is a vector of tables or strings
+type fbVector []interface{}
+
+// fbStructs is a vector of structs (or scalars) of 8 byte aligned elements
+type fbStructs struct {
+	n    int
+	data []byte
+}
+
+type fbBuilder struct {
+	buf []byte
+}
+
+// buildFlatbuffer returns the encoded buffer with root as the root table
+func buildFlatbuffer(root *fbTable) []byte {
+	b := &fbBuilder{buf: make([]byte, 4)}
+	pos := b.writeTable(root)
+	byteOrder.PutUint32(b.buf, uint32(pos))
+	return b.buf
+}
+
+func (b *fbBuilder) pad(align int) {
+	for len(b.buf)%align != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+func (b *fbBuilder) appendUint32(val uint32) {
+	var tmp [4]byte
+	byteOrder.PutUint32(tmp[:], val)
+	b.buf = append(b.buf, tmp[:]...)
+}
+
+func (b *fbBuilder) write(obj interface{}) int {
+	switch obj := obj.(type) {
+	case *fbTable:
+		return b.writeTable(obj)
+	case fbString:
+		return b.writeString(string(obj))
+	case fbVector:
+		return b.writeVector(obj)
+	case fbStructs:
+		return b.writeStructs(obj)
+	}
+
+	panic(fmt.Sprintf("unknown flatbuffers object - %T", obj))
+}
+
+func (b *fbBuilder) writeTable(t *fbTable) int {
+	numSlots := 0
+	offsets := make([]int, len(t.fields))
+	size, maxAlign := 4, 4 // soffset to vtable
+	for i, f := range t.fields {
+		if f.id >= numSlots {
+			numSlots = f.id + 1
+		}
+
+		fsize := f.size()
+		size = alignTo(size, fsize)
+		offsets[i] = size
+		size += fsize
+		if fsize > maxAlign {
+			maxAlign = fsize
+		}
+	}
+
+	// vtable
+	b.pad(2)
+	vtPos := len(b.buf)
+	vtSize := 4 + 2*numSlots
+	b.buf = append(b.buf, make([]byte, vtSize)...)
+	byteOrder.PutUint16(b.buf[vtPos:], uint16(vtSize))
+	byteOrder.PutUint16(b.buf[vtPos+2:], uint16(size))
+	for i, f := range t.fields {
+		byteOrder.PutUint16(b.buf[vtPos+4+2*f.id:], uint16(offsets[i]))
+	}
+
+	// table
+	b.pad(maxAlign)
+	pos := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	byteOrder.PutUint32(b.buf[pos:], uint32(int32(pos-vtPos)))
+	for i, f := range t.fields {
+		fpos := pos + offsets[i]
+		switch f.kind {
+		case fbBool, fbUint8:
+			b.buf[fpos] = byte(f.val)
+		case fbInt16:
+			byteOrder.PutUint16(b.buf[fpos:], uint16(f.val))
+		case fbInt32:
+			byteOrder.PutUint32(b.buf[fpos:], uint32(f.val))
+		case fbInt64:
+			byteOrder.PutUint64(b.buf[fpos:], uint64(f.val))
+		}
+	}
+
+	// children
+	for i, f := range t.fields {
+		if f.kind != fbRef {
+			continue
+		}
+
+		fpos := pos + offsets[i]
+		childPos := b.write(f.ref)
+		byteOrder.PutUint32(b.buf[fpos:], uint32(childPos-fpos))
+	}
+
+	return pos
+}
+
+func (b *fbBuilder) writeString(s string) int {
+	b.pad(4)
+	pos := len(b.buf)
+	b.appendUint32(uint32(len(s)))
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return pos
+}
+
+func (b *fbBuilder) writeVector(v fbVector) int {
+	b.pad(4)
+	pos := len(b.buf)
+	b.appendUint32(uint32(len(v)))
+	b.buf = append(b.buf, make([]byte, 4*len(v))...)
+	for i, obj := range v {
+		epos := pos + 4 + 4*i
+		childPos := b.write(obj)
+		byteOrder.PutUint32(b.buf[epos:], uint32(childPos-epos))
+	}
+
+	return pos
+}
+
+func (b *fbBuilder) writeStructs(s fbStructs) int {
+	// Elements start right after the length and should be 8 byte aligned
+	for (len(b.buf)+4)%8 != 0 {
+		b.buf = append(b.buf, 0)
+	}
+
+	pos := len(b.buf)
+	b.appendUint32(uint32(s.n))
+	b.buf = append(b.buf, s.data...)
+	return pos
+}
+
+// fbReader reads a flatbuffers table. Methods panic on malformed buffers,
+// callers should recover
+type fbReader struct {
+	buf []byte
+	pos int
+}
+
+func fbRoot(buf []byte) fbReader {
+	return fbReader{buf, int(byteOrder.Uint32(buf))}
+}
+
+// offset returns the field offset in the table, 0 if field is missing
+func (t fbReader) offset(id int) int {
+	vtPos := t.pos - int(int32(byteOrder.Uint32(t.buf[t.pos:])))
+	vtSize := int(byteOrder.Uint16(t.buf[vtPos:]))
+	slot := 4 + 2*id
+	if slot+2 > vtSize {
+		return 0
+	}
+
+	return int(byteOrder.Uint16(t.buf[vtPos+slot:]))
+}
+
+func (t fbReader) uint8(id int, def uint8) uint8 {
+	off := t.offset(id)
+	if off == 0 {
+		return def
+	}
+	return t.buf[t.pos+off]
+}
+
+func (t fbReader) bool(id int) bool {
+	return t.uint8(id, 0) != 0
+}
+
+func (t fbReader) int16(id int, def int16) int16 {
+	off := t.offset(id)
+	if off == 0 {
+		return def
+	}
+	return int16(byteOrder.Uint16(t.buf[t.pos+off:]))
+}
+
+func (t fbReader) int32(id int, def int32) int32 {
+	off := t.offset(id)
+	if off == 0 {
+		return def
+	}
+	return int32(byteOrder.Uint32(t.buf[t.pos+off:]))
+}
+
+func (t fbReader) int64(id int, def int64) int64 {
+	off := t.offset(id)
+	if off == 0 {
+		return def
+	}
+	return int64(byteOrder.Uint64(t.buf[t.pos+off:]))
+}
+
+// deref follows the uoffset at pos
+func (t fbReader) deref(pos int) int {
+	return pos + int(byteOrder.Uint32(t.buf[pos:]))
+}
+
+func (t fbReader) table(id int) (fbReader, bool) {
+	off := t.offset(id)
+	if off == 0 {
+		return fbReader{}, false
+	}
+	return fbReader{t.buf, t.deref(t.pos + off)}, true
+}
+
+func (t fbReader) stringAt(pos int) string {
+	n := int(byteOrder.Uint32(t.buf[pos:]))
+	return string(t.buf[pos+4 : pos+4+n])
+}
+
+func (t fbReader) string(id int) string {
+	off := t.offset(id)
+	if off == 0 {
+		return ""
+	}
+	return t.stringAt(t.deref(t.pos + off))
+}
+
+// vector returns the position of the first element and the vector length
+func (t fbReader) vector(id int) (int, int, bool) {
+	off := t.offset(id)
+	if off == 0 {
+		return 0, 0, false
+	}
+
+	pos := t.deref(t.pos + off)
+	return pos + 4, int(byteOrder.Uint32(t.buf[pos:])), true
+}
+
+// tableAt returns the i'th table in a vector of tables starting at pos
+func (t fbReader) tableAt(pos int, i int) fbReader {
+	return fbReader{t.buf, t.deref(pos + 4*i)}
+}
+
+func alignTo(n, align int) int {
+	if rem := n % align; rem != 0 {
+		n += align - rem
+	}
+	return n
+}
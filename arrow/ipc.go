@@ -0,0 +1,584 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package arrow
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+)
+
+// IPC message encoding constants
+const (
+	continuationMarker = 0xFFFFFFFF
+	metadataVersionV5  = 4
+
+	schemaHeader      = 1
+	dictionaryHeader  = 2
+	recordBatchHeader = 3
+
+	// Maximal message metadata size we accept
+	maxMetadataSize = 64 * 1024 * 1024
+)
+
+// Writer writes frames in the Arrow IPC streaming format. When a frame schema
+// changes (including labels) the current stream ends and a new one starts, Reader
+// reads all the streams. Other readers (e.g. pyarrow) should open a new stream
+// reader after the end of each stream
+type Writer struct {
+	w      io.Writer
+	schema *Schema // Current stream schema, nil if not in a stream
+	closed bool
+}
+
+// NewWriter returns a new Writer writing to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes frame as a record batch
+func (w *Writer) Write(frame frames.Frame) error {
+	rec, err := FrameToRecord(frame)
+	if err != nil {
+		return err
+	}
+
+	return w.WriteRecord(rec)
+}
+
+// WriteRecord writes a record batch
+func (w *Writer) WriteRecord(rec *Record) error {
+	if w.closed {
+		return fmt.Errorf("write to closed writer")
+	}
+
+	if !w.schema.equal(rec.Schema) {
+		if err := w.endStream(); err != nil {
+			return err
+		}
+
+		if err := w.writeMessage(schemaHeader, encodeSchema(rec.Schema), nil); err != nil {
+			return err
+		}
+		w.schema = rec.Schema
+	}
+
+	header, body, err := encodeRecordBatch(rec)
+	if err != nil {
+		return err
+	}
+
+	return w.writeMessage(recordBatchHeader, header, body)
+}
+
+// WriteError ends the current stream and writes a stream with an error, Reader
+// will return err from Err
+func (w *Writer) WriteError(err error) error {
+	if w.closed {
+		return fmt.Errorf("write to closed writer")
+	}
+
+	if err := w.endStream(); err != nil {
+		return err
+	}
+
+	schema := &Schema{Metadata: map[string]string{errorKey: err.Error()}}
+	if err := w.writeMessage(schemaHeader, encodeSchema(schema), nil); err != nil {
+		return err
+	}
+
+	w.schema = schema
+	return w.Close()
+}
+
+// Close ends the current stream, it does not close the underlying io.Writer
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+
+	if w.schema == nil {
+		// Empty stream, write schema so it'll be a valid Arrow stream
+		if err := w.writeMessage(schemaHeader, encodeSchema(&Schema{}), nil); err != nil {
+			return err
+		}
+		w.schema = &Schema{}
+	}
+
+	w.closed = true
+	return w.endStream()
+}
+
+func (w *Writer) endStream() error {
+	if w.schema == nil {
+		return nil
+	}
+
+	w.schema = nil
+	var eos [8]byte
+	byteOrder.PutUint32(eos[:], continuationMarker)
+	_, err := w.w.Write(eos[:])
+	return err
+}
+
+func (w *Writer) writeMessage(headerType int, header *fbTable, body [][]byte) error {
+	bodyLen := 0
+	for _, buf := range body {
+		bodyLen += alignTo(len(buf), 8)
+	}
+
+	msg := &fbTable{}
+	msg.addInt16(0, metadataVersionV5)
+	msg.addUint8(1, uint8(headerType))
+	msg.addRef(2, header)
+	msg.addInt64(3, int64(bodyLen))
+	meta := buildFlatbuffer(msg)
+
+	// continuation, size & metadata should be 8 byte aligned
+	size := alignTo(len(meta)+8, 8) - 8
+	buf := make([]byte, 8+size)
+	byteOrder.PutUint32(buf, continuationMarker)
+	byteOrder.PutUint32(buf[4:], uint32(size))
+	copy(buf[8:], meta)
+	if _, err := w.w.Write(buf); err != nil {
+		return errors.Wrap(err, "can't write message")
+	}
+
+	var pad [8]byte
+	for _, data := range body {
+		if _, err := w.w.Write(data); err != nil {
+			return errors.Wrap(err, "can't write message body")
+		}
+
+		if n := alignTo(len(data), 8) - len(data); n > 0 {
+			if _, err := w.w.Write(pad[:n]); err != nil {
+				return errors.Wrap(err, "can't write message body")
+			}
+		}
+	}
+
+	return nil
+}
+
+func encodeSchema(schema *Schema) *fbTable {
+	fields := make(fbVector, len(schema.Fields))
+	for i, field := range schema.Fields {
+		fields[i] = encodeField(field)
+	}
+
+	tbl := &fbTable{}
+	tbl.addInt16(0, 0) // little endian
+	tbl.addRef(1, fields)
+	if len(schema.Metadata) > 0 {
+		tbl.addRef(2, encodeMetadata(schema.Metadata))
+	}
+	return tbl
+}
+
+func encodeField(field Field) *fbTable {
+	typ := &fbTable{}
+	switch field.Type.ID {
+	case IntType:
+		typ.addInt32(0, int32(field.Type.BitWidth))
+		typ.addBool(1, field.Type.Signed)
+	case FloatType:
+		typ.addInt16(0, int16(field.Type.Precision))
+	case TimestampType:
+		typ.addInt16(0, int16(field.Type.Unit))
+		if field.Type.Timezone != "" {
+			typ.addRef(1, fbString(field.Type.Timezone))
+		}
+	}
+
+	tbl := &fbTable{}
+	tbl.addRef(0, fbString(field.Name))
+	tbl.addBool(1, field.Nullable)
+	tbl.addUint8(2, uint8(field.Type.ID))
+	tbl.addRef(3, typ)
+	tbl.addRef(5, fbVector{}) // children
+	if len(field.Metadata) > 0 {
+		tbl.addRef(6, encodeMetadata(field.Metadata))
+	}
+	return tbl
+}
+
+func encodeMetadata(metadata map[string]string) fbVector {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	vec := make(fbVector, len(keys))
+	for i, key := range keys {
+		kv := &fbTable{}
+		kv.addRef(0, fbString(key))
+		kv.addRef(1, fbString(metadata[key]))
+		vec[i] = kv
+	}
+	return vec
+}
+
+func encodeRecordBatch(rec *Record) (*fbTable, [][]byte, error) {
+	if len(rec.Columns) != len(rec.Schema.Fields) {
+		return nil, nil, fmt.Errorf("record has %d columns but schema has %d fields", len(rec.Columns), len(rec.Schema.Fields))
+	}
+
+	var nodes, buffers []byte
+	var body [][]byte
+	offset := 0
+	for _, arr := range rec.Columns {
+		nodes = appendInt64s(nodes, int64(arr.Len), int64(arr.NullCount))
+		for _, buf := range arr.Buffers {
+			buffers = appendInt64s(buffers, int64(offset), int64(len(buf)))
+			body = append(body, buf)
+			offset += alignTo(len(buf), 8)
+		}
+	}
+
+	tbl := &fbTable{}
+	tbl.addInt64(0, int64(rec.NumRows))
+	tbl.addRef(1, fbStructs{len(rec.Columns), nodes})
+	tbl.addRef(2, fbStructs{len(body), buffers})
+	return tbl, body, nil
+}
+
+func appendInt64s(buf []byte, values ...int64) []byte {
+	var tmp [8]byte
+	for _, v := range values {
+		byteOrder.PutUint64(tmp[:], uint64(v))
+		buf = append(buf, tmp[:]...)
+	}
+	return buf
+}
+
+// Reader reads frames from Arrow IPC streams, it reads consecutive streams
+// until the end of the underlying io.Reader
+type Reader struct {
+	r      io.Reader
+	schema *Schema
+	record *Record
+	frame  frames.Frame
+	err    error
+}
+
+// NewReader returns a new Reader reading from r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Next advances to the next record, it returns false on end of input or error
+func (r *Reader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	r.record, r.frame = nil, nil
+	for {
+		headerType, header, body, err := r.readMessage()
+		if err == io.EOF {
+			return false
+		}
+
+		if err != nil {
+			r.err = err
+			return false
+		}
+
+		switch headerType {
+		case 0: // end of stream
+			r.schema = nil
+		case schemaHeader:
+			schema, err := decodeSchema(header)
+			if err != nil {
+				r.err = err
+				return false
+			}
+
+			if msg, ok := schema.Metadata[errorKey]; ok {
+				r.err = errors.New(msg)
+				return false
+			}
+			r.schema = schema
+		case recordBatchHeader:
+			if r.schema == nil {
+				r.err = fmt.Errorf("record batch without schema")
+				return false
+			}
+
+			r.record, r.err = decodeRecordBatch(r.schema, header, body)
+			return r.err == nil
+		case dictionaryHeader:
+			r.err = fmt.Errorf("dictionaries are not supported")
+			return false
+		default:
+			r.err = fmt.Errorf("unsupported message type - %d", headerType)
+			return false
+		}
+	}
+}
+
+// Record returns the current record
+func (r *Reader) Record() *Record {
+	return r.record
+}
+
+// At returns the current record as a frame
+func (r *Reader) At() frames.Frame {
+	if r.frame != nil || r.record == nil {
+		return r.frame
+	}
+
+	frame, err := RecordToFrame(r.record)
+	if err != nil {
+		r.err = err
+		return nil
+	}
+
+	r.frame = frame
+	return frame
+}
+
+// Err returns the read error
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// readMessage reads the next message, header type 0 is end of stream
+func (r *Reader) readMessage() (int, fbReader, []byte, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+		// Clean EOF only on message boundary
+		return 0, fbReader{}, nil, err
+	}
+
+	size := byteOrder.Uint32(buf[:])
+	if size == continuationMarker {
+		if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+			return 0, fbReader{}, nil, errors.Wrap(noEOF(err), "can't read message size")
+		}
+		size = byteOrder.Uint32(buf[:])
+	}
+
+	if size == 0 {
+		return 0, fbReader{}, nil, nil
+	}
+
+	if size > maxMetadataSize {
+		return 0, fbReader{}, nil, fmt.Errorf("message metadata too big - %d", size)
+	}
+
+	meta := make([]byte, size)
+	if _, err := io.ReadFull(r.r, meta); err != nil {
+		return 0, fbReader{}, nil, errors.Wrap(noEOF(err), "can't read message metadata")
+	}
+
+	var (
+		headerType int
+		header     fbReader
+		bodyLen    int64
+	)
+
+	err := safeDecode(func() error {
+		msg := fbRoot(meta)
+		headerType = int(msg.uint8(1, 0))
+		bodyLen = msg.int64(3, 0)
+		var ok bool
+		header, ok = msg.table(2)
+		if !ok {
+			return fmt.Errorf("message without header")
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, fbReader{}, nil, err
+	}
+
+	if headerType == 0 {
+		return 0, fbReader{}, nil, fmt.Errorf("message without header type")
+	}
+
+	if bodyLen < 0 {
+		return 0, fbReader{}, nil, fmt.Errorf("bad body length - %d", bodyLen)
+	}
+
+	// Don't trust bodyLen for allocation, buffer grows as data is read
+	var body bytes.Buffer
+	if _, err := io.CopyN(&body, r.r, bodyLen); err != nil {
+		return 0, fbReader{}, nil, errors.Wrap(noEOF(err), "can't read message body")
+	}
+
+	return headerType, header, body.Bytes(), nil
+}
+
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// safeDecode runs fn and converts out of bounds panics (from malformed
+// flatbuffers) to errors
+func safeDecode(fn func() error) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("malformed message - %v", e)
+		}
+	}()
+
+	return fn()
+}
+
+func decodeSchema(tbl fbReader) (*Schema, error) {
+	schema := &Schema{}
+	err := safeDecode(func() error {
+		pos, n, _ := tbl.vector(1)
+		for i := 0; i < n; i++ {
+			field, err := decodeField(tbl.tableAt(pos, i))
+			if err != nil {
+				return err
+			}
+			schema.Fields = append(schema.Fields, field)
+		}
+
+		schema.Metadata = decodeMetadata(tbl, 2)
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "can't decode schema")
+	}
+
+	return schema, nil
+}
+
+func decodeField(tbl fbReader) (Field, error) {
+	field := Field{
+		Name:     tbl.string(0),
+		Nullable: tbl.bool(1),
+		Metadata: decodeMetadata(tbl, 6),
+	}
+
+	if _, ok := tbl.table(4); ok {
+		return field, fmt.Errorf("%q - dictionaries are not supported", field.Name)
+	}
+
+	if _, n, _ := tbl.vector(5); n > 0 {
+		return field, fmt.Errorf("%q - nested types are not supported", field.Name)
+	}
+
+	field.Type.ID = TypeID(tbl.uint8(2, 0))
+	typ, ok := tbl.table(3)
+	switch field.Type.ID {
+	case IntType:
+		if ok {
+			field.Type.BitWidth = int(typ.int32(0, 0))
+			field.Type.Signed = typ.bool(1)
+		}
+	case FloatType:
+		if ok {
+			field.Type.Precision = int(typ.int16(0, 0))
+		}
+	case TimestampType:
+		if ok {
+			field.Type.Unit = TimeUnit(typ.int16(0, 0))
+			field.Type.Timezone = typ.string(1)
+		}
+	case Utf8Type, LargeUtf8Type, BoolType:
+	default:
+		return field, fmt.Errorf("%q - unsupported type - %d", field.Name, field.Type.ID)
+	}
+
+	return field, nil
+}
+
+func decodeMetadata(tbl fbReader, id int) map[string]string {
+	pos, n, ok := tbl.vector(id)
+	if !ok || n == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		kv := tbl.tableAt(pos, i)
+		metadata[kv.string(0)] = kv.string(1)
+	}
+	return metadata
+}
+
+func decodeRecordBatch(schema *Schema, tbl fbReader, body []byte) (*Record, error) {
+	rec := &Record{Schema: schema}
+	err := safeDecode(func() error {
+		if _, ok := tbl.table(3); ok {
+			return fmt.Errorf("compression is not supported")
+		}
+
+		rec.NumRows = int(tbl.int64(0, 0))
+		nodesPos, numNodes, _ := tbl.vector(1)
+		buffersPos, numBuffers, _ := tbl.vector(2)
+		if numNodes != len(schema.Fields) {
+			return fmt.Errorf("record batch has %d nodes but schema has %d fields", numNodes, len(schema.Fields))
+		}
+
+		bufIdx := 0
+		for i, field := range schema.Fields {
+			node := tbl.buf[nodesPos+16*i:]
+			arr := &Array{
+				Len:       int(byteOrder.Uint64(node)),
+				NullCount: int(byteOrder.Uint64(node[8:])),
+			}
+
+			if arr.Len < 0 || arr.Len > rec.NumRows {
+				return fmt.Errorf("%q - bad length - %d", field.Name, arr.Len)
+			}
+
+			for j := 0; j < field.Type.numBuffers(); j++ {
+				if bufIdx >= numBuffers {
+					return fmt.Errorf("%q - missing buffers", field.Name)
+				}
+
+				desc := tbl.buf[buffersPos+16*bufIdx:]
+				offset, size := int64(byteOrder.Uint64(desc)), int64(byteOrder.Uint64(desc[8:]))
+				if offset < 0 || size < 0 || offset+size > int64(len(body)) {
+					return fmt.Errorf("%q - buffer out of bounds", field.Name)
+				}
+				arr.Buffers = append(arr.Buffers, body[offset:offset+size])
+				bufIdx++
+			}
+			rec.Columns = append(rec.Columns, arr)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "can't decode record batch")
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,738 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+/*
+Package conformance is a test suite for the frames.DataBackend contract.
+Call Run from a backend test:
+
+	func TestConformance(t *testing.T) {
+		conformance.Run(t, func(t *testing.T) frames.DataBackend {
+			return newBackend(t)
+		}, nil)
+	}
+
+Tests of operations or data types that are not in the backend capabilities
+are skipped. Filters and columns the backend doesn't push down (see
+frames.Pushdown) are applied in process the way the API does, backends without
+pushdown must either handle them or fail the read.
+*/
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/ops"
+	"github.com/v3io/frames/pb"
+)
+
+// Factory returns a new backend with empty storage
+type Factory func(t *testing.T) frames.DataBackend
+
+// Options are backend specific settings. Hooks can change requests before
+// they are sent to the backend (e.g. to add create attributes)
+type Options struct {
+	// Index is the name of the index column read back for frames written with
+	// an index named "key", "" if the backend doesn't store indices
+	Index string
+	// Labels are stored and read back
+	Labels bool
+	// Skip maps test names to skip to the reason (known deviations)
+	Skip map[string]string
+
+	Create func(*frames.CreateRequest)
+	Write  func(*frames.WriteRequest)
+	Read   func(*frames.ReadRequest)
+	Delete func(*frames.DeleteRequest)
+}
+
+const (
+	keyColumn = "key"
+	numRows   = 50
+)
+
+// suite is a conformance test run
+type suite struct {
+	factory  Factory
+	options  *Options
+	caps     *frames.Capabilities
+	pushdown *frames.Pushdown
+	dtypes   map[pb.DType]bool
+	ops      map[string]bool
+}
+
+// Run runs the conformance tests on backends from factory, options can be nil
+func Run(t *testing.T, factory Factory, options *Options) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	s := &suite{
+		factory: factory,
+		options: options,
+		dtypes:  make(map[pb.DType]bool),
+		ops:     make(map[string]bool),
+	}
+
+	s.caps = factory(t).Capabilities()
+	for _, dtype := range s.caps.Dtypes {
+		s.dtypes[dtype] = true
+	}
+
+	for _, op := range s.caps.Operations {
+		s.ops[op] = true
+	}
+
+	if pdBackend, ok := factory(t).(frames.PushdownBackend); ok {
+		s.pushdown = pdBackend.Pushdown()
+	}
+
+	tests := []struct {
+		name string
+		ops  []string
+		fn   func(*testing.T, frames.DataBackend)
+	}{
+		{"RoundTrip", []string{frames.WriteOperation, frames.ReadOperation}, s.testRoundTrip},
+		{"Indices", []string{frames.WriteOperation, frames.ReadOperation}, s.testIndices},
+		{"Labels", []string{frames.WriteOperation, frames.ReadOperation}, s.testLabels},
+		{"Limit", []string{frames.WriteOperation, frames.ReadOperation}, s.testLimit},
+		{"MessageLimit", []string{frames.WriteOperation, frames.ReadOperation}, s.testMessageLimit},
+		{"Columns", []string{frames.WriteOperation, frames.ReadOperation}, s.testColumns},
+		{"Filter", []string{frames.WriteOperation, frames.ReadOperation}, s.testFilter},
+		{"IfExists", []string{frames.CreateOperation}, s.testIfExists},
+		{"IfMissing", []string{frames.DeleteOperation}, s.testIfMissing},
+		{"Delete", []string{frames.WriteOperation, frames.DeleteOperation, frames.ReadOperation}, s.testDelete},
+		{"ConcurrentWriters", []string{frames.WriteOperation, frames.ReadOperation}, s.testConcurrentWriters},
+		{"ReadMissing", []string{frames.ReadOperation}, s.testReadMissing},
+		{"Cancel", []string{frames.WriteOperation, frames.ReadOperation}, s.testCancel},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if reason, ok := options.Skip[test.name]; ok {
+				t.Skip(reason)
+			}
+
+			for _, op := range test.ops {
+				if !s.ops[op] {
+					t.Skipf("backend doesn't support %s", op)
+				}
+			}
+
+			test.fn(t, factory(t))
+		})
+	}
+}
+
+// tableName returns a unique table name for test
+func tableName(t *testing.T) string {
+	name := strings.Replace(t.Name(), "/", "_", -1)
+	return fmt.Sprintf("%s_%d", strings.ToLower(name), time.Now().UnixNano())
+}
+
+// newFrame returns a frame with a string key column and a column for every
+// supported dtype. Row i has key "k%04d" and int column value i
+func (s *suite) newFrame(t *testing.T, start, size int, index bool, labels map[string]interface{}) frames.Frame {
+	keys := make([]string, size)
+	ints := make([]int64, size)
+	floats := make([]float64, size)
+	strs := make([]string, size)
+	times := make([]time.Time, size)
+	bools := make([]bool, size)
+	base := time.Date(2018, 11, 13, 0, 0, 0, 0, time.UTC)
+
+	for i := range keys {
+		n := start + i
+		keys[i] = fmt.Sprintf("k%04d", n)
+		ints[i] = int64(n)
+		floats[i] = float64(n) + 0.5
+		strs[i] = fmt.Sprintf("s%d", n)
+		times[i] = base.Add(time.Duration(n) * time.Minute)
+		bools[i] = n%2 == 0
+	}
+
+	data := []struct {
+		name  string
+		dtype pb.DType
+		data  interface{}
+	}{
+		{"i", pb.DType_INTEGER, ints},
+		{"f", pb.DType_FLOAT, floats},
+		{"s", pb.DType_STRING, strs},
+		{"t", pb.DType_TIME, times},
+		{"b", pb.DType_BOOLEAN, bools},
+	}
+
+	keyCol, err := frames.NewSliceColumn(keyColumn, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var columns, indices []frames.Column
+	if index {
+		indices = append(indices, keyCol)
+	} else {
+		columns = append(columns, keyCol)
+	}
+
+	for _, d := range data {
+		if !s.dtypes[d.dtype] {
+			continue
+		}
+
+		col, err := frames.NewSliceColumn(d.name, d.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, col)
+	}
+
+	frame, err := frames.NewFrame(columns, indices, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return frame
+}
+
+// schema returns the table schema of frame
+func schema(frame frames.Frame) *frames.TableSchema {
+	dtypeNames := map[frames.DType]string{
+		frames.IntType:    "long",
+		frames.FloatType:  "double",
+		frames.StringType: "string",
+		frames.TimeType:   "timestamp",
+		frames.BoolType:   "boolean",
+	}
+
+	schema := &frames.TableSchema{}
+	columns := append([]frames.Column{}, frame.Indices()...)
+	for _, name := range frame.Names() {
+		col, _ := frame.Column(name)
+		columns = append(columns, col)
+	}
+
+	for _, col := range columns {
+		schema.Fields = append(schema.Fields, &frames.SchemaField{
+			Name: col.Name(),
+			Type: dtypeNames[col.DType()],
+		})
+	}
+
+	return schema
+}
+
+func (s *suite) create(ctx context.Context, backend frames.DataBackend, request *frames.CreateRequest) error {
+	if s.options.Create != nil {
+		s.options.Create(request)
+	}
+	return backend.Create(ctx, request)
+}
+
+func (s *suite) delete(ctx context.Context, backend frames.DataBackend, request *frames.DeleteRequest) error {
+	if s.options.Delete != nil {
+		s.options.Delete(request)
+	}
+	return backend.Delete(ctx, request)
+}
+
+// write writes frames to table, it doesn't use t so it can be called from
+// several goroutines
+func (s *suite) write(backend frames.DataBackend, table string, frs ...frames.Frame) error {
+	request := &frames.WriteRequest{Table: table}
+	if s.options.Write != nil {
+		s.options.Write(request)
+	}
+
+	appender, err := backend.Write(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	for _, frame := range frs {
+		if err := appender.Add(frame); err != nil {
+			return err
+		}
+	}
+
+	return appender.WaitForComplete(10 * time.Second)
+}
+
+func (s *suite) mustWrite(t *testing.T, backend frames.DataBackend, table string, frs ...frames.Frame) {
+	if err := s.write(backend, table, frs...); err != nil {
+		t.Fatalf("can't write %q - %s", table, err)
+	}
+}
+
+// read returns all the frames from a read request
+func (s *suite) read(ctx context.Context, backend frames.DataBackend, request *frames.ReadRequest) ([]frames.Frame, error) {
+	if s.options.Read != nil {
+		s.options.Read(request)
+	}
+
+	it, err := backend.Read(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var frs []frames.Frame
+	for it.Next() {
+		frs = append(frs, it.At())
+	}
+
+	return frs, it.Err()
+}
+
+func (s *suite) mustRead(t *testing.T, backend frames.DataBackend, request *frames.ReadRequest) []frames.Frame {
+	frs, err := s.read(context.Background(), backend, request)
+	if err != nil {
+		t.Fatalf("can't read %q - %s", request.Table, err)
+	}
+
+	return frs
+}
+
+// rows returns the rows in frames by key (column or index named key)
+func rows(t *testing.T, frs []frames.Frame, key string) map[string]map[string]interface{} {
+	out := make(map[string]map[string]interface{})
+	for _, frame := range frs {
+		it := frame.IterRows(true)
+		for it.Next() {
+			row := it.Row()
+			kval, ok := row[key].(string)
+			if !ok {
+				t.Fatalf("no string %q in row %v", key, row)
+			}
+
+			if _, ok := out[kval]; ok {
+				t.Fatalf("duplicate key %q", kval)
+			}
+			out[kval] = row
+		}
+
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return out
+}
+
+// numRowsIn returns the total number of rows in frames
+func numRowsIn(frs []frames.Frame) int {
+	n := 0
+	for _, frame := range frs {
+		n += frame.Len()
+	}
+	return n
+}
+
+// checkRows checks that frs have the rows of expected, columns not in
+// expected are ignored
+func checkRows(t *testing.T, expected frames.Frame, frs []frames.Frame, key string) {
+	actual := rows(t, frs, key)
+	want := rows(t, []frames.Frame{expected}, keyColumn)
+	if len(actual) != len(want) {
+		t.Fatalf("wrong number of rows: %d != %d", len(actual), len(want))
+	}
+
+	for kval, wrow := range want {
+		row, ok := actual[kval]
+		if !ok {
+			t.Fatalf("missing row %q", kval)
+		}
+
+		for name, wval := range wrow {
+			if name == keyColumn {
+				continue
+			}
+
+			val, ok := row[name]
+			if !ok {
+				t.Fatalf("row %q: missing column %q", kval, name)
+			}
+
+			if !equal(wval, val) {
+				t.Fatalf("row %q, column %q: %v (%T) != %v (%T)", kval, name, val, val, wval, wval)
+			}
+		}
+	}
+
+	// Column dtypes
+	for _, frame := range frs {
+		for _, name := range expected.Names() {
+			col, err := frame.Column(name)
+			if err != nil {
+				continue // Might be the key
+			}
+
+			wcol, _ := expected.Column(name)
+			if col.DType() != wcol.DType() {
+				t.Fatalf("column %q: wrong dtype %v != %v", name, col.DType(), wcol.DType())
+			}
+		}
+	}
+}
+
+func equal(expected, actual interface{}) bool {
+	if etime, ok := expected.(time.Time); ok {
+		atime, ok := actual.(time.Time)
+		return ok && atime.Equal(etime)
+	}
+
+	return expected == actual
+}
+
+func (s *suite) testRoundTrip(t *testing.T, backend frames.DataBackend) {
+	table := tableName(t)
+	frame := s.newFrame(t, 0, numRows, false, nil)
+	s.mustWrite(t, backend, table, frame)
+
+	frs := s.mustRead(t, backend, &frames.ReadRequest{Table: table})
+	checkRows(t, frame, frs, keyColumn)
+}
+
+func (s *suite) testIndices(t *testing.T, backend frames.DataBackend) {
+	if s.options.Index == "" {
+		t.Skip("backend doesn't store indices")
+	}
+
+	table := tableName(t)
+	frame := s.newFrame(t, 0, numRows, true, nil)
+	s.mustWrite(t, backend, table, frame)
+
+	frs := s.mustRead(t, backend, &frames.ReadRequest{Table: table})
+	for _, fr := range frs {
+		indices := fr.Indices()
+		if len(indices) != 1 || indices[0].Name() != s.options.Index {
+			t.Fatalf("bad indices: %v", indices)
+		}
+	}
+
+	checkRows(t, frame, frs, s.options.Index)
+}
+
+func (s *suite) testLabels(t *testing.T, backend frames.DataBackend) {
+	if !s.options.Labels {
+		t.Skip("backend doesn't store labels")
+	}
+
+	table := tableName(t)
+	labels := map[string]interface{}{"source": "conformance"}
+	frame := s.newFrame(t, 0, numRows, false, labels)
+	s.mustWrite(t, backend, table, frame)
+
+	frs := s.mustRead(t, backend, &frames.ReadRequest{Table: table})
+	if len(frs) == 0 {
+		t.Fatal("no frames")
+	}
+
+	for _, fr := range frs {
+		if val := fr.Labels()["source"]; val != "conformance" {
+			t.Fatalf("bad labels: %v", fr.Labels())
+		}
+	}
+}
+
+func (s *suite) testLimit(t *testing.T, backend frames.DataBackend) {
+	table := tableName(t)
+	s.mustWrite(t, backend, table, s.newFrame(t, 0, numRows, false, nil))
+
+	limit := numRows / 3
+	frs := s.mustRead(t, backend, &frames.ReadRequest{Table: table, Limit: int64(limit)})
+	if n := numRowsIn(frs); n != limit {
+		t.Fatalf("wrong number of rows: %d != %d", n, limit)
+	}
+
+	// Limit with a message limit that doesn't divide it
+	frs = s.mustRead(t, backend, &frames.ReadRequest{Table: table, Limit: int64(limit), MessageLimit: 7})
+	if n := numRowsIn(frs); n != limit {
+		t.Fatalf("wrong number of rows with message limit: %d != %d", n, limit)
+	}
+}
+
+func (s *suite) testMessageLimit(t *testing.T, backend frames.DataBackend) {
+	table := tableName(t)
+	frame := s.newFrame(t, 0, numRows, false, nil)
+	s.mustWrite(t, backend, table, frame)
+
+	messageLimit := 7
+	frs := s.mustRead(t, backend, &frames.ReadRequest{Table: table, MessageLimit: int64(messageLimit)})
+	for i, fr := range frs {
+		if fr.Len() == 0 || fr.Len() > messageLimit {
+			t.Fatalf("frame %d: bad size %d (message limit %d)", i, fr.Len(), messageLimit)
+		}
+	}
+
+	checkRows(t, frame, frs, keyColumn)
+}
+
+func (s *suite) testColumns(t *testing.T, backend frames.DataBackend) {
+	table := tableName(t)
+	s.mustWrite(t, backend, table, s.newFrame(t, 0, numRows, false, nil))
+
+	columns := []string{keyColumn, "i"}
+	request := &frames.ReadRequest{Table: table, Columns: columns}
+	frs, err := s.read(context.Background(), backend, request)
+	switch {
+	case s.pushdown == nil && err != nil:
+		// Backends that don't select columns may reject them
+		t.Logf("columns rejected - %s", err)
+		return
+	case err != nil:
+		t.Fatalf("can't read %q - %s", table, err)
+	case s.pushdown != nil && !s.pushdown.Columns:
+		// The API selects the columns
+		if frs, err = selectColumns(frs, columns); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if numRowsIn(frs) != numRows {
+		t.Fatalf("wrong number of rows: %d != %d", numRowsIn(frs), numRows)
+	}
+
+	for _, fr := range frs {
+		names := fr.Names()
+		for _, idx := range fr.Indices() {
+			names = append(names, idx.Name())
+		}
+		sort.Strings(names)
+
+		if len(names) != len(columns) || names[0] != "i" || names[1] != keyColumn {
+			t.Fatalf("bad columns: %v", names)
+		}
+	}
+}
+
+func (s *suite) testFilter(t *testing.T, backend frames.DataBackend) {
+	if !s.dtypes[pb.DType_INTEGER] {
+		t.Skip("no integer columns")
+	}
+
+	operators := make(map[string]bool)
+	opNames := make(map[string]string)
+	if s.pushdown != nil {
+		for _, op := range s.pushdown.FilterOperators {
+			operators[strings.ToLower(op)] = true
+		}
+		opNames = s.pushdown.OperatorNames
+	}
+
+	// The API evaluates filters that aren't pushed down (and hints)
+	inProcess := s.pushdown != nil && (!operators[">"] || !operators["<="] || !operators["and"])
+
+	opName := func(op string) string {
+		if name, ok := opNames[op]; ok && !inProcess {
+			return name
+		}
+		return op
+	}
+
+	filter := fmt.Sprintf("i %s 10 %s i %s 20", opName(">"), opName("and"), opName("<="))
+	low, high := 11, 20
+
+	table := tableName(t)
+	s.mustWrite(t, backend, table, s.newFrame(t, 0, numRows, false, nil))
+
+	frs, err := s.read(context.Background(), backend, &frames.ReadRequest{Table: table, Filter: filter})
+	switch {
+	case s.pushdown == nil && err != nil:
+		// Backends that don't filter may reject filters
+		t.Logf("filter rejected - %s", err)
+		return
+	case err != nil:
+		t.Fatalf("can't read %q - %s", table, err)
+	case inProcess:
+		if numRowsIn(frs) < high-low+1 {
+			t.Fatalf("filter hint dropped rows: %d < %d", numRowsIn(frs), high-low+1)
+		}
+
+		if frs, err = filterFrames(frs, filter); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkRows(t, s.newFrame(t, low, high-low+1, false, nil), frs, keyColumn)
+}
+
+// selectColumns selects columns from frs the way the API does for backends
+// that don't select columns
+func selectColumns(frs []frames.Frame, columns []string) ([]frames.Frame, error) {
+	var out []frames.Frame
+	for _, frame := range frs {
+		frame, err := ops.Select(frame, columns...)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, frame)
+	}
+
+	return out, nil
+}
+
+// filterFrames filters frs the way the API does for filters that aren't pushed
+// down
+func filterFrames(frs []frames.Frame, text string) ([]frames.Frame, error) {
+	filter, err := utils.ParseFilter(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []frames.Frame
+	for _, frame := range frs {
+		frame, err := ops.Filter(frame, filter.Predicate())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, frame)
+	}
+
+	return out, nil
+}
+
+func (s *suite) testIfExists(t *testing.T, backend frames.DataBackend) {
+	ctx := context.Background()
+	table := tableName(t)
+	tableSchema := schema(s.newFrame(t, 0, 1, false, nil))
+
+	if err := s.create(ctx, backend, &frames.CreateRequest{Table: table, Schema: tableSchema}); err != nil {
+		t.Fatalf("can't create - %s", err)
+	}
+
+	request := &frames.CreateRequest{Table: table, Schema: tableSchema}
+	if err := s.create(ctx, backend, request); err == nil {
+		t.Fatal("no error on create of existing table")
+	}
+
+	request = &frames.CreateRequest{Table: table, Schema: tableSchema, IfExists: frames.IgnoreError}
+	if err := s.create(ctx, backend, request); err != nil {
+		t.Fatalf("error on create of existing table with IfExists=IGNORE - %s", err)
+	}
+}
+
+func (s *suite) testIfMissing(t *testing.T, backend frames.DataBackend) {
+	ctx := context.Background()
+	table := tableName(t)
+
+	if err := s.delete(ctx, backend, &frames.DeleteRequest{Table: table}); err == nil {
+		t.Fatal("no error on delete of missing table")
+	}
+
+	request := &frames.DeleteRequest{Table: table, IfMissing: frames.IgnoreError}
+	if err := s.delete(ctx, backend, request); err != nil {
+		t.Fatalf("error on delete of missing table with IfMissing=IGNORE - %s", err)
+	}
+}
+
+func (s *suite) testDelete(t *testing.T, backend frames.DataBackend) {
+	table := tableName(t)
+	s.mustWrite(t, backend, table, s.newFrame(t, 0, numRows, false, nil))
+
+	if err := s.delete(context.Background(), backend, &frames.DeleteRequest{Table: table}); err != nil {
+		t.Fatalf("can't delete - %s", err)
+	}
+
+	frs, err := s.read(context.Background(), backend, &frames.ReadRequest{Table: table})
+	if err == nil && numRowsIn(frs) > 0 {
+		t.Fatalf("read %d rows from deleted table", numRowsIn(frs))
+	}
+}
+
+func (s *suite) testConcurrentWriters(t *testing.T, backend frames.DataBackend) {
+	numWriters := 4
+	tables := make([]string, numWriters)
+	expected := make([]frames.Frame, numWriters)
+	errs := make([]error, numWriters)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numWriters; i++ {
+		tables[i] = fmt.Sprintf("%s_%d", tableName(t), i)
+		expected[i] = s.newFrame(t, i*numRows, numRows, false, nil)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = s.write(backend, tables[i], expected[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, table := range tables {
+		if errs[i] != nil {
+			t.Fatalf("writer %d - %s", i, errs[i])
+		}
+
+		frs := s.mustRead(t, backend, &frames.ReadRequest{Table: table})
+		checkRows(t, expected[i], frs, keyColumn)
+	}
+}
+
+func (s *suite) testReadMissing(t *testing.T, backend frames.DataBackend) {
+	_, err := s.read(context.Background(), backend, &frames.ReadRequest{Table: tableName(t)})
+	if err == nil {
+		t.Fatal("no error reading missing table")
+	}
+}
+
+// testCancel checks that the iterator stops and returns the context error
+// once the context is canceled
+func (s *suite) testCancel(t *testing.T, backend frames.DataBackend) {
+	table := tableName(t)
+	s.mustWrite(t, backend, table, s.newFrame(t, 0, numRows, false, nil))
+
+	request := &frames.ReadRequest{Table: table, MessageLimit: 5}
+	if s.options.Read != nil {
+		s.options.Read(request)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	it, err := backend.Read(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !it.Next() {
+		t.Fatalf("no frames - %v", it.Err())
+	}
+	cancel()
+
+	n := it.At().Len()
+	for it.Next() {
+		n += it.At().Len()
+	}
+
+	if n == numRows {
+		t.Skip("all rows read before cancel")
+	}
+
+	if err := it.Err(); err != context.Canceled {
+		t.Fatalf("bad error after cancel: %v", err)
+	}
+}
@@ -21,10 +21,15 @@ such restriction.
 package csv
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -35,176 +40,803 @@ import (
 	"github.com/v3io/frames"
 	"github.com/v3io/frames/backends"
 	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/ops"
 )
 
+// Default number of rows used to infer column types
+const defaultSampleRows = 100
+
+// Default null value, distinct from empty strings
+const defaultNullValue = `\N`
+
 // Backend is CSV backend
 type Backend struct {
 	rootDir string
 	logger  logger.Logger
+	workers int
+
+	delimiter   rune
+	lazyQuotes  bool
+	header      bool
+	timeFormats []string
+	nullValues  []string
+	sampleRows  int
+
+	compressionType  string
+	compressionLevel int
 }
 
-// NewBackend returns a new CSV backend
+// NewBackend returns a new CSV backend. Options (in config.Options) are:
+//
+//	delimiter: field delimiter (default ",")
+//	lazyQuotes: allow quotes in unquoted fields and non-doubled quotes in
+//		quoted fields (default false)
+//	header: files start with a header line (default true), column names of
+//		header-less files are taken from the table schema or are col0, col1 ...
+//	timeFormats: layouts (see time.Parse) of time values (default RFC3339 and
+//		2006-01-02)
+//	nullValues: values read as nulls, the first is used when writing nulls
+//		(default \N). Empty values are always nulls in non-string columns, in
+//		string columns they are empty strings unless "" is in nullValues
+//	sampleRows: number of rows used to infer types of columns not in the table
+//		schema (default 100)
+//	compression: compression of table files - "auto" (by file extension, e.g.
+//		.csv.gz or .csv.zst), "none", "gzip" or "zstd" (default "auto")
+//	compressionLevel: compression level of written files, -1 (default) to 9
+//
+// Tables are files or partitioned directories (see partition.go), partitions
+// are read concurrently by config.Workers workers
 func NewBackend(logger logger.Logger, config *frames.BackendConfig, framesConfig *frames.Config) (frames.DataBackend, error) {
 	backend := &Backend{
 		rootDir: config.RootDir,
 		logger:  logger.GetChild("csv"),
+		workers: config.Workers,
+	}
+
+	if err := backend.parseOptions(config.Options); err != nil {
+		return nil, errors.Wrap(err, "bad CSV backend options")
 	}
 
 	return backend, nil
 }
 
-// Create will create a table
-func (b *Backend) Create(request *frames.CreateRequest) error {
+func (b *Backend) parseOptions(options map[string]interface{}) error {
+	delimiter, err := utils.StringOption(options, "delimiter", ",")
+	if err != nil {
+		return err
+	}
+
+	runes := []rune(delimiter)
+	if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' {
+		return fmt.Errorf("bad delimiter - %q", delimiter)
+	}
+	b.delimiter = runes[0]
+
+	if b.lazyQuotes, err = utils.BoolOption(options, "lazyQuotes", false); err != nil {
+		return err
+	}
+
+	if b.header, err = utils.BoolOption(options, "header", true); err != nil {
+		return err
+	}
+
+	defaultFormats := []string{time.RFC3339, time.RFC3339Nano, "2006-01-02"}
+	if b.timeFormats, err = utils.StringsOption(options, "timeFormats", defaultFormats); err != nil {
+		return err
+	}
+
+	if len(b.timeFormats) == 0 {
+		return fmt.Errorf("empty timeFormats")
+	}
+
+	if b.nullValues, err = utils.StringsOption(options, "nullValues", []string{defaultNullValue}); err != nil {
+		return err
+	}
+
+	if len(b.nullValues) == 0 {
+		return fmt.Errorf("empty nullValues")
+	}
+
+	if b.sampleRows, err = utils.IntOption(options, "sampleRows", defaultSampleRows); err != nil {
+		return err
+	}
+
+	if b.sampleRows < 1 {
+		return fmt.Errorf("bad sampleRows - %d", b.sampleRows)
+	}
+
+	if b.compressionType, err = utils.StringOption(options, "compression", autoCompression); err != nil {
+		return err
+	}
+
+	if err := checkCompression(b.compressionType); err != nil {
+		return err
+	}
+
+	if b.compressionLevel, err = utils.IntOption(options, "compressionLevel", gzip.DefaultCompression); err != nil {
+		return err
+	}
+
+	if b.compressionLevel < gzip.DefaultCompression || b.compressionLevel > gzip.BestCompression {
+		return fmt.Errorf("bad compressionLevel - %d", b.compressionLevel)
+	}
+
+	return nil
+}
+
+// Create will create a table, field types in the schema are saved in the table
+// schema file. Fields with a "partition" property set to true are partition
+// keys, tables with partition keys are directories
+func (b *Backend) Create(ctx context.Context, request *frames.CreateRequest) error {
 	csvPath := b.csvPath(request.Table)
-	// TODO: Overwrite?
 	if fileExists(csvPath) {
+		if request.IfExists == frames.IgnoreError {
+			return nil
+		}
 		return fmt.Errorf("table %q already exists", request.Table)
 	}
 
+	var fields []*frames.SchemaField
+	if request.Schema != nil {
+		fields = request.Schema.Fields
+	}
+
+	names := make([]string, len(fields))
+	dtypes := make(map[string]frames.DType)
+	var keys []string
+	for i, field := range fields {
+		if field.Name == "" {
+			return fmt.Errorf("field %d with no name", i)
+		}
+
+		names[i] = field.Name
+		if isPartitionField(field) {
+			keys = append(keys, field.Name)
+		}
+
+		if field.Type == "" {
+			continue
+		}
+
+		dtype, err := utils.SchemaDType(field.Type)
+		if err != nil {
+			return errors.Wrapf(err, "field %q", field.Name)
+		}
+		dtypes[field.Name] = dtype
+	}
+
+	if len(keys) > 0 {
+		return b.createDataset(request.Table, names, dtypes, keys)
+	}
+
 	file, err := os.Create(csvPath)
 	if err != nil {
 		return errors.Wrapf(err, "can't create table file")
 	}
 
 	defer file.Close()
-	if request.Schema == nil || len(request.Schema.Fields) == 0 {
+	if len(names) == 0 {
 		return nil
 	}
 
-	numFields := len(request.Schema.Fields)
-	names := make([]string, numFields)
-	for i, field := range request.Schema.Fields {
-		if field.Name == "" {
-			return fmt.Errorf("field %d with no name", i)
+	if b.header {
+		out, err := b.newCompressor(file, b.compression(csvPath))
+		if err != nil {
+			return err
 		}
 
-		names[i] = field.Name
-	}
+		csvWriter := b.newWriter(out)
+		if err := csvWriter.Write(names); err != nil {
+			return errors.Wrapf(err, "can't create header")
+		}
+
+		csvWriter.Flush()
+		if err := out.Close(); err != nil {
+			return errors.Wrap(err, "can't flush csv file")
+		}
 
-	csvWriter := csv.NewWriter(file)
-	if err := csvWriter.Write(names); err != nil {
-		return errors.Wrapf(err, "can't create header")
+		if err := file.Sync(); err != nil {
+			return errors.Wrap(err, "can't flush csv file")
+		}
 	}
 
-	csvWriter.Flush()
-	if err := file.Sync(); err != nil {
-		return errors.Wrap(err, "can't flush csv file")
+	if len(dtypes) > 0 || !b.header {
+		if err := b.writeSchema(request.Table, names, dtypes, nil); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// Delete will delete a table
-func (b *Backend) Delete(request *frames.DeleteRequest) error {
+// Delete will delete a table, with a filter only the matching rows are
+// deleted by rewriting the table
+func (b *Backend) Delete(ctx context.Context, request *frames.DeleteRequest) error {
 	csvPath := b.csvPath(request.Table)
-	if request.IfMissing == frames.FailOnError && !fileExists(csvPath) {
+	if !fileExists(csvPath) {
+		if request.IfMissing == frames.IgnoreError {
+			return nil
+		}
 		return fmt.Errorf("table %q doesn't exist", request.Table)
 	}
 
-	if err := os.Remove(csvPath); err != nil {
+	isDataset := b.isDataset(request.Table)
+	if request.Filter != "" {
+		if isDataset {
+			return b.deleteDatasetRows(ctx, request.Table, request.Filter)
+		}
+		return b.deleteRows(ctx, request.Table, request.Filter)
+	}
+
+	remove := os.Remove
+	if isDataset {
+		remove = os.RemoveAll
+	}
+
+	if err := remove(csvPath); err != nil {
 		return errors.Wrapf(err, "can't delete %q", request.Table)
 	}
 
+	if err := os.Remove(b.schemaPath(request.Table)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "can't delete %q schema", request.Table)
+	}
+
 	return nil
 }
 
-// Read handles reading
-func (b *Backend) Read(request *frames.ReadRequest) (frames.FrameIterator, error) {
-	file, err := os.Open(b.csvPath(request.Table))
-	if err != nil {
-		return nil, err
+// Read handles reading, column types are taken from the request schema, the
+// table schema file or are inferred from the first rows
+func (b *Backend) Read(ctx context.Context, request *frames.ReadRequest) (frames.FrameIterator, error) {
+	var filter *utils.Filter
+	if request.Filter != "" {
+		var err error
+		if filter, err = utils.ParseFilter(request.Filter); err != nil {
+			return nil, err
+		}
 	}
 
-	reader := csv.NewReader(file)
-	columns, err := reader.Read()
+	if b.isDataset(request.Table) {
+		return b.readDataset(ctx, request, filter)
+	}
+
+	it, err := b.newIterator(ctx, request.Table, request.Schema)
 	if err != nil {
-		return nil, errors.Wrap(err, "can't read header (columns)")
+		return nil, err
 	}
 
-	it := &FrameIterator{
-		logger:      b.logger,
-		path:        request.Table,
-		reader:      reader,
-		columnNames: columns,
-		limit:       int(request.Limit),
-		frameLimit:  int(request.MessageLimit),
+	if err := it.selectColumns(request.Columns, filter); err != nil {
+		it.close()
+		return nil, err
 	}
 
+	it.limit = int(request.Limit)
+	it.frameLimit = int(request.MessageLimit)
 	return it, nil
 }
 
-// Write handles writing
-func (b *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, error) {
-	// TODO: Append?
-	file, err := os.Create(b.csvPath(request.Table))
-	if err != nil {
-		return nil, err
-	}
-
-	ca := &csvAppender{
-		writer:    file,
-		csvWriter: csv.NewWriter(file),
-		logger:    b.logger,
+// Write handles writing, in overwrite mode the frames are written to a
+// temporary file (or directory) which replaces the table in WaitForComplete
+func (b *Backend) Write(ctx context.Context, request *frames.WriteRequest) (frames.FrameAppender, error) {
+	var appender frames.FrameAppender
+	if b.isDataset(request.Table) {
+		pa, err := b.newPartitionAppender(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		appender = pa
+	} else {
+		ca, err := b.newAppender(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		appender = ca
 	}
 
 	if request.ImmidiateData != nil {
-		if err := ca.Add(request.ImmidiateData); err != nil {
+		if err := appender.Add(request.ImmidiateData); err != nil {
 			return nil, errors.Wrap(err, "can't Add ImmidiateData")
 		}
 	}
 
-	return ca, nil
+	return appender, nil
+}
 
+func (b *Backend) newAppender(ctx context.Context, request *frames.WriteRequest) (*csvAppender, error) {
+	ca := &csvAppender{
+		ctx:     ctx,
+		logger:  b.logger,
+		backend: b,
+		table:   request.Table,
+		path:    b.csvPath(request.Table),
+		mode:    request.SaveMode,
+	}
+
+	var err error
+	switch request.SaveMode {
+	case frames.OverwriteMode:
+		err = ca.createTable()
+	case frames.AppendMode, frames.AppendNewColumnsMode:
+		err = ca.open()
+	default:
+		err = fmt.Errorf("unknown save mode - %s", request.SaveMode)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ca, nil
 }
 
 // Exec executes a command
-func (b *Backend) Exec(request *frames.ExecRequest) error {
+func (b *Backend) Exec(ctx context.Context, request *frames.ExecRequest) (frames.Frame, error) {
 	if strings.ToLower(request.Command) == "ping" {
 		b.logger.Info("PONG")
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("CSV backend does not support %q exec command", request.Command)
+}
+
+// List lists the tables (files and partitioned directories) in root directory
+func (b *Backend) List(ctx context.Context, request *frames.ListRequest) ([]string, error) {
+	dir := filepath.Join(b.rootDir, request.Path)
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't list %q", request.Path)
+	}
+
+	var tables []string
+	for _, info := range infos {
+		if strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+
+		table := path.Join(request.Path, info.Name())
+		if !info.Mode().IsRegular() && !(info.IsDir() && b.isPartitioned(table)) {
+			continue
+		}
+
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+// Describe returns the table schema, column types are taken from the table
+// schema file or are inferred from the first rows
+func (b *Backend) Describe(ctx context.Context, request *frames.DescribeRequest) (*frames.TableInfo, error) {
+	if b.isDataset(request.Table) {
+		schema, err := b.describeDataset(ctx, request.Table)
+		if err != nil {
+			return nil, err
+		}
+
+		return &frames.TableInfo{Name: request.Table, Schema: schema}, nil
+	}
+
+	it, err := b.newIterator(ctx, request.Table, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer it.close()
+	schema := &frames.TableSchema{}
+	for i, name := range it.columnNames {
+		field := &frames.SchemaField{Name: name, Type: utils.DTypeName(it.dtypes[i])}
+		schema.Fields = append(schema.Fields, field)
+	}
+
+	return &frames.TableInfo{Name: request.Table, Schema: schema}, nil
+}
+
+// Capabilities returns the backend capabilities
+func (b *Backend) Capabilities() *frames.Capabilities {
+	return &frames.Capabilities{
+		Operations: []string{
+			frames.ReadOperation, frames.WriteOperation, frames.CreateOperation,
+			frames.DeleteOperation, frames.ExecOperation, frames.ListOperation,
+			frames.DescribeOperation,
+		},
+		ExecCommands: []*frames.ExecCommandInfo{
+			{Name: "ping", Doc: "check the backend is alive"},
+		},
+		FilterDialect:   "sql",
+		FilterOperators: b.Pushdown().FilterOperators,
+		Dtypes:          frames.AllDTypes(),
+	}
+}
+
+// Pushdown returns the read request parts handled by the backend, columns and
+// filters in the KV filter syntax
+func (b *Backend) Pushdown() *frames.Pushdown {
+	return &frames.Pushdown{
+		Columns:         true,
+		FilterOperators: utils.FilterOperators,
+	}
+}
+
+// deleteRows rewrites the table without the rows matching filter, row text is
+// copied as is
+func (b *Backend) deleteRows(ctx context.Context, table string, filterText string) error {
+	filter, err := utils.ParseFilter(filterText)
+	if err != nil {
+		return err
+	}
+
+	it, err := b.newIterator(ctx, table, nil)
+	if err != nil {
+		return err
+	}
+	defer it.close()
+	if len(it.columnNames) == 0 {
+		// Empty table
 		return nil
 	}
 
-	return fmt.Errorf("CSV backend does not support %q exec command", request.Command)
+	if err := it.selectColumns(filter.Columns(), nil); err != nil {
+		return err
+	}
+
+	ca := &csvAppender{
+		ctx:     ctx,
+		logger:  b.logger,
+		backend: b,
+		table:   table,
+		path:    b.csvPath(table),
+	}
+
+	if err := ca.create(); err != nil {
+		return err
+	}
+
+	if err := ca.deleteRows(it, filter); err != nil {
+		ca.abort()
+		return err
+	}
+
+	return ca.WaitForComplete(0)
 }
 
 func (b *Backend) csvPath(table string) string {
 	return fmt.Sprintf("%s/%s", b.rootDir, table)
 }
 
+func (b *Backend) newReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = b.delimiter
+	reader.LazyQuotes = b.lazyQuotes
+	return reader
+}
+
+func (b *Backend) newWriter(w io.Writer) *csv.Writer {
+	writer := csv.NewWriter(w)
+	writer.Comma = b.delimiter
+	return writer
+}
+
+// isNull returns true if value is a null in a column of type dtype
+func (b *Backend) isNull(value string, dtype frames.DType) bool {
+	if value == "" && dtype != frames.StringType {
+		return true
+	}
+
+	for _, null := range b.nullValues {
+		if value == null {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newIterator opens table for reading, it reads the header and samples rows
+// for column types not in schema
+func (b *Backend) newIterator(ctx context.Context, table string, schema *frames.TableSchema) (*FrameIterator, error) {
+	if schema == nil || len(schema.Fields) == 0 {
+		var err error
+		if schema, err = b.readSchema(table); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.newFileIterator(ctx, b.csvPath(table), table, schema)
+}
+
+// newFileIterator opens the CSV file in filePath for reading, name is used in
+// error messages
+func (b *Backend) newFileIterator(ctx context.Context, filePath string, name string, schema *frames.TableSchema) (*FrameIterator, error) {
+	file, err := b.openReader(filePath, b.compression(filePath))
+	if err != nil {
+		return nil, err
+	}
+
+	it := &FrameIterator{
+		ctx:     ctx,
+		logger:  b.logger,
+		backend: b,
+		path:    name,
+		file:    file,
+		reader:  b.newReader(file),
+	}
+
+	if err := it.init(schema); err != nil {
+		it.close()
+		return nil, err
+	}
+
+	return it, nil
+}
+
 // FrameIterator iterates over CSV
 type FrameIterator struct {
+	ctx         context.Context
 	logger      logger.Logger
+	backend     *Backend
 	path        string
+	file        io.ReadCloser // decompressed file
 	reader      *csv.Reader
 	frame       frames.Frame
 	err         error
 	columnNames []string
-	nRows       int
+	dtypes      []frames.DType
+	sample      [][]string // rows read for type inference
+	nRows       int        // rows read
+	nOut        int        // rows returned
 	limit       int
 	frameLimit  int
+	filter      *utils.Filter
+	needed      []int    // indices of columns to build, nil for all
+	columns     []string // returned columns, nil for all
+	extra       []extraColumn
+}
+
+// extraColumn is a column that's not in the file, it's added after the file
+// columns. Partition keys are label columns and table columns missing from the
+// file are nulls
+type extraColumn struct {
+	name  string
+	dtype frames.DType
+	value interface{} // nil for nulls
+}
+
+// init sets the column names and types, schema can be nil
+func (it *FrameIterator) init(schema *frames.TableSchema) error {
+	dtypes := make(map[string]frames.DType)
+	var fieldNames []string
+	if schema != nil {
+		for _, field := range schema.Fields {
+			fieldNames = append(fieldNames, field.Name)
+			if field.Type == "" {
+				continue
+			}
+
+			dtype, err := utils.SchemaDType(field.Type)
+			if err != nil {
+				return errors.Wrapf(err, "schema field %q", field.Name)
+			}
+			dtypes[field.Name] = dtype
+		}
+	}
+
+	switch {
+	case it.backend.header:
+		names, err := it.reader.Read()
+		if err != nil && err != io.EOF { // io.EOF is an empty table
+			return errors.Wrap(err, "can't read header (columns)")
+		}
+		it.columnNames = names
+	case fieldNames != nil:
+		it.columnNames = fieldNames
+	default:
+		// Header-less file without schema, names are col0, col1 ...
+		if err := it.readSample(); err != nil {
+			return err
+		}
+
+		if len(it.sample) > 0 {
+			for i := range it.sample[0] {
+				it.columnNames = append(it.columnNames, fmt.Sprintf("col%d", i))
+			}
+		}
+	}
+
+	it.dtypes = make([]frames.DType, len(it.columnNames))
+	for c, name := range it.columnNames {
+		dtype, ok := dtypes[name]
+		if !ok {
+			if err := it.readSample(); err != nil {
+				return err
+			}
+			dtype = it.inferType(c)
+		}
+		it.dtypes[c] = dtype
+	}
+
+	return nil
+}
+
+// selectColumns sets the columns returned, nil for all, and the filter
+func (it *FrameIterator) selectColumns(columns []string, filter *utils.Filter) error {
+	it.filter = filter
+	var names []string
+	if len(columns) > 0 {
+		it.columns = columns
+		names = append(names, columns...)
+	}
+
+	if filter != nil {
+		// Filter columns are validated even if all columns are needed
+		names = append(names, filter.Columns()...)
+	}
+
+	indices := make(map[string]int)
+	for i, name := range it.columnNames {
+		indices[name] = i
+	}
+
+	isExtra := make(map[string]bool)
+	for _, col := range it.extra {
+		isExtra[col.name] = true
+	}
+
+	it.needed = []int{}
+	added := make(map[int]bool)
+	for _, name := range names {
+		i, ok := indices[name]
+		if !ok {
+			if isExtra[name] {
+				continue
+			}
+			return fmt.Errorf("column %q not found in %q", name, it.path)
+		}
+
+		if !added[i] {
+			it.needed = append(it.needed, i)
+			added[i] = true
+		}
+	}
+
+	if it.columns == nil {
+		it.needed = nil
+	}
+
+	return nil
+}
+
+// readSample reads the first rows for type inference (once)
+func (it *FrameIterator) readSample() error {
+	if it.sample != nil {
+		return nil
+	}
+
+	it.sample = [][]string{}
+	for len(it.sample) < it.backend.sampleRows {
+		row, err := it.reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "can't read rows")
+		}
+
+		it.sample = append(it.sample, row)
+	}
+
+	return nil
+}
+
+// inferType returns the type of column c in the sampled rows. Columns with
+// integer and float values are float columns, columns with other mixed types
+// or with only nulls are string columns
+func (it *FrameIterator) inferType(c int) frames.DType {
+	var dtype frames.DType
+	found := false
+	for _, row := range it.sample {
+		if c >= len(row) || row[c] == "" || it.backend.isNull(row[c], frames.StringType) {
+			continue
+		}
+
+		vtype := valueDType(it.backend.parseValue(row[c]))
+		if !found {
+			dtype, found = vtype, true
+			continue
+		}
+
+		dtype = widenDType(dtype, vtype)
+		if dtype == frames.StringType {
+			break
+		}
+	}
+
+	if !found {
+		return frames.StringType
+	}
+
+	return dtype
 }
 
 // Next reads the next frame, return true of succeeded
 func (it *FrameIterator) Next() bool {
-	rows, err := it.readNextRows()
-	if err != nil {
-		it.logger.ErrorWith("can't read rows", "error", err)
-		it.err = err
+	if it.file == nil {
 		return false
 	}
 
-	if len(rows) == 0 {
-		return false
+	for {
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			it.close()
+			return false
+		}
+
+		if it.limit > 0 && it.nOut >= it.limit {
+			it.close()
+			return false
+		}
+
+		rows, err := it.readNextRows()
+		if err != nil {
+			it.logger.ErrorWith("can't read rows", "error", err)
+			it.err = err
+			it.close()
+			return false
+		}
+
+		if len(rows) == 0 {
+			it.close()
+			return false
+		}
+
+		frame, err := it.buildFrame(rows)
+		if err == nil {
+			frame, err = it.applyRequest(frame)
+		}
+
+		if err != nil {
+			it.logger.ErrorWith("can't build frame", "error", err)
+			it.err = err
+			it.close()
+			return false
+		}
+
+		if frame.Len() == 0 {
+			continue
+		}
+
+		it.frame = frame
+		it.nOut += frame.Len()
+		return true
 	}
+}
 
-	it.frame, err = it.buildFrame(rows)
-	if err != nil {
-		it.logger.ErrorWith("can't build frame", "error", err)
-		it.err = err
-		return false
+// applyRequest applies the filter, limit and column selection on frame
+func (it *FrameIterator) applyRequest(frame frames.Frame) (frames.Frame, error) {
+	var err error
+	if it.filter != nil {
+		if frame, err = ops.Filter(frame, it.filter.Predicate()); err != nil {
+			return nil, err
+		}
 	}
 
-	return true
+	if it.limit > 0 && it.nOut+frame.Len() > it.limit {
+		if frame, err = ops.Slice(frame, 0, it.limit-it.nOut); err != nil {
+			return nil, err
+		}
+	}
+
+	if it.columns == nil || sameNames(it.columns, frame.Names()) {
+		return frame, nil
+	}
+
+	columns := make([]frames.Column, len(it.columns))
+	for i, name := range it.columns {
+		if columns[i], err = frame.Column(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return frames.NewFrame(columns, nil, nil)
 }
 
 // At return the current Frame
@@ -217,10 +849,21 @@ func (it *FrameIterator) Err() error {
 	return it.err
 }
 
+func (it *FrameIterator) close() {
+	if it.file == nil {
+		return
+	}
+
+	if err := it.file.Close(); err != nil {
+		it.logger.WarnWith("can't close file", "path", it.path, "error", err)
+	}
+	it.file = nil
+}
+
 func (it *FrameIterator) readNextRows() ([][]string, error) {
 	var rows [][]string
 	for r := 0; it.inLimits(r); r, it.nRows = r+1, it.nRows+1 {
-		row, err := it.reader.Read()
+		row, err := it.nextRow()
 		if err != nil {
 			if err == io.EOF {
 				it.logger.DebugWith("EOF", "numRows", it.nRows)
@@ -242,8 +885,20 @@ func (it *FrameIterator) readNextRows() ([][]string, error) {
 	return rows, nil
 }
 
+// nextRow returns the next sampled row or reads one
+func (it *FrameIterator) nextRow() ([]string, error) {
+	if len(it.sample) > 0 {
+		row := it.sample[0]
+		it.sample = it.sample[1:]
+		return row, nil
+	}
+
+	return it.reader.Read()
+}
+
 func (it *FrameIterator) inLimits(frameRow int) bool {
-	if it.limit > 0 && it.nRows >= it.limit {
+	// With a filter the limit is checked after filtering
+	if it.filter == nil && it.limit > 0 && it.nOut+frameRow >= it.limit {
 		return false
 	}
 
@@ -254,106 +909,87 @@ func (it *FrameIterator) inLimits(frameRow int) bool {
 	return true
 }
 
+// buildFrame builds a frame from the needed columns of rows
 func (it *FrameIterator) buildFrame(rows [][]string) (frames.Frame, error) {
-	columns := make([]frames.Column, len(it.columnNames))
-	for c, colName := range it.columnNames {
-		var (
-			val0 = it.parseValue(rows[0][c])
-			col  frames.Column
-			data interface{}
-			err  error
-		)
-
-		switch val0.(type) {
-		case int64:
-			typedData := make([]int64, len(rows))
-			typedData[0] = val0.(int64)
-			for r, row := range rows[1:] {
-				val, ok := it.parseValue(row[c]).(int64)
-				if !ok {
-					err := fmt.Errorf("type mismatch in row %d, col %d", it.nRows, c)
-					it.logger.ErrorWith("type mismatch", "error", err)
-					return nil, err
-				}
+	needed := it.needed
+	if needed == nil {
+		needed = make([]int, len(it.columnNames))
+		for c := range needed {
+			needed[c] = c
+		}
+	}
 
-				typedData[r+1] = val // +1 since we start in first row
-			}
-			data = typedData
-		case float64:
-			typedData := make([]float64, len(rows))
-			typedData[0] = val0.(float64)
-			for r, row := range rows[1:] {
-				val, ok := it.parseValue(row[c]).(float64)
-				if !ok {
-					err := fmt.Errorf("type mismatch in row %d, col %d", it.nRows, c)
-					it.logger.ErrorWith("type mismatch", "error", err)
-					return nil, err
-				}
+	columns := make([]frames.Column, len(needed))
+	for i, c := range needed {
+		colName := it.columnNames[c]
+		col, err := newColumn(colName, it.dtypes[c])
+		if err != nil {
+			it.logger.ErrorWith("can't build column", "error", err, "column", colName)
+			return nil, errors.Wrapf(err, "can't build column %s", colName)
+		}
 
-				typedData[r+1] = val // +1 since we start in first row
-			}
-			data = typedData
-		case string:
-			typedData := make([]string, len(rows))
-			typedData[0] = val0.(string)
-			for r, row := range rows[1:] {
-				typedData[r+1] = row[c] // +1 since we start in first row
-			}
-			data = typedData
-		case time.Time:
-			typedData := make([]time.Time, len(rows))
-			typedData[0] = val0.(time.Time)
-			for r, row := range rows[1:] {
-				val, ok := it.parseValue(row[c]).(time.Time)
-				if !ok {
-					err := fmt.Errorf("type mismatch in row %d, col %d", it.nRows, c)
-					it.logger.ErrorWith("type mismatch", "error", err)
-					return nil, err
+		for r, row := range rows {
+			if it.backend.isNull(row[c], it.dtypes[c]) {
+				if err := utils.AppendNil(col); err != nil {
+					return nil, errors.Wrapf(err, "%s:%d can't append null", colName, r)
 				}
+				continue
+			}
 
-				typedData[r+1] = val // +1 since we start in first row
+			val, err := it.backend.parseTyped(row[c], it.dtypes[c])
+			if err != nil {
+				err := fmt.Errorf("%s:%d can't parse %q in column %q as %s", it.path, it.nRows-len(rows)+r, row[c], colName, utils.DTypeName(it.dtypes[c]))
+				it.logger.ErrorWith("type mismatch", "error", err)
+				return nil, err
 			}
-			data = typedData
-		case bool:
-			typedData := make([]bool, len(rows))
-			typedData[0] = val0.(bool)
-			for r, row := range rows[1:] {
-				val, ok := it.parseValue(row[c]).(bool)
-				if !ok {
-					err := fmt.Errorf("type mismatch in row %d, col %d", it.nRows, c)
-					it.logger.ErrorWith("type mismatch", "error", err)
-					return nil, err
-				}
 
-				typedData[r+1] = val // +1 since we start in first row
+			if err := utils.AppendColumn(col, val); err != nil {
+				err := fmt.Errorf("type mismatch in row %d, col %d", it.nRows-len(rows)+r, c)
+				it.logger.ErrorWith("type mismatch", "error", err)
+				return nil, err
 			}
-			data = typedData
-		default:
-			return nil, fmt.Errorf("%s - unknown type %T", colName, val0)
 		}
 
-		col, err = frames.NewSliceColumn(colName, data)
+		columns[i] = col
+	}
+
+	for _, extra := range it.extra {
+		col, err := it.buildExtra(extra, len(rows))
 		if err != nil {
-			it.logger.ErrorWith("can't build column", "error", err, "column", colName)
-			return nil, errors.Wrapf(err, "can't build column %s", colName)
+			return nil, errors.Wrapf(err, "can't build column %s", extra.name)
 		}
-
-		columns[c] = col
+		columns = append(columns, col)
 	}
 
 	return frames.NewFrame(columns, nil, nil)
 }
 
-func (it *FrameIterator) parseValue(value string) interface{} {
-	// time/date formats
-	timeFormats := []string{time.RFC3339, time.RFC3339Nano, "2006-01-02"}
-	for _, format := range timeFormats {
-		t, err := time.Parse(format, value)
-		if err == nil {
-			return t
+func (it *FrameIterator) buildExtra(extra extraColumn, size int) (frames.Column, error) {
+	if extra.value != nil {
+		return frames.NewLabelColumn(extra.name, extra.value, size)
+	}
+
+	col, err := newColumn(extra.name, extra.dtype)
+	if err != nil {
+		return nil, err
+	}
+
+	for r := 0; r < size; r++ {
+		if err := utils.AppendNil(col); err != nil {
+			return nil, err
 		}
 	}
 
+	return col, nil
+}
+
+// parseValue parses value to the first matching type
+func (b *Backend) parseValue(value string) interface{} {
+	// time/date formats
+	if t, err := b.parseTime(value); err == nil {
+		return t
+	}
+
 	// bool
 	switch strings.ToLower(value) {
 	case "true":
@@ -363,9 +999,9 @@ func (it *FrameIterator) parseValue(value string) interface{} {
 	}
 
 	// int
-	i, err := strconv.Atoi(value)
+	i, err := strconv.ParseInt(value, 10, 64)
 	if err == nil {
-		return int64(i)
+		return i
 	}
 
 	// float
@@ -378,69 +1014,109 @@ func (it *FrameIterator) parseValue(value string) interface{} {
 	return value
 }
 
-type csvAppender struct {
-	logger        logger.Logger
-	writer        io.Writer
-	csvWriter     *csv.Writer
-	headerWritten bool
+// parseTyped parses value as dtype
+func (b *Backend) parseTyped(value string, dtype frames.DType) (interface{}, error) {
+	switch dtype {
+	case frames.IntType:
+		return strconv.ParseInt(value, 10, 64)
+	case frames.FloatType:
+		return strconv.ParseFloat(value, 64)
+	case frames.StringType:
+		return value, nil
+	case frames.TimeType:
+		return b.parseTime(value)
+	case frames.BoolType:
+		return strconv.ParseBool(value)
+	}
+
+	return nil, fmt.Errorf("unsupported data type - %d", dtype)
 }
 
-func (ca *csvAppender) Add(frame frames.Frame) error {
-	ca.logger.InfoWith("adding frame", "size", frame.Len())
-	names := frame.Names()
-	if !ca.headerWritten {
-		if err := ca.csvWriter.Write(names); err != nil {
-			ca.logger.ErrorWith("can't write header", "error", err)
-			return errors.Wrap(err, "can't write header")
+func (b *Backend) parseTime(value string) (time.Time, error) {
+	var err error
+	for _, format := range b.timeFormats {
+		var t time.Time
+		if t, err = time.Parse(format, value); err == nil {
+			return t, nil
 		}
-		ca.headerWritten = true
 	}
 
-	for r := 0; r < frame.Len(); r++ {
-		record := make([]string, len(names))
-		for c, name := range names {
-			col, err := frame.Column(name)
-			if err != nil {
-				ca.logger.ErrorWith("can't get column", "error", err)
-				return errors.Wrap(err, "can't get column")
-			}
+	return time.Time{}, err
+}
 
-			val, err := utils.ColAt(col, r)
-			if err != nil {
-				ca.logger.ErrorWith("can't get value", "error", err, "name", name, "row", r)
-				return errors.Wrapf(err, "%s:%d can't get value", name, r)
-			}
+// newColumn returns an empty column
+func newColumn(name string, dtype frames.DType) (frames.Column, error) {
+	var data interface{}
+	switch dtype {
+	case frames.IntType:
+		data = []int64{}
+	case frames.FloatType:
+		data = []float64{}
+	case frames.StringType:
+		data = []string{}
+	case frames.TimeType:
+		data = []time.Time{}
+	case frames.BoolType:
+		data = []bool{}
+	default:
+		return nil, fmt.Errorf("unsupported data type - %d", dtype)
+	}
 
-			record[c] = fmt.Sprintf("%v", val)
-		}
+	return frames.NewSliceColumn(name, data)
+}
 
-		if err := ca.csvWriter.Write(record); err != nil {
-			ca.logger.ErrorWith("can't write record", "error", err)
-			return errors.Wrap(err, "can't write record")
-		}
+// valueDType returns the data type of a parseValue result
+func valueDType(value interface{}) frames.DType {
+	switch value.(type) {
+	case int64:
+		return frames.IntType
+	case float64:
+		return frames.FloatType
+	case time.Time:
+		return frames.TimeType
+	case bool:
+		return frames.BoolType
 	}
 
-	return nil
+	return frames.StringType
 }
 
-// File Sync
-type syncer interface {
-	Sync() error
+// widenDType returns a type that can hold values of both types
+func widenDType(dtype1, dtype2 frames.DType) frames.DType {
+	switch {
+	case dtype1 == dtype2:
+		return dtype1
+	case dtype1 == frames.IntType && dtype2 == frames.FloatType:
+		return frames.FloatType
+	case dtype1 == frames.FloatType && dtype2 == frames.IntType:
+		return frames.FloatType
+	}
+
+	return frames.StringType
 }
 
-// WaitForComplete wait for write completion
-func (ca *csvAppender) WaitForComplete(timeout time.Duration) error {
-	ca.csvWriter.Flush()
-	if err := ca.csvWriter.Error(); err != nil {
-		ca.logger.ErrorWith("csv Flush", "error", err)
-		return err
+func sameNames(names1, names2 []string) bool {
+	if len(names1) != len(names2) {
+		return false
 	}
 
-	if s, ok := ca.writer.(syncer); ok {
-		return s.Sync()
+	for i, name := range names1 {
+		if names2[i] != name {
+			return false
+		}
 	}
 
-	return nil
+	return true
+}
+
+func indexOf(name string, names []string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
 }
 
 func fileExists(path string) bool {
@@ -21,11 +21,21 @@ such restriction.
 package csv
 
 import (
+	"bytes"
+	"context"
 	"io/ioutil"
+	"os"
 	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/conformance"
+	"github.com/v3io/frames/pb"
 )
 
 var (
@@ -113,6 +123,967 @@ func TestMaxInMessage(t *testing.T) {
 	}
 }
 
+func TestDescribe(t *testing.T) {
+	logger, err := frames.NewLogger("debug")
+	if err != nil {
+		t.Fatalf("can't create logger - %s", err)
+	}
+
+	csvPath, err := tmpCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &frames.BackendConfig{
+		Name:    "testCsv",
+		Type:    "csv",
+		RootDir: path.Dir(csvPath),
+	}
+
+	backend, err := NewBackend(logger, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := path.Base(csvPath)
+	tables, err := backend.List(context.Background(), &frames.ListRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, name := range tables {
+		if name == table {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("%q not in %v", table, tables)
+	}
+
+	info, err := backend.Describe(context.Background(), &frames.DescribeRequest{Table: table})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(info.Schema.Fields) != numCSVCols {
+		t.Fatalf("# fields mismatch %d != %d", len(info.Schema.Fields), numCSVCols)
+	}
+
+	types := map[string]string{}
+	for _, field := range info.Schema.Fields {
+		types[field.Name] = field.Type
+	}
+
+	expected := map[string]string{
+		"STATION": "string",
+		"DATE":    "time",
+		"PRCP":    "integer",
+		"FMTM":    "float",
+	}
+
+	for name, typ := range expected {
+		if types[name] != typ {
+			t.Fatalf("%s: type mismatch %q != %q", name, types[name], typ)
+		}
+	}
+}
+
+func TestWriteModes(t *testing.T) {
+	backend := newBackend(t, nil)
+	table := "modes.csv"
+	tablePath := filepath.Join(backend.rootDir, table)
+
+	write := func(mode frames.SaveMode, columns map[string]interface{}) error {
+		appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: table, SaveMode: mode})
+		if err != nil {
+			return err
+		}
+
+		if err := appender.Add(makeFrame(t, columns)); err != nil {
+			return err
+		}
+
+		return appender.WaitForComplete(time.Second)
+	}
+
+	assertContent := func(expected string) {
+		data, err := ioutil.ReadFile(tablePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != expected {
+			t.Fatalf("bad content:\n%s\nexpected:\n%s", data, expected)
+		}
+	}
+
+	// Append to missing table creates it
+	if err := write(frames.AppendMode, map[string]interface{}{"a": []int64{1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Overwrite keeps the table file mode
+	if err := os.Chmod(tablePath, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := write(frames.OverwriteMode, map[string]interface{}{"a": []int64{1, 2}, "b": []string{"x", "y"}}); err != nil {
+		t.Fatal(err)
+	}
+	assertContent("a,b\n1,x\n2,y\n")
+
+	info, err := os.Stat(tablePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode := info.Mode().Perm(); mode != 0640 {
+		t.Fatalf("bad table file mode - %v", mode)
+	}
+
+	// Missing columns are nulls
+	if err := write(frames.AppendMode, map[string]interface{}{"b": []string{"z"}}); err != nil {
+		t.Fatal(err)
+	}
+	assertContent("a,b\n1,x\n2,y\n\\N,z\n")
+
+	if err := write(frames.AppendMode, map[string]interface{}{"c": []int64{3}}); err == nil {
+		t.Fatal("no error on unknown column")
+	}
+	assertContent("a,b\n1,x\n2,y\n\\N,z\n")
+
+	if err := write(frames.AppendNewColumnsMode, map[string]interface{}{"c": []int64{3}, "a": []int64{4}}); err != nil {
+		t.Fatal(err)
+	}
+	assertContent("a,b,c\n1,x,\\N\n2,y,\\N\n\\N,z,\\N\n4,\\N,3\n")
+
+	// Overwrite is visible only on completion
+	appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: table})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Add(makeFrame(t, map[string]interface{}{"d": []float64{1.5}})); err != nil {
+		t.Fatal(err)
+	}
+	assertContent("a,b,c\n1,x,\\N\n2,y,\\N\n\\N,z,\\N\n4,\\N,3\n")
+
+	if err := appender.WaitForComplete(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	assertContent("d\n1.5\n")
+
+	infos, err := ioutil.ReadDir(backend.rootDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, info := range infos {
+		if strings.Contains(info.Name(), ".tmp") {
+			t.Fatalf("temporary file left - %s", info.Name())
+		}
+	}
+}
+
+func TestTypeInference(t *testing.T) {
+	backend := newBackend(t, map[string]interface{}{"sampleRows": 3})
+	writeTable(t, backend, "t.csv", "i,f,s,t\n1,12,1,2018-01-01\n2,12.5,a,2018-01-02\n3,,1.5,\n4,7,2,2018-01-04\n")
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "t.csv", MessageLimit: 1})
+	if len(frs) != 4 {
+		t.Fatalf("wrong number of frames - %d", len(frs))
+	}
+
+	expected := map[string]frames.DType{
+		"i": frames.IntType,
+		"f": frames.FloatType,
+		"s": frames.StringType,
+		"t": frames.TimeType,
+	}
+
+	for _, frame := range frs {
+		for name, dtype := range expected {
+			col, err := frame.Column(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if col.DType() != dtype {
+				t.Fatalf("%s: dtype mismatch %d != %d", name, col.DType(), dtype)
+			}
+		}
+	}
+
+	col, _ := frs[2].Column("f")
+	if !col.IsNull(0) {
+		t.Fatal("empty value is not null")
+	}
+
+	// Type after the sampled rows
+	writeTable(t, backend, "t.csv", "i\n1\n2\n3\nx\n")
+	it, err := backend.Read(context.Background(), &frames.ReadRequest{Table: "t.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for it.Next() {
+	}
+
+	if it.Err() == nil {
+		t.Fatal("no error on bad integer")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	backend := newBackend(t, nil)
+	writeTable(t, backend, "t.csv", "zip,n\n01234,1\n12345,2\n")
+
+	schema := &frames.TableSchema{
+		Fields: []*frames.SchemaField{
+			{Name: "zip", Type: "string"},
+		},
+	}
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "t.csv", Schema: schema})
+	zip, _ := frs[0].Column("zip")
+	if zip.DType() != frames.StringType {
+		t.Fatalf("zip is not a string column")
+	}
+
+	if val, _ := zip.StringAt(0); val != "01234" {
+		t.Fatalf("bad zip - %q", val)
+	}
+
+	n, _ := frs[0].Column("n")
+	if n.DType() != frames.IntType {
+		t.Fatalf("n is not inferred as integer")
+	}
+
+	// Schema file from Create
+	ctx := context.Background()
+	schema.Fields = append(schema.Fields, &frames.SchemaField{Name: "n", Type: "integer"})
+	if err := backend.Create(ctx, &frames.CreateRequest{Table: "c.csv", Schema: schema}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Schema files are readable by others
+	stat, err := os.Stat(backend.schemaPath("c.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode := stat.Mode().Perm(); mode != 0644 {
+		t.Fatalf("bad schema file mode - %v", mode)
+	}
+
+	appender, err := backend.Write(ctx, &frames.WriteRequest{Table: "c.csv", SaveMode: frames.AppendMode})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Add(makeFrame(t, map[string]interface{}{"n": []string{"1"}})); err == nil {
+		t.Fatal("no error on type mismatch")
+	}
+
+	writeFrame := func(mode frames.SaveMode, data map[string]interface{}) {
+		appender, err := backend.Write(ctx, &frames.WriteRequest{Table: "c.csv", SaveMode: mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.Add(makeFrame(t, data)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.WaitForComplete(time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFrame(frames.AppendMode, map[string]interface{}{"zip": []string{"00001"}})
+	writeFrame(frames.AppendNewColumnsMode, map[string]interface{}{"x": []float64{1}})
+
+	info, err := backend.Describe(ctx, &frames.DescribeRequest{Table: "c.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := map[string]string{}
+	for _, field := range info.Schema.Fields {
+		types[field.Name] = field.Type
+	}
+
+	if expected := map[string]string{"zip": "string", "n": "integer", "x": "float"}; !reflect.DeepEqual(types, expected) {
+		t.Fatalf("bad types - %v", types)
+	}
+
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "c.csv"})
+	zip, _ = frs[0].Column("zip")
+	if val, _ := zip.StringAt(0); val != "00001" {
+		t.Fatalf("bad zip - %q", val)
+	}
+
+	x, _ := frs[0].Column("x")
+	if x.DType() != frames.FloatType || !x.IsNull(0) {
+		t.Fatalf("bad x column")
+	}
+
+	if err := backend.Delete(ctx, &frames.DeleteRequest{Table: "c.csv"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if fileExists(backend.schemaPath("c.csv")) {
+		t.Fatal("schema file not deleted")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	options := map[string]interface{}{
+		"delimiter":   ";",
+		"header":      false,
+		"nullValues":  []interface{}{"NA", ""},
+		"timeFormats": []interface{}{"02/01/2006"},
+	}
+
+	backend := newBackend(t, options)
+	writeTable(t, backend, "t.csv", "a;31/12/2018;1\nNA;01/01/2019;\n")
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "t.csv"})
+	if names := frs[0].Names(); !reflect.DeepEqual(names, []string{"col0", "col1", "col2"}) {
+		t.Fatalf("bad names - %v", names)
+	}
+
+	col0, _ := frs[0].Column("col0")
+	if !col0.IsNull(1) {
+		t.Fatal("NA is not null")
+	}
+
+	col1, _ := frs[0].Column("col1")
+	if val, _ := col1.TimeAt(0); !val.Equal(time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("bad time - %s", val)
+	}
+
+	appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: "w.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame := makeFrame(t, map[string]interface{}{"a": []string{"x;y"}, "b": []int64{1}})
+	if err := appender.Add(frame); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.WaitForComplete(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(backend.csvPath("w.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "\"x;y\";1\n" {
+		t.Fatalf("bad content - %q", data)
+	}
+
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "w.csv"})
+	if names := frs[0].Names(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Fatalf("bad names - %v", names)
+	}
+
+	cfg := &frames.BackendConfig{Options: map[string]interface{}{"delimiter": ";;"}}
+	if _, err := NewBackend(backend.logger, cfg, nil); err == nil {
+		t.Fatal("no error on bad delimiter")
+	}
+}
+
+func TestNulls(t *testing.T) {
+	backend := newBackend(t, nil)
+
+	y, err := frames.NewNullableSliceColumn("y", []string{"", "", "a"}, []bool{false, true, false})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := frames.NewNullableSliceColumn("x", []int64{1, 2, 0}, []bool{false, false, true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, err := frames.NewFrame([]frames.Column{x, y}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: "n.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Add(frame); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.WaitForComplete(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "n.csv"})
+	ry, _ := frs[0].Column("y")
+	rx, _ := frs[0].Column("x")
+	for i := 0; i < frame.Len(); i++ {
+		if ry.IsNull(i) != y.IsNull(i) || rx.IsNull(i) != x.IsNull(i) {
+			t.Fatalf("%d: null mismatch", i)
+		}
+	}
+
+	if val, _ := ry.StringAt(0); val != "" {
+		t.Fatalf("bad empty string - %q", val)
+	}
+
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "n.csv", Filter: "not exists(y)"})
+	if nRows := totalRows(frs); nRows != 1 {
+		t.Fatalf("bad number of null rows - %d", nRows)
+	}
+
+	// Empty values are nulls in non-string columns
+	writeTable(t, backend, "e.csv", "x,y\n1,a\n,\n")
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "e.csv"})
+	rx, _ = frs[0].Column("x")
+	ry, _ = frs[0].Column("y")
+	if !rx.IsNull(1) || ry.IsNull(1) {
+		t.Fatal("bad nulls in empty values")
+	}
+
+	// Strings that are null values can't be written
+	frame = makeFrame(t, map[string]interface{}{"y": []string{`\N`}})
+	appender, err = backend.Write(context.Background(), &frames.WriteRequest{Table: "n.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Add(frame); err == nil {
+		t.Fatal("no error on null value string")
+	}
+}
+
+func TestFilterColumns(t *testing.T) {
+	backend := newBackend(t, nil)
+	writeTable(t, backend, "t.csv", "name,temp,city\na,31,tlv\nb,20,nyc\nc,35,tokyo\nd,,tlv\ne,40,tlv\n")
+
+	testCases := []struct {
+		request *frames.ReadRequest
+		names   []string
+		rows    int
+	}{
+		{&frames.ReadRequest{Filter: "temp > 30"}, []string{"name", "temp", "city"}, 3},
+		{&frames.ReadRequest{Filter: "temp > 30", Columns: []string{"name"}}, []string{"name"}, 3},
+		{&frames.ReadRequest{Filter: "temp > 30 and city == 'tlv'", Limit: 1}, []string{"name", "temp", "city"}, 1},
+		{&frames.ReadRequest{Filter: "starts(city, 't') AND NOT exists(temp)"}, []string{"name", "temp", "city"}, 1},
+		{&frames.ReadRequest{Columns: []string{"city", "name"}, Limit: 4, MessageLimit: 3}, []string{"city", "name"}, 4},
+		{&frames.ReadRequest{Filter: "temp > 100"}, nil, 0},
+	}
+
+	for _, tc := range testCases {
+		tc.request.Table = "t.csv"
+		frs := readTable(t, backend, tc.request)
+		nRows := 0
+		for _, frame := range frs {
+			if !reflect.DeepEqual(frame.Names(), tc.names) {
+				t.Fatalf("%+v: bad names - %v", tc.request, frame.Names())
+			}
+			nRows += frame.Len()
+		}
+
+		if nRows != tc.rows {
+			t.Fatalf("%+v: bad number of rows - %d != %d", tc.request, nRows, tc.rows)
+		}
+	}
+
+	badRequests := []*frames.ReadRequest{
+		{Table: "t.csv", Columns: []string{"nosuch"}},
+		{Table: "t.csv", Filter: "nosuch > 1"},
+		{Table: "t.csv", Filter: "temp >"},
+	}
+
+	for _, request := range badRequests {
+		if _, err := backend.Read(context.Background(), request); err == nil {
+			t.Fatalf("%+v: no error", request)
+		}
+	}
+
+	request := &frames.DeleteRequest{Table: "t.csv", Filter: "city = 'tlv' and temp < 35"}
+	if err := backend.Delete(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(backend.csvPath("t.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "name,temp,city\nb,20,nyc\nc,35,tokyo\nd,,tlv\ne,40,tlv\n"; string(data) != expected {
+		t.Fatalf("bad content after delete:\n%s", data)
+	}
+}
+
+func TestPartitions(t *testing.T) {
+	backend := newBackend(t, nil)
+	backend.workers = 4
+	files := map[string]string{
+		"date=2018-12-01/host=a/part-0001.csv": "cpu,mem\n1.5,10\n2.5,20\n",
+		"date=2018-12-01/host=b/part-0001.csv": "cpu,mem\n3.5,30\n",
+		"date=2018-12-02/host=a/part-0001.csv": "cpu,mem\n4.5,40\n5.5,50\n",
+		"date=2018-12-02/host=a/part-0002.csv": "cpu\n6.5\n",
+		"date=2018-12-02/host=c/part-0001.csv": "cpu,mem\nbad row,1,2\n",
+		"date=2018-12-02/host=c/_SUCCESS":      "",
+	}
+
+	for name, data := range files {
+		filePath := filepath.Join(backend.csvPath("metrics"), filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filePath, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// host=c is broken, reading it fails
+	request := &frames.ReadRequest{Table: "metrics"}
+	it, err := backend.Read(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for it.Next() {
+	}
+
+	if it.Err() == nil {
+		t.Fatal("no error reading bad partition")
+	}
+
+	testCases := []struct {
+		request *frames.ReadRequest
+		names   []string
+		rows    int
+	}{
+		{&frames.ReadRequest{Filter: "host <> 'c'", Columns: []string{"host", "cpu"}}, []string{"host", "cpu"}, 6},
+		{&frames.ReadRequest{Filter: "host = 'a' AND cpu > 2"}, nil, 4},
+		{&frames.ReadRequest{Filter: "date = '2018-12-01'", Columns: []string{"cpu", "date"}, Limit: 2}, []string{"cpu", "date"}, 2},
+		{&frames.ReadRequest{Filter: "host in ('a', 'b')", Limit: 4, MessageLimit: 1}, nil, 4},
+	}
+
+	for _, tc := range testCases {
+		tc.request.Table = "metrics"
+		frs := readTable(t, backend, tc.request)
+		if nRows := totalRows(frs); nRows != tc.rows {
+			t.Fatalf("%+v: bad number of rows - %d != %d", tc.request, nRows, tc.rows)
+		}
+
+		for _, frame := range frs {
+			if tc.names != nil && !reflect.DeepEqual(frame.Names(), tc.names) {
+				t.Fatalf("%+v: bad names - %v", tc.request, frame.Names())
+			}
+
+			host, err := frame.Column("host")
+			if err != nil {
+				continue
+			}
+
+			if !frames.IsLabelColumn(host) {
+				t.Fatalf("%+v: host is not a label column", tc.request)
+			}
+		}
+	}
+
+	if err := os.RemoveAll(filepath.Join(backend.csvPath("metrics"), "date=2018-12-02", "host=c")); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := backend.Describe(context.Background(), &frames.DescribeRequest{Table: "metrics"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := make(map[string]string)
+	for _, field := range info.Schema.Fields {
+		types[field.Name] = field.Type
+	}
+
+	expected := map[string]string{"cpu": "float", "mem": "integer", "date": "time", "host": "string"}
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("bad schema - %v", types)
+	}
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "metrics", Filter: "date > '2018-12-01'"})
+	if nRows := totalRows(frs); nRows != 3 {
+		t.Fatalf("bad number of rows - %d", nRows)
+	}
+}
+
+func TestPartitionedWrite(t *testing.T) {
+	backend := newBackend(t, nil)
+	partitionKey := map[string]*pb.Value{
+		"partition": &pb.Value{Value: &pb.Value_Bval{Bval: true}},
+	}
+
+	request := &frames.CreateRequest{
+		Table: "events",
+		Schema: &frames.TableSchema{
+			Fields: []*frames.SchemaField{
+				{Name: "day", Type: "int", Properties: partitionKey},
+				{Name: "kind", Properties: partitionKey},
+			},
+		},
+	}
+
+	if err := backend.Create(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+
+	tables, err := backend.List(context.Background(), &frames.ListRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(tables, []string{"events"}) {
+		t.Fatalf("bad tables - %v", tables)
+	}
+
+	write := func(mode frames.SaveMode, data map[string]interface{}) error {
+		request := &frames.WriteRequest{Table: "events", SaveMode: mode}
+		appender, err := backend.Write(context.Background(), request)
+		if err != nil {
+			return err
+		}
+
+		if err := appender.Add(makeFrame(t, data)); err != nil {
+			return err
+		}
+
+		return appender.WaitForComplete(time.Second)
+	}
+
+	frame := map[string]interface{}{
+		"day":   []int64{1, 1, 2, 2, 1},
+		"kind":  []string{"a", "b/c", "a", "a", "a"},
+		"value": []float64{1, 2, 3, 4, 5},
+	}
+
+	if err := write(frames.OverwriteMode, frame); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{"day=1/kind=a", "day=1/kind=b%2Fc", "day=2/kind=a"} {
+		if !fileExists(filepath.Join(backend.csvPath("events"), filepath.FromSlash(dir))) {
+			t.Fatalf("partition %q not found", dir)
+		}
+	}
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "events", Filter: "kind = 'b/c'"})
+	if len(frs) != 1 || frs[0].Len() != 1 {
+		t.Fatalf("bad result - %v", frs)
+	}
+
+	value, err := frs[0].Column("value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, _ := value.FloatAt(0); v != 2 {
+		t.Fatalf("bad value - %v", v)
+	}
+
+	if err := write(frames.AppendMode, map[string]interface{}{"day": []int64{3}, "kind": []string{"a"}, "other": []int64{1}}); err == nil {
+		t.Fatal("no error appending new column")
+	}
+
+	if err := write(frames.AppendMode, map[string]interface{}{"day": []string{"x"}, "kind": []string{"a"}, "value": []float64{1}}); err == nil {
+		t.Fatal("no error appending bad partition key type")
+	}
+
+	if err := write(frames.AppendMode, map[string]interface{}{"day": []int64{3}, "kind": []string{"a"}, "value": []float64{6}}); err != nil {
+		t.Fatal(err)
+	}
+
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "events", Filter: "kind = 'a'"})
+	if nRows := totalRows(frs); nRows != 5 {
+		t.Fatalf("bad number of rows - %d", nRows)
+	}
+
+	if err := backend.Delete(context.Background(), &frames.DeleteRequest{Table: "events", Filter: "day = 1 AND value > 1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "events"})
+	if nRows := totalRows(frs); nRows != 4 {
+		t.Fatalf("bad number of rows after delete - %d", nRows)
+	}
+
+	// Overwrite replaces all partitions
+	if err := write(frames.OverwriteMode, map[string]interface{}{"day": []int64{9}, "kind": []string{"z"}, "value": []float64{1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "events"})
+	if nRows := totalRows(frs); nRows != 1 {
+		t.Fatalf("bad number of rows after overwrite - %d", nRows)
+	}
+
+	infos, err := ioutil.ReadDir(backend.rootDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, info := range infos {
+		if strings.Contains(info.Name(), ".tmp") || strings.Contains(info.Name(), ".old") {
+			t.Fatalf("temporary directory left - %s", info.Name())
+		}
+	}
+
+	if err := backend.Delete(context.Background(), &frames.DeleteRequest{Table: "events"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if fileExists(backend.csvPath("events")) || fileExists(backend.schemaPath("events")) {
+		t.Fatal("table not deleted")
+	}
+}
+
+func TestCompression(t *testing.T) {
+	backend := newBackend(t, nil)
+	write := func(table string, mode frames.SaveMode, data map[string]interface{}) {
+		appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: table, SaveMode: mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.Add(makeFrame(t, data)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.WaitForComplete(time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	isGzip := func(table string) bool {
+		data, err := ioutil.ReadFile(backend.csvPath(table))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b
+	}
+
+	write("t.csv.gz", frames.OverwriteMode, map[string]interface{}{"a": []int64{1, 2, 3}, "b": []string{"x", "y", "z"}})
+	write("t.csv.gz", frames.AppendMode, map[string]interface{}{"a": []int64{4}, "b": []string{"w"}})
+	write("t.csv.gz", frames.AppendNewColumnsMode, map[string]interface{}{"a": []int64{5}, "c": []float64{1.5}})
+	if !isGzip("t.csv.gz") {
+		t.Fatal("table is not compressed")
+	}
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "t.csv.gz", MessageLimit: 2})
+	if len(frs) != 3 || totalRows(frs) != 5 {
+		t.Fatalf("bad result - %d frames, %d rows", len(frs), totalRows(frs))
+	}
+
+	if names := frs[0].Names(); !reflect.DeepEqual(names, []string{"a", "b", "c"}) {
+		t.Fatalf("bad names - %v", names)
+	}
+
+	if err := backend.Delete(context.Background(), &frames.DeleteRequest{Table: "t.csv.gz", Filter: "a > 3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if frs := readTable(t, backend, &frames.ReadRequest{Table: "t.csv.gz"}); totalRows(frs) != 3 {
+		t.Fatalf("bad number of rows after delete - %d", totalRows(frs))
+	}
+
+	if !isGzip("t.csv.gz") {
+		t.Fatal("table is not compressed after delete")
+	}
+
+	// Compression from options
+	gzBackend := newBackend(t, map[string]interface{}{"compression": "gzip", "compressionLevel": 9})
+	backend.rootDir = gzBackend.rootDir
+	if err := gzBackend.Create(context.Background(), &frames.CreateRequest{Table: "t.csv", Schema: &frames.TableSchema{Fields: []*frames.SchemaField{{Name: "a"}}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !isGzip("t.csv") {
+		t.Fatal("table is not compressed")
+	}
+
+	info, err := gzBackend.Describe(context.Background(), &frames.DescribeRequest{Table: "t.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(info.Schema.Fields) != 1 || info.Schema.Fields[0].Name != "a" {
+		t.Fatalf("bad schema - %+v", info.Schema)
+	}
+
+	badOptions := []map[string]interface{}{
+		{"compression": "lzma"},
+		{"compressionLevel": 10},
+	}
+
+	for _, options := range badOptions {
+		b := &Backend{}
+		if err := b.parseOptions(options); err == nil {
+			t.Fatalf("%v: no error", options)
+		}
+	}
+}
+
+func TestCompressionZstd(t *testing.T) {
+	backend := newBackend(t, nil)
+	write := func(backend *Backend, table string, mode frames.SaveMode, data map[string]interface{}) {
+		appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: table, SaveMode: mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.Add(makeFrame(t, data)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.WaitForComplete(time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	isZstd := func(table string) bool {
+		data, err := ioutil.ReadFile(backend.csvPath(table))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bytes.HasPrefix(data, []byte{0x28, 0xb5, 0x2f, 0xfd})
+	}
+
+	write(backend, "t.csv.zst", frames.OverwriteMode, map[string]interface{}{"a": []int64{1, 2, 3}, "b": []string{"x", "y", "z"}})
+	write(backend, "t.csv.zst", frames.AppendMode, map[string]interface{}{"a": []int64{4}, "b": []string{"w"}})
+	if !isZstd("t.csv.zst") {
+		t.Fatal("table is not compressed")
+	}
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "t.csv.zst"})
+	if totalRows(frs) != 4 {
+		t.Fatalf("bad number of rows - %d", totalRows(frs))
+	}
+
+	col, err := frs[len(frs)-1].Column("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val, _ := col.StringAt(col.Len() - 1); val != "w" {
+		t.Fatalf("bad last value - %q", val)
+	}
+
+	if err := backend.Delete(context.Background(), &frames.DeleteRequest{Table: "t.csv.zst", Filter: "a > 2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if frs := readTable(t, backend, &frames.ReadRequest{Table: "t.csv.zst"}); totalRows(frs) != 2 {
+		t.Fatalf("bad number of rows after delete - %d", totalRows(frs))
+	}
+
+	if !isZstd("t.csv.zst") {
+		t.Fatal("table is not compressed after delete")
+	}
+
+	// Compression from options
+	zBackend := newBackend(t, map[string]interface{}{"compression": "zstd", "compressionLevel": 1})
+	backend.rootDir = zBackend.rootDir
+	write(zBackend, "t.csv", frames.OverwriteMode, map[string]interface{}{"a": []int64{1, 2}})
+	if !isZstd("t.csv") {
+		t.Fatal("table is not compressed")
+	}
+
+	if frs := readTable(t, zBackend, &frames.ReadRequest{Table: "t.csv"}); totalRows(frs) != 2 {
+		t.Fatalf("bad number of rows - %d", totalRows(frs))
+	}
+}
+
+func writeTable(t *testing.T, backend *Backend, table string, data string) {
+	if err := ioutil.WriteFile(backend.csvPath(table), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTable(t *testing.T, backend *Backend, request *frames.ReadRequest) []frames.Frame {
+	it, err := backend.Read(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frs []frames.Frame
+	for it.Next() {
+		frs = append(frs, it.At())
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return frs
+}
+
+func newBackend(t *testing.T, options map[string]interface{}) *Backend {
+	logger, err := frames.NewLogger("debug")
+	if err != nil {
+		t.Fatalf("can't create logger - %s", err)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "csv-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &frames.BackendConfig{
+		Name:    "testCsv",
+		Type:    "csv",
+		RootDir: tmpDir,
+		Options: options,
+	}
+
+	backend, err := NewBackend(logger, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return backend.(*Backend)
+}
+
+func makeFrame(t *testing.T, data map[string]interface{}) frames.Frame {
+	var names []string
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var columns []frames.Column
+	for _, name := range names {
+		col, err := frames.NewSliceColumn(name, data[name])
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, col)
+	}
+
+	frame, err := frames.NewFrame(columns, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return frame
+}
+
 func totalRows(result []frames.Frame) int {
 	total := 0
 	for _, frame := range result {
@@ -145,7 +1116,7 @@ func loadTempCSV(t *testing.T, req *frames.ReadRequest) []frames.Frame {
 	}
 
 	req.Table = path.Base(csvPath)
-	it, err := backend.Read(req)
+	it, err := backend.Read(context.Background(), req)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -179,3 +1150,11 @@ func tmpCSV() (string, error) {
 
 	return tmp.Name(), nil
 }
+
+func TestConformance(t *testing.T) {
+	factory := func(t *testing.T) frames.DataBackend {
+		return newBackend(t, nil)
+	}
+
+	conformance.Run(t, factory, nil)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package csv
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// Compression option values
+const (
+	autoCompression = "auto" // by file extension
+	noCompression   = "none"
+	gzipCompression = "gzip"
+	zstdCompression = "zstd"
+)
+
+// compressionExt is the file extensions of compression types
+var compressionExt = map[string]string{
+	gzipCompression: ".gz",
+	zstdCompression: ".zst",
+}
+
+// compression returns the compression type of the file in path
+func (b *Backend) compression(path string) string {
+	if b.compressionType != autoCompression {
+		return b.compressionType
+	}
+
+	for compression, ext := range compressionExt {
+		if strings.HasSuffix(path, ext) {
+			return compression
+		}
+	}
+
+	return noCompression
+}
+
+// fileExt returns the extension of new files (partition files)
+func (b *Backend) fileExt() string {
+	return ".csv" + compressionExt[b.compressionType]
+}
+
+func checkCompression(compression string) error {
+	switch compression {
+	case autoCompression, noCompression, gzipCompression, zstdCompression:
+		return nil
+	}
+
+	return fmt.Errorf("unknown compression - %q", compression)
+}
+
+// openReader opens the file in path for reading, decompressing by compression
+func (b *Backend) openReader(path string, compression string) (io.ReadCloser, error) {
+	if err := checkCompression(compression); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch compression {
+	case gzipCompression:
+		gzReader, err := gzip.NewReader(file)
+		if err == io.EOF {
+			// Empty file
+			return &compressedReader{Reader: bytes.NewReader(nil), file: file}, nil
+		}
+
+		if err != nil {
+			file.Close()
+			return nil, errors.Wrapf(err, "can't read compressed file %q", path)
+		}
+
+		return &compressedReader{Reader: gzReader, file: file}, nil
+	case zstdCompression:
+		zReader, err := zstd.NewReader(file, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			file.Close()
+			return nil, errors.Wrapf(err, "can't read compressed file %q", path)
+		}
+
+		return &compressedReader{Reader: zReader, file: file, release: zReader.Close}, nil
+	}
+
+	return file, nil
+}
+
+// compressedReader reads decompressed data from file
+type compressedReader struct {
+	io.Reader
+	file    *os.File
+	release func() // Releases decompressor resources, can be nil
+}
+
+func (r *compressedReader) Close() error {
+	if r.release != nil {
+		r.release()
+	}
+	return r.file.Close()
+}
+
+// newCompressor returns a writer compressing to w by compression, Close must
+// be called to flush it (it doesn't close w)
+func (b *Backend) newCompressor(w io.Writer, compression string) (io.WriteCloser, error) {
+	if err := checkCompression(compression); err != nil {
+		return nil, err
+	}
+
+	switch compression {
+	case gzipCompression:
+		return gzip.NewWriterLevel(w, b.compressionLevel)
+	case zstdCompression:
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstdLevel(b.compressionLevel)), zstd.WithEncoderConcurrency(1))
+	}
+
+	return nopCloser{w}, nil
+}
+
+// zstdLevel returns the zstd encoder level closest to a gzip compression level
+func zstdLevel(level int) zstd.EncoderLevel {
+	if level == gzip.DefaultCompression {
+		return zstd.SpeedDefault
+	}
+
+	return zstd.EncoderLevelFromZstd(level)
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
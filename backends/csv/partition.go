@@ -0,0 +1,1018 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package csv
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/ops"
+)
+
+// A partitioned table is a directory with a sub directory per partition key
+// value (Hive style), e.g. date=2018-12-01/host=a/part-0001.csv. Partition keys
+// are label columns of the frames read. Files and directories starting with
+// "." or "_" are ignored
+
+// dataset is a table directory
+type dataset struct {
+	path       string
+	schema     *frames.TableSchema // data fields, nil if there's no table schema
+	keys       []string
+	keyTypes   []frames.DType
+	partitions []*partition
+}
+
+// partition is a leaf directory of a dataset
+type partition struct {
+	dir    string // relative to the dataset, "" for the dataset directory
+	raw    []string
+	values []interface{}
+	files  []string
+}
+
+// isDataset returns true if table is a directory
+func (b *Backend) isDataset(table string) bool {
+	info, err := os.Stat(b.csvPath(table))
+	return err == nil && info.IsDir()
+}
+
+// openDataset scans the partitions of table, partition keys and their types are
+// taken from schema, the table schema file or the directory names
+func (b *Backend) openDataset(table string, schema *frames.TableSchema) (*dataset, error) {
+	if schema == nil || len(schema.Fields) == 0 {
+		var err error
+		if schema, err = b.readSchema(table); err != nil {
+			return nil, err
+		}
+	}
+
+	dataSchema, keyFields := splitSchema(schema)
+	ds := &dataset{
+		path:   b.csvPath(table),
+		schema: dataSchema,
+	}
+
+	var partKeys [][]string
+	var scan func(dir string, keys []string, raw []string) error
+	scan = func(dir string, keys []string, raw []string) error {
+		infos, err := ioutil.ReadDir(filepath.Join(ds.path, filepath.FromSlash(dir)))
+		if err != nil {
+			return errors.Wrapf(err, "can't read %q partitions", table)
+		}
+
+		var files []string
+		hasDirs := false
+		for _, info := range infos {
+			name := info.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				continue
+			}
+
+			subDir := path.Join(dir, name)
+			if !info.IsDir() {
+				files = append(files, filepath.Join(ds.path, filepath.FromSlash(subDir)))
+				continue
+			}
+
+			i := strings.Index(name, "=")
+			if i <= 0 {
+				return fmt.Errorf("bad partition directory %q in %q (not key=value)", subDir, table)
+			}
+
+			value, err := url.PathUnescape(name[i+1:])
+			if err != nil {
+				return errors.Wrapf(err, "bad partition directory %q in %q", subDir, table)
+			}
+
+			hasDirs = true
+			subKeys := append(append([]string{}, keys...), name[:i])
+			subRaw := append(append([]string{}, raw...), value)
+			if err := scan(subDir, subKeys, subRaw); err != nil {
+				return err
+			}
+		}
+
+		if len(files) == 0 {
+			return nil
+		}
+
+		if hasDirs {
+			return fmt.Errorf("partition directory %q in %q has both files and directories", dir, table)
+		}
+
+		ds.partitions = append(ds.partitions, &partition{dir: dir, raw: raw, files: files})
+		partKeys = append(partKeys, keys)
+		return nil
+	}
+
+	if err := scan("", nil, nil); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(keyFields) > 0:
+		for _, field := range keyFields {
+			ds.keys = append(ds.keys, field.Name)
+		}
+	case len(partKeys) > 0:
+		ds.keys = partKeys[0]
+	}
+
+	for i, keys := range partKeys {
+		if !sameNames(keys, ds.keys) {
+			return nil, fmt.Errorf("partition %q in %q doesn't match partition keys %v", ds.partitions[i].dir, table, ds.keys)
+		}
+	}
+
+	if err := ds.parseValues(b, keyFields); err != nil {
+		return nil, errors.Wrapf(err, "table %q", table)
+	}
+
+	return ds, nil
+}
+
+// parseValues sets the key types (from keyFields or inferred from the values)
+// and parses the partition values
+func (ds *dataset) parseValues(b *Backend, keyFields []*frames.SchemaField) error {
+	ds.keyTypes = make([]frames.DType, len(ds.keys))
+	for k := range ds.keys {
+		if k < len(keyFields) && keyFields[k].Type != "" {
+			dtype, err := utils.SchemaDType(keyFields[k].Type)
+			if err != nil {
+				return errors.Wrapf(err, "schema field %q", keyFields[k].Name)
+			}
+			ds.keyTypes[k] = dtype
+			continue
+		}
+
+		dtype := frames.StringType
+		for i, part := range ds.partitions {
+			vtype := valueDType(b.parseValue(part.raw[k]))
+			if i == 0 {
+				dtype = vtype
+			} else {
+				dtype = widenDType(dtype, vtype)
+			}
+		}
+		ds.keyTypes[k] = dtype
+	}
+
+	for _, part := range ds.partitions {
+		part.values = make([]interface{}, len(ds.keys))
+		for k, raw := range part.raw {
+			value, err := b.parseTyped(raw, ds.keyTypes[k])
+			if err != nil {
+				return fmt.Errorf("can't parse partition %q value %q as %s", ds.keys[k], raw, utils.DTypeName(ds.keyTypes[k]))
+			}
+			part.values[k] = value
+		}
+	}
+
+	return nil
+}
+
+// prune returns the partitions that may have rows matching filter, only filter
+// conditions on partition keys are evaluated
+func (ds *dataset) prune(filter *utils.Filter) ([]*partition, error) {
+	if filter == nil || len(ds.keys) == 0 {
+		return ds.partitions, nil
+	}
+
+	restricted := filter.Restrict(ds.keys)
+	if restricted == nil {
+		return ds.partitions, nil
+	}
+
+	pred := restricted.Predicate()
+	var partitions []*partition
+	for _, part := range ds.partitions {
+		columns := make([]frames.Column, len(ds.keys))
+		for k, key := range ds.keys {
+			col, err := frames.NewLabelColumn(key, part.values[k], 1)
+			if err != nil {
+				return nil, err
+			}
+			columns[k] = col
+		}
+
+		frame, err := frames.NewFrame(columns, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		match, err := pred(frame)
+		if err != nil {
+			return nil, err
+		}
+
+		if match(0) {
+			partitions = append(partitions, part)
+		}
+	}
+
+	return partitions, nil
+}
+
+// extraColumns returns the table schema columns missing from columnNames (as
+// nulls) and the partition keys of part
+func (ds *dataset) extraColumns(part *partition, columnNames []string) []extraColumn {
+	inFile := make(map[string]bool)
+	for _, name := range columnNames {
+		inFile[name] = true
+	}
+
+	var extra []extraColumn
+	if ds.schema != nil {
+		for _, field := range ds.schema.Fields {
+			if inFile[field.Name] {
+				continue
+			}
+
+			dtype, err := utils.SchemaDType(field.Type)
+			if err != nil {
+				dtype = frames.StringType
+			}
+			extra = append(extra, extraColumn{name: field.Name, dtype: dtype})
+		}
+	}
+
+	for k, key := range ds.keys {
+		extra = append(extra, extraColumn{name: key, dtype: ds.keyTypes[k], value: part.values[k]})
+	}
+
+	return extra
+}
+
+// columnNames returns the table schema columns and the partition keys, nil if
+// there's no table schema
+func (ds *dataset) columnNames() []string {
+	if ds.schema == nil || len(ds.schema.Fields) == 0 {
+		return nil
+	}
+
+	var names []string
+	for _, field := range ds.schema.Fields {
+		names = append(names, field.Name)
+	}
+
+	return append(names, ds.keys...)
+}
+
+// openFile opens a partition file for reading
+func (b *Backend) openFile(ctx context.Context, ds *dataset, part *partition, file string) (*FrameIterator, error) {
+	name, err := filepath.Rel(b.rootDir, file)
+	if err != nil {
+		name = file
+	}
+
+	it, err := b.newFileIterator(ctx, file, name, ds.schema)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't open %q", name)
+	}
+
+	it.extra = ds.extraColumns(part, it.columnNames)
+	return it, nil
+}
+
+// partitionFile is a file to read
+type partitionFile struct {
+	partition *partition
+	path      string
+}
+
+// readDataset reads the partitions matching filter, files are read
+// concurrently by the backend workers and returned in order
+func (b *Backend) readDataset(ctx context.Context, request *frames.ReadRequest, filter *utils.Filter) (frames.FrameIterator, error) {
+	ds, err := b.openDataset(request.Table, request.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if names := ds.columnNames(); names != nil {
+		var columns []string
+		columns = append(columns, request.Columns...)
+		if filter != nil {
+			columns = append(columns, filter.Columns()...)
+		}
+
+		for _, name := range columns {
+			if indexOf(name, names) == -1 {
+				return nil, fmt.Errorf("column %q not found in %q", name, request.Table)
+			}
+		}
+	}
+
+	partitions, err := ds.prune(filter)
+	if err != nil {
+		b.logger.WarnWith("can't prune partitions, reading all", "table", request.Table, "error", err)
+		partitions = ds.partitions
+	}
+
+	var files []partitionFile
+	for _, part := range partitions {
+		for _, file := range part.files {
+			files = append(files, partitionFile{part, file})
+		}
+	}
+
+	b.logger.DebugWith("reading partitions", "table", request.Table, "partitions", len(partitions), "files", len(files))
+	it := &partitionIterator{
+		logger:  b.logger,
+		results: make([]chan partitionResult, len(files)),
+		limit:   int(request.Limit),
+	}
+	it.ctx, it.cancel = context.WithCancel(ctx)
+	for i := range it.results {
+		it.results[i] = make(chan partitionResult, 1)
+	}
+
+	go b.readFiles(it.ctx, ds, files, request, filter, it.results)
+	return it, nil
+}
+
+// readFiles reads files with at most b.workers files read at once, frames of
+// files[i] are sent to results[i]
+func (b *Backend) readFiles(ctx context.Context, ds *dataset, files []partitionFile, request *frames.ReadRequest, filter *utils.Filter, results []chan partitionResult) {
+	numWorkers := b.workers
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	sem := make(chan struct{}, numWorkers)
+	for i, file := range files {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for _, ch := range results[i:] {
+				close(ch)
+			}
+			return
+		}
+
+		go func(file partitionFile, ch chan partitionResult) {
+			defer func() { <-sem }()
+			defer close(ch)
+			b.readFile(ctx, ds, file, request, filter, ch)
+		}(file, results[i])
+	}
+}
+
+// readFile sends the frames of file to ch
+func (b *Backend) readFile(ctx context.Context, ds *dataset, file partitionFile, request *frames.ReadRequest, filter *utils.Filter, ch chan partitionResult) {
+	send := func(result partitionResult) bool {
+		select {
+		case ch <- result:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	it, err := b.openFile(ctx, ds, file.partition, file.path)
+	if err != nil {
+		send(partitionResult{err: err})
+		return
+	}
+	defer it.close()
+
+	if err := it.selectColumns(request.Columns, filter); err != nil {
+		send(partitionResult{err: err})
+		return
+	}
+
+	// The iterator limit is per file, the total limit is applied when reading
+	// results
+	it.limit = int(request.Limit)
+	it.frameLimit = int(request.MessageLimit)
+	for it.Next() {
+		if !send(partitionResult{frame: it.At()}) {
+			return
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		send(partitionResult{err: err})
+	}
+}
+
+type partitionResult struct {
+	frame frames.Frame
+	err   error
+}
+
+// partitionIterator iterates over frames of partition files
+type partitionIterator struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	logger  logger.Logger
+	results []chan partitionResult
+	current int
+	frame   frames.Frame
+	err     error
+	limit   int
+	nOut    int
+}
+
+// Next reads the next frame, return true of succeeded
+func (it *partitionIterator) Next() bool {
+	for it.current < len(it.results) {
+		if it.limit > 0 && it.nOut >= it.limit {
+			break
+		}
+
+		result, ok := <-it.results[it.current]
+		if !ok {
+			it.current++
+			continue
+		}
+
+		if result.err != nil {
+			it.logger.ErrorWith("can't read partition", "error", result.err)
+			it.err = result.err
+			it.cancel()
+			return false
+		}
+
+		frame := result.frame
+		if it.limit > 0 && it.nOut+frame.Len() > it.limit {
+			var err error
+			if frame, err = ops.Slice(frame, 0, it.limit-it.nOut); err != nil {
+				it.err = err
+				it.cancel()
+				return false
+			}
+		}
+
+		it.frame = frame
+		it.nOut += frame.Len()
+		return true
+	}
+
+	// Workers may stop without error when the request is canceled
+	if err := it.ctx.Err(); err != nil && it.err == nil {
+		it.err = err
+	}
+
+	it.cancel()
+	return false
+}
+
+// At return the current Frame
+func (it *partitionIterator) At() frames.Frame {
+	return it.frame
+}
+
+// Err returns the last error
+func (it *partitionIterator) Err() error {
+	return it.err
+}
+
+// describeDataset returns the data columns of the first partition file (or of
+// the table schema) and the partition keys
+func (b *Backend) describeDataset(ctx context.Context, table string) (*frames.TableSchema, error) {
+	ds, err := b.openDataset(table, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &frames.TableSchema{}
+	if len(ds.partitions) > 0 {
+		part := ds.partitions[0]
+		it, err := b.openFile(ctx, ds, part, part.files[0])
+		if err != nil {
+			return nil, err
+		}
+		defer it.close()
+
+		for i, name := range it.columnNames {
+			field := &frames.SchemaField{Name: name, Type: utils.DTypeName(it.dtypes[i])}
+			schema.Fields = append(schema.Fields, field)
+		}
+
+		for _, extra := range it.extra {
+			field := &frames.SchemaField{Name: extra.name, Type: utils.DTypeName(extra.dtype)}
+			schema.Fields = append(schema.Fields, field)
+		}
+
+		return schema, nil
+	}
+
+	if ds.schema != nil {
+		for _, field := range ds.schema.Fields {
+			dtype, err := utils.SchemaDType(field.Type)
+			if err != nil {
+				dtype = frames.StringType
+			}
+			schema.Fields = append(schema.Fields, &frames.SchemaField{Name: field.Name, Type: utils.DTypeName(dtype)})
+		}
+	}
+
+	for k, key := range ds.keys {
+		schema.Fields = append(schema.Fields, &frames.SchemaField{Name: key, Type: utils.DTypeName(ds.keyTypes[k])})
+	}
+
+	return schema, nil
+}
+
+// deleteDatasetRows rewrites the partition files without the rows matching
+// filter, partitions not matching filter are skipped
+func (b *Backend) deleteDatasetRows(ctx context.Context, table string, filterText string) error {
+	filter, err := utils.ParseFilter(filterText)
+	if err != nil {
+		return err
+	}
+
+	ds, err := b.openDataset(table, nil)
+	if err != nil {
+		return err
+	}
+
+	partitions, err := ds.prune(filter)
+	if err != nil {
+		b.logger.WarnWith("can't prune partitions, deleting from all", "table", table, "error", err)
+		partitions = ds.partitions
+	}
+
+	for _, part := range partitions {
+		for _, file := range part.files {
+			if err := b.deleteFileRows(ctx, ds, part, file, filter); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *Backend) deleteFileRows(ctx context.Context, ds *dataset, part *partition, file string, filter *utils.Filter) error {
+	it, err := b.openFile(ctx, ds, part, file)
+	if err != nil {
+		return err
+	}
+	defer it.close()
+	if len(it.columnNames) == 0 {
+		return nil
+	}
+
+	if err := it.selectColumns(filter.Columns(), nil); err != nil {
+		return err
+	}
+
+	ca := &csvAppender{
+		ctx:     ctx,
+		logger:  b.logger,
+		backend: b,
+		table:   it.path,
+		path:    file,
+	}
+
+	if err := ca.create(); err != nil {
+		return err
+	}
+
+	if err := ca.deleteRows(it, filter); err != nil {
+		ca.abort()
+		return err
+	}
+
+	return ca.WaitForComplete(0)
+}
+
+// createDataset creates an empty partitioned table
+func (b *Backend) createDataset(table string, names []string, dtypes map[string]frames.DType, keys []string) error {
+	for _, key := range keys {
+		if strings.ContainsAny(key, "=/") || strings.HasPrefix(key, ".") || strings.HasPrefix(key, "_") {
+			return fmt.Errorf("bad partition key name - %q", key)
+		}
+	}
+
+	if err := os.MkdirAll(b.csvPath(table), 0755); err != nil {
+		return errors.Wrapf(err, "can't create table directory")
+	}
+
+	return b.writeSchema(table, names, dtypes, keys)
+}
+
+// isPartitioned returns true if the table directory is a partitioned table,
+// it has partition keys in the table schema or key=value sub directories
+func (b *Backend) isPartitioned(table string) bool {
+	schema, err := b.readSchema(table)
+	if err == nil && schema != nil {
+		if _, keys := splitSchema(schema); len(keys) > 0 {
+			return true
+		}
+	}
+
+	infos, err := ioutil.ReadDir(b.csvPath(table))
+	if err != nil {
+		return false
+	}
+
+	for _, info := range infos {
+		if info.IsDir() && strings.Index(info.Name(), "=") > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// partitionAppender routes rows to partition files by the partition key values,
+// there's a new file for every partition written. In overwrite mode the files
+// are written to a temporary directory which replaces the table on completion
+type partitionAppender struct {
+	ctx       context.Context
+	logger    logger.Logger
+	backend   *Backend
+	table     string
+	mode      frames.SaveMode
+	dir       string
+	temporary bool // dir replaces the table on completion
+	keys      []string
+	keyTypes  map[string]frames.DType
+	names     []string // data columns
+	// Column types for the table schema file, nil for existing tables without
+	// schema file
+	dtypes    map[string]frames.DType
+	prefix    string // file name prefix
+	appenders map[string]*csvAppender
+	order     []string // partition directories in order of creation
+	closed    bool
+}
+
+// newPartitionAppender returns an appender to the table directory
+func (b *Backend) newPartitionAppender(ctx context.Context, request *frames.WriteRequest) (*partitionAppender, error) {
+	ds, err := b.openDataset(request.Table, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pa := &partitionAppender{
+		ctx:       ctx,
+		logger:    b.logger,
+		backend:   b,
+		table:     request.Table,
+		mode:      request.SaveMode,
+		keys:      ds.keys,
+		keyTypes:  make(map[string]frames.DType),
+		prefix:    fmt.Sprintf("part-%d", time.Now().UnixNano()),
+		appenders: make(map[string]*csvAppender),
+	}
+
+	switch request.SaveMode {
+	case frames.OverwriteMode:
+		pa.dtypes = make(map[string]frames.DType)
+		tablePath := b.csvPath(request.Table)
+		pa.dir, err = ioutil.TempDir(filepath.Dir(tablePath), "."+filepath.Base(tablePath)+".tmp")
+		if err != nil {
+			return nil, errors.Wrap(err, "can't create temporary directory")
+		}
+		pa.temporary = true
+	case frames.AppendMode, frames.AppendNewColumnsMode:
+		pa.dir = ds.path
+		for k, key := range ds.keys {
+			pa.keyTypes[key] = ds.keyTypes[k]
+		}
+
+		if ds.schema != nil {
+			pa.dtypes = make(map[string]frames.DType)
+			for _, field := range ds.schema.Fields {
+				pa.names = append(pa.names, field.Name)
+				if field.Type == "" {
+					continue
+				}
+
+				dtype, err := utils.SchemaDType(field.Type)
+				if err != nil {
+					return nil, errors.Wrapf(err, "schema field %q", field.Name)
+				}
+				pa.dtypes[field.Name] = dtype
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown save mode - %s", request.SaveMode)
+	}
+
+	return pa, nil
+}
+
+// Add writes frame rows to their partitions, partition key columns must not
+// have nulls. In case of a failure the appender is closed
+func (pa *partitionAppender) Add(frame frames.Frame) error {
+	if err := pa.add(frame); err != nil {
+		pa.abort()
+		return err
+	}
+
+	return nil
+}
+
+func (pa *partitionAppender) add(frame frames.Frame) error {
+	if pa.closed {
+		return fmt.Errorf("appender is closed")
+	}
+
+	if err := pa.ctx.Err(); err != nil {
+		return err
+	}
+
+	pa.logger.InfoWith("adding frame", "size", frame.Len(), "table", pa.table)
+	keyCols := make([]frames.Column, len(pa.keys))
+	for k, key := range pa.keys {
+		col, err := frame.Column(key)
+		if err != nil {
+			return fmt.Errorf("partition key %q not in frame", key)
+		}
+
+		if err := pa.checkType(pa.keyTypes, col); err != nil {
+			return err
+		}
+		keyCols[k] = col
+	}
+
+	var dataNames []string
+	for _, name := range frame.Names() {
+		if indexOf(name, pa.keys) == -1 {
+			dataNames = append(dataNames, name)
+		}
+	}
+
+	if err := pa.checkColumns(frame, dataNames); err != nil {
+		return err
+	}
+
+	data, err := ops.Select(frame, dataNames...)
+	if err != nil {
+		return err
+	}
+
+	var dirs []string
+	rows := make(map[string][]int)
+	for r := 0; r < frame.Len(); r++ {
+		dir, err := pa.partitionDir(keyCols, r)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := rows[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		rows[dir] = append(rows[dir], r)
+	}
+
+	for _, dir := range dirs {
+		part, err := ops.Take(data, rows[dir])
+		if err != nil {
+			return err
+		}
+
+		ca, err := pa.appender(dir)
+		if err != nil {
+			return err
+		}
+
+		if err := ca.add(part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkColumns checks data column types and adds new columns, new columns
+// are allowed in the first frame or in AppendNewColumnsMode
+func (pa *partitionAppender) checkColumns(frame frames.Frame, names []string) error {
+	var newNames []string
+	for _, name := range names {
+		col, err := frame.Column(name)
+		if err != nil {
+			return errors.Wrap(err, "can't get column")
+		}
+
+		if pa.dtypes != nil {
+			if err := pa.checkType(pa.dtypes, col); err != nil {
+				return err
+			}
+		}
+
+		if indexOf(name, pa.names) == -1 {
+			newNames = append(newNames, name)
+		}
+	}
+
+	if len(newNames) == 0 {
+		return nil
+	}
+
+	if len(pa.names) > 0 && pa.mode != frames.AppendNewColumnsMode {
+		return fmt.Errorf("columns %v are not in table columns %v", newNames, pa.names)
+	}
+
+	pa.names = append(pa.names, newNames...)
+	return nil
+}
+
+// checkType checks the type of col against dtypes, unknown columns are added
+func (pa *partitionAppender) checkType(dtypes map[string]frames.DType, col frames.Column) error {
+	dtype, ok := dtypes[col.Name()]
+	if !ok {
+		dtypes[col.Name()] = col.DType()
+		return nil
+	}
+
+	// Integers can be read as float, anything can be read as string
+	if widenDType(dtype, col.DType()) != dtype {
+		return fmt.Errorf("column %q type mismatch (%s != %s)", col.Name(), utils.DTypeName(col.DType()), utils.DTypeName(dtype))
+	}
+
+	return nil
+}
+
+// partitionDir returns the partition directory of row r
+func (pa *partitionAppender) partitionDir(keyCols []frames.Column, r int) (string, error) {
+	parts := make([]string, len(keyCols))
+	for k, col := range keyCols {
+		if col.IsNull(r) {
+			return "", fmt.Errorf("%s:%d null partition key", col.Name(), r)
+		}
+
+		var value string
+		if col.DType() == frames.TimeType {
+			t, err := col.TimeAt(r)
+			if err != nil {
+				return "", err
+			}
+			value = t.Format(pa.backend.timeFormats[0])
+		} else {
+			v, err := utils.ColAt(col, r)
+			if err != nil {
+				return "", errors.Wrapf(err, "%s:%d can't get value", col.Name(), r)
+			}
+			value = fmt.Sprintf("%v", v)
+		}
+
+		parts[k] = col.Name() + "=" + url.PathEscape(value)
+	}
+
+	return path.Join(parts...), nil
+}
+
+// appender returns the appender of the partition in dir, creating it if needed
+func (pa *partitionAppender) appender(dir string) (*csvAppender, error) {
+	if ca, ok := pa.appenders[dir]; ok {
+		return ca, nil
+	}
+
+	partDir := filepath.Join(pa.dir, filepath.FromSlash(dir))
+	if err := os.MkdirAll(partDir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "can't create partition directory")
+	}
+
+	ca := &csvAppender{
+		ctx:     pa.ctx,
+		logger:  pa.logger,
+		backend: pa.backend,
+		table:   pa.table,
+		path:    filepath.Join(partDir, fmt.Sprintf("%s-%04d%s", pa.prefix, len(pa.order), pa.backend.fileExt())),
+		// Frames may have different columns, pa checks the table columns
+		mode: frames.AppendNewColumnsMode,
+	}
+
+	if err := ca.create(); err != nil {
+		return nil, err
+	}
+
+	pa.appenders[dir] = ca
+	pa.order = append(pa.order, dir)
+	return ca, nil
+}
+
+// WaitForComplete wait for write completion
+func (pa *partitionAppender) WaitForComplete(timeout time.Duration) error {
+	if pa.closed {
+		return fmt.Errorf("appender is closed")
+	}
+
+	if err := pa.complete(); err != nil {
+		pa.abort()
+		return err
+	}
+
+	pa.closed = true
+	return nil
+}
+
+func (pa *partitionAppender) complete() error {
+	for _, dir := range pa.order {
+		if err := pa.appenders[dir].complete(); err != nil {
+			return err
+		}
+	}
+
+	if pa.temporary {
+		if err := pa.replaceTable(); err != nil {
+			return err
+		}
+		pa.temporary = false
+	}
+
+	if pa.dtypes == nil {
+		return nil
+	}
+
+	names := append(append([]string{}, pa.names...), pa.keys...)
+	dtypes := make(map[string]frames.DType)
+	for _, tmap := range []map[string]frames.DType{pa.dtypes, pa.keyTypes} {
+		for name, dtype := range tmap {
+			dtypes[name] = dtype
+		}
+	}
+
+	return pa.backend.writeSchema(pa.table, names, dtypes, pa.keys)
+}
+
+// replaceTable replaces the table directory with the temporary one
+func (pa *partitionAppender) replaceTable() error {
+	tablePath := pa.backend.csvPath(pa.table)
+	oldPath := filepath.Join(filepath.Dir(tablePath), fmt.Sprintf(".%s.old%d", filepath.Base(tablePath), time.Now().UnixNano()))
+	if err := os.Rename(tablePath, oldPath); err != nil {
+		if !os.IsNotExist(err) {
+			return errors.Wrap(err, "can't move table directory")
+		}
+		oldPath = ""
+	}
+
+	if err := os.Rename(pa.dir, tablePath); err != nil {
+		if oldPath != "" {
+			os.Rename(oldPath, tablePath)
+		}
+		return errors.Wrap(err, "can't replace table directory")
+	}
+
+	if oldPath != "" {
+		if err := os.RemoveAll(oldPath); err != nil {
+			pa.logger.WarnWith("can't remove old table directory", "path", oldPath, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// abort removes the files written
+func (pa *partitionAppender) abort() {
+	if pa.closed {
+		return
+	}
+
+	for _, dir := range pa.order {
+		ca := pa.appenders[dir]
+		if ca.file != nil {
+			ca.abort()
+			continue
+		}
+
+		// Completed file
+		if err := os.Remove(ca.path); err != nil && !os.IsNotExist(err) {
+			pa.logger.WarnWith("can't remove partition file", "path", ca.path, "error", err)
+		}
+	}
+
+	if pa.temporary {
+		if err := os.RemoveAll(pa.dir); err != nil {
+			pa.logger.WarnWith("can't remove temporary directory", "path", pa.dir, "error", err)
+		}
+	}
+
+	pa.closed = true
+}
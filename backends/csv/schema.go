@@ -0,0 +1,162 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package csv
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/pb"
+)
+
+// Schema field property of partition keys (see partition.go)
+const partitionProperty = "partition"
+
+// schemaFile is the format of the table schema file, it's a hidden file in the
+// table directory. Fields without type are inferred when reading
+type schemaFile struct {
+	Fields []schemaFileField `json:"fields"`
+}
+
+type schemaFileField struct {
+	Name      string `json:"name"`
+	Type      string `json:"type,omitempty"`
+	Partition bool   `json:"partition,omitempty"`
+}
+
+func (b *Backend) schemaPath(table string) string {
+	csvPath := b.csvPath(table)
+	return filepath.Join(filepath.Dir(csvPath), "."+filepath.Base(csvPath)+".schema.json")
+}
+
+// readSchema returns the table schema from the schema file, nil if there's no
+// schema file
+func (b *Backend) readSchema(table string) (*frames.TableSchema, error) {
+	data, err := ioutil.ReadFile(b.schemaPath(table))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "can't read %q schema", table)
+	}
+
+	var sf schemaFile
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return nil, errors.Wrapf(err, "bad %q schema", table)
+	}
+
+	schema := &frames.TableSchema{}
+	for _, field := range sf.Fields {
+		schemaField := &frames.SchemaField{Name: field.Name, Type: field.Type}
+		if field.Partition {
+			schemaField.Properties = map[string]*pb.Value{
+				partitionProperty: &pb.Value{Value: &pb.Value_Bval{Bval: true}},
+			}
+		}
+		schema.Fields = append(schema.Fields, schemaField)
+	}
+
+	return schema, nil
+}
+
+// writeSchema replaces the table schema file, columns missing from dtypes have
+// no type. Partition keys are fields as well
+func (b *Backend) writeSchema(table string, names []string, dtypes map[string]frames.DType, partitions []string) error {
+	isPartition := make(map[string]bool)
+	for _, name := range partitions {
+		isPartition[name] = true
+	}
+
+	var sf schemaFile
+	for _, name := range names {
+		field := schemaFileField{Name: name, Partition: isPartition[name]}
+		if dtype, ok := dtypes[name]; ok {
+			field.Type = utils.DTypeName(dtype)
+		}
+		sf.Fields = append(sf.Fields, field)
+	}
+
+	data, err := json.MarshalIndent(sf, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "can't encode schema")
+	}
+
+	schemaPath := b.schemaPath(table)
+	file, err := utils.TempFile(schemaPath)
+	if err != nil {
+		return errors.Wrap(err, "can't create schema file")
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return errors.Wrap(err, "can't write schema file")
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return errors.Wrap(err, "can't close schema file")
+	}
+
+	if err := os.Rename(file.Name(), schemaPath); err != nil {
+		os.Remove(file.Name())
+		return errors.Wrap(err, "can't replace schema file")
+	}
+
+	return nil
+}
+
+// isPartitionField returns true if field is a partition key
+func isPartitionField(field *frames.SchemaField) bool {
+	value, ok := field.Property(partitionProperty)
+	if !ok {
+		return false
+	}
+
+	isPartition, ok := value.(bool)
+	return ok && isPartition
+}
+
+// splitSchema splits schema to the data fields schema and the partition keys,
+// schema can be nil
+func splitSchema(schema *frames.TableSchema) (*frames.TableSchema, []*frames.SchemaField) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	data := &frames.TableSchema{}
+	var partitions []*frames.SchemaField
+	for _, field := range schema.Fields {
+		if isPartitionField(field) {
+			partitions = append(partitions, field)
+		} else {
+			data.Fields = append(data.Fields, field)
+		}
+	}
+
+	return data, partitions
+}
@@ -0,0 +1,548 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package csv
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+)
+
+// Number of rows evaluated at once in filtered delete
+const deleteChunkRows = 1024
+
+// csvAppender writes frames to the table file (appending) or to a temporary
+// file which replaces the table on completion
+type csvAppender struct {
+	ctx       context.Context
+	logger    logger.Logger
+	backend   *Backend
+	table     string
+	path      string
+	mode      frames.SaveMode
+	file      *os.File
+	temporary bool           // file replaces path on completion
+	out       io.WriteCloser // compressing writer to file
+	csvWriter *csv.Writer
+	header    []string // nil until known
+	// Column types for the table schema file, nil for existing tables without
+	// schema file
+	dtypes        map[string]frames.DType
+	schemaChanged bool
+}
+
+// create starts writing to a new temporary file
+func (ca *csvAppender) create() error {
+	file, err := utils.TempFile(ca.path)
+	if err != nil {
+		return errors.Wrap(err, "can't create file")
+	}
+
+	if err := ca.setFile(file); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	ca.temporary = true
+	return nil
+}
+
+// setFile starts writing to file, compressed by the table compression
+func (ca *csvAppender) setFile(file *os.File) error {
+	out, err := ca.backend.newCompressor(file, ca.backend.compression(ca.path))
+	if err != nil {
+		return err
+	}
+
+	ca.file, ca.out, ca.csvWriter = file, out, ca.backend.newWriter(out)
+	return nil
+}
+
+// flush writes buffered data to the file, the compressor is closed and a new
+// one must be set to continue writing
+func (ca *csvAppender) flush() error {
+	ca.csvWriter.Flush()
+	if err := ca.csvWriter.Error(); err != nil {
+		return err
+	}
+
+	return ca.out.Close()
+}
+
+// createTable starts writing a new table
+func (ca *csvAppender) createTable() error {
+	ca.dtypes = make(map[string]frames.DType)
+	ca.schemaChanged = true
+	return ca.create()
+}
+
+// open starts appending to the table file, tables that don't exist or are
+// empty are created
+func (ca *csvAppender) open() error {
+	schema, err := ca.backend.readSchema(ca.table)
+	if err != nil {
+		return err
+	}
+
+	var header []string
+	if ca.backend.header {
+		if header, err = ca.backend.readHeader(ca.path); err != nil {
+			return err
+		}
+	} else if info, err := os.Stat(ca.path); err == nil && info.Size() > 0 {
+		if schema == nil {
+			return fmt.Errorf("can't append to header-less table %q without schema", ca.table)
+		}
+
+		for _, field := range schema.Fields {
+			header = append(header, field.Name)
+		}
+	}
+
+	if header == nil {
+		return ca.createTable()
+	}
+
+	if schema != nil {
+		ca.dtypes = make(map[string]frames.DType)
+		for _, field := range schema.Fields {
+			if field.Type == "" {
+				continue
+			}
+
+			dtype, err := utils.SchemaDType(field.Type)
+			if err != nil {
+				return errors.Wrapf(err, "schema field %q", field.Name)
+			}
+			ca.dtypes[field.Name] = dtype
+		}
+	}
+
+	file, err := os.OpenFile(ca.path, os.O_RDWR|os.O_APPEND, 0)
+	if err != nil {
+		return errors.Wrap(err, "can't open table file")
+	}
+
+	// Appended rows must start in a new line, compressed files are assumed to
+	// end with one. Appending to a gzip file adds a gzip member
+	if ca.backend.compression(ca.path) == noCompression {
+		if err := ensureNewline(file); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if err := ca.setFile(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	ca.temporary = false
+	ca.header = header
+	return nil
+}
+
+// ensureNewline adds a newline at the end of file if it's missing
+func ensureNewline(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Wrap(err, "can't stat table file")
+	}
+
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+		return errors.Wrap(err, "can't read table file")
+	}
+
+	if last[0] != '\n' {
+		if _, err := file.Write([]byte("\n")); err != nil {
+			return errors.Wrap(err, "can't write to table file")
+		}
+	}
+
+	return nil
+}
+
+// Add writes frame rows, columns are matched to the table header by name and
+// missing columns are written as nulls. In case of a failure the appender is
+// closed
+func (ca *csvAppender) Add(frame frames.Frame) error {
+	if err := ca.add(frame); err != nil {
+		ca.abort()
+		return err
+	}
+
+	return nil
+}
+
+func (ca *csvAppender) add(frame frames.Frame) error {
+	if ca.file == nil {
+		return fmt.Errorf("appender is closed")
+	}
+
+	if err := ca.ctx.Err(); err != nil {
+		return err
+	}
+
+	ca.logger.InfoWith("adding frame", "size", frame.Len())
+	if ca.header == nil {
+		if ca.backend.header {
+			if err := ca.writeRecord(frame.Names()); err != nil {
+				ca.logger.ErrorWith("can't write header", "error", err)
+				return errors.Wrap(err, "can't write header")
+			}
+		}
+		ca.header = frame.Names()
+	}
+
+	columns, err := ca.matchColumns(frame)
+	if err != nil {
+		return err
+	}
+
+	for r := 0; r < frame.Len(); r++ {
+		record := make([]string, len(columns))
+		for c, col := range columns {
+			if col == nil || col.IsNull(r) {
+				record[c] = ca.backend.nullValues[0]
+				continue
+			}
+
+			val, err := utils.ColAt(col, r)
+			if err != nil {
+				ca.logger.ErrorWith("can't get value", "error", err, "name", col.Name(), "row", r)
+				return errors.Wrapf(err, "%s:%d can't get value", col.Name(), r)
+			}
+
+			// Strings that are null values would be read back as nulls
+			if str, ok := val.(string); ok && ca.backend.isNull(str, frames.StringType) {
+				return fmt.Errorf("%s:%d string %q is a null value", col.Name(), r, str)
+			}
+
+			record[c] = fmt.Sprintf("%v", val)
+		}
+
+		if err := ca.writeRecord(record); err != nil {
+			ca.logger.ErrorWith("can't write record", "error", err)
+			return errors.Wrap(err, "can't write record")
+		}
+	}
+
+	return nil
+}
+
+// matchColumns returns the frame columns in header order (nil for missing
+// columns), in AppendNewColumnsMode new columns are added to the header.
+// Column types are checked against the table schema
+func (ca *csvAppender) matchColumns(frame frames.Frame) ([]frames.Column, error) {
+	inHeader := make(map[string]bool)
+	for _, name := range ca.header {
+		inHeader[name] = true
+	}
+
+	byName := make(map[string]frames.Column)
+	var newNames []string
+	for _, name := range frame.Names() {
+		col, err := frame.Column(name)
+		if err != nil {
+			ca.logger.ErrorWith("can't get column", "error", err)
+			return nil, errors.Wrap(err, "can't get column")
+		}
+
+		byName[name] = col
+		if !inHeader[name] {
+			newNames = append(newNames, name)
+		}
+
+		if ca.dtypes == nil {
+			continue
+		}
+
+		dtype, ok := ca.dtypes[name]
+		if !ok {
+			ca.dtypes[name] = col.DType()
+			ca.schemaChanged = true
+			continue
+		}
+
+		// Integers can be read as float, anything can be read as string
+		if widenDType(dtype, col.DType()) != dtype {
+			return nil, fmt.Errorf("column %q type mismatch (%s != %s)", name, utils.DTypeName(col.DType()), utils.DTypeName(dtype))
+		}
+	}
+
+	if len(newNames) > 0 {
+		if ca.mode != frames.AppendNewColumnsMode {
+			return nil, fmt.Errorf("columns %v are not in table header %v", newNames, ca.header)
+		}
+
+		if err := ca.extendHeader(newNames); err != nil {
+			return nil, err
+		}
+	}
+
+	columns := make([]frames.Column, len(ca.header))
+	for i, name := range ca.header {
+		columns[i] = byName[name]
+	}
+
+	return columns, nil
+}
+
+// extendHeader adds names to the header, the rows written so far are copied
+// to a new temporary file with empty values in the new columns
+func (ca *csvAppender) extendHeader(names []string) error {
+	ca.logger.InfoWith("extending header", "path", ca.path, "columns", names)
+	if err := ca.flush(); err != nil {
+		return errors.Wrap(err, "can't flush")
+	}
+
+	src, err := ca.backend.openReader(ca.file.Name(), ca.backend.compression(ca.path))
+	if err != nil {
+		return errors.Wrap(err, "can't open file")
+	}
+	defer src.Close()
+
+	old, oldTemporary := ca.file, ca.temporary
+	if err := ca.create(); err != nil {
+		return err
+	}
+
+	header := append(append([]string{}, ca.header...), names...)
+	if err := ca.copyRows(src, header); err != nil {
+		// Remove the new file and go back to the old one
+		ca.abort()
+		if setErr := ca.setFile(old); setErr != nil {
+			old.Close()
+			return setErr
+		}
+		ca.temporary = oldTemporary
+		return err
+	}
+
+	old.Close()
+	if oldTemporary {
+		if err := os.Remove(old.Name()); err != nil {
+			ca.logger.WarnWith("can't remove temporary file", "path", old.Name(), "error", err)
+		}
+	}
+
+	ca.header = header
+	return nil
+}
+
+// copyRows writes header and the rows of src (without its header) to the
+// current file, missing values are nulls
+func (ca *csvAppender) copyRows(src io.Reader, header []string) error {
+	reader := ca.backend.newReader(src)
+	reader.FieldsPerRecord = -1
+	if ca.backend.header {
+		if err := ca.writeRecord(header); err != nil {
+			return errors.Wrap(err, "can't write header")
+		}
+
+		if _, err := reader.Read(); err != nil {
+			return errors.Wrap(err, "can't read header")
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "can't read record")
+		}
+
+		for len(record) < len(header) {
+			record = append(record, ca.backend.nullValues[0])
+		}
+
+		if err := ca.writeRecord(record); err != nil {
+			return errors.Wrap(err, "can't write record")
+		}
+	}
+}
+
+func (ca *csvAppender) writeRecord(record []string) error {
+	// csv.Writer writes a single empty field as an empty line, which
+	// readers skip
+	if len(record) == 1 && record[0] == "" {
+		ca.csvWriter.Flush()
+		_, err := io.WriteString(ca.out, "\"\"\n")
+		return err
+	}
+
+	return ca.csvWriter.Write(record)
+}
+
+// deleteRows copies the rows of it that don't match filter, it must have all
+// filter columns selected
+func (ca *csvAppender) deleteRows(it *FrameIterator, filter *utils.Filter) error {
+	if ca.backend.header {
+		if err := ca.writeRecord(it.columnNames); err != nil {
+			return errors.Wrap(err, "can't write header")
+		}
+	}
+
+	it.frameLimit = deleteChunkRows
+	pred := filter.Predicate()
+	numDeleted := 0
+	for {
+		if err := ca.ctx.Err(); err != nil {
+			return err
+		}
+
+		rows, err := it.readNextRows()
+		if err != nil {
+			return err
+		}
+
+		if len(rows) == 0 {
+			break
+		}
+
+		frame, err := it.buildFrame(rows)
+		if err != nil {
+			return err
+		}
+
+		match, err := pred(frame)
+		if err != nil {
+			return errors.Wrap(err, "can't evaluate filter")
+		}
+
+		for r, row := range rows {
+			if match(r) {
+				numDeleted++
+				continue
+			}
+
+			if err := ca.writeRecord(row); err != nil {
+				return errors.Wrap(err, "can't write record")
+			}
+		}
+	}
+
+	ca.logger.InfoWith("deleted rows", "table", ca.table, "count", numDeleted)
+	return nil
+}
+
+// WaitForComplete wait for write completion
+func (ca *csvAppender) WaitForComplete(timeout time.Duration) error {
+	if ca.file == nil {
+		return fmt.Errorf("appender is closed")
+	}
+
+	if err := ca.complete(); err != nil {
+		ca.abort()
+		return err
+	}
+
+	return nil
+}
+
+func (ca *csvAppender) complete() error {
+	if err := ca.flush(); err != nil {
+		ca.logger.ErrorWith("csv Flush", "error", err)
+		return err
+	}
+
+	if err := ca.file.Sync(); err != nil {
+		return errors.Wrap(err, "can't sync file")
+	}
+
+	if err := ca.file.Close(); err != nil {
+		return errors.Wrap(err, "can't close file")
+	}
+
+	if ca.temporary {
+		if err := os.Rename(ca.file.Name(), ca.path); err != nil {
+			return errors.Wrap(err, "can't replace table file")
+		}
+	}
+
+	ca.file = nil
+	if ca.dtypes == nil || !ca.schemaChanged {
+		return nil
+	}
+
+	if len(ca.header) == 0 {
+		// Table without columns
+		if err := os.Remove(ca.backend.schemaPath(ca.table)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "can't remove schema file")
+		}
+		return nil
+	}
+
+	return ca.backend.writeSchema(ca.table, ca.header, ca.dtypes, nil)
+}
+
+// abort closes the file and removes it if temporary
+func (ca *csvAppender) abort() {
+	if ca.file == nil {
+		return
+	}
+
+	ca.file.Close()
+	if ca.temporary {
+		if err := os.Remove(ca.file.Name()); err != nil {
+			ca.logger.WarnWith("can't remove temporary file", "path", ca.file.Name(), "error", err)
+		}
+	}
+	ca.file = nil
+}
+
+// readHeader returns the header of the CSV file in path, nil if the file is
+// missing or empty
+func (b *Backend) readHeader(path string) ([]string, error) {
+	file, err := b.openReader(path, b.compression(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer file.Close()
+	header, err := b.newReader(file).Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read header (columns)")
+	}
+
+	return header, nil
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package jsonl
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends"
+	"github.com/v3io/frames/backends/utils"
+)
+
+// Default number of records used to infer column types
+const defaultSampleRows = 100
+
+// Backend is JSON Lines backend, tables are files under rootDir with a JSON
+// object per line
+type Backend struct {
+	rootDir     string
+	logger      logger.Logger
+	sampleRows  int
+	flatten     bool
+	timeFormats []string
+}
+
+// NewBackend returns a new JSON Lines backend. Options (in config.Options) are:
+//
+//	sampleRows: number of records used to infer column types (default 100)
+//	flatten: nested objects are read as columns with dotted names (e.g.
+//		"a.b"), and written back as nested objects (default true)
+//	timeFormats: layouts (see time.Parse) of string values read as time
+//		(default RFC3339)
+func NewBackend(logger logger.Logger, config *frames.BackendConfig, framesConfig *frames.Config) (frames.DataBackend, error) {
+	backend := &Backend{
+		rootDir: config.RootDir,
+		logger:  logger.GetChild("jsonl"),
+	}
+
+	if err := backend.parseOptions(config.Options); err != nil {
+		return nil, errors.Wrap(err, "bad JSON Lines backend options")
+	}
+
+	return backend, nil
+}
+
+func (b *Backend) parseOptions(options map[string]interface{}) error {
+	var err error
+	if b.sampleRows, err = utils.IntOption(options, "sampleRows", defaultSampleRows); err != nil {
+		return err
+	}
+
+	if b.sampleRows < 1 {
+		return fmt.Errorf("bad sampleRows - %d", b.sampleRows)
+	}
+
+	if b.flatten, err = utils.BoolOption(options, "flatten", true); err != nil {
+		return err
+	}
+
+	if b.timeFormats, err = utils.StringsOption(options, "timeFormats", []string{time.RFC3339}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Create creates an empty table, column types are inferred on read so the
+// schema is not saved
+func (b *Backend) Create(ctx context.Context, request *frames.CreateRequest) error {
+	filePath := b.tablePath(request.Table)
+	if fileExists(filePath) {
+		if request.IfExists == frames.IgnoreError {
+			return nil
+		}
+		return fmt.Errorf("table %q already exists", request.Table)
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return errors.Wrap(err, "can't create table file")
+	}
+
+	return file.Close()
+}
+
+// Delete deletes a table
+func (b *Backend) Delete(ctx context.Context, request *frames.DeleteRequest) error {
+	if request.Filter != "" {
+		return fmt.Errorf("JSON Lines backend does not support filtered delete")
+	}
+
+	filePath := b.tablePath(request.Table)
+	if !fileExists(filePath) {
+		if request.IfMissing == frames.IgnoreError {
+			return nil
+		}
+		return fmt.Errorf("table %q doesn't exist", request.Table)
+	}
+
+	if err := os.Remove(filePath); err != nil {
+		return errors.Wrapf(err, "can't delete %q", request.Table)
+	}
+
+	return nil
+}
+
+// Read reads a table, column types are taken from the request schema or are
+// inferred from the first records. The filter and columns are applied in
+// process
+func (b *Backend) Read(ctx context.Context, request *frames.ReadRequest) (frames.FrameIterator, error) {
+	var filter *utils.Filter
+	if request.Filter != "" {
+		var err error
+		if filter, err = utils.ParseFilter(request.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	it, err := b.newIterator(ctx, request.Table, request.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := it.selectColumns(request.Columns, filter); err != nil {
+		it.close()
+		return nil, err
+	}
+
+	it.limit = int(request.Limit)
+	it.frameLimit = int(request.MessageLimit)
+	return it, nil
+}
+
+// Write writes frames as a JSON object per row, null values are omitted. In
+// overwrite mode the rows are written to a temporary file which replaces the
+// table in WaitForComplete. Rows are self describing, both append modes add
+// rows with any columns
+func (b *Backend) Write(ctx context.Context, request *frames.WriteRequest) (frames.FrameAppender, error) {
+	ja := &jsonlAppender{
+		ctx:     ctx,
+		logger:  b.logger,
+		backend: b,
+		path:    b.tablePath(request.Table),
+	}
+
+	var err error
+	switch request.SaveMode {
+	case frames.OverwriteMode:
+		err = ja.create()
+	case frames.AppendMode, frames.AppendNewColumnsMode:
+		err = ja.open()
+	default:
+		err = fmt.Errorf("unknown save mode - %s", request.SaveMode)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if request.ImmidiateData != nil {
+		if err := ja.Add(request.ImmidiateData); err != nil {
+			return nil, errors.Wrap(err, "can't Add ImmidiateData")
+		}
+	}
+
+	return ja, nil
+}
+
+// Exec executes a command
+func (b *Backend) Exec(ctx context.Context, request *frames.ExecRequest) (frames.Frame, error) {
+	if strings.ToLower(request.Command) == "ping" {
+		b.logger.Info("PONG")
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("JSON Lines backend does not support %q exec command", request.Command)
+}
+
+// List lists the tables (files) in root directory
+func (b *Backend) List(ctx context.Context, request *frames.ListRequest) ([]string, error) {
+	dir := filepath.Join(b.rootDir, request.Path)
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't list %q", request.Path)
+	}
+
+	var tables []string
+	for _, info := range infos {
+		if !info.Mode().IsRegular() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+
+		tables = append(tables, path.Join(request.Path, info.Name()))
+	}
+
+	return tables, nil
+}
+
+// Describe returns the table schema inferred from the first records
+func (b *Backend) Describe(ctx context.Context, request *frames.DescribeRequest) (*frames.TableInfo, error) {
+	it, err := b.newIterator(ctx, request.Table, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer it.close()
+	schema := &frames.TableSchema{}
+	for i, name := range it.columnNames {
+		field := &frames.SchemaField{Name: name, Type: utils.DTypeName(it.dtypes[i])}
+		schema.Fields = append(schema.Fields, field)
+	}
+
+	return &frames.TableInfo{Name: request.Table, Schema: schema}, nil
+}
+
+// Capabilities returns the backend capabilities
+func (b *Backend) Capabilities() *frames.Capabilities {
+	return &frames.Capabilities{
+		Operations: []string{
+			frames.ReadOperation, frames.WriteOperation, frames.CreateOperation,
+			frames.DeleteOperation, frames.ExecOperation, frames.ListOperation,
+			frames.DescribeOperation,
+		},
+		ExecCommands: []*frames.ExecCommandInfo{
+			{Name: "ping", Doc: "check the backend is alive"},
+		},
+		FilterDialect:   "sql",
+		FilterOperators: b.Pushdown().FilterOperators,
+		Dtypes:          frames.AllDTypes(),
+	}
+}
+
+// Pushdown returns the read request parts handled by the backend, columns and
+// filters in the KV filter syntax
+func (b *Backend) Pushdown() *frames.Pushdown {
+	return &frames.Pushdown{
+		Columns:         true,
+		FilterOperators: utils.FilterOperators,
+	}
+}
+
+func (b *Backend) tablePath(table string) string {
+	return fmt.Sprintf("%s/%s", b.rootDir, table)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func init() {
+	if err := backends.Register("jsonl", NewBackend); err != nil {
+		panic(err)
+	}
+}
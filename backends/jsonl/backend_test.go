@@ -0,0 +1,311 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package jsonl
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/conformance"
+)
+
+var jsonlData = `{"id": 1, "name": "a", "pos": {"x": 1.5, "y": 2}, "tags": ["t1"], "ok": true, "time": "2018-12-01T10:00:00Z"}
+{"id": 2, "name": "b", "pos": {"x": 2, "y": 3}, "ok": false}
+
+{"id": 3, "name": null, "pos": {"x": 3.5}, "tags": [], "ok": true, "time": "2018-12-02T10:00:00Z", "extra": 1}
+`
+
+func TestRead(t *testing.T) {
+	backend := newBackend(t, nil)
+	writeTable(t, backend, "t.jsonl", jsonlData)
+
+	info, err := backend.Describe(context.Background(), &frames.DescribeRequest{Table: "t.jsonl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names, types []string
+	for _, field := range info.Schema.Fields {
+		names = append(names, field.Name)
+		types = append(types, field.Type)
+	}
+
+	expectedNames := []string{"id", "name", "pos.x", "pos.y", "tags", "ok", "time", "extra"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("bad names - %v", names)
+	}
+
+	expectedTypes := []string{"integer", "string", "float", "integer", "string", "boolean", "time", "integer"}
+	if !reflect.DeepEqual(types, expectedTypes) {
+		t.Fatalf("bad types - %v", types)
+	}
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "t.jsonl", MessageLimit: 2})
+	if len(frs) != 2 || frs[0].Len() != 2 || frs[1].Len() != 1 {
+		t.Fatalf("bad frames - %v", frs)
+	}
+
+	col, err := frs[1].Column("name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !col.IsNull(0) {
+		t.Fatal("name is not null")
+	}
+
+	col, err = frs[0].Column("tags")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tags, _ := col.StringAt(0); tags != `["t1"]` {
+		t.Fatalf("bad tags - %q", tags)
+	}
+
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "t.jsonl", Limit: 2})
+	if len(frs) != 1 || frs[0].Len() != 2 {
+		t.Fatalf("bad limit frames - %v", frs)
+	}
+
+	// Types from the sample only, "extra" is ignored
+	sampleBackend := newBackend(t, map[string]interface{}{"sampleRows": 1, "flatten": false})
+	writeTable(t, sampleBackend, "t.jsonl", jsonlData)
+	frs = readTable(t, sampleBackend, &frames.ReadRequest{Table: "t.jsonl"})
+	if names := frs[0].Names(); !reflect.DeepEqual(names, []string{"id", "name", "pos", "tags", "ok", "time"}) {
+		t.Fatalf("bad names - %v", names)
+	}
+
+	writeTable(t, sampleBackend, "bad.jsonl", "{\"a\": 1}\n{\"a\": \"x\"}\n")
+	it, err := sampleBackend.Read(context.Background(), &frames.ReadRequest{Table: "bad.jsonl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for it.Next() {
+	}
+
+	if it.Err() == nil {
+		t.Fatal("no error on type mismatch")
+	}
+}
+
+func TestReadFilter(t *testing.T) {
+	backend := newBackend(t, nil)
+	writeTable(t, backend, "t.jsonl", jsonlData)
+
+	request := &frames.ReadRequest{
+		Table:        "t.jsonl",
+		Filter:       "id > 1 AND ok == true",
+		Columns:      []string{"pos.x", "id"},
+		MessageLimit: 1,
+	}
+
+	frs := readTable(t, backend, request)
+	if len(frs) != 1 || frs[0].Len() != 1 {
+		t.Fatalf("bad frames - %v", frs)
+	}
+
+	if names := frs[0].Names(); !reflect.DeepEqual(names, request.Columns) {
+		t.Fatalf("bad names - %v", names)
+	}
+
+	col, err := frs[0].Column("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id, _ := col.IntAt(0); id != 3 {
+		t.Fatalf("bad id - %d", id)
+	}
+
+	// Limit is applied after filtering
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "t.jsonl", Filter: "id >= 2", Limit: 1})
+	if len(frs) != 1 || frs[0].Len() != 1 {
+		t.Fatalf("bad limit frames - %v", frs)
+	}
+
+	badRequests := []*frames.ReadRequest{
+		{Table: "t.jsonl", Filter: "nope > 1"},
+		{Table: "t.jsonl", Columns: []string{"nope"}},
+		{Table: "t.jsonl", Filter: "id >"},
+	}
+
+	for _, request := range badRequests {
+		if _, err := backend.Read(context.Background(), request); err == nil {
+			t.Fatalf("no error for %+v", request)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	backend := newBackend(t, nil)
+	write := func(mode frames.SaveMode, columns ...frames.Column) {
+		frame, err := frames.NewFrame(columns, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		request := &frames.WriteRequest{Table: "t.jsonl", SaveMode: mode, ImmidiateData: frame}
+		appender, err := backend.Write(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.WaitForComplete(time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Date(2018, 12, 1, 10, 0, 0, 5, time.UTC)
+	write(frames.OverwriteMode,
+		testColumn(t, "id", []int64{1, 2}, nil),
+		testColumn(t, "pos.x", []float64{1, 2.5}, nil),
+		testColumn(t, "pos.y", []float64{3, 0}, []bool{false, true}),
+		testColumn(t, "time", []time.Time{now, now}, nil),
+	)
+	// New tables are readable by others, the same as created by append
+	info, err := os.Stat(backend.tablePath("t.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode := info.Mode().Perm(); mode != 0644 {
+		t.Fatalf("bad table file mode - %v", mode)
+	}
+
+	write(frames.AppendMode,
+		testColumn(t, "id", []int64{3}, nil),
+		testColumn(t, "name", []string{"c\""}, nil),
+	)
+
+	data, err := ioutil.ReadFile(backend.tablePath("t.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"id":1,"pos":{"x":1.0,"y":3.0},"time":"2018-12-01T10:00:00.000000005Z"}
+{"id":2,"pos":{"x":2.5},"time":"2018-12-01T10:00:00.000000005Z"}
+{"id":3,"name":"c\""}
+`
+	if string(data) != expected {
+		t.Fatalf("bad data:\n%s", data)
+	}
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "t.jsonl"})
+	if len(frs) != 1 || frs[0].Len() != 3 {
+		t.Fatalf("bad frames - %v", frs)
+	}
+
+	col, err := frs[0].Column("time")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value, _ := col.TimeAt(0); !value.Equal(now) {
+		t.Fatalf("bad time - %v", value)
+	}
+
+	frame, err := frames.NewFrame([]frames.Column{
+		testColumn(t, "a", []int64{1}, nil),
+		testColumn(t, "a.b", []int64{1}, nil),
+	}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := &frames.WriteRequest{Table: "t.jsonl", ImmidiateData: frame}
+	if _, err := backend.Write(context.Background(), request); err == nil {
+		t.Fatal("no error on conflicting columns")
+	}
+}
+
+func writeTable(t *testing.T, backend *Backend, table string, data string) {
+	if err := ioutil.WriteFile(backend.tablePath(table), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTable(t *testing.T, backend *Backend, request *frames.ReadRequest) []frames.Frame {
+	it, err := backend.Read(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frs []frames.Frame
+	for it.Next() {
+		frs = append(frs, it.At())
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return frs
+}
+
+func newBackend(t *testing.T, options map[string]interface{}) *Backend {
+	logger, err := frames.NewLogger("debug")
+	if err != nil {
+		t.Fatalf("can't create logger - %s", err)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "jsonl-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &frames.BackendConfig{
+		Name:    "testJSONL",
+		Type:    "jsonl",
+		RootDir: tmpDir,
+		Options: options,
+	}
+
+	backend, err := NewBackend(logger, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return backend.(*Backend)
+}
+
+func testColumn(t *testing.T, name string, data interface{}, nulls []bool) frames.Column {
+	col, err := frames.NewNullableSliceColumn(name, data, nulls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return col
+}
+
+func TestConformance(t *testing.T) {
+	factory := func(t *testing.T) frames.DataBackend {
+		return newBackend(t, nil)
+	}
+
+	conformance.Run(t, factory, nil)
+}
@@ -0,0 +1,592 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/ops"
+)
+
+// field is a record field, values are nil, bool, json.Number, string or
+// jsonText
+type field struct {
+	name  string
+	value interface{}
+}
+
+// jsonText is an array (or a nested object when not flattening), read as a
+// string column
+type jsonText string
+
+// newIterator opens table for reading and sets the columns from schema and the
+// sampled records, schema can be nil
+func (b *Backend) newIterator(ctx context.Context, table string, schema *frames.TableSchema) (*FrameIterator, error) {
+	file, err := os.Open(b.tablePath(table))
+	if err != nil {
+		return nil, err
+	}
+
+	it := &FrameIterator{
+		ctx:     ctx,
+		logger:  b.logger,
+		backend: b,
+		path:    table,
+		file:    file,
+		decoder: json.NewDecoder(bufio.NewReader(file)),
+	}
+	it.decoder.UseNumber()
+
+	if err := it.init(schema); err != nil {
+		it.close()
+		return nil, err
+	}
+
+	return it, nil
+}
+
+// FrameIterator iterates over JSON Lines records
+type FrameIterator struct {
+	ctx         context.Context
+	logger      logger.Logger
+	backend     *Backend
+	path        string
+	file        *os.File
+	decoder     *json.Decoder
+	frame       frames.Frame
+	err         error
+	columnNames []string
+	dtypes      []frames.DType
+	indices     map[string]int
+	unknown     map[string]bool // columns not in the schema (logged once)
+	sample      [][]field
+	nRows       int // records read
+	nOut        int // rows returned
+	limit       int
+	frameLimit  int
+	filter      *utils.Filter
+	columns     []string // returned columns, nil for all
+}
+
+// init sets the column names and types, columns not in schema are taken from
+// the sampled records in order of appearance
+func (it *FrameIterator) init(schema *frames.TableSchema) error {
+	dtypes := make(map[string]frames.DType)
+	if schema != nil {
+		for _, field := range schema.Fields {
+			it.columnNames = append(it.columnNames, field.Name)
+			if field.Type == "" {
+				continue
+			}
+
+			dtype, err := utils.SchemaDType(field.Type)
+			if err != nil {
+				return errors.Wrapf(err, "schema field %q", field.Name)
+			}
+			dtypes[field.Name] = dtype
+		}
+	}
+
+	it.sample = [][]field{}
+	for len(it.sample) < it.backend.sampleRows {
+		record, err := it.readRecord()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		it.sample = append(it.sample, record)
+	}
+
+	it.indices = make(map[string]int)
+	for i, name := range it.columnNames {
+		it.indices[name] = i
+	}
+
+	// Inferred types of sampled values
+	inferred := make(map[string]frames.DType)
+	noSchema := len(it.columnNames) == 0
+	for _, record := range it.sample {
+		for _, field := range record {
+			if _, ok := it.indices[field.name]; !ok {
+				if !noSchema {
+					continue
+				}
+				it.indices[field.name] = len(it.columnNames)
+				it.columnNames = append(it.columnNames, field.name)
+			}
+
+			if field.value == nil {
+				continue
+			}
+
+			vtype := it.valueDType(field.value)
+			if dtype, ok := inferred[field.name]; ok {
+				vtype = widenDType(dtype, vtype)
+			}
+			inferred[field.name] = vtype
+		}
+	}
+
+	it.dtypes = make([]frames.DType, len(it.columnNames))
+	for i, name := range it.columnNames {
+		dtype, ok := dtypes[name]
+		if !ok {
+			if dtype, ok = inferred[name]; !ok {
+				// Only nulls
+				dtype = frames.StringType
+			}
+		}
+		it.dtypes[i] = dtype
+	}
+
+	it.unknown = make(map[string]bool)
+	return nil
+}
+
+// selectColumns sets the returned columns, nil for all, and the filter
+func (it *FrameIterator) selectColumns(columns []string, filter *utils.Filter) error {
+	it.filter = filter
+	if len(columns) > 0 {
+		it.columns = columns
+	}
+
+	// Filter columns are validated even if all columns are returned
+	names := append([]string{}, columns...)
+	if filter != nil {
+		names = append(names, filter.Columns()...)
+	}
+
+	for _, name := range names {
+		if _, ok := it.indices[name]; !ok {
+			return fmt.Errorf("unknown column - %q", name)
+		}
+	}
+
+	return nil
+}
+
+// readRecord reads the next record as a list of fields
+func (it *FrameIterator) readRecord() ([]field, error) {
+	var raw json.RawMessage
+	if err := it.decoder.Decode(&raw); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "%s:%d bad record", it.path, it.nRows+len(it.sample))
+	}
+
+	var fields []field
+	if err := it.readObject(raw, "", &fields); err != nil {
+		return nil, errors.Wrapf(err, "%s:%d bad record", it.path, it.nRows+len(it.sample))
+	}
+
+	return fields, nil
+}
+
+// readObject appends the fields of the object in data to fields, keeping their
+// order. Nested objects are flattened to "prefix.key" fields
+func (it *FrameIterator) readObject(data []byte, prefix string, fields *[]field) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != json.Delim('{') {
+		return fmt.Errorf("not a JSON object")
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		name := prefix + token.(string)
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return err
+		}
+
+		switch raw[0] {
+		case '{':
+			if it.backend.flatten {
+				if err := it.readObject(raw, name+".", fields); err != nil {
+					return err
+				}
+				continue
+			}
+			*fields = append(*fields, field{name, jsonText(raw)})
+		case '[':
+			*fields = append(*fields, field{name, jsonText(raw)})
+		default:
+			var value interface{}
+			valueDecoder := json.NewDecoder(bytes.NewReader(raw))
+			valueDecoder.UseNumber()
+			if err := valueDecoder.Decode(&value); err != nil {
+				return err
+			}
+			*fields = append(*fields, field{name, value})
+		}
+	}
+
+	return nil
+}
+
+// Next reads the next frame, return true of succeeded
+func (it *FrameIterator) Next() bool {
+	if it.file == nil {
+		return false
+	}
+
+	for {
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			it.close()
+			return false
+		}
+
+		if it.limit > 0 && it.nOut >= it.limit {
+			it.close()
+			return false
+		}
+
+		var records [][]field
+		for it.inLimits(len(records)) {
+			record, err := it.nextRecord()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				it.logger.ErrorWith("can't read record", "error", err)
+				it.err = err
+				it.close()
+				return false
+			}
+
+			records = append(records, record)
+		}
+
+		if len(records) == 0 {
+			it.close()
+			return false
+		}
+
+		frame, err := it.buildFrame(records)
+		if err == nil {
+			frame, err = it.applyRequest(frame)
+		}
+
+		if err != nil {
+			it.logger.ErrorWith("can't build frame", "error", err)
+			it.err = err
+			it.close()
+			return false
+		}
+
+		// All the records were filtered out
+		if frame.Len() == 0 {
+			continue
+		}
+
+		it.frame = frame
+		it.nOut += frame.Len()
+		return true
+	}
+}
+
+// applyRequest applies the filter, limit and column selection on frame
+func (it *FrameIterator) applyRequest(frame frames.Frame) (frames.Frame, error) {
+	var err error
+	if it.filter != nil {
+		if frame, err = ops.Filter(frame, it.filter.Predicate()); err != nil {
+			return nil, err
+		}
+	}
+
+	if it.limit > 0 && it.nOut+frame.Len() > it.limit {
+		if frame, err = ops.Slice(frame, 0, it.limit-it.nOut); err != nil {
+			return nil, err
+		}
+	}
+
+	if it.columns == nil {
+		return frame, nil
+	}
+
+	return ops.Select(frame, it.columns...)
+}
+
+// nextRecord returns the next sampled record or reads one
+func (it *FrameIterator) nextRecord() ([]field, error) {
+	if len(it.sample) > 0 {
+		record := it.sample[0]
+		it.sample = it.sample[1:]
+		it.nRows++
+		return record, nil
+	}
+
+	record, err := it.readRecord()
+	if err == nil {
+		it.nRows++
+	}
+
+	return record, err
+}
+
+func (it *FrameIterator) inLimits(frameRow int) bool {
+	// With a filter the limit is checked after filtering
+	if it.filter == nil && it.limit > 0 && it.nOut+frameRow >= it.limit {
+		return false
+	}
+
+	if it.frameLimit > 0 && frameRow >= it.frameLimit {
+		return false
+	}
+
+	return true
+}
+
+// buildFrame builds a frame from records, fields not in the columns are
+// ignored
+func (it *FrameIterator) buildFrame(records [][]field) (frames.Frame, error) {
+	values := make([][]interface{}, len(it.columnNames))
+	for c := range values {
+		values[c] = make([]interface{}, len(records))
+	}
+
+	for r, record := range records {
+		for _, field := range record {
+			c, ok := it.indices[field.name]
+			if !ok {
+				if !it.unknown[field.name] {
+					it.logger.WarnWith("ignoring field not in columns", "path", it.path, "field", field.name)
+					it.unknown[field.name] = true
+				}
+				continue
+			}
+
+			if field.value == nil {
+				continue
+			}
+
+			value, err := it.convert(field.value, it.dtypes[c])
+			if err != nil {
+				row := it.nRows - len(records) + r
+				return nil, fmt.Errorf("%s:%d can't convert %v in column %q to %s", it.path, row, field.value, field.name, utils.DTypeName(it.dtypes[c]))
+			}
+			values[c][r] = value
+		}
+	}
+
+	columns := make([]frames.Column, len(it.columnNames))
+	for c, name := range it.columnNames {
+		col, err := newColumn(name, it.dtypes[c], values[c])
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't build column %s", name)
+		}
+		columns[c] = col
+	}
+
+	return frames.NewFrame(columns, nil, nil)
+}
+
+// At return the current Frame
+func (it *FrameIterator) At() frames.Frame {
+	return it.frame
+}
+
+// Err returns the last error
+func (it *FrameIterator) Err() error {
+	return it.err
+}
+
+func (it *FrameIterator) close() {
+	if it.file == nil {
+		return
+	}
+
+	if err := it.file.Close(); err != nil {
+		it.logger.WarnWith("can't close file", "path", it.path, "error", err)
+	}
+	it.file = nil
+}
+
+// valueDType returns the data type of a field value
+func (it *FrameIterator) valueDType(value interface{}) frames.DType {
+	switch value := value.(type) {
+	case bool:
+		return frames.BoolType
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return frames.IntType
+		}
+		return frames.FloatType
+	case string:
+		if _, err := it.parseTime(value); err == nil {
+			return frames.TimeType
+		}
+	}
+
+	return frames.StringType
+}
+
+// convert converts a field value to dtype, any value can be converted to string
+func (it *FrameIterator) convert(value interface{}, dtype frames.DType) (interface{}, error) {
+	switch dtype {
+	case frames.IntType:
+		if number, ok := value.(json.Number); ok {
+			return number.Int64()
+		}
+	case frames.FloatType:
+		if number, ok := value.(json.Number); ok {
+			return number.Float64()
+		}
+	case frames.BoolType:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case frames.TimeType:
+		if s, ok := value.(string); ok {
+			return it.parseTime(s)
+		}
+	case frames.StringType:
+		switch value := value.(type) {
+		case string:
+			return value, nil
+		case jsonText:
+			return string(value), nil
+		case json.Number:
+			return value.String(), nil
+		case bool:
+			return strconv.FormatBool(value), nil
+		}
+	}
+
+	return nil, fmt.Errorf("can't convert %T to %s", value, utils.DTypeName(dtype))
+}
+
+func (it *FrameIterator) parseTime(value string) (time.Time, error) {
+	var err error
+	for _, format := range it.backend.timeFormats {
+		var t time.Time
+		if t, err = time.Parse(format, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("can't parse %q as time", value)
+}
+
+// newColumn returns a column from values, nil values are nulls
+func newColumn(name string, dtype frames.DType, values []interface{}) (frames.Column, error) {
+	nulls := make([]bool, len(values))
+	hasNulls := false
+	for i, value := range values {
+		nulls[i] = value == nil
+		hasNulls = hasNulls || nulls[i]
+	}
+
+	if !hasNulls {
+		nulls = nil
+	}
+
+	var data interface{}
+	switch dtype {
+	case frames.IntType:
+		typed := make([]int64, len(values))
+		for i, value := range values {
+			if value != nil {
+				typed[i] = value.(int64)
+			}
+		}
+		data = typed
+	case frames.FloatType:
+		typed := make([]float64, len(values))
+		for i, value := range values {
+			if value != nil {
+				typed[i] = value.(float64)
+			}
+		}
+		data = typed
+	case frames.StringType:
+		typed := make([]string, len(values))
+		for i, value := range values {
+			if value != nil {
+				typed[i] = value.(string)
+			}
+		}
+		data = typed
+	case frames.TimeType:
+		typed := make([]time.Time, len(values))
+		for i, value := range values {
+			if value != nil {
+				typed[i] = value.(time.Time)
+			}
+		}
+		data = typed
+	case frames.BoolType:
+		typed := make([]bool, len(values))
+		for i, value := range values {
+			if value != nil {
+				typed[i] = value.(bool)
+			}
+		}
+		data = typed
+	default:
+		return nil, fmt.Errorf("unsupported data type - %d", dtype)
+	}
+
+	return frames.NewNullableSliceColumn(name, data, nulls)
+}
+
+// widenDType returns a type that can hold values of both types
+func widenDType(dtype1, dtype2 frames.DType) frames.DType {
+	switch {
+	case dtype1 == dtype2:
+		return dtype1
+	case dtype1 == frames.IntType && dtype2 == frames.FloatType:
+		return frames.FloatType
+	case dtype1 == frames.FloatType && dtype2 == frames.IntType:
+		return frames.FloatType
+	}
+
+	return frames.StringType
+}
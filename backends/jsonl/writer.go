@@ -0,0 +1,342 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+)
+
+// jsonlAppender writes frames to the table file (appending) or to a temporary
+// file which replaces the table on completion
+type jsonlAppender struct {
+	ctx       context.Context
+	logger    logger.Logger
+	backend   *Backend
+	path      string
+	file      *os.File
+	temporary bool // file replaces path on completion
+	writer    *bufio.Writer
+}
+
+// create starts writing to a new temporary file
+func (ja *jsonlAppender) create() error {
+	file, err := utils.TempFile(ja.path)
+	if err != nil {
+		return errors.Wrap(err, "can't create file")
+	}
+
+	ja.file, ja.temporary, ja.writer = file, true, bufio.NewWriter(file)
+	return nil
+}
+
+// open starts appending to the table file, tables that don't exist are created
+func (ja *jsonlAppender) open() error {
+	file, err := os.OpenFile(ja.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Wrap(err, "can't open table file")
+	}
+
+	// Appended records must start in a new line
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.Wrap(err, "can't stat table file")
+	}
+
+	if info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+			file.Close()
+			return errors.Wrap(err, "can't read table file")
+		}
+
+		if last[0] != '\n' {
+			if _, err := file.Write([]byte("\n")); err != nil {
+				file.Close()
+				return errors.Wrap(err, "can't write to table file")
+			}
+		}
+	}
+
+	ja.file, ja.temporary, ja.writer = file, false, bufio.NewWriter(file)
+	return nil
+}
+
+// Add writes a JSON object per frame row. In case of a failure the appender is
+// closed
+func (ja *jsonlAppender) Add(frame frames.Frame) error {
+	if err := ja.add(frame); err != nil {
+		ja.abort()
+		return err
+	}
+
+	return nil
+}
+
+func (ja *jsonlAppender) add(frame frames.Frame) error {
+	if ja.file == nil {
+		return fmt.Errorf("appender is closed")
+	}
+
+	if err := ja.ctx.Err(); err != nil {
+		return err
+	}
+
+	ja.logger.InfoWith("adding frame", "size", frame.Len())
+	root, err := ja.buildTree(frame)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for r := 0; r < frame.Len(); r++ {
+		buf.Reset()
+		if _, err := root.write(&buf, r); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+
+		if _, err := ja.writer.Write(buf.Bytes()); err != nil {
+			ja.logger.ErrorWith("can't write record", "error", err)
+			return errors.Wrap(err, "can't write record")
+		}
+	}
+
+	return nil
+}
+
+// node is an object in the written records, leaves are columns
+type node struct {
+	name     string
+	col      frames.Column
+	children []*node
+}
+
+// buildTree returns the record object of frame columns, with flattening
+// dotted names are nested objects
+func (ja *jsonlAppender) buildTree(frame frames.Frame) (*node, error) {
+	root := &node{}
+	for _, name := range frame.Names() {
+		col, err := frame.Column(name)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't get column")
+		}
+
+		path := []string{name}
+		if ja.backend.flatten {
+			path = strings.Split(name, ".")
+		}
+
+		parent := root
+		for _, key := range path[:len(path)-1] {
+			child := parent.child(key)
+			if child == nil {
+				child = &node{name: key}
+				parent.children = append(parent.children, child)
+			}
+
+			if child.col != nil {
+				return nil, fmt.Errorf("column %q conflicts with column %q", name, child.col.Name())
+			}
+			parent = child
+		}
+
+		key := path[len(path)-1]
+		if child := parent.child(key); child != nil {
+			return nil, fmt.Errorf("column %q conflicts with nested columns", name)
+		}
+		parent.children = append(parent.children, &node{name: key, col: col})
+	}
+
+	return root, nil
+}
+
+func (n *node) child(name string) *node {
+	for _, child := range n.children {
+		if child.name == name {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// write writes the object of row r to buf, null values and empty nested
+// objects are omitted. It returns the number of values written
+func (n *node) write(buf *bytes.Buffer, r int) (int, error) {
+	buf.WriteByte('{')
+	count := 0
+	for _, child := range n.children {
+		start := buf.Len()
+		if count > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(child.name)
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		var err error
+		written := 1
+		if child.col == nil {
+			written, err = child.write(buf, r)
+		} else if child.col.IsNull(r) {
+			written = 0
+		} else {
+			err = writeValue(buf, child.col, r)
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if written == 0 {
+			buf.Truncate(start)
+			continue
+		}
+		count++
+	}
+	buf.WriteByte('}')
+
+	return count, nil
+}
+
+// writeValue writes the JSON value of col at row r, floats always have a
+// fraction or exponent so they're read back as floats
+func writeValue(buf *bytes.Buffer, col frames.Column, r int) error {
+	switch col.DType() {
+	case frames.IntType:
+		value, err := col.IntAt(r)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatInt(value, 10))
+	case frames.FloatType:
+		value, err := col.FloatAt(r)
+		if err != nil {
+			return err
+		}
+
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			buf.WriteString("null")
+			return nil
+		}
+
+		text := strconv.FormatFloat(value, 'g', -1, 64)
+		if !strings.ContainsAny(text, ".eE") {
+			text += ".0"
+		}
+		buf.WriteString(text)
+	case frames.StringType:
+		value, err := col.StringAt(r)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	case frames.TimeType:
+		value, err := col.TimeAt(r)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(strconv.Quote(value.Format(time.RFC3339Nano)))
+	case frames.BoolType:
+		value, err := col.BoolAt(r)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatBool(value))
+	default:
+		return fmt.Errorf("%s: unsupported data type - %d", col.Name(), col.DType())
+	}
+
+	return nil
+}
+
+// WaitForComplete wait for write completion
+func (ja *jsonlAppender) WaitForComplete(timeout time.Duration) error {
+	if ja.file == nil {
+		return fmt.Errorf("appender is closed")
+	}
+
+	if err := ja.complete(); err != nil {
+		ja.abort()
+		return err
+	}
+
+	return nil
+}
+
+func (ja *jsonlAppender) complete() error {
+	if err := ja.writer.Flush(); err != nil {
+		return errors.Wrap(err, "can't flush file")
+	}
+
+	if err := ja.file.Sync(); err != nil {
+		return errors.Wrap(err, "can't sync file")
+	}
+
+	if err := ja.file.Close(); err != nil {
+		return errors.Wrap(err, "can't close file")
+	}
+
+	if ja.temporary {
+		if err := os.Rename(ja.file.Name(), ja.path); err != nil {
+			return errors.Wrap(err, "can't replace table file")
+		}
+	}
+
+	ja.file = nil
+	return nil
+}
+
+// abort closes the file and removes it if temporary
+func (ja *jsonlAppender) abort() {
+	if ja.file == nil {
+		return
+	}
+
+	ja.file.Close()
+	if ja.temporary {
+		if err := os.Remove(ja.file.Name()); err != nil {
+			ja.logger.WarnWith("can't remove temporary file", "path", ja.file.Name(), "error", err)
+		}
+	}
+	ja.file = nil
+}
@@ -21,6 +21,7 @@ such restriction.
 package kv
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -29,6 +30,8 @@ import (
 	v3io "github.com/v3io/v3io-go-http"
 
 	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/pb"
 	"github.com/v3io/frames/v3ioutils"
 )
 
@@ -37,28 +40,37 @@ type Backend struct {
 	logger       logger.Logger
 	numWorkers   int
 	framesConfig *frames.Config
+	retry        *v3ioutils.RetryPolicy
+	strictSchema bool // Reject writes that change column types
 }
 
 // NewBackend return a new key/value backend
 func NewBackend(logger logger.Logger, config *frames.BackendConfig, framesConfig *frames.Config) (frames.DataBackend, error) {
 
 	frames.InitBackendDefaults(config, framesConfig)
+	strictSchema, err := utils.BoolOption(config.Options, "strictSchema", false)
+	if err != nil {
+		return nil, err
+	}
+
 	newBackend := Backend{
 		logger:       logger.GetChild("kv"),
 		numWorkers:   config.Workers,
 		framesConfig: framesConfig,
+		retry:        v3ioutils.NewRetryPolicy(config.Retry),
+		strictSchema: strictSchema,
 	}
 
 	return &newBackend, nil
 }
 
 // Create creates a table
-func (b *Backend) Create(request *frames.CreateRequest) error {
+func (b *Backend) Create(ctx context.Context, request *frames.CreateRequest) error {
 	return fmt.Errorf("not requiered, table is created on first write")
 }
 
 // Delete deletes a table (or part of it)
-func (b *Backend) Delete(request *frames.DeleteRequest) error {
+func (b *Backend) Delete(ctx context.Context, request *frames.DeleteRequest) error {
 
 	if !strings.HasSuffix(request.Table, "/") {
 		request.Table += "/"
@@ -69,24 +81,23 @@ func (b *Backend) Delete(request *frames.DeleteRequest) error {
 		return err
 	}
 
-	return v3ioutils.DeleteTable(b.logger, container, request.Table, request.Filter, b.numWorkers)
+	return v3ioutils.DeleteTable(ctx, b.logger, container, request.Table, request.Filter, b.numWorkers, b.retry)
 	// TODO: delete the table directory entry if filter == ""
 }
 
 // Exec executes a command
-func (b *Backend) Exec(request *frames.ExecRequest) error {
-	// FIXME
+func (b *Backend) Exec(ctx context.Context, request *frames.ExecRequest) (frames.Frame, error) {
 	cmd := strings.TrimSpace(strings.ToLower(request.Command))
 	switch cmd {
 	case "infer", "inferschema":
-		return b.inferSchema(request)
+		return b.inferSchema(ctx, request)
 	case "update":
-		return b.updateItem(request)
+		return nil, b.updateItem(ctx, request)
 	}
-	return fmt.Errorf("KV backend does not support Exec")
+	return nil, fmt.Errorf("KV backend does not support %q exec command", request.Command)
 }
 
-func (b *Backend) updateItem(request *frames.ExecRequest) error {
+func (b *Backend) updateItem(ctx context.Context, request *frames.ExecRequest) error {
 	container, err := b.newContainer(request.Session)
 	if err != nil {
 		return err
@@ -98,8 +109,91 @@ func (b *Backend) updateItem(request *frames.ExecRequest) error {
 	}
 
 	b.logger.DebugWith("update item", "path", request.Table, "expr", request.Expression)
-	return container.Sync.UpdateItem(&v3io.UpdateItemInput{
-		Path: request.Table, Expression: &request.Expression, Condition: condition})
+	input := &v3io.UpdateItemInput{Path: request.Table, Expression: &request.Expression, Condition: condition}
+	return b.retry.Do(ctx, b.logger, func() error {
+		return container.Sync.UpdateItem(input)
+	})
+}
+
+// List lists the tables (directories with a schema)
+func (b *Backend) List(ctx context.Context, request *frames.ListRequest) ([]string, error) {
+	container, err := b.newContainer(request.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	return v3ioutils.ListTables(container, request.Path, v3ioutils.SchemaObject)
+}
+
+// Describe returns the table schema
+func (b *Backend) Describe(ctx context.Context, request *frames.DescribeRequest) (*frames.TableInfo, error) {
+	container, err := b.newContainer(request.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := v3ioutils.ReadSchema(container, strings.TrimSuffix(request.Table, "/")+"/")
+	if err != nil {
+		return nil, errors.Wrapf(err, "table %q", request.Table)
+	}
+
+	info := &frames.TableInfo{
+		Name:   request.Table,
+		Schema: schema.TableSchema(),
+	}
+
+	return info, nil
+}
+
+// Capabilities returns the backend capabilities
+func (b *Backend) Capabilities() *frames.Capabilities {
+	return &frames.Capabilities{
+		// Tables are created on first write
+		Operations: []string{
+			frames.ReadOperation, frames.WriteOperation, frames.DeleteOperation,
+			frames.ExecOperation, frames.ListOperation, frames.DescribeOperation,
+		},
+		ExecCommands: []*frames.ExecCommandInfo{
+			{
+				Name: "infer",
+				Args: []*frames.ArgumentInfo{
+					{Name: "key", Dtype: pb.DType_STRING, Doc: "key field name (default __name)"},
+					{Name: "sample", Dtype: pb.DType_INTEGER, Doc: "number of items to sample, 0 for all (default 1000)"},
+					{Name: "strategy", Dtype: pb.DType_STRING, Doc: "segments (sample evenly from every segment, default) or first (first items)"},
+					{Name: "segments", Dtype: pb.DType_INTEGER, Doc: "number of segments to sample from (default backend workers)"},
+					{Name: "dryrun", Dtype: pb.DType_BOOLEAN, Doc: "don't write the schema"},
+				},
+				Doc: "infer table schema from data, returns the schema fields and their change from the current schema",
+			},
+			{
+				Name: "update",
+				Args: []*frames.ArgumentInfo{
+					{Name: "condition", Dtype: pb.DType_STRING, Doc: "update only if condition is true"},
+				},
+				Doc: "update item (table) with expression",
+			},
+		},
+		FilterDialect:   "sql",
+		FilterOperators: b.Pushdown().FilterOperators,
+		// v3io typed attributes have no boolean and times, they are written as
+		// numbers and strings and read by the table schema
+		Dtypes: []pb.DType{
+			pb.DType_INTEGER, pb.DType_FLOAT, pb.DType_STRING, pb.DType_TIME, pb.DType_BOOLEAN,
+		},
+		ConditionalWrites: true,
+	}
+}
+
+// Pushdown returns the read request parts handled by v3io
+func (b *Backend) Pushdown() *frames.Pushdown {
+	return &frames.Pushdown{
+		Columns: true,
+		FilterOperators: []string{
+			"=", "!=", "<", "<=", ">", ">=", "in", "and", "or", "not",
+			"exists", "starts", "ends", "contains",
+		},
+		OperatorNames: map[string]string{"=": "=="},
+	}
 }
 
 func (b *Backend) newContainer(session *frames.Session) (*v3io.Container, error) {
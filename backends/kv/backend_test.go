@@ -0,0 +1,49 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"testing"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/conformance"
+	"github.com/v3io/frames/v3ioutils/v3iotest"
+)
+
+func TestConformance(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	srv.SetPageSize(16)
+
+	factory := func(t *testing.T) frames.DataBackend {
+		return newTestBackend(t, srv)
+	}
+
+	options := &conformance.Options{
+		Index: "__name",
+		Skip: map[string]string{
+			"IfMissing":   "missing tables are empty, delete doesn't fail",
+			"ReadMissing": "missing tables are empty, read doesn't fail",
+		},
+	}
+
+	conformance.Run(t, factory, options)
+}
@@ -21,18 +21,129 @@ such restriction.
 package kv
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	v3io "github.com/v3io/v3io-go-http"
+
 	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/pb"
 	"github.com/v3io/frames/v3ioutils"
-	"github.com/v3io/v3io-go-http"
-	"strings"
 )
 
-func (b *Backend) inferSchema(request *frames.ExecRequest) error {
+const (
+	defaultSampleSize = 1000
+	// Sample evenly from every segment
+	segmentsStrategy = "segments"
+	// Sample the first items of all segments
+	firstStrategy = "first"
+)
+
+// Schema field changes reported by inferSchema
+const (
+	fieldNew       = "new"
+	fieldChanged   = "changed"
+	fieldUnchanged = "unchanged"
+	fieldUnsampled = "unsampled" // Kept, not in any sampled item
+	fieldRemoved   = "removed"
+)
+
+// fieldStats are the sampled values kinds of an attribute
+type fieldStats struct {
+	name      string
+	count     int // Number of items with the attribute
+	hasInt    bool
+	hasFloat  bool
+	hasString bool // Strings that are not times
+	hasTime   bool // Times are written as RFC3339 strings
+	hasBool   bool
+	other     string // Unsupported value type, such attributes are not in the schema
+
+	current *v3ioutils.SchemaField // Field in the current schema, nil if there's none
+	misfit  bool                   // Has values that can't be read as the current type
+}
+
+func newFieldStats(name string, current *v3ioutils.Schema) *fieldStats {
+	field := &fieldStats{name: name}
+	if current != nil {
+		field.current = current.Field(name)
+	}
+
+	return field
+}
+
+func (f *fieldStats) add(value interface{}) {
+	f.count++
+	if f.current != nil && !f.misfit {
+		f.misfit = !fitsType(value, f.current)
+	}
+
+	switch value := value.(type) {
+	case int, int64:
+		f.hasInt = true
+	case float64:
+		f.hasFloat = true
+	case bool:
+		f.hasBool = true
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			f.hasTime = true
+		} else {
+			f.hasString = true
+		}
+	default:
+		f.other = fmt.Sprintf("%T", value)
+	}
+}
+
+// dtype returns the narrowest type of all the sampled values
+func (f *fieldStats) dtype() frames.DType {
+	numbers := f.hasInt || f.hasFloat
+	switch {
+	case f.hasString, f.hasTime && (numbers || f.hasBool), f.hasBool && numbers:
+		return frames.StringType
+	case f.hasTime:
+		return frames.TimeType
+	case f.hasBool:
+		return frames.BoolType
+	case f.hasFloat:
+		return frames.FloatType
+	}
+
+	return frames.IntType
+}
+
+// fitsType returns true if value can be read as the type of field. Booleans
+// are written as 0/1, so other numbers don't fit boolean fields
+func fitsType(value interface{}, field *v3ioutils.SchemaField) bool {
+	dtype, err := field.DType()
+	if err != nil {
+		return false
+	}
+
+	if n, ok := value.(int); ok && dtype == frames.BoolType {
+		return n == 0 || n == 1
+	}
+
+	_, err = schemaValue(value, dtype)
+	return err == nil
+}
 
+// inferSchema infers the table schema from a sample of the table items and
+// writes it (unless it's a dry run). The inferred schema is merged into the
+// current one: current types that fit all the sampled values are kept (e.g.
+// booleans, which are written as 0/1) and current fields that are not in any
+// sampled item are kept as well. The result frame has the inferred fields and
+// their changes from the current schema
+func (b *Backend) inferSchema(ctx context.Context, request *frames.ExecRequest) (frames.Frame, error) {
 	container, err := b.newContainer(request.Session)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	table := request.Table
@@ -40,57 +151,283 @@ func (b *Backend) inferSchema(request *frames.ExecRequest) error {
 		table += "/"
 	}
 
-	keyField := "__name"
-	if val, ok := request.Args["key"]; ok {
-		keyField = val.GetSval()
+	args := pb.AsGoMap(request.Args)
+	keyField, err := utils.StringOption(args, "key", indexColKey)
+	if err != nil {
+		return nil, err
 	}
-	maxrec := 50
 
-	input := v3io.GetItemsInput{
-		Path: table, Filter: "", AttributeNames: []string{"*"}}
-	b.logger.DebugWith("GetItems for schema", "input", input)
-	iter, err := v3ioutils.NewAsyncItemsCursor(
-		container, &input, b.numWorkers, []string{}, b.logger, 0)
+	sample, err := utils.IntOption(args, "sample", defaultSampleSize)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	rowSet := []map[string]interface{}{}
-	indicies := []string{}
+	strategy, err := utils.StringOption(args, "strategy", segmentsStrategy)
+	if err != nil {
+		return nil, err
+	}
 
-	for rowNum := 0; rowNum < maxrec && iter.Next(); rowNum++ {
-		row := iter.GetFields()
-		rowSet = append(rowSet, row)
-		index, ok := row["__name"]
-		if !ok {
-			return fmt.Errorf("key (__name) was not found in row")
+	segments, err := utils.IntOption(args, "segments", b.numWorkers)
+	if err != nil {
+		return nil, err
+	}
+	if segments < 1 {
+		segments = 1
+	}
+
+	dryRun, err := utils.BoolOption(args, "dryrun", false)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := v3ioutils.ReadSchema(container, table)
+	if err != nil {
+		if v3ioutils.StatusCode(err) != http.StatusNotFound {
+			return nil, err
 		}
-		indicies = append(indicies, index.(string))
+		current = nil
 	}
 
-	if iter.Err() != nil {
-		return iter.Err()
+	stats := make(map[string]*fieldStats)
+	nRows := 0
+	addItem := func(item map[string]interface{}) {
+		nRows++
+		for name, value := range item {
+			// System attributes (e.g. __name)
+			if strings.HasPrefix(name, "__") {
+				continue
+			}
+
+			field, ok := stats[name]
+			if !ok {
+				field = newFieldStats(name, current)
+				stats[name] = field
+			}
+			field.add(value)
+		}
+	}
+
+	b.logger.DebugWith("infer schema", "table", table, "sample", sample, "strategy", strategy, "segments", segments)
+	if err := b.sampleItems(ctx, container, table, sample, segments, strategy, addItem); err != nil {
+		return nil, err
+	}
+
+	if nRows == 0 {
+		return nil, fmt.Errorf("no items in %q to infer schema from", request.Table)
+	}
+
+	schema, err := statsSchema(stats, nRows, keyField, current)
+	if err != nil {
+		return nil, err
 	}
 
-	labels := map[string]interface{}{}
-	frame, err := frames.NewFrameFromRows(rowSet, indicies, labels)
+	frame, err := schemaDiffFrame(schema, current, stats, nRows)
 	if err != nil {
-		return fmt.Errorf("Failed to create frame - %v", err)
+		return nil, err
+	}
+
+	if dryRun {
+		return frame, nil
 	}
 
-	nullSchema := v3ioutils.NewSchema(keyField)
-	newSchema := v3ioutils.NewSchema(keyField)
+	if current != nil {
+		schema.HashingBucketNum = current.HashingBucketNum
+	}
+
+	if err := v3ioutils.NewSchema(keyField).UpdateSchema(container, table, schema, false); err != nil {
+		return nil, err
+	}
 
-	for _, name := range frame.Names() {
-		col, err := frame.Column(name)
+	return frame, nil
+}
+
+// sampleItems calls fn with sampled items of the table at tablePath, sample
+// <= 0 samples all the items
+func (b *Backend) sampleItems(
+	ctx context.Context, container *v3io.Container, tablePath string, sample, segments int,
+	strategy string, fn func(map[string]interface{})) error {
+
+	newInput := func(segment int) *v3io.GetItemsInput {
+		return &v3io.GetItemsInput{
+			Path:           tablePath,
+			AttributeNames: []string{"*"},
+			Segment:        segment,
+			TotalSegments:  segments,
+		}
+	}
+
+	// scan calls fn with up to limit items (<= 0 for all) of inputs
+	scan := func(inputs []*v3io.GetItemsInput, limit int) error {
+		iter, err := v3ioutils.NewScanCursor(ctx, container, inputs, b.logger, 0, b.retry)
 		if err != nil {
 			return err
 		}
-		err = newSchema.AddColumn(name, col, true)
+		defer iter.Release()
+
+		for n := 0; (limit <= 0 || n < limit) && iter.Next(); {
+			item := iter.GetFields()
+			if item[indexColKey] == ".#schema" {
+				continue
+			}
+
+			fn(item)
+			n++
+		}
+
+		return iter.Err()
+	}
+
+	switch strategy {
+	case segmentsStrategy:
+		// Round up so small samples have items from every segment
+		limit := 0
+		if sample > 0 {
+			limit = (sample + segments - 1) / segments
+		}
+
+		for segment := 0; segment < segments; segment++ {
+			input := newInput(segment)
+			if limit > 0 {
+				input.Limit = limit + 1 // Schema object
+			}
+
+			if err := scan([]*v3io.GetItemsInput{input}, limit); err != nil {
+				return err
+			}
+		}
+	case firstStrategy:
+		inputs := make([]*v3io.GetItemsInput, segments)
+		for segment := range inputs {
+			inputs[segment] = newInput(segment)
+		}
+
+		return scan(inputs, sample)
+	default:
+		return fmt.Errorf("unknown sampling strategy - %q", strategy)
+	}
+
+	return nil
+}
+
+// statsSchema returns the schema of sampled fields merged into the current
+// schema (which can be nil). Current fields come first, in their order
+func statsSchema(stats map[string]*fieldStats, nRows int, keyField string, current *v3ioutils.Schema) (*v3ioutils.Schema, error) {
+	var names, newNames []string
+	if current != nil {
+		for _, field := range current.Fields {
+			names = append(names, field.Name)
+		}
+	}
+
+	for name, field := range stats {
+		if field.current == nil {
+			newNames = append(newNames, name)
+		}
+	}
+	sort.Strings(newNames)
+
+	schema := v3ioutils.NewSchema(keyField)
+	for _, name := range append(names, newNames...) {
+		field, ok := stats[name]
+		if !ok {
+			// Not in any sampled item, nothing disproves the current field
+			schema.Fields = append(schema.Fields, *current.Field(name))
+			continue
+		}
+
+		if field.other != "" {
+			continue
+		}
+
+		dtype, nullable := field.dtype(), nRows > field.count
+		if field.current != nil && !field.misfit {
+			var err error
+			if dtype, err = field.current.DType(); err != nil {
+				return nil, err
+			}
+			nullable = nullable || field.current.Nullable
+		}
+
+		if err := schema.AddDType(name, dtype, nullable); err != nil {
+			return nil, err
+		}
+	}
+
+	return schema, nil
+}
+
+// schemaDiffFrame returns a frame with the fields of schema and their changes
+// from the current schema (which can be nil), with the null ratio of the
+// fields in the sample
+func schemaDiffFrame(schema, current *v3ioutils.Schema, stats map[string]*fieldStats, nRows int) (frames.Frame, error) {
+	var (
+		names, types, currentTypes, changes []string
+		nullable                            []bool
+		ratios                              []float64
+	)
+
+	add := func(name, ftype, currentType, change string, isNullable bool) {
+		ratio := 1.0
+		if field, ok := stats[name]; ok {
+			ratio = float64(nRows-field.count) / float64(nRows)
+		}
+
+		names = append(names, name)
+		types = append(types, ftype)
+		currentTypes = append(currentTypes, currentType)
+		changes = append(changes, change)
+		nullable = append(nullable, isNullable)
+		ratios = append(ratios, ratio)
+	}
+
+	for _, field := range schema.Fields {
+		var currentField *v3ioutils.SchemaField
+		if current != nil {
+			currentField = current.Field(field.Name)
+		}
+
+		switch {
+		case currentField == nil:
+			add(field.Name, field.Type, "", fieldNew, field.Nullable)
+		case stats[field.Name] == nil:
+			add(field.Name, field.Type, currentField.Type, fieldUnsampled, field.Nullable)
+		case currentField.Type != field.Type || currentField.Nullable != field.Nullable:
+			add(field.Name, field.Type, currentField.Type, fieldChanged, field.Nullable)
+		default:
+			add(field.Name, field.Type, currentField.Type, fieldUnchanged, field.Nullable)
+		}
+	}
+
+	if current != nil {
+		for _, field := range current.Fields {
+			if schema.Field(field.Name) == nil {
+				// Sampled values have an unsupported type
+				add(field.Name, "", field.Type, fieldRemoved, true)
+			}
+		}
+	}
+
+	columns := []struct {
+		name string
+		data interface{}
+	}{
+		{"name", names},
+		{"type", types},
+		{"current_type", currentTypes},
+		{"change", changes},
+		{"nullable", nullable},
+		{"null_ratio", ratios},
+	}
+
+	var cols []frames.Column
+	for _, c := range columns {
+		col, err := frames.NewSliceColumn(c.name, c.data)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		cols = append(cols, col)
 	}
 
-	return nullSchema.UpdateSchema(container, table, newSchema)
+	labels := map[string]interface{}{"key": schema.Key}
+	return frames.NewFrame(cols, nil, labels)
 }
@@ -0,0 +1,167 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v3io "github.com/v3io/v3io-go-http"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/pb"
+	"github.com/v3io/frames/v3ioutils"
+	"github.com/v3io/frames/v3ioutils/v3iotest"
+)
+
+func TestInferSchema(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	backend := newTestBackend(t, srv)
+
+	container, err := backend.newContainer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Items written by other tools, with sparse attributes and mixed types
+	ts := time.Date(2018, 11, 20, 10, 30, 0, 0, time.UTC).Format(time.RFC3339Nano)
+	items := map[string]map[string]interface{}{
+		"a": {"id": "a", "n": 1, "f": 1, "s": "x", "t": ts, "b": 1},
+		"b": {"id": "b", "n": 2, "f": 2.5, "s": 3, "t": ts, "b": 0},
+		"c": {"id": "c", "n": 3, "f": 3, "t": ts, "b": 1},
+		"d": {"id": "d", "n": 4, "f": 4, "s": "y", "b": 0},
+	}
+	for key, attrs := range items {
+		input := &v3io.PutItemInput{Path: "infer/" + key, Attributes: attrs}
+		if err := container.Sync.PutItem(input); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Current schema, "old" is not in the data and booleans are written as 0/1
+	current := v3ioutils.NewSchema("id")
+	current.Fields = []v3ioutils.SchemaField{
+		{Name: "n", Type: "long"},
+		{Name: "f", Type: "long"},
+		{Name: "old", Type: "string", Nullable: true},
+		{Name: "b", Type: "boolean"},
+	}
+	if err := v3ioutils.NewSchema("id").UpdateSchema(container, "infer/", current, false); err != nil {
+		t.Fatal(err)
+	}
+
+	infer := func(args map[string]interface{}) frames.Frame {
+		pbArgs, err := pb.FromGoMap(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		request := &frames.ExecRequest{Table: "infer", Command: "infer", Args: pbArgs}
+		frame, err := backend.Exec(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return frame
+	}
+
+	frame := infer(map[string]interface{}{"key": "id", "dryrun": true, "sample": 0})
+
+	var rows []string
+	for it := frame.IterRows(false); it.Next(); {
+		row := it.Row()
+		rows = append(rows, fmt.Sprintf("%v:%v:%v:%v:%v:%v",
+			row["name"], row["type"], row["current_type"], row["change"], row["nullable"], row["null_ratio"]))
+	}
+
+	// Current fields the sample can't disprove are kept
+	expected := []string{
+		"n:long:long:unchanged:false:0",
+		"f:double:long:changed:false:0",
+		"old:string:string:unsampled:true:1",
+		"b:boolean:boolean:unchanged:false:0",
+		"id:string::new:false:0",
+		"s:string::new:true:0.25",
+		"t:time::new:true:0.25",
+	}
+	if fmt.Sprint(rows) != fmt.Sprint(expected) {
+		t.Fatalf("bad inferred schema:\n%v\n!=\n%v", rows, expected)
+	}
+
+	// Dry run doesn't change the schema
+	info, err := backend.Describe(context.Background(), &frames.DescribeRequest{Table: "infer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(info.Schema.Fields); n != 4 {
+		t.Fatalf("dry run changed schema: %v", info.Schema.Fields)
+	}
+
+	for _, strategy := range []string{"segments", "first"} {
+		frame := infer(map[string]interface{}{"key": "id", "sample": 2, "strategy": strategy, "segments": 2})
+		if frame.Len() == 0 {
+			t.Fatalf("%s: empty result", strategy)
+		}
+	}
+
+	infer(map[string]interface{}{"key": "id"})
+	info, err = backend.Describe(context.Background(), &frames.DescribeRequest{Table: "infer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields []string
+	for _, field := range info.Schema.Fields {
+		fields = append(fields, field.Name+":"+field.Type)
+	}
+
+	if fmt.Sprint(fields) != "[n:long f:double old:string b:boolean id:string s:string t:time]" {
+		t.Fatalf("bad written schema: %v", fields)
+	}
+
+	// Typed reads by the inferred schema
+	it, err := backend.Read(context.Background(), &frames.ReadRequest{Table: "infer", Columns: []string{"f", "s", "t", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for it.Next() {
+		for _, name := range []string{"f", "s", "t", "b"} {
+			col, err := it.At().Column(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if dtype := utils.DTypeName(col.DType()); dtype != map[string]string{"f": "float", "s": "string", "t": "time", "b": "boolean"}[name] {
+				t.Fatalf("%s: bad type %s", name, dtype)
+			}
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -21,8 +21,15 @@ such restriction.
 package kv
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	v3io "github.com/v3io/v3io-go-http"
 
 	"github.com/v3io/frames"
@@ -33,10 +40,21 @@ import (
 
 const (
 	indexColKey = "__name"
+	// MarkerLabel is the label with the marker to continue the frame scan
+	// after the frame (see ReadRequest.Marker), "" after the last frame of
+	// the scan
+	MarkerLabel = "marker"
+	// SegmentLabel and TotalSegmentsLabel are the labels with the segment of
+	// the frame scan, for segment scans
+	SegmentLabel       = "segment"
+	TotalSegmentsLabel = "total_segments"
+	// ShardingKeyLabel is the label with the sharding key of the frame scan,
+	// for sharding key scans
+	ShardingKeyLabel = "sharding_key"
 )
 
 // Read does a read request
-func (kv *Backend) Read(request *frames.ReadRequest) (frames.FrameIterator, error) {
+func (kv *Backend) Read(ctx context.Context, request *frames.ReadRequest) (frames.FrameIterator, error) {
 	tablePath := request.Table
 	if !strings.HasSuffix(tablePath, "/") {
 		tablePath += "/"
@@ -51,51 +69,317 @@ func (kv *Backend) Read(request *frames.ReadRequest) (frames.FrameIterator, erro
 		columns = []string{"*"}
 	}
 
-	input := v3io.GetItemsInput{Path: tablePath, Filter: request.Filter, AttributeNames: columns}
-	kv.logger.DebugWith("read input", "input", input, "request", request)
+	inputs, err := kv.scanInputs(tablePath, columns, request)
+	if err != nil {
+		return nil, err
+	}
+	kv.logger.DebugWith("read inputs", "inputs", inputs, "request", request)
 
 	container, err := kv.newContainer(request.Session)
 	if err != nil {
 		return nil, err
 	}
 
-	iter, err := v3ioutils.NewAsyncItemsCursor(
-		container, &input, kv.numWorkers, request.ShardingKeys, kv.logger, 0)
+	fields, err := kv.schemaFields(container, tablePath, request.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := v3ioutils.NewScanCursor(ctx, container, inputs, kv.logger, 0, kv.retry)
 	if err != nil {
 		return nil, err
 	}
 
-	newKVIter := Iterator{request: request, iter: iter}
+	newKVIter := Iterator{request: request, iter: iter, fields: fields}
 	return &newKVIter, nil
 }
 
+// schemaField is a column with a type from the table schema
+type schemaField struct {
+	name  string
+	dtype frames.DType
+}
+
+// schemaFields returns the read columns that are in the table schema, all the
+// schema fields if columns is empty. Tables without a schema have no schema
+// fields, column types are inferred from values
+func (kv *Backend) schemaFields(container *v3io.Container, tablePath string, columns []string) ([]schemaField, error) {
+	schema, err := v3ioutils.ReadSchema(container, tablePath)
+	if v3ioutils.StatusCode(err) == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 || columns[0] == "" || columns[0] == "*" {
+		columns = make([]string, len(schema.Fields))
+		for i, field := range schema.Fields {
+			columns[i] = field.Name
+		}
+	}
+
+	var fields []schemaField
+	for _, name := range columns {
+		field := schema.Field(name)
+		if field == nil {
+			continue
+		}
+
+		dtype, err := field.DType()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, schemaField{name: name, dtype: dtype})
+	}
+
+	return fields, nil
+}
+
+// newSchemaColumn returns an empty column for a schema field
+func newSchemaColumn(field schemaField) (frames.Column, error) {
+	var data interface{}
+	switch field.dtype {
+	case frames.IntType:
+		data = []int64{}
+	case frames.FloatType:
+		data = []float64{}
+	case frames.StringType:
+		data = []string{}
+	case frames.TimeType:
+		data = []time.Time{}
+	case frames.BoolType:
+		data = []bool{}
+	default:
+		return nil, fmt.Errorf("%q - unsupported type %v", field.name, field.dtype)
+	}
+
+	return frames.NewSliceColumn(field.name, data)
+}
+
+// schemaValue converts an attribute value to the schema type, values written
+// before the schema type changed can have other types
+func schemaValue(value interface{}, dtype frames.DType) (interface{}, error) {
+	switch dtype {
+	case frames.IntType:
+		switch value := value.(type) {
+		case int:
+			return int64(value), nil
+		case int64:
+			return value, nil
+		case float64:
+			if value == math.Trunc(value) {
+				return int64(value), nil
+			}
+		}
+	case frames.FloatType:
+		switch value := value.(type) {
+		case int:
+			return float64(value), nil
+		case float64:
+			return value, nil
+		}
+	case frames.StringType:
+		switch value := value.(type) {
+		case string:
+			return value, nil
+		case int:
+			return strconv.Itoa(value), nil
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(value), nil
+		}
+	case frames.TimeType:
+		// Times are written as strings
+		if value, ok := value.(string); ok {
+			return time.Parse(time.RFC3339Nano, value)
+		}
+	case frames.BoolType:
+		// Booleans are written as 0/1
+		switch value := value.(type) {
+		case int:
+			return value != 0, nil
+		case bool:
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("can't read %T value as %s", value, utils.DTypeName(dtype))
+}
+
+// scanInputs returns the GetItems inputs of a read, one for every parallel
+// scan. Scans are the requested segments, the sharding keys or segments by the
+// number of workers
+func (kv *Backend) scanInputs(tablePath string, columns []string, request *frames.ReadRequest) ([]*v3io.GetItemsInput, error) {
+	hasRange := request.SortKeyRangeStart != "" || request.SortKeyRangeEnd != ""
+	switch {
+	case len(request.Segments) > 0 && len(request.ShardingKeys) > 0:
+		return nil, fmt.Errorf("can't read both segments and sharding keys")
+	case hasRange && len(request.ShardingKeys) == 0:
+		return nil, fmt.Errorf("sort key range requires sharding keys")
+	case request.Marker != "" && (len(request.Segments) > 1 || len(request.ShardingKeys) > 1):
+		return nil, fmt.Errorf("marker requires a single segment or sharding key")
+	}
+
+	newInput := func() *v3io.GetItemsInput {
+		return &v3io.GetItemsInput{
+			Path:           tablePath,
+			AttributeNames: columns,
+			Filter:         request.Filter,
+			Marker:         request.Marker,
+		}
+	}
+
+	var inputs []*v3io.GetItemsInput
+	switch {
+	case len(request.Segments) > 0:
+		total := int(request.TotoalSegments)
+		for _, segment := range request.Segments {
+			if segment < 0 || int(segment) >= total {
+				return nil, fmt.Errorf("segment %d out of %d segments", segment, total)
+			}
+
+			input := newInput()
+			input.Segment, input.TotalSegments = int(segment), total
+			inputs = append(inputs, input)
+		}
+	case len(request.ShardingKeys) > 0:
+		for _, key := range request.ShardingKeys {
+			input := newInput()
+			input.ShardingKey = key
+			if hasRange {
+				rangeFilter, err := sortKeyFilter(key, request.SortKeyRangeStart, request.SortKeyRangeEnd)
+				if err != nil {
+					return nil, err
+				}
+				input.Filter = andFilters(input.Filter, rangeFilter)
+			}
+			inputs = append(inputs, input)
+		}
+	case request.Marker != "":
+		// Continue a single scan
+		inputs = append(inputs, newInput())
+	default:
+		workers := kv.numWorkers
+		if workers < 1 {
+			workers = 1
+		}
+
+		for i := 0; i < workers; i++ {
+			input := newInput()
+			input.Segment, input.TotalSegments = i, workers
+			inputs = append(inputs, input)
+		}
+	}
+
+	// Frames are scan pages, so every frame ends with a marker of its scan
+	for _, input := range inputs {
+		input.Limit = int(request.MessageLimit)
+	}
+
+	return inputs, nil
+}
+
+// sortKeyFilter returns a filter for items of shardingKey with a sorting key
+// in [start, end), an empty start or end is unbounded. Items are named
+// <sharding key>.<sorting key>, sorting keys are compared as strings
+func sortKeyFilter(shardingKey, start, end string) (string, error) {
+	shardingKey = strings.TrimSuffix(shardingKey, ".")
+	for _, value := range []string{shardingKey, start, end} {
+		if strings.ContainsRune(value, '\'') {
+			return "", fmt.Errorf("bad sort key range value %q", value)
+		}
+	}
+
+	var filters []string
+	if start != "" {
+		filters = append(filters, fmt.Sprintf("%s >= '%s.%s'", indexColKey, shardingKey, start))
+	}
+
+	if end != "" {
+		filters = append(filters, fmt.Sprintf("%s < '%s.%s'", indexColKey, shardingKey, end))
+	}
+
+	return strings.Join(filters, " and "), nil
+}
+
+// andFilters returns a filter matching both filters, either can be empty
+func andFilters(filter1, filter2 string) string {
+	switch {
+	case filter1 == "":
+		return filter2
+	case filter2 == "":
+		return filter1
+	}
+
+	return fmt.Sprintf("(%s) and (%s)", filter1, filter2)
+}
+
 // Iterator is key/value iterator
 type Iterator struct {
 	request   *frames.ReadRequest
 	iter      *v3ioutils.AsyncItemsCursor
 	err       error
 	currFrame frames.Frame
+	numRows   int // Rows returned so far, for request.Limit
+	fields    []schemaField
 }
 
 // Next advances the iterator to next frame
 func (ki *Iterator) Next() bool {
+	ok := ki.next()
+	if !ok {
+		// Release in-flight requests on error, cancel or end of data
+		ki.iter.Release()
+	}
+
+	return ok
+}
+
+func (ki *Iterator) next() bool {
 	var columns []frames.Column
 	byName := map[string]frames.Column{}
+	dtypes := make(map[string]frames.DType, len(ki.fields))
+
+	// Schema columns are in every frame, even if all their values are null
+	for _, field := range ki.fields {
+		col, err := newSchemaColumn(field)
+		if err != nil {
+			ki.err = err
+			return false
+		}
+
+		columns = append(columns, col)
+		byName[field.name] = col
+		dtypes[field.name] = field.dtype
+	}
 
-	rowNum := 0
-	for ; rowNum < int(ki.request.MessageLimit) && ki.iter.Next(); rowNum++ {
+	limit := int(ki.request.MessageLimit)
+	if ki.request.Limit > 0 && int(ki.request.Limit)-ki.numRows < limit {
+		limit = int(ki.request.Limit) - ki.numRows
+	}
+
+	rowNum, pageEnd := 0, false
+	for rowNum < limit && ki.iter.Next() {
 		row := ki.iter.GetFields()
+		pageEnd = ki.iter.PageEnd()
 
-		// Skip table schema object
+		// Skip table schema object, it has no attributes when reading
+		// specific columns
 		rowIndex, ok := row[indexColKey]
-		if ok && rowIndex == ".#schema" {
+		if (ok && rowIndex == ".#schema") || len(row) == 0 {
+			if pageEnd && rowNum > 0 {
+				break
+			}
 			continue
 		}
 
 		for name, field := range row {
 			col, ok := byName[name]
 			if !ok {
-				data, err := utils.NewColumn(field, rowNum)
+				data, err := utils.NewColumn(field, 0)
 				if err != nil {
 					ki.err = err
 					return false
@@ -106,10 +390,28 @@ func (ki *Iterator) Next() bool {
 					ki.err = err
 					return false
 				}
+
+				// Attribute was missing from previous rows
+				for i := 0; i < rowNum; i++ {
+					if err := utils.AppendNil(col); err != nil {
+						ki.err = err
+						return false
+					}
+				}
+
 				columns = append(columns, col)
 				byName[name] = col
 			}
 
+			if dtype, ok := dtypes[name]; ok {
+				value, err := schemaValue(field, dtype)
+				if err != nil {
+					ki.err = errors.Wrapf(err, "%q", name)
+					return false
+				}
+				field = value
+			}
+
 			if err := utils.AppendColumn(col, field); err != nil {
 				ki.err = err
 				return false
@@ -129,6 +431,11 @@ func (ki *Iterator) Next() bool {
 				return false
 			}
 		}
+
+		rowNum++
+		if pageEnd {
+			break
+		}
 	}
 
 	if ki.iter.Err() != nil {
@@ -139,6 +446,7 @@ func (ki *Iterator) Next() bool {
 	if rowNum == 0 {
 		return false
 	}
+	ki.numRows += rowNum
 
 	var indices []frames.Column
 	indexCol, ok := byName[indexColKey]
@@ -148,8 +456,13 @@ func (ki *Iterator) Next() bool {
 		columns = utils.RemoveColumn(indexColKey, columns)
 	}
 
+	var labels map[string]interface{}
+	if pageEnd {
+		labels = scanLabels(ki.iter.Scan(), ki.iter.NextMarker())
+	}
+
 	var err error
-	ki.currFrame, err = frames.NewFrame(columns, indices, nil)
+	ki.currFrame, err = frames.NewFrame(columns, indices, labels)
 	if err != nil {
 		ki.err = err
 		return false
@@ -158,6 +471,22 @@ func (ki *Iterator) Next() bool {
 	return true
 }
 
+// scanLabels returns the labels of a frame that ends a page of scan, a read of
+// the scan labels with marker continues the scan after the frame
+func scanLabels(scan *v3io.GetItemsInput, marker string) map[string]interface{} {
+	labels := map[string]interface{}{MarkerLabel: marker}
+	if scan.TotalSegments > 0 {
+		labels[SegmentLabel] = int64(scan.Segment)
+		labels[TotalSegmentsLabel] = int64(scan.TotalSegments)
+	}
+
+	if scan.ShardingKey != "" {
+		labels[ShardingKeyLabel] = scan.ShardingKey
+	}
+
+	return labels
+}
+
 // Err return the last error
 func (ki *Iterator) Err() error {
 	return ki.err
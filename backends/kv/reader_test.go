@@ -21,9 +21,216 @@ such restriction.
 package kv
 
 import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
 	"testing"
+
+	v3io "github.com/v3io/v3io-go-http"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils/v3iotest"
 )
 
 func TestReader(t *testing.T) {
-	t.Skip("TODO")
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	srv.SetPageSize(4) // Make sure we read more than one page
+	backend := newTestBackend(t, srv)
+
+	container, err := backend.newContainer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size := 20
+	for i := 0; i < size; i++ {
+		input := &v3io.PutItemInput{
+			Path:       fmt.Sprintf("rtable/%02d", i),
+			Attributes: map[string]interface{}{"i": i, "s": fmt.Sprintf("s%d", i)},
+		}
+		if err := container.Sync.PutItem(input); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	request := &frames.ReadRequest{
+		Table:        "rtable",
+		Columns:      []string{"__name", "i"},
+		Filter:       "i >= 5",
+		MessageLimit: 7,
+	}
+
+	it, err := backend.Read(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nRows := 0
+	for it.Next() {
+		frame := it.At()
+		if names := frame.Names(); len(names) != 1 || names[0] != "i" {
+			t.Fatalf("bad columns: %v", names)
+		}
+
+		if indices := frame.Indices(); len(indices) != 1 || indices[0].Name() != "__name" {
+			t.Fatalf("bad indices: %v", indices)
+		}
+
+		nRows += frame.Len()
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if nRows != size-5 {
+		t.Fatalf("wrong number of rows: %d != %d", nRows, size-5)
+	}
+}
+
+func TestReaderScans(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	srv.SetPageSize(4)
+	backend := newTestBackend(t, srv)
+
+	container, err := backend.newContainer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var all []string
+	for _, shard := range []string{"a", "b"} {
+		for i := 0; i < 10; i++ {
+			name := fmt.Sprintf("%s.%d", shard, i)
+			input := &v3io.PutItemInput{Path: "stable/" + name, Attributes: map[string]interface{}{"i": i}}
+			if err := container.Sync.PutItem(input); err != nil {
+				t.Fatal(err)
+			}
+			all = append(all, name)
+		}
+	}
+
+	// read returns the item names, and the marker label of the last frame
+	read := func(request *frames.ReadRequest, maxFrames int) ([]string, interface{}) {
+		request.Table = "stable"
+		it, err := backend.Read(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		var marker interface{}
+		for nFrames := 0; nFrames < maxFrames && it.Next(); nFrames++ {
+			frame := it.At()
+			col := frame.Indices()[0]
+			for i := 0; i < frame.Len(); i++ {
+				name, _ := col.StringAt(i)
+				names = append(names, name)
+			}
+			marker = frame.Labels()[MarkerLabel]
+		}
+
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		sort.Strings(names)
+		return names, marker
+	}
+
+	var segmented []string
+	for segment := int64(0); segment < 3; segment++ {
+		names, _ := read(&frames.ReadRequest{Segments: []int64{segment}, TotoalSegments: 3}, 100)
+		segmented = append(segmented, names...)
+	}
+	sort.Strings(segmented)
+
+	if !reflect.DeepEqual(segmented, all) {
+		t.Fatalf("bad segmented read: %v", segmented)
+	}
+
+	request := &frames.ReadRequest{
+		ShardingKeys:      []string{"b"},
+		SortKeyRangeStart: "3",
+		SortKeyRangeEnd:   "6",
+	}
+
+	if names, _ := read(request, 100); fmt.Sprint(names) != "[b.3 b.4 b.5]" {
+		t.Fatalf("bad sort key range read: %v", names)
+	}
+
+	// Resume a single scan after every frame
+	var resumed []string
+	request = &frames.ReadRequest{Segments: []int64{0}, TotoalSegments: 1, MessageLimit: 3}
+	for {
+		names, marker := read(request, 1)
+		if len(names) == 0 || len(names) > 3 {
+			t.Fatalf("bad frame: %v", names)
+		}
+		resumed = append(resumed, names...)
+
+		if marker == "" {
+			break
+		}
+
+		request = &frames.ReadRequest{Segments: []int64{0}, TotoalSegments: 1, MessageLimit: 3, Marker: marker.(string)}
+	}
+	sort.Strings(resumed)
+
+	if !reflect.DeepEqual(resumed, all) {
+		t.Fatalf("bad resumed read: %v", resumed)
+	}
+
+	// Resume a parallel read from the markers of every segment
+	it, err := backend.Read(context.Background(), &frames.ReadRequest{Table: "stable", MessageLimit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parallel []string
+	markers := make(map[int64]string)
+	total := int64(0)
+	for nFrames := 0; nFrames < 3 && it.Next(); nFrames++ {
+		frame := it.At()
+		col := frame.Indices()[0]
+		for i := 0; i < frame.Len(); i++ {
+			name, _ := col.StringAt(i)
+			parallel = append(parallel, name)
+		}
+
+		labels := frame.Labels()
+		segment, ok := labels[SegmentLabel].(int64)
+		if !ok {
+			t.Fatalf("no segment label: %v", labels)
+		}
+		markers[segment] = labels[MarkerLabel].(string)
+		total = labels[TotalSegmentsLabel].(int64)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	for segment := int64(0); segment < total; segment++ {
+		marker, ok := markers[segment]
+		if ok && marker == "" {
+			continue
+		}
+
+		names, _ := read(&frames.ReadRequest{Segments: []int64{segment}, TotoalSegments: total, Marker: marker}, 100)
+		parallel = append(parallel, names...)
+	}
+	sort.Strings(parallel)
+
+	if !reflect.DeepEqual(parallel, all) {
+		t.Fatalf("bad resumed parallel read: %v", parallel)
+	}
+
+	_, err = backend.Read(context.Background(), &frames.ReadRequest{Table: "stable", SortKeyRangeStart: "3"})
+	if err == nil {
+		t.Fatal("no error on sort key range without sharding key")
+	}
 }
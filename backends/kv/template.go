@@ -0,0 +1,266 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+)
+
+// exprTemplate is an update (or condition) expression template, placeholders
+// are {name} where name is a frame column, index or label. e.g.
+// "SET count = count + {n}; SET last = {time}"
+type exprTemplate struct {
+	// Literal text and placeholder names alternate, placeholders are the odd
+	// parts
+	parts []string
+}
+
+// valueFunc renders the value of a placeholder at a row as an expression
+// literal
+type valueFunc func(row int) (string, error)
+
+// parseExprTemplate parses an expression template
+func parseExprTemplate(text string) (*exprTemplate, error) {
+	tmpl := &exprTemplate{}
+	for {
+		start := strings.IndexByte(text, '{')
+		if start == -1 {
+			tmpl.parts = append(tmpl.parts, text)
+			return tmpl, nil
+		}
+
+		end := strings.IndexByte(text[start:], '}')
+		if end == -1 {
+			return nil, fmt.Errorf("unterminated placeholder in %q", text)
+		}
+		end += start
+
+		name := text[start+1 : end]
+		if name == "" || strings.IndexByte(name, '{') != -1 {
+			return nil, fmt.Errorf("bad placeholder %q", text[start:end+1])
+		}
+
+		tmpl.parts = append(tmpl.parts, text[:start], name)
+		text = text[end+1:]
+	}
+}
+
+// render returns the expression of every frame row. All the rows are rendered
+// before returning so template errors are reported before any item is written
+func (t *exprTemplate) render(frame frames.Frame) ([]string, error) {
+	values, err := t.bind(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := make([]string, frame.Len())
+	var buf strings.Builder
+	for r := range exprs {
+		buf.Reset()
+		for i, part := range t.parts {
+			if i%2 == 0 {
+				buf.WriteString(part)
+				continue
+			}
+
+			val, err := values[i/2](r)
+			if err != nil {
+				return nil, errors.Wrapf(err, "row %d: {%s}", r, part)
+			}
+			buf.WriteString(val)
+		}
+		exprs[r] = buf.String()
+	}
+
+	return exprs, nil
+}
+
+// bind returns the value functions of the template placeholders in frame,
+// placeholders are looked up in columns, then indices and then labels
+func (t *exprTemplate) bind(frame frames.Frame) ([]valueFunc, error) {
+	columns := make(map[string]frames.Column)
+	for _, col := range frame.Indices() {
+		columns[col.Name()] = col
+	}
+
+	for _, name := range frame.Names() {
+		col, err := frame.Column(name)
+		if err != nil {
+			return nil, err
+		}
+		columns[name] = col
+	}
+
+	var values []valueFunc
+	for i := 1; i < len(t.parts); i += 2 {
+		name := t.parts[i]
+		if col, ok := columns[name]; ok {
+			fn, err := columnValueFunc(col)
+			if err != nil {
+				return nil, errors.Wrapf(err, "{%s}", name)
+			}
+			values = append(values, fn)
+			continue
+		}
+
+		label, ok := frame.Labels()[name]
+		if !ok {
+			return nil, fmt.Errorf("{%s}: no such column or label", name)
+		}
+
+		literal, err := formatLiteral(label)
+		if err != nil {
+			return nil, errors.Wrapf(err, "{%s}", name)
+		}
+		values = append(values, func(int) (string, error) { return literal, nil })
+	}
+
+	return values, nil
+}
+
+// columnValueFunc returns a value function over a column
+func columnValueFunc(col frames.Column) (valueFunc, error) {
+	var fn valueFunc
+	switch col.DType() {
+	case frames.IntType:
+		fn = func(i int) (string, error) {
+			val, err := col.IntAt(i)
+			if err != nil {
+				return "", err
+			}
+			return strconv.FormatInt(val, 10), nil
+		}
+	case frames.FloatType:
+		fn = func(i int) (string, error) {
+			val, err := col.FloatAt(i)
+			if err != nil {
+				return "", err
+			}
+			return formatFloat(val)
+		}
+	case frames.StringType:
+		fn = func(i int) (string, error) {
+			val, err := col.StringAt(i)
+			if err != nil {
+				return "", err
+			}
+			return quote(val)
+		}
+	case frames.TimeType:
+		fn = func(i int) (string, error) {
+			val, err := col.TimeAt(i)
+			if err != nil {
+				return "", err
+			}
+			return formatTime(val), nil
+		}
+	case frames.BoolType:
+		fn = func(i int) (string, error) {
+			val, err := col.BoolAt(i)
+			if err != nil {
+				return "", err
+			}
+			return formatBool(val), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported column type - %v", col.DType())
+	}
+
+	return func(i int) (string, error) {
+		if col.IsNull(i) {
+			return "", fmt.Errorf("null value")
+		}
+		return fn(i)
+	}, nil
+}
+
+// formatLiteral formats a label value as an expression literal
+func formatLiteral(val interface{}) (string, error) {
+	switch val := val.(type) {
+	case int:
+		return strconv.Itoa(val), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case float64:
+		return formatFloat(val)
+	case string:
+		return quote(val)
+	case bool:
+		return formatBool(val), nil
+	case time.Time:
+		return formatTime(val), nil
+	}
+
+	return "", fmt.Errorf("unsupported value type - %T", val)
+}
+
+// formatFloat formats f with full precision, v3io expressions have no
+// exponent notation
+func formatFloat(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("can't use %v in expression", f)
+	}
+
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0" // Keep the value a float
+	}
+	return s, nil
+}
+
+// formatBool formats b the way booleans are written (as 1/0), so conditions
+// and updates compare with and set the stored values
+func formatBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// formatTime formats t the way times are written (as strings)
+func formatTime(t time.Time) string {
+	return "'" + t.Format(time.RFC3339Nano) + "'"
+}
+
+// quote returns s as a string literal. v3io expression string literals are in
+// single or double quotes and have no escape sequences (a backslash is a
+// literal backslash), so strings with single quotes are double quoted and
+// strings with both quote characters can't be expressed. Such values fail the
+// render and no item is written
+func quote(s string) (string, error) {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'", nil
+	}
+
+	if strings.Contains(s, `"`) {
+		return "", fmt.Errorf("can't quote %q, it has both quote characters", s)
+	}
+
+	return `"` + s + `"`, nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package kv
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+)
+
+func TestExprTemplate(t *testing.T) {
+	ts := time.Date(2018, 11, 20, 10, 30, 0, 500, time.UTC)
+	columns := []struct {
+		name   string
+		values interface{}
+	}{
+		{"i", []int64{1, -2}},
+		{"f", []float64{0.1234567891, 3}},
+		{"s", []string{"a b", "it's"}},
+		{"t", []time.Time{ts, ts}},
+		{"b", []bool{true, false}},
+	}
+
+	var cols []frames.Column
+	for _, c := range columns {
+		col, err := frames.NewSliceColumn(c.name, c.values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cols = append(cols, col)
+	}
+
+	index, err := frames.NewSliceColumn("key", []string{"k1", "k2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := map[string]interface{}{"lbl": "L", "n": 7, "ok": true}
+	frame, err := frames.NewFrame(cols, []frames.Column{index}, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		template string
+		exprs    []string
+	}{
+		{"SET x = {i}", []string{"SET x = 1", "SET x = -2"}},
+		{"SET x = {f}", []string{"SET x = 0.1234567891", "SET x = 3.0"}},
+		{"SET x = {s}", []string{"SET x = 'a b'", `SET x = "it's"`}},
+		{"SET x = {t}", []string{"SET x = '2018-11-20T10:30:00.0000005Z'", "SET x = '2018-11-20T10:30:00.0000005Z'"}},
+		{"SET x = {b}", []string{"SET x = 1", "SET x = 0"}},
+		{"SET x = {ok}", []string{"SET x = 1", "SET x = 1"}},
+		{"SET x = {key}; SET y = {lbl}; SET z = {n}", []string{"SET x = 'k1'; SET y = 'L'; SET z = 7", "SET x = 'k2'; SET y = 'L'; SET z = 7"}},
+		{"SET x = 1", []string{"SET x = 1", "SET x = 1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.template, func(t *testing.T) {
+			tmpl, err := parseExprTemplate(tc.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			exprs, err := tmpl.render(frame)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if fmt.Sprintf("%q", exprs) != fmt.Sprintf("%q", tc.exprs) {
+				t.Fatalf("bad expressions: %q != %q", exprs, tc.exprs)
+			}
+		})
+	}
+
+	for _, text := range []string{"SET x = {i", "SET x = {}", "SET x = {{i}"} {
+		if _, err := parseExprTemplate(text); err == nil {
+			t.Fatalf("%q: no parse error", text)
+		}
+	}
+
+	for _, text := range []string{"SET x = {nope}", "SET x = {x} + {i}"} {
+		tmpl, err := parseExprTemplate(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tmpl.render(frame); err == nil {
+			t.Fatalf("%q: no render error", text)
+		}
+	}
+
+	bad, err := frames.NewSliceColumn("bad", []string{"'\"", "ok"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nan, err := frames.NewSliceColumn("nan", []float64{1, math.NaN()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, col := range []frames.Column{bad, nan} {
+		frame, err := frames.NewFrame([]frames.Column{col}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tmpl, err := parseExprTemplate("SET x = {" + col.Name() + "}")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tmpl.render(frame); err == nil {
+			t.Fatalf("%s: no render error", col.Name())
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	testCases := []struct {
+		value  string
+		quoted string
+	}{
+		{"a b", "'a b'"},
+		{"", "''"},
+		{`say "hi"`, `'say "hi"'`},
+		{"it's", `"it's"`},
+		{`a\b`, `'a\b'`},
+	}
+
+	for _, tc := range testCases {
+		quoted, err := quote(tc.value)
+		if err != nil {
+			t.Fatalf("%q: %s", tc.value, err)
+		}
+
+		if quoted != tc.quoted {
+			t.Fatalf("%q: bad literal %s != %s", tc.value, quoted, tc.quoted)
+		}
+	}
+
+	// v3io string literals have no escapes
+	for _, value := range []string{`it's "quoted"`, `'"`} {
+		if quoted, err := quote(value); err == nil {
+			t.Fatalf("%q: no error (%s)", value, quoted)
+		}
+	}
+}
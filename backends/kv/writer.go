@@ -21,12 +21,16 @@ such restriction.
 package kv
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
 	v3io "github.com/v3io/v3io-go-http"
 
 	"github.com/v3io/frames"
@@ -36,6 +40,7 @@ import (
 
 // Appender is key/value appender
 type Appender struct {
+	ctx          context.Context
 	request      *frames.WriteRequest
 	container    *v3io.Container
 	tablePath    string
@@ -44,32 +49,73 @@ type Appender struct {
 	doneChan     chan bool
 	sent         int
 	logger       logger.Logger
-	schema       v3ioutils.V3ioSchema
+	schema       *v3ioutils.Schema
+	strictSchema bool
 	asyncErr     error
+	retry        *v3ioutils.RetryPolicy
+	rows         int // Rows sent (over all frames)
+	report       *frames.WriteReport
+	expr         *exprTemplate // Update expression (nil for attribute writes)
+	condition    *exprTemplate
+}
+
+// writeContext is the context of a row write request
+type writeContext struct {
+	row     int
+	key     string
+	attempt int
 }
 
 // Write support writing to backend
-func (kv *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, error) {
+func (kv *Backend) Write(ctx context.Context, request *frames.WriteRequest) (frames.FrameAppender, error) {
 	tablePath := request.Table
 	if !strings.HasSuffix(tablePath, "/") {
 		tablePath += "/"
 	}
 
+	var expr, condition *exprTemplate
+	if request.Expression != "" {
+		var err error
+		if expr, err = parseExprTemplate(request.Expression); err != nil {
+			return nil, errors.Wrap(err, "bad expression")
+		}
+	}
+
+	if request.Condition != "" {
+		var err error
+		if condition, err = parseExprTemplate(request.Condition); err != nil {
+			return nil, errors.Wrap(err, "bad condition")
+		}
+	}
+
 	container, err := kv.newContainer(request.Session)
 	if err != nil {
 		return nil, err
 	}
 
+	schema, err := v3ioutils.ReadSchemaOrNew(container, tablePath, indexColKey)
+	if err != nil {
+		return nil, err
+	}
+
 	appender := Appender{
+		ctx:          ctx,
 		request:      request,
 		container:    container,
 		tablePath:    tablePath,
 		responseChan: make(chan *v3io.Response, 1000),
 		commChan:     make(chan int, 2),
 		logger:       kv.logger,
-		schema:       v3ioutils.NewSchema("__name"),
+		schema:       schema,
+		strictSchema: kv.strictSchema,
+		retry:        kv.retry,
+		report:       &frames.WriteReport{},
+		expr:         expr,
+		condition:    condition,
+		// Buffered so respWaitLoop won't block if WaitForComplete was canceled
+		doneChan: make(chan bool, 1),
 	}
-	go appender.respWaitLoop(time.Minute)
+	go appender.respWaitLoop()
 
 	if request.ImmidiateData != nil {
 		err := appender.Add(request.ImmidiateData)
@@ -88,8 +134,13 @@ func (a *Appender) Add(frame frames.Frame) error {
 		return fmt.Errorf("empty frame")
 	}
 
-	if a.request.Expression != "" {
-		return a.update(frame)
+	conditions, err := a.conditions(frame)
+	if err != nil {
+		return err
+	}
+
+	if a.expr != nil {
+		return a.update(frame, conditions)
 	}
 
 	columns := make(map[string]frames.Column)
@@ -111,14 +162,10 @@ func (a *Appender) Add(frame frames.Frame) error {
 		}
 		columns[name] = col
 	}
-	for name, val := range frame.Labels() {
-		err := newSchema.AddField(name, val, true)
-		if err != nil {
-			return err
-		}
-	}
 
-	err := a.schema.UpdateSchema(a.container, a.tablePath, newSchema)
+	// Labels are not item attributes (they're only used by templates), so
+	// they're not in the schema either
+	err = a.schema.UpdateSchema(a.container, a.tablePath, newSchema, a.strictSchema)
 	if err != nil {
 		return err
 	}
@@ -129,42 +176,51 @@ func (a *Appender) Add(frame frames.Frame) error {
 	}
 
 	for r := 0; r < frame.Len(); r++ {
+		if err := a.ctx.Err(); err != nil {
+			return err
+		}
+
 		row := make(map[string]interface{})
 
-		// set row values from columns
+		// set row values from columns, nulls are not written
 		for name, col := range columns {
+			if col.IsNull(r) {
+				continue
+			}
+
 			val, err := utils.ColAt(col, r)
 			if err != nil {
 				return err
 			}
 
-			if val64, ok := val.(int64); ok {
-				val = int(val64)
+			switch typed := val.(type) {
+			case int64:
+				val = int(typed)
+			case bool:
+				// v3io has no boolean attributes, readers use the schema type
+				val = 0
+				if typed {
+					val = 1
+				}
 			}
 
 			row[name] = val
 		}
 
-		key := indexVal(r)
-		input := v3io.PutItemInput{Path: a.tablePath + key, Attributes: row}
-		a.logger.DebugWith("write", "input", input)
-		_, err := a.container.PutItem(&input, r, a.responseChan)
-		if err != nil {
-			a.logger.ErrorWith("write error", "error", err)
+		if err := a.writeRow(indexVal(r), row, "", conditions[r]); err != nil {
 			return err
 		}
-
-		a.sent++
 	}
 
 	return nil
 }
 
 // update updates rows from a frame
-func (a *Appender) update(frame frames.Frame) error {
-	names := frame.Names()
-	if len(names) == 0 {
-		return fmt.Errorf("empty frame")
+func (a *Appender) update(frame frames.Frame, conditions []string) error {
+	exprs, err := a.expr.render(frame)
+	if err != nil {
+		a.logger.ErrorWith("error generating expression", "error", err)
+		return errors.Wrap(err, "bad expression")
 	}
 
 	indexVal, err := a.indexValFunc(frame)
@@ -172,63 +228,83 @@ func (a *Appender) update(frame frames.Frame) error {
 		return err
 	}
 
-	for r := 0; r < frame.Len(); r++ {
-
-		expr, err := genExpr(a.request.Expression, frame, r)
-		if err != nil {
-			a.logger.ErrorWith("error generating expression", "error", err)
+	for r, expr := range exprs {
+		if err := a.ctx.Err(); err != nil {
 			return err
 		}
 
-		key := indexVal(r)
-		input := v3io.UpdateItemInput{Path: a.tablePath + key, Expression: &expr}
-		a.logger.DebugWith("write update", "input", input)
-		_, err = a.container.UpdateItem(&input, r, a.responseChan)
-		if err != nil {
-			a.logger.ErrorWith("write update error", "error", err)
+		if err := a.writeRow(indexVal(r), nil, expr, conditions[r]); err != nil {
 			return err
 		}
-
-		a.sent++
 	}
 
 	return nil
 }
 
-// generate the update expression
-func genExpr(expr string, frame frames.Frame, index int) (string, error) {
-	args := make([]string, 0)
-
-	for _, name := range frame.Names() {
-		col, err := frame.Column(name)
+// conditions returns the row conditions of a frame, including the write mode
+// condition
+func (a *Appender) conditions(frame frames.Frame) ([]string, error) {
+	conditions := make([]string, frame.Len())
+	if a.condition != nil {
+		var err error
+		conditions, err = a.condition.render(frame)
 		if err != nil {
-			return "", err
+			a.logger.ErrorWith("error generating condition", "error", err)
+			return nil, errors.Wrap(err, "bad condition")
 		}
+	}
 
-		val, err := utils.ColAt(col, index)
-		if err != nil {
-			return "", err
-		}
+	modeCond := modeCondition(a.request.WriteMode)
+	for r, cond := range conditions {
+		conditions[r] = andFilters(modeCond, cond)
+	}
 
-		args = append(args, "{"+name+"}")
-		valString := ""
-
-		switch col.DType() {
-		case frames.IntType:
-			valString = fmt.Sprintf("%d", val)
-		case frames.FloatType:
-			valString = fmt.Sprintf("%f", val.(float64))
-		case frames.StringType, frames.TimeType:
-			valString = "'" + val.(string) + "'"
-		default:
-			valString = fmt.Sprintf("%v", val)
-		}
+	return conditions, nil
+}
+
+// writeRow sends the write request of a row, either attributes or an update
+// expression, according to the request write mode
+func (a *Appender) writeRow(key string, attrs map[string]interface{}, expr, condition string) error {
+
+	path := a.tablePath + key
+	wctx := &writeContext{row: a.rows, key: key, attempt: 1}
+	var err error
+	switch {
+	case expr != "":
+		input := &v3io.UpdateItemInput{Path: path, Expression: &expr, Condition: condition}
+		a.logger.DebugWith("write update", "input", input)
+		_, err = a.container.UpdateItem(input, wctx, a.responseChan)
+	case a.request.WriteMode == frames.UpsertMode || a.request.WriteMode == frames.UpdateMode:
+		// UpdateItem with attributes keeps the item attributes not in the row
+		input := &v3io.UpdateItemInput{Path: path, Attributes: attrs, Condition: condition}
+		a.logger.DebugWith("write upsert", "input", input)
+		_, err = a.container.UpdateItem(input, wctx, a.responseChan)
+	default:
+		input := &v3io.PutItemInput{Path: path, Attributes: attrs, Condition: condition}
+		a.logger.DebugWith("write", "input", input)
+		_, err = a.container.PutItem(input, wctx, a.responseChan)
+	}
+
+	if err != nil {
+		a.logger.ErrorWith("write error", "error", err)
+		return err
+	}
+
+	a.rows++
+	a.sent++
+	return nil
+}
 
-		args = append(args, valString)
+// modeCondition returns the item condition of a write mode
+func modeCondition(mode frames.WriteMode) string {
+	switch mode {
+	case frames.UpdateMode:
+		return "exists(__name)"
+	case frames.InsertMode:
+		return "not exists(__name)"
 	}
 
-	r := strings.NewReplacer(args...)
-	return r.Replace(expr), nil
+	return ""
 }
 
 // convert Col name to a v3io valid attr name
@@ -242,12 +318,38 @@ func validColName(name string) string {
 	return name
 }
 
-// WaitForComplete waits for write to complete
+// WaitForComplete waits for write to complete, timeout <= 0 waits until the
+// write context is done
 func (a *Appender) WaitForComplete(timeout time.Duration) error {
 	a.logger.DebugWith("WaitForComplete", "sent", a.sent)
 	a.commChan <- a.sent
-	<-a.doneChan
-	return a.asyncErr
+
+	var timeoutChan <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutChan = timer.C
+	}
+
+	select {
+	case <-a.doneChan:
+		return a.asyncErr
+	case <-a.ctx.Done():
+		return a.ctx.Err()
+	case <-timeoutChan:
+		a.logger.ErrorWith("write timed out", "sent", a.sent, "timeout", timeout)
+		return fmt.Errorf("write timed out after %s", timeout)
+	}
+}
+
+// Report returns the outcome of the written rows, it's valid after
+// WaitForComplete returned
+func (a *Appender) Report() *frames.WriteReport {
+	// Responses arrive out of order
+	sort.Slice(a.report.Rows, func(i, j int) bool {
+		return a.report.Rows[i].Row < a.report.Rows[j].Row
+	})
+	return a.report
 }
 
 func (a *Appender) indexValFunc(frame frames.Frame) (func(int) string, error) {
@@ -311,28 +413,56 @@ func (a *Appender) indexValFunc(frame frames.Frame) (func(int) string, error) {
 	return fn, nil
 }
 
-func (a *Appender) respWaitLoop(timeout time.Duration) {
+// reportRow adds the outcome of a row to the report. Rows of conditional
+// writes that fail are only reported, other writes fail on the first failed row
+func (a *Appender) reportRow(wctx *writeContext, err error) {
+	if err == nil {
+		a.report.Applied++
+		return
+	}
+
+	outcome := &frames.RowOutcome{Row: int64(wctx.row), Key: wctx.key, Error: err.Error()}
+	if v3ioutils.StatusCode(err) == http.StatusPreconditionFailed {
+		a.logger.DebugWith("write condition failed", "key", wctx.key)
+		outcome.Status = frames.RowConditionFailed
+		a.report.ConditionFailed++
+	} else {
+		a.logger.ErrorWith("failed write response", "key", wctx.key, "error", err)
+		outcome.Status = frames.RowFailed
+		a.report.Failed++
+		if !a.request.IsConditional() {
+			a.asyncErr = err
+		}
+	}
+
+	a.report.Rows = append(a.report.Rows, outcome)
+}
+
+// respWaitLoop collects write responses until all requests are done or the
+// write context is done, failed requests are sent again according to the
+// retry policy. Resends are scheduled so the loop keeps collecting responses
+// during backoff
+func (a *Appender) respWaitLoop() {
 	responses := 0
 	requests := -1
-	a.doneChan = make(chan bool)
 	a.logger.Debug("write wait loop started")
-	timer := time.NewTimer(timeout)
 
-	active := false
 	for {
 		select {
 
 		case resp := <-a.responseChan:
 			a.logger.DebugWith("write response", "response", resp)
-			responses++
-			active = true
-			timer.Reset(timeout)
-
-			if resp.Error != nil {
-				a.logger.ErrorWith("failed write response", "error", resp.Error)
-				a.asyncErr = resp.Error
+			wctx := resp.Context.(*writeContext)
+			if a.retry.Retry(resp.Error, wctx.attempt) {
+				a.logger.WarnWith("retrying write", "attempt", wctx.attempt, "error", resp.Error)
+				next := &writeContext{row: wctx.row, key: wctx.key, attempt: wctx.attempt + 1}
+				a.retry.ResendAfter(a.ctx, a.container, resp, wctx.attempt, next, a.responseChan)
+				continue
 			}
 
+			responses++
+			a.reportRow(wctx, resp.Error)
+
 			if requests == responses {
 				a.doneChan <- true
 				return
@@ -344,14 +474,9 @@ func (a *Appender) respWaitLoop(timeout time.Duration) {
 				return
 			}
 
-		case <-timer.C:
-			if !active {
-				a.logger.ErrorWith("Resp loop timed out! ", "requests", requests, "response", responses)
-				a.asyncErr = fmt.Errorf("Resp loop timed out!")
-				a.doneChan <- true
-				return
-			}
-			active = false
+		case <-a.ctx.Done():
+			a.logger.WarnWith("write canceled", "requests", requests, "responses", responses)
+			return
 		}
 	}
 }
@@ -21,9 +21,567 @@ such restriction.
 package kv
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
+
+	v3io "github.com/v3io/v3io-go-http"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils"
+	"github.com/v3io/frames/v3ioutils/v3iotest"
 )
 
+func newTestBackend(t *testing.T, srv *v3iotest.Server) *Backend {
+	logger, err := frames.NewLogger("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &frames.Config{WebAPIEndpoint: srv.Addr, Container: "bigdata"}
+	backend, err := NewBackend(logger, &frames.BackendConfig{Type: "kv", Workers: 2}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return backend.(*Backend)
+}
+
 func TestWriter(t *testing.T) {
-	t.Skip("TODO")
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	backend := newTestBackend(t, srv)
+
+	index, err := frames.NewSliceColumn("key", []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := frames.NewSliceColumn("x", []float64{1.5, 2.5, 3.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Labels are not written as attributes, so they're not in the schema
+	labels := map[string]interface{}{"lbl": "L"}
+	frame, err := frames.NewFrame([]frames.Column{x}, []frames.Column{index}, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: "wtable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Add(frame); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.WaitForComplete(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	container, err := backend.newContainer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := container.Sync.GetItem(&v3io.GetItemInput{Path: "wtable/b", AttributeNames: []string{"x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Release()
+
+	if item := resp.Output.(*v3io.GetItemOutput).Item; item["x"] != 2.5 {
+		t.Fatalf("bad item: %v", item)
+	}
+
+	info, err := backend.Describe(context.Background(), &frames.DescribeRequest{Table: "wtable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := info.Schema
+	if len(schema.Fields) != 1 || schema.Fields[0].Name != "x" || fmt.Sprint(schema.Key.ShardingKey) != "[key]" {
+		t.Fatalf("bad schema: %+v", schema)
+	}
+}
+
+func TestWriterRetry(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	backend := newTestBackend(t, srv)
+	backend.retry = v3ioutils.NewRetryPolicy(&frames.RetryConfig{MaxAttempts: 3, Backoff: 1})
+
+	col, err := frames.NewSliceColumn("key", []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, err := frames.NewFrame([]frames.Column{col}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(table string) error {
+		appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: table})
+		if err != nil {
+			return err
+		}
+
+		if err := appender.Add(frame); err != nil {
+			return err
+		}
+
+		return appender.WaitForComplete(10 * time.Second)
+	}
+
+	// Fewer failures than attempts
+	srv.Fail("PutItem", 2, http.StatusServiceUnavailable)
+	if err := write("retry"); err != nil {
+		t.Fatal(err)
+	}
+
+	frs, err := backend.Read(context.Background(), &frames.ReadRequest{Table: "retry"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nRows := 0
+	for frs.Next() {
+		nRows += frs.At().Len()
+	}
+
+	if err := frs.Err(); err != nil || nRows != 2 {
+		t.Fatalf("bad read: %d rows (%v)", nRows, err)
+	}
+
+	srv.Fail("PutItem", 10, http.StatusServiceUnavailable)
+	if err := write("fail"); v3ioutils.StatusCode(err) != http.StatusServiceUnavailable {
+		t.Fatalf("bad error after too many failures: %v", err)
+	}
+
+	srv.Fail("PutItem", 1, http.StatusBadRequest)
+	if err := write("bad"); v3ioutils.StatusCode(err) != http.StatusBadRequest {
+		t.Fatalf("bad error on non retryable failure: %v", err)
+	}
+}
+
+func TestWriterConditional(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	backend := newTestBackend(t, srv)
+
+	write := func(request *frames.WriteRequest, keys []string, xs []int64) *frames.WriteReport {
+		index, err := frames.NewSliceColumn("key", keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		x, err := frames.NewSliceColumn("x", xs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		frame, err := frames.NewFrame([]frames.Column{x}, []frames.Column{index}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		request.Table = "cond"
+		appender, err := backend.Write(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.Add(frame); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.WaitForComplete(10 * time.Second); err != nil {
+			t.Fatal(err)
+		}
+
+		return appender.(frames.ReportingAppender).Report()
+	}
+
+	container, err := backend.newContainer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	itemX := func(key string) interface{} {
+		resp, err := container.Sync.GetItem(&v3io.GetItemInput{Path: "cond/" + key, AttributeNames: []string{"x"}})
+		if v3ioutils.StatusCode(err) == http.StatusNotFound {
+			return nil
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Release()
+
+		return resp.Output.(*v3io.GetItemOutput).Item["x"]
+	}
+
+	report := write(&frames.WriteRequest{}, []string{"a", "b"}, []int64{1, 2})
+	if report.Applied != 2 || len(report.Rows) != 0 {
+		t.Fatalf("bad put report: %+v", report)
+	}
+
+	testCases := []struct {
+		name    string
+		request *frames.WriteRequest
+		keys    []string
+		xs      []int64
+		failed  []string // Keys that fail the condition
+		items   map[string]interface{}
+	}{
+		{
+			name:    "insert",
+			request: &frames.WriteRequest{WriteMode: frames.InsertMode},
+			keys:    []string{"a", "c"},
+			xs:      []int64{10, 30},
+			failed:  []string{"a"},
+			items:   map[string]interface{}{"a": 1, "c": 30},
+		},
+		{
+			name:    "update",
+			request: &frames.WriteRequest{WriteMode: frames.UpdateMode},
+			keys:    []string{"b", "d"},
+			xs:      []int64{20, 40},
+			failed:  []string{"d"},
+			items:   map[string]interface{}{"b": 20, "d": nil},
+		},
+		{
+			name:    "upsert",
+			request: &frames.WriteRequest{WriteMode: frames.UpsertMode},
+			keys:    []string{"c", "e"},
+			xs:      []int64{31, 50},
+			items:   map[string]interface{}{"c": 31, "e": 50},
+		},
+		{
+			name:    "condition",
+			request: &frames.WriteRequest{Condition: "x < {x}"},
+			keys:    []string{"a", "b"},
+			xs:      []int64{100, 0},
+			failed:  []string{"b"},
+			items:   map[string]interface{}{"a": 100, "b": 20},
+		},
+		{
+			name:    "update condition",
+			request: &frames.WriteRequest{WriteMode: frames.UpdateMode, Condition: "x > 50"},
+			keys:    []string{"a", "e", "f"},
+			xs:      []int64{101, 51, 60},
+			failed:  []string{"e", "f"},
+			items:   map[string]interface{}{"a": 101, "e": 50, "f": nil},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report := write(tc.request, tc.keys, tc.xs)
+			if int(report.Applied) != len(tc.keys)-len(tc.failed) || int(report.ConditionFailed) != len(tc.failed) || report.Failed != 0 {
+				t.Fatalf("bad report: %+v", report)
+			}
+
+			var failed []string
+			for _, row := range report.Rows {
+				if row.Status != frames.RowConditionFailed || tc.keys[row.Row] != row.Key {
+					t.Fatalf("bad row outcome: %+v", row)
+				}
+				failed = append(failed, row.Key)
+			}
+
+			if fmt.Sprint(failed) != fmt.Sprint(tc.failed) {
+				t.Fatalf("bad failed rows: %v != %v", failed, tc.failed)
+			}
+
+			for key, x := range tc.items {
+				if val := itemX(key); fmt.Sprint(val) != fmt.Sprint(x) {
+					t.Fatalf("bad %q value: %v != %v", key, val, x)
+				}
+			}
+		})
+	}
+
+	// Failed rows of conditional writes are reported and don't fail the write
+	srv.Fail("PutItem", 1, http.StatusBadRequest)
+	report = write(&frames.WriteRequest{WriteMode: frames.InsertMode}, []string{"g"}, []int64{70})
+	if report.Failed != 1 || len(report.Rows) != 1 || report.Rows[0].Status != frames.RowFailed {
+		t.Fatalf("bad failed report: %+v", report)
+	}
+}
+
+func TestWriterBoolCondition(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	backend := newTestBackend(t, srv)
+
+	write := func(request *frames.WriteRequest, xs []int64, flags []bool) *frames.WriteReport {
+		index, err := frames.NewSliceColumn("key", []string{"a", "b"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		x, err := frames.NewSliceColumn("x", xs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		flag, err := frames.NewSliceColumn("flag", flags)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		labels := map[string]interface{}{"on": true}
+		frame, err := frames.NewFrame([]frames.Column{x, flag}, []frames.Column{index}, labels)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		request.Table = "bools"
+		appender, err := backend.Write(context.Background(), request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.Add(frame); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := appender.WaitForComplete(10 * time.Second); err != nil {
+			t.Fatal(err)
+		}
+
+		return appender.(frames.ReportingAppender).Report()
+	}
+
+	write(&frames.WriteRequest{}, []int64{1, 2}, []bool{true, false})
+
+	// Booleans are stored as 1/0, conditions on them use the same values
+	report := write(&frames.WriteRequest{Condition: "flag == {flag}"}, []int64{10, 20}, []bool{true, true})
+	if report.Applied != 1 || report.ConditionFailed != 1 || report.Rows[0].Key != "b" {
+		t.Fatalf("bad column condition report: %+v", report)
+	}
+
+	report = write(&frames.WriteRequest{Condition: "flag == {on}"}, []int64{100, 200}, []bool{true, false})
+	if report.Applied != 1 || report.ConditionFailed != 1 || report.Rows[0].Key != "b" {
+		t.Fatalf("bad label condition report: %+v", report)
+	}
+}
+
+func TestWriterExpression(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	backend := newTestBackend(t, srv)
+
+	index, err := frames.NewSliceColumn("key", []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := frames.NewSliceColumn("x", []float64{0.1234567891, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := frames.NewSliceColumn("s", []string{"it's", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, err := frames.NewFrame([]frames.Column{x, s}, []frames.Column{index}, map[string]interface{}{"n": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(expr string) error {
+		appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: "expr", Expression: expr})
+		if err != nil {
+			return err
+		}
+
+		if err := appender.Add(frame); err != nil {
+			return err
+		}
+
+		return appender.WaitForComplete(10 * time.Second)
+	}
+
+	if err := write("SET x = {x}; SET s = {s}; SET n = {n}"); err != nil {
+		t.Fatal(err)
+	}
+
+	container, err := backend.newContainer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := container.Sync.GetItem(&v3io.GetItemInput{Path: "expr/a", AttributeNames: []string{"*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Release()
+
+	item := resp.Output.(*v3io.GetItemOutput).Item
+	if item["x"] != 0.1234567891 || item["s"] != "it's" || fmt.Sprint(item["n"]) != "3" {
+		t.Fatalf("bad item: %v", item)
+	}
+
+	// Template errors are reported before any item is written
+	if err := write("SET y = {x}; SET z = {nope}"); err == nil {
+		t.Fatal("no error on unknown placeholder")
+	}
+
+	resp, err = container.Sync.GetItem(&v3io.GetItemInput{Path: "expr/b", AttributeNames: []string{"*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Release()
+
+	if item := resp.Output.(*v3io.GetItemOutput).Item; item["y"] != nil {
+		t.Fatalf("item updated by bad template: %v", item)
+	}
+
+	if err := write("SET y = {x"); err == nil {
+		t.Fatal("no error on bad template")
+	}
+}
+
+func TestWriterSchema(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	backend := newTestBackend(t, srv)
+
+	write := func(keys []string, columns ...frames.Column) error {
+		index, err := frames.NewSliceColumn("key", keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		frame, err := frames.NewFrame(columns, []frames.Column{index}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: "schema"})
+		if err != nil {
+			return err
+		}
+
+		if err := appender.Add(frame); err != nil {
+			return err
+		}
+
+		return appender.WaitForComplete(10 * time.Second)
+	}
+
+	column := func(name string, data interface{}) frames.Column {
+		col, err := frames.NewSliceColumn(name, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return col
+	}
+
+	if err := write([]string{"a", "b"}, column("x", []int64{1, 2}), column("b", []bool{true, false})); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sparse attribute and x widened to double
+	if err := write([]string{"c"}, column("x", []float64{3.5}), column("y", []string{"why"})); err != nil {
+		t.Fatal(err)
+	}
+
+	backend.strictSchema = true
+	if err := write([]string{"d"}, column("x", []string{"nope"})); err == nil {
+		t.Fatal("strict schema type change")
+	}
+
+	info, err := backend.Describe(context.Background(), &frames.DescribeRequest{Table: "schema"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields []string
+	for _, field := range info.Schema.Fields {
+		fields = append(fields, field.Name+":"+field.Type)
+	}
+	if fmt.Sprint(fields) != "[x:double b:boolean y:string]" {
+		t.Fatalf("bad schema fields: %v", fields)
+	}
+
+	// Frames of one row, so every frame has all the columns with the schema types
+	it, err := backend.Read(context.Background(), &frames.ReadRequest{Table: "schema", MessageLimit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[string]string)
+	for it.Next() {
+		frame := it.At()
+		if names := frame.Names(); fmt.Sprint(names) != "[x b y]" {
+			t.Fatalf("bad columns: %v", names)
+		}
+
+		x, _ := frame.Column("x")
+		b, _ := frame.Column("b")
+		y, _ := frame.Column("y")
+		if x.DType() != frames.FloatType || b.DType() != frames.BoolType || y.DType() != frames.StringType {
+			t.Fatalf("bad column types: %v %v %v", x.DType(), b.DType(), y.DType())
+		}
+
+		key, _ := frame.Indices()[0].StringAt(0)
+		fx, _ := x.FloatAt(0)
+		fb, _ := b.BoolAt(0)
+		values[key] = fmt.Sprintf("%v %v %v %v", fx, fb, b.IsNull(0), y.IsNull(0))
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"a": "1 true false true",
+		"b": "2 false false true",
+		"c": "3.5 false true false",
+	}
+	if fmt.Sprint(values) != fmt.Sprint(expected) {
+		t.Fatalf("bad values: %v", values)
+	}
+}
+
+func TestWriterOldSchema(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	backend := newTestBackend(t, srv)
+
+	container, err := backend.newContainer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Old schemas have duplicate fields and no boolean type
+	old := `{"fields": [
+		{"name": "x", "type": "long"},
+		{"name": "x", "type": "long", "nullable": true},
+		{"name": "b", "type": ""}
+	], "key": "key", "hashingBucketNum": 0}`
+	err = container.Sync.PutObject(&v3io.PutObjectInput{Path: "old/" + v3ioutils.SchemaObject, Body: []byte(old)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := backend.Describe(context.Background(), &frames.DescribeRequest{Table: "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fields := info.Schema.Fields; len(fields) != 1 || fields[0].Name != "x" || fields[0].Type != "long" {
+		t.Fatalf("bad migrated schema: %v", fields)
+	}
 }
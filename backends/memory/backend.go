@@ -0,0 +1,735 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/ops"
+)
+
+// Backend is an in-memory backend, tables are lists of frames. Frames with an
+// index are upserted (KV style), rows with the same index value as a new row
+// are replaced
+type Backend struct {
+	logger logger.Logger
+	lock   sync.RWMutex
+	tables map[string]*table
+}
+
+// table is a list of frames, frames are never modified so readers can use the
+// list while it's replaced by writers
+type table struct {
+	frames []frames.Frame
+	schema *frames.TableSchema // from Create
+}
+
+// NewBackend returns a new memory backend. Tables can be seeded with the
+// "tables" option, a map of table name to a list of rows or to an object with
+// "rows" and "index" (index column name), e.g.
+//
+//	tables:
+//	  users:
+//	    index: "id"
+//	    rows:
+//	    - {"id": 1, "name": "a"}
+//
+// Integral numbers are read as integers
+func NewBackend(logger logger.Logger, config *frames.BackendConfig, framesConfig *frames.Config) (frames.DataBackend, error) {
+	backend := &Backend{
+		logger: logger.GetChild("memory"),
+		tables: make(map[string]*table),
+	}
+
+	if err := backend.seed(config.Options); err != nil {
+		return nil, errors.Wrap(err, "bad memory backend options")
+	}
+
+	return backend, nil
+}
+
+// seed creates the tables in the "tables" option
+func (b *Backend) seed(options map[string]interface{}) error {
+	value, ok := options["tables"]
+	if !ok {
+		return nil
+	}
+
+	tables, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("tables - not a map (%T)", value)
+	}
+
+	for name, value := range tables {
+		var rowList []interface{}
+		index := ""
+		switch value := value.(type) {
+		case []interface{}:
+			rowList = value
+		case map[string]interface{}:
+			if rowList, ok = value["rows"].([]interface{}); !ok {
+				return fmt.Errorf("table %q - rows is not a list (%T)", name, value["rows"])
+			}
+
+			if idx, ok := value["index"]; ok {
+				if index, ok = idx.(string); !ok {
+					return fmt.Errorf("table %q - index is not a string (%T)", name, idx)
+				}
+			}
+		default:
+			return fmt.Errorf("table %q - not a list or a map (%T)", name, value)
+		}
+
+		frame, err := seedFrame(rowList, index)
+		if err != nil {
+			return errors.Wrapf(err, "table %q", name)
+		}
+
+		tbl := &table{}
+		if frame.Len() > 0 {
+			tbl.frames = upsert(nil, frame)
+		}
+		b.tables[name] = tbl
+	}
+
+	return nil
+}
+
+// seedFrame returns a frame from config rows
+func seedFrame(rowList []interface{}, index string) (frames.Frame, error) {
+	rows := make([]map[string]interface{}, len(rowList))
+	integral := make(map[string]bool)
+	for i, value := range rowList {
+		row, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("row %d is not a map (%T)", i, value)
+		}
+
+		for name, value := range row {
+			if value == nil {
+				continue
+			}
+
+			f, ok := value.(float64)
+			isIntegral, seen := integral[name]
+			integral[name] = (isIntegral || !seen) && ok && f == math.Trunc(f)
+		}
+		rows[i] = row
+	}
+
+	for _, row := range rows {
+		for name, value := range row {
+			if integral[name] && value != nil {
+				row[name] = int64(value.(float64))
+			}
+		}
+	}
+
+	var indices []string
+	if index != "" {
+		indices = []string{index}
+	}
+
+	return frames.NewFrameFromRows(rows, indices, nil)
+}
+
+// Create creates an empty table, the schema is returned by Describe until the
+// table has data
+func (b *Backend) Create(ctx context.Context, request *frames.CreateRequest) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, ok := b.tables[request.Table]; ok {
+		if request.IfExists == frames.IgnoreError {
+			return nil
+		}
+		return fmt.Errorf("table %q already exists", request.Table)
+	}
+
+	b.tables[request.Table] = &table{schema: request.Schema}
+	return nil
+}
+
+// Delete deletes a table, with a filter only the matching rows are deleted
+func (b *Backend) Delete(ctx context.Context, request *frames.DeleteRequest) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	tbl, ok := b.tables[request.Table]
+	if !ok {
+		if request.IfMissing == frames.IgnoreError {
+			return nil
+		}
+		return fmt.Errorf("table %q doesn't exist", request.Table)
+	}
+
+	if request.Filter == "" {
+		delete(b.tables, request.Table)
+		return nil
+	}
+
+	filter, err := utils.ParseFilter(request.Filter)
+	if err != nil {
+		return err
+	}
+
+	pred := ops.Not(withNulls(filter.Predicate(), filter.Columns()))
+	var kept []frames.Frame
+	for _, frame := range tbl.frames {
+		frame, err := ops.Filter(frame, pred)
+		if err != nil {
+			return err
+		}
+
+		if frame.Len() > 0 {
+			kept = append(kept, frame)
+		}
+	}
+
+	b.tables[request.Table] = &table{frames: kept, schema: tbl.schema}
+	return nil
+}
+
+// Read reads a table
+func (b *Backend) Read(ctx context.Context, request *frames.ReadRequest) (frames.FrameIterator, error) {
+	tbl, err := b.table(request.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	it := &frameIterator{
+		ctx:        ctx,
+		frames:     tbl.frames,
+		columns:    request.Columns,
+		limit:      int(request.Limit),
+		frameLimit: int(request.MessageLimit),
+	}
+
+	columns := tableColumns(tbl.frames)
+	for _, name := range request.Columns {
+		if len(tbl.frames) > 0 && !columns[name] {
+			return nil, fmt.Errorf("column %q not found in %q", name, request.Table)
+		}
+	}
+
+	if request.Filter != "" {
+		filter, err := utils.ParseFilter(request.Filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range filter.Columns() {
+			if len(tbl.frames) > 0 && !columns[name] {
+				return nil, fmt.Errorf("column %q not found in %q", name, request.Table)
+			}
+		}
+		it.pred = withNulls(filter.Predicate(), filter.Columns())
+	}
+
+	return it, nil
+}
+
+// Write writes frames to a table, frames are added to the table in
+// WaitForComplete
+func (b *Backend) Write(ctx context.Context, request *frames.WriteRequest) (frames.FrameAppender, error) {
+	switch request.SaveMode {
+	case frames.OverwriteMode, frames.AppendMode, frames.AppendNewColumnsMode:
+	default:
+		return nil, fmt.Errorf("unknown save mode - %s", request.SaveMode)
+	}
+
+	if request.Expression != "" {
+		return nil, fmt.Errorf("memory backend does not support update expressions")
+	}
+
+	appender := &appender{
+		ctx:     ctx,
+		logger:  b.logger,
+		backend: b,
+		table:   request.Table,
+		mode:    request.SaveMode,
+	}
+
+	if request.ImmidiateData != nil {
+		if err := appender.Add(request.ImmidiateData); err != nil {
+			return nil, errors.Wrap(err, "can't Add ImmidiateData")
+		}
+	}
+
+	return appender, nil
+}
+
+// Exec executes a command
+func (b *Backend) Exec(ctx context.Context, request *frames.ExecRequest) (frames.Frame, error) {
+	if strings.ToLower(request.Command) == "ping" {
+		b.logger.Info("PONG")
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("memory backend does not support %q exec command", request.Command)
+}
+
+// List lists the tables starting with request.Path
+func (b *Backend) List(ctx context.Context, request *frames.ListRequest) ([]string, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	var tables []string
+	for name := range b.tables {
+		if strings.HasPrefix(name, request.Path) {
+			tables = append(tables, name)
+		}
+	}
+
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// Describe returns the schema of the first frame in the table (or of the
+// Create request) and the number of rows
+func (b *Backend) Describe(ctx context.Context, request *frames.DescribeRequest) (*frames.TableInfo, error) {
+	tbl, err := b.table(request.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &frames.TableInfo{Name: request.Table, Schema: tbl.schema}
+	if len(tbl.frames) > 0 {
+		frame := tbl.frames[0]
+		schema := &frames.TableSchema{}
+		for _, col := range frame.Indices() {
+			schema.Fields = append(schema.Fields, &frames.SchemaField{Name: col.Name(), Type: utils.DTypeName(col.DType())})
+		}
+
+		for _, name := range frame.Names() {
+			col, err := frame.Column(name)
+			if err != nil {
+				return nil, err
+			}
+			schema.Fields = append(schema.Fields, &frames.SchemaField{Name: name, Type: utils.DTypeName(col.DType())})
+		}
+
+		if len(frame.Indices()) > 0 {
+			schema.Key = &frames.SchemaKey{ShardingKey: []string{frame.Indices()[0].Name()}}
+		}
+		info.Schema = schema
+	}
+
+	numRows := int64(0)
+	for _, frame := range tbl.frames {
+		numRows += int64(frame.Len())
+	}
+
+	if err := info.SetAttribute("num_rows", numRows); err != nil {
+		return nil, errors.Wrap(err, "can't set num_rows attribute")
+	}
+
+	return info, nil
+}
+
+// Capabilities returns the backend capabilities
+func (b *Backend) Capabilities() *frames.Capabilities {
+	return &frames.Capabilities{
+		Operations: []string{
+			frames.ReadOperation, frames.WriteOperation, frames.CreateOperation,
+			frames.DeleteOperation, frames.ExecOperation, frames.ListOperation,
+			frames.DescribeOperation,
+		},
+		ExecCommands: []*frames.ExecCommandInfo{
+			{Name: "ping", Doc: "check the backend is alive"},
+		},
+		FilterDialect:   "sql",
+		FilterOperators: b.Pushdown().FilterOperators,
+		Dtypes:          frames.AllDTypes(),
+	}
+}
+
+// Pushdown returns the read request parts handled by the backend, columns and
+// filters in the KV filter syntax
+func (b *Backend) Pushdown() *frames.Pushdown {
+	return &frames.Pushdown{
+		Columns:         true,
+		FilterOperators: utils.FilterOperators,
+	}
+}
+
+func (b *Backend) table(name string) (*table, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	tbl, ok := b.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("table %q doesn't exist", name)
+	}
+
+	return tbl, nil
+}
+
+// frameIterator iterates over table frames
+type frameIterator struct {
+	ctx        context.Context
+	frames     []frames.Frame
+	pred       ops.Predicate
+	columns    []string
+	limit      int
+	frameLimit int
+	current    frames.Frame // rest of current table frame
+	frame      frames.Frame
+	nOut       int
+	err        error
+}
+
+// Next reads the next frame, return true of succeeded
+func (it *frameIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.limit <= 0 || it.nOut < it.limit {
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		if it.current == nil || it.current.Len() == 0 {
+			if len(it.frames) == 0 {
+				return false
+			}
+
+			frame, err := it.prepare(it.frames[0])
+			if err != nil {
+				it.err = err
+				return false
+			}
+			it.current, it.frames = frame, it.frames[1:]
+			continue
+		}
+
+		size := it.current.Len()
+		if it.frameLimit > 0 && size > it.frameLimit {
+			size = it.frameLimit
+		}
+
+		if it.limit > 0 && it.nOut+size > it.limit {
+			size = it.limit - it.nOut
+		}
+
+		frame, err := ops.Slice(it.current, 0, size)
+		if err == nil {
+			it.current, err = ops.Slice(it.current, size, it.current.Len())
+		}
+
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.frame = frame
+		it.nOut += size
+		return true
+	}
+
+	return false
+}
+
+// prepare applies the filter and column selection on a table frame
+func (it *frameIterator) prepare(frame frames.Frame) (frames.Frame, error) {
+	var err error
+	if it.pred != nil {
+		if frame, err = ops.Filter(frame, it.pred); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(it.columns) > 0 {
+		// Index columns are kept as indices
+		var names []string
+		for _, name := range it.columns {
+			if findIndex(frame, name) == nil {
+				names = append(names, name)
+			}
+		}
+
+		if frame, err = ops.Select(addNulls(frame, names), names...); err != nil {
+			return nil, err
+		}
+	}
+
+	return frame, nil
+}
+
+// At return the current Frame
+func (it *frameIterator) At() frames.Frame {
+	return it.frame
+}
+
+// Err returns the last error
+func (it *frameIterator) Err() error {
+	return it.err
+}
+
+// appender collects frames and adds them to the table on completion
+type appender struct {
+	ctx     context.Context
+	logger  logger.Logger
+	backend *Backend
+	table   string
+	mode    frames.SaveMode
+	frames  []frames.Frame
+	closed  bool
+}
+
+// Add adds a frame
+func (a *appender) Add(frame frames.Frame) error {
+	if a.closed {
+		return fmt.Errorf("appender is closed")
+	}
+
+	if err := a.ctx.Err(); err != nil {
+		return err
+	}
+
+	a.logger.DebugWith("adding frame", "table", a.table, "size", frame.Len())
+	a.frames = append(a.frames, frame)
+	return nil
+}
+
+// WaitForComplete adds the frames to the table, in overwrite mode they replace
+// the table frames
+func (a *appender) WaitForComplete(timeout time.Duration) error {
+	if a.closed {
+		return fmt.Errorf("appender is closed")
+	}
+	a.closed = true
+
+	a.backend.lock.Lock()
+	defer a.backend.lock.Unlock()
+
+	tbl := &table{}
+	if old, ok := a.backend.tables[a.table]; ok {
+		tbl.schema = old.schema
+		if a.mode != frames.OverwriteMode {
+			tbl.frames = old.frames
+		}
+	}
+
+	if a.mode == frames.AppendMode && len(tbl.frames) > 0 {
+		if err := checkColumns(tbl.frames, a.frames); err != nil {
+			return err
+		}
+	}
+
+	for _, frame := range a.frames {
+		if frame.Len() > 0 {
+			tbl.frames = upsert(tbl.frames, frame)
+		}
+	}
+
+	a.backend.tables[a.table] = tbl
+	return nil
+}
+
+// checkColumns checks that newFrames have only columns of tableFrames
+func checkColumns(tableFrames []frames.Frame, newFrames []frames.Frame) error {
+	columns := tableColumns(tableFrames)
+	for _, frame := range newFrames {
+		for _, name := range frameNames(frame) {
+			if !columns[name] {
+				return fmt.Errorf("column %q is not in table", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// tableColumns returns the index and column names in frames
+func tableColumns(frs []frames.Frame) map[string]bool {
+	columns := make(map[string]bool)
+	for _, frame := range frs {
+		for _, name := range frameNames(frame) {
+			columns[name] = true
+		}
+	}
+
+	return columns
+}
+
+// withNulls returns pred evaluated with columns missing from the frame as
+// nulls, tables frames may have different columns
+func withNulls(pred ops.Predicate, columns []string) ops.Predicate {
+	return func(frame frames.Frame) (ops.RowPredicate, error) {
+		return pred(addNulls(frame, columns))
+	}
+}
+
+// addNulls returns frame with null columns for names not in frame
+func addNulls(frame frames.Frame, names []string) frames.Frame {
+	existing := make(map[string]bool)
+	for _, name := range frameNames(frame) {
+		existing[name] = true
+	}
+
+	var columns []frames.Column
+	for _, name := range frame.Names() {
+		// Names are from frame
+		col, _ := frame.Column(name)
+		columns = append(columns, col)
+	}
+
+	added := false
+	for _, name := range names {
+		if existing[name] {
+			continue
+		}
+
+		nulls := make([]bool, frame.Len())
+		for i := range nulls {
+			nulls[i] = true
+		}
+
+		col, err := frames.NewNullableSliceColumn(name, make([]string, frame.Len()), nulls)
+		if err != nil {
+			return frame
+		}
+		columns = append(columns, col)
+		existing[name], added = true, true
+	}
+
+	if !added {
+		return frame
+	}
+
+	out, err := frames.NewFrame(columns, frame.Indices(), frame.Labels())
+	if err != nil {
+		return frame
+	}
+
+	return out
+}
+
+// frameNames returns the index and column names of frame
+func frameNames(frame frames.Frame) []string {
+	var names []string
+	for _, col := range frame.Indices() {
+		names = append(names, col.Name())
+	}
+
+	return append(names, frame.Names()...)
+}
+
+// upsert returns tableFrames with frame added. If frame has an index, rows with
+// the same (first) index value are removed from tableFrames and from frame
+// (the last row is kept). The returned list is a new one
+func upsert(tableFrames []frames.Frame, frame frames.Frame) []frames.Frame {
+	out := make([]frames.Frame, 0, len(tableFrames)+1)
+	if len(frame.Indices()) == 0 {
+		return append(append(out, tableFrames...), frame)
+	}
+
+	index := frame.Indices()[0]
+	last := make(map[string]int)
+	for r := 0; r < frame.Len(); r++ {
+		last[keyAt(index, r)] = r
+	}
+
+	if len(last) < frame.Len() {
+		var rows []int
+		for r := 0; r < frame.Len(); r++ {
+			if last[keyAt(index, r)] == r {
+				rows = append(rows, r)
+			}
+		}
+
+		// Indices are in range
+		frame, _ = ops.Take(frame, rows)
+	}
+
+	for _, tableFrame := range tableFrames {
+		key := findIndex(tableFrame, index.Name())
+		if key == nil {
+			out = append(out, tableFrame)
+			continue
+		}
+
+		var rows []int
+		for r := 0; r < tableFrame.Len(); r++ {
+			if _, ok := last[keyAt(key, r)]; !ok {
+				rows = append(rows, r)
+			}
+		}
+
+		switch len(rows) {
+		case tableFrame.Len():
+			out = append(out, tableFrame)
+		case 0:
+			continue
+		default:
+			tableFrame, _ = ops.Take(tableFrame, rows)
+			out = append(out, tableFrame)
+		}
+	}
+
+	return append(out, frame)
+}
+
+// findIndex returns the index column called name, nil if not found
+func findIndex(frame frames.Frame, name string) frames.Column {
+	for _, col := range frame.Indices() {
+		if col.Name() == name {
+			return col
+		}
+	}
+
+	return nil
+}
+
+// keyAt returns the key of row r in index
+func keyAt(index frames.Column, r int) string {
+	if index.IsNull(r) {
+		return ""
+	}
+
+	value, err := utils.ColAt(index, r)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+func init() {
+	if err := backends.Register("memory", NewBackend); err != nil {
+		panic(err)
+	}
+}
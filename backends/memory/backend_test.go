@@ -0,0 +1,282 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/conformance"
+)
+
+func TestReadWrite(t *testing.T) {
+	backend := newBackend(t, nil)
+	write := func(mode frames.SaveMode, frame frames.Frame) error {
+		request := &frames.WriteRequest{Table: "t", SaveMode: mode, ImmidiateData: frame}
+		appender, err := backend.Write(context.Background(), request)
+		if err != nil {
+			return err
+		}
+		return appender.WaitForComplete(0)
+	}
+
+	frame := makeFrame(t, "id", map[string]interface{}{
+		"id":   []int64{1, 2, 3},
+		"name": []string{"a", "b", "c"},
+		"x":    []float64{1.5, 2.5, 3.5},
+	})
+
+	if err := write(frames.OverwriteMode, frame); err != nil {
+		t.Fatal(err)
+	}
+
+	// Upsert
+	frame = makeFrame(t, "id", map[string]interface{}{
+		"id":   []int64{2, 4, 4},
+		"name": []string{"B", "d", "D"},
+		"x":    []float64{20, 40, 41},
+	})
+
+	if err := write(frames.AppendMode, frame); err != nil {
+		t.Fatal(err)
+	}
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "t", MessageLimit: 2})
+	names := columnValues(t, frs, "name")
+	if expected := []interface{}{"a", "c", "B", "D"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("bad names - %v", names)
+	}
+
+	if len(frs) != 2 {
+		t.Fatalf("bad number of frames - %d", len(frs))
+	}
+
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "t", Filter: "x > 3 AND id <> 4", Columns: []string{"name"}})
+	if names := columnValues(t, frs, "name"); !reflect.DeepEqual(names, []interface{}{"c", "B"}) {
+		t.Fatalf("bad filtered names - %v", names)
+	}
+
+	if fr := frs[0]; len(fr.Names()) != 1 || len(fr.Indices()) != 1 {
+		t.Fatalf("bad columns - %v", fr.Names())
+	}
+
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "t", Limit: 3, MessageLimit: 2})
+	if n := totalRows(frs); n != 3 {
+		t.Fatalf("bad number of rows with limit - %d", n)
+	}
+
+	newColumn := makeFrame(t, "", map[string]interface{}{"y": []int64{1}})
+	if err := write(frames.AppendMode, newColumn); err == nil {
+		t.Fatal("no error appending new column")
+	}
+
+	if err := write(frames.AppendNewColumnsMode, newColumn); err != nil {
+		t.Fatal(err)
+	}
+
+	request := &frames.DeleteRequest{Table: "t", Filter: "exists(y) OR name = 'a'"}
+	if err := backend.Delete(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+
+	frs = readTable(t, backend, &frames.ReadRequest{Table: "t"})
+	if names := columnValues(t, frs, "name"); !reflect.DeepEqual(names, []interface{}{"c", "B", "D"}) {
+		t.Fatalf("bad names after delete - %v", names)
+	}
+
+	info, err := backend.Describe(context.Background(), &frames.DescribeRequest{Table: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if numRows := info.Attributes()["num_rows"]; numRows != int64(3) {
+		t.Fatalf("bad num_rows - %v", numRows)
+	}
+
+	if err := backend.Delete(context.Background(), &frames.DeleteRequest{Table: "t"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := backend.Read(context.Background(), &frames.ReadRequest{Table: "t"}); err == nil {
+		t.Fatal("no error reading deleted table")
+	}
+}
+
+func TestSeed(t *testing.T) {
+	options := map[string]interface{}{
+		"tables": map[string]interface{}{
+			"users": map[string]interface{}{
+				"index": "id",
+				"rows": []interface{}{
+					map[string]interface{}{"id": 1.0, "name": "a", "score": 1.5},
+					map[string]interface{}{"id": 2.0, "name": "b", "score": 2.0},
+				},
+			},
+			"events": []interface{}{
+				map[string]interface{}{"kind": "x"},
+			},
+		},
+	}
+
+	backend := newBackend(t, options)
+	tables, err := backend.List(context.Background(), &frames.ListRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(tables, []string{"events", "users"}) {
+		t.Fatalf("bad tables - %v", tables)
+	}
+
+	frs := readTable(t, backend, &frames.ReadRequest{Table: "users"})
+	if len(frs) != 1 || len(frs[0].Indices()) != 1 {
+		t.Fatalf("bad frames - %v", frs)
+	}
+
+	if dtype := frs[0].Indices()[0].DType(); dtype != frames.IntType {
+		t.Fatalf("bad index type - %v", dtype)
+	}
+
+	score, err := frs[0].Column("score")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if score.DType() != frames.FloatType {
+		t.Fatalf("bad score type - %v", score.DType())
+	}
+
+	bad := map[string]interface{}{"tables": map[string]interface{}{"t": 1}}
+	if err := newBackendErr(bad); err == nil {
+		t.Fatal("no error on bad seed")
+	}
+}
+
+func readTable(t *testing.T, backend *Backend, request *frames.ReadRequest) []frames.Frame {
+	it, err := backend.Read(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frs []frames.Frame
+	for it.Next() {
+		frs = append(frs, it.At())
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return frs
+}
+
+func columnValues(t *testing.T, frs []frames.Frame, name string) []interface{} {
+	var values []interface{}
+	for _, frame := range frs {
+		col, err := frame.Column(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < col.Len(); i++ {
+			value, err := col.StringAt(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func totalRows(frs []frames.Frame) int {
+	total := 0
+	for _, frame := range frs {
+		total += frame.Len()
+	}
+
+	return total
+}
+
+func makeFrame(t *testing.T, index string, data map[string]interface{}) frames.Frame {
+	var columns, indices []frames.Column
+	for _, name := range []string{"id", "name", "x", "y"} {
+		values, ok := data[name]
+		if !ok {
+			continue
+		}
+
+		col, err := frames.NewSliceColumn(name, values)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if name == index {
+			indices = append(indices, col)
+		} else {
+			columns = append(columns, col)
+		}
+	}
+
+	frame, err := frames.NewFrame(columns, indices, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return frame
+}
+
+func newBackend(t *testing.T, options map[string]interface{}) *Backend {
+	logger, err := frames.NewLogger("debug")
+	if err != nil {
+		t.Fatalf("can't create logger - %s", err)
+	}
+
+	cfg := &frames.BackendConfig{Name: "memory", Type: "memory", Options: options}
+	backend, err := NewBackend(logger, cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return backend.(*Backend)
+}
+
+func newBackendErr(options map[string]interface{}) error {
+	logger, err := frames.NewLogger("debug")
+	if err != nil {
+		return err
+	}
+
+	cfg := &frames.BackendConfig{Name: "memory", Type: "memory", Options: options}
+	_, err = NewBackend(logger, cfg, nil)
+	return err
+}
+
+func TestConformance(t *testing.T) {
+	factory := func(t *testing.T) frames.DataBackend {
+		return newBackend(t, nil)
+	}
+
+	conformance.Run(t, factory, &conformance.Options{Index: "key", Labels: true})
+}
@@ -0,0 +1,595 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package parquet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends"
+	"github.com/v3io/frames/backends/utils"
+	"github.com/v3io/frames/ops"
+	"github.com/v3io/frames/pb"
+)
+
+const (
+	defaultRowGroupSize = 64 * 1024
+	// Key value metadata with index column names (JSON list)
+	indicesKey = "frames.indices"
+	// pandas metadata, we use index_columns
+	pandasKey = "pandas"
+)
+
+var codecs = map[string]int32{
+	"none":   uncompressed,
+	"snappy": snappyCodec,
+	"gzip":   gzipCodec,
+}
+
+// Backend is Parquet backend, tables are Parquet files under rootDir.
+// Options are "compression" ("snappy", "gzip" or "none") and "rowGroupSize"
+// (number of rows in a row group)
+type Backend struct {
+	rootDir      string
+	codec        int32
+	rowGroupSize int
+	logger       logger.Logger
+}
+
+// NewBackend returns a new Parquet backend
+func NewBackend(logger logger.Logger, config *frames.BackendConfig, framesConfig *frames.Config) (frames.DataBackend, error) {
+	compression, err := utils.StringOption(config.Options, "compression", "snappy")
+	if err != nil {
+		return nil, err
+	}
+
+	codec, ok := codecs[strings.ToLower(compression)]
+	if !ok {
+		return nil, fmt.Errorf("unknown compression - %q", compression)
+	}
+
+	rowGroupSize, err := utils.IntOption(config.Options, "rowGroupSize", defaultRowGroupSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if rowGroupSize <= 0 {
+		return nil, fmt.Errorf("bad rowGroupSize - %d", rowGroupSize)
+	}
+
+	backend := &Backend{
+		rootDir:      config.RootDir,
+		codec:        codec,
+		rowGroupSize: rowGroupSize,
+		logger:       logger.GetChild("parquet"),
+	}
+
+	return backend, nil
+}
+
+// Create creates an empty table with the request schema. Field properties are
+// "nullable" (default true) and "index" (default false)
+func (b *Backend) Create(ctx context.Context, request *frames.CreateRequest) error {
+	filePath := b.tablePath(request.Table)
+	if fileExists(filePath) {
+		if request.IfExists == frames.IgnoreError {
+			return nil
+		}
+		return fmt.Errorf("table %q already exists", request.Table)
+	}
+
+	if request.Schema == nil || len(request.Schema.Fields) == 0 {
+		return fmt.Errorf("missing schema")
+	}
+
+	var columns []*columnSchema
+	var indices []string
+	for i, field := range request.Schema.Fields {
+		if field.Name == "" {
+			return fmt.Errorf("field %d with no name", i)
+		}
+
+		dtype, err := utils.SchemaDType(field.Type)
+		if err != nil {
+			return errors.Wrapf(err, "field %q", field.Name)
+		}
+
+		nullable := true
+		if val, ok := field.Property("nullable"); ok {
+			nullable, _ = val.(bool)
+		}
+
+		col := newColumnSchema(field.Name, dtype, nullable)
+		if val, ok := field.Property("index"); ok && val == true {
+			indices = append(indices, field.Name)
+		}
+		columns = append(columns, col)
+	}
+
+	w, err := b.newWriter(ctx, request.Table, columns, indices)
+	if err != nil {
+		return err
+	}
+
+	return w.WaitForComplete(0)
+}
+
+// Delete deletes a table
+func (b *Backend) Delete(ctx context.Context, request *frames.DeleteRequest) error {
+	if request.Filter != "" {
+		return fmt.Errorf("Parquet backend does not support filtered delete")
+	}
+
+	filePath := b.tablePath(request.Table)
+	if !fileExists(filePath) {
+		if request.IfMissing == frames.IgnoreError {
+			return nil
+		}
+		return fmt.Errorf("table %q doesn't exist", request.Table)
+	}
+
+	if err := os.Remove(filePath); err != nil {
+		return errors.Wrapf(err, "can't delete %q", request.Table)
+	}
+
+	return nil
+}
+
+// Read reads a table, row groups that can't match the filter (by column
+// statistics) are skipped
+func (b *Backend) Read(ctx context.Context, request *frames.ReadRequest) (frames.FrameIterator, error) {
+	reader, err := openFile(b.tablePath(request.Table))
+	if err != nil {
+		return nil, err
+	}
+
+	it := &frameIterator{
+		ctx:        ctx,
+		logger:     b.logger,
+		reader:     reader,
+		indices:    indexNames(reader.meta),
+		limit:      int(request.Limit),
+		frameLimit: int(request.MessageLimit),
+	}
+
+	if err := it.selectColumns(request.Columns); err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	if request.Filter != "" {
+		it.filter, err = newRowGroupFilter(request.Filter, reader.meta)
+		if err != nil {
+			// The filter is evaluated in process anyway
+			b.logger.WarnWith("can't use filter to skip row groups", "error", err)
+		}
+	}
+
+	return it, nil
+}
+
+// Write writes a table, if the table exists and is empty (e.g. from Create)
+// frames must match its schema, otherwise the table is overwritten and the
+// first frame determines the schema
+func (b *Backend) Write(ctx context.Context, request *frames.WriteRequest) (frames.FrameAppender, error) {
+	if request.SaveMode != frames.OverwriteMode {
+		return nil, fmt.Errorf("Parquet backend does not support %s save mode", request.SaveMode)
+	}
+
+	var columns []*columnSchema
+	var indices []string
+	if reader, err := openFile(b.tablePath(request.Table)); err == nil {
+		if reader.meta.numRows == 0 {
+			columns, indices = reader.meta.columns, indexNames(reader.meta)
+		}
+		reader.Close()
+	}
+
+	w, err := b.newWriter(ctx, request.Table, columns, indices)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.ImmidiateData != nil {
+		if err := w.Add(request.ImmidiateData); err != nil {
+			w.abort()
+			return nil, errors.Wrap(err, "can't Add ImmidiateData")
+		}
+	}
+
+	return w, nil
+}
+
+// Exec executes a command
+func (b *Backend) Exec(ctx context.Context, request *frames.ExecRequest) (frames.Frame, error) {
+	return nil, fmt.Errorf("Parquet backend does not support %q exec command", request.Command)
+}
+
+// List lists the tables (files) in root directory
+func (b *Backend) List(ctx context.Context, request *frames.ListRequest) ([]string, error) {
+	dir := filepath.Join(b.rootDir, request.Path)
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't list %q", request.Path)
+	}
+
+	var tables []string
+	for _, info := range infos {
+		if !info.Mode().IsRegular() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+
+		tables = append(tables, path.Join(request.Path, info.Name()))
+	}
+
+	return tables, nil
+}
+
+// Describe returns the table schema from the file metadata
+func (b *Backend) Describe(ctx context.Context, request *frames.DescribeRequest) (*frames.TableInfo, error) {
+	reader, err := openFile(b.tablePath(request.Table))
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+	meta := reader.meta
+	indices := make(map[string]bool)
+	for _, name := range indexNames(meta) {
+		indices[name] = true
+	}
+
+	schema := &frames.TableSchema{}
+	for _, col := range meta.columns {
+		dtype, err := col.dtype()
+		if err != nil {
+			return nil, err
+		}
+
+		field := &frames.SchemaField{
+			Name: col.name,
+			Type: utils.DTypeName(dtype),
+			Properties: map[string]*pb.Value{
+				"nullable": &pb.Value{Value: &pb.Value_Bval{Bval: col.repetition == optional}},
+			},
+		}
+
+		if indices[col.name] {
+			field.Properties["index"] = &pb.Value{Value: &pb.Value_Bval{Bval: true}}
+		}
+
+		schema.Fields = append(schema.Fields, field)
+	}
+
+	info := &frames.TableInfo{Name: request.Table, Schema: schema}
+	attrs := map[string]interface{}{
+		"num_rows":   meta.numRows,
+		"row_groups": int64(len(meta.rowGroups)),
+		"created_by": meta.createdBy,
+	}
+
+	for key, value := range attrs {
+		if err := info.SetAttribute(key, value); err != nil {
+			return nil, errors.Wrapf(err, "can't set %q attribute", key)
+		}
+	}
+
+	return info, nil
+}
+
+// Capabilities returns the backend capabilities
+func (b *Backend) Capabilities() *frames.Capabilities {
+	return &frames.Capabilities{
+		Operations: []string{
+			frames.ReadOperation, frames.WriteOperation, frames.CreateOperation,
+			frames.DeleteOperation, frames.ListOperation, frames.DescribeOperation,
+		},
+		FilterDialect: "sql",
+		Dtypes:        frames.AllDTypes(),
+	}
+}
+
+// Pushdown returns the read request parts handled by the backend, it reads
+// only the requested columns and uses the filter to skip row groups
+func (b *Backend) Pushdown() *frames.Pushdown {
+	return &frames.Pushdown{
+		Columns:    true,
+		FilterHint: true,
+	}
+}
+
+func (b *Backend) tablePath(table string) string {
+	return fmt.Sprintf("%s/%s", b.rootDir, table)
+}
+
+// newColumnSchema returns the column schema we write for dtype
+func newColumnSchema(name string, dtype frames.DType, nullable bool) *columnSchema {
+	col := &columnSchema{
+		name:       name,
+		repetition: required,
+		converted:  noConverted,
+	}
+
+	if nullable {
+		col.repetition = optional
+	}
+
+	switch dtype {
+	case frames.IntType:
+		col.physical = int64Type
+	case frames.FloatType:
+		col.physical = doubleType
+	case frames.StringType:
+		col.physical = byteArrayType
+		col.converted = utf8Converted
+		col.logical = stringLogical
+	case frames.TimeType:
+		col.physical = int64Type
+		col.logical = timestampLogical
+		col.timeUnit = nanos
+	case frames.BoolType:
+		col.physical = booleanType
+	}
+
+	return col
+}
+
+// indexNames returns the index columns names from file metadata, we support
+// ours and pandas
+func indexNames(meta *fileMetadata) []string {
+	if data, ok := meta.kv[indicesKey]; ok {
+		var names []string
+		if err := json.Unmarshal([]byte(data), &names); err == nil {
+			return names
+		}
+	}
+
+	if data, ok := meta.kv[pandasKey]; ok {
+		var pandasMeta struct {
+			IndexColumns []interface{} `json:"index_columns"`
+		}
+
+		if err := json.Unmarshal([]byte(data), &pandasMeta); err == nil {
+			var names []string
+			for _, col := range pandasMeta.IndexColumns {
+				// RangeIndex is a JSON object and is not stored as a column
+				if name, ok := col.(string); ok {
+					names = append(names, name)
+				}
+			}
+			return names
+		}
+	}
+
+	return nil
+}
+
+// frameIterator iterates over row groups, a row group is split to frames of
+// at most frameLimit rows
+type frameIterator struct {
+	ctx        context.Context
+	logger     logger.Logger
+	reader     *fileReader
+	columns    []int // column indices to read
+	indices    []string
+	filter     *rowGroupFilter // nil if there's no filter
+	rowGroup   int             // next row group
+	pending    frames.Frame    // current row group data
+	offset     int             // offset in pending
+	limit      int
+	frameLimit int
+	nRows      int
+	frame      frames.Frame
+	err        error
+	done       bool
+}
+
+func (it *frameIterator) selectColumns(names []string) error {
+	byName := make(map[string]int)
+	for i, col := range it.reader.meta.columns {
+		if _, err := col.dtype(); err != nil {
+			if len(names) == 0 {
+				return err
+			}
+			continue
+		}
+		byName[col.name] = i
+	}
+
+	if len(names) == 0 {
+		for i := range it.reader.meta.columns {
+			it.columns = append(it.columns, i)
+		}
+		return nil
+	}
+
+	for _, name := range names {
+		i, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("column %q not found", name)
+		}
+		it.columns = append(it.columns, i)
+	}
+
+	return nil
+}
+
+// Next advances to the next frame
+func (it *frameIterator) Next() bool {
+	if it.done {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		return it.fail(err)
+	}
+
+	if it.limit > 0 && it.nRows >= it.limit {
+		return it.finish()
+	}
+
+	for it.pending == nil || it.offset >= it.pending.Len() {
+		if !it.nextRowGroup() {
+			return false
+		}
+	}
+
+	end := it.pending.Len()
+	if it.frameLimit > 0 && it.offset+it.frameLimit < end {
+		end = it.offset + it.frameLimit
+	}
+
+	if it.limit > 0 && it.offset+it.limit-it.nRows < end {
+		end = it.offset + it.limit - it.nRows
+	}
+
+	frame := it.pending
+	if it.offset > 0 || end < frame.Len() {
+		var err error
+		frame, err = ops.Slice(frame, it.offset, end)
+		if err != nil {
+			return it.fail(err)
+		}
+	}
+
+	it.frame = frame
+	it.nRows += frame.Len()
+	it.offset = end
+	return true
+}
+
+// nextRowGroup reads the next row group that can match the filter to
+// it.pending, it returns false when done
+func (it *frameIterator) nextRowGroup() bool {
+	meta := it.reader.meta
+	for ; it.rowGroup < len(meta.rowGroups); it.rowGroup++ {
+		rg := meta.rowGroups[it.rowGroup]
+		if rg.numRows == 0 {
+			continue
+		}
+
+		if it.filter != nil && it.filter.skip(rg) {
+			it.logger.DebugWith("skipping row group", "index", it.rowGroup)
+			continue
+		}
+
+		frame, err := it.readRowGroup(rg)
+		if err != nil {
+			return it.fail(errors.Wrapf(err, "row group %d", it.rowGroup))
+		}
+
+		it.rowGroup++
+		it.pending, it.offset = frame, 0
+		return true
+	}
+
+	return it.finish()
+}
+
+func (it *frameIterator) readRowGroup(rg *rowGroup) (frames.Frame, error) {
+	isIndex := make(map[string]bool)
+	for _, name := range it.indices {
+		isIndex[name] = true
+	}
+
+	var columns, indices []frames.Column
+	for _, i := range it.columns {
+		col := it.reader.meta.columns[i]
+		values, nulls, err := it.reader.readColumn(rg, i)
+		if err != nil {
+			return nil, err
+		}
+
+		dtype, err := col.dtype()
+		if err != nil {
+			return nil, err
+		}
+
+		if dtype == frames.TimeType {
+			ints, ok := values.([]int64)
+			if !ok {
+				return nil, fmt.Errorf("%q - bad time values", col.name)
+			}
+			values = col.toTimes(ints)
+		}
+
+		fcol, err := frames.NewNullableSliceColumn(col.name, values, nulls)
+		if err != nil {
+			return nil, err
+		}
+
+		if isIndex[col.name] {
+			indices = append(indices, fcol)
+		} else {
+			columns = append(columns, fcol)
+		}
+	}
+
+	return frames.NewFrame(columns, indices, nil)
+}
+
+func (it *frameIterator) fail(err error) bool {
+	it.logger.ErrorWith("read error", "error", err)
+	it.err = err
+	it.finish()
+	return false
+}
+
+func (it *frameIterator) finish() bool {
+	if !it.done {
+		it.done = true
+		if err := it.reader.Close(); err != nil {
+			it.logger.WarnWith("can't close file", "error", err)
+		}
+	}
+	return false
+}
+
+// At returns the current frame
+func (it *frameIterator) At() frames.Frame {
+	return it.frame
+}
+
+// Err returns the last error
+func (it *frameIterator) Err() error {
+	return it.err
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func init() {
+	if err := backends.Register("parquet", NewBackend); err != nil {
+		panic(err)
+	}
+}
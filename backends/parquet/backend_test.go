@@ -0,0 +1,400 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/conformance"
+	"github.com/v3io/frames/pb"
+)
+
+func newBackend(t *testing.T, options map[string]interface{}) *Backend {
+	logger, err := frames.NewLogger("debug")
+	if err != nil {
+		t.Fatalf("can't create logger - %s", err)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "frames-parquet-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &frames.BackendConfig{
+		Name:    "parquet",
+		Type:    "parquet",
+		RootDir: tmpDir,
+		Options: options,
+	}
+
+	backend, err := NewBackend(logger, cfg, &frames.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return backend.(*Backend)
+}
+
+func makeFrame(t *testing.T, start, size int) frames.Frame {
+	var (
+		ints   = make([]int64, size)
+		floats = make([]float64, size)
+		strs   = make([]string, size)
+		times  = make([]time.Time, size)
+		bools  = make([]bool, size)
+		nulls  = make([]bool, size)
+		keys   = make([]string, size)
+	)
+
+	t0 := time.Date(2018, 12, 1, 10, 11, 12, 13, time.UTC)
+	for i := 0; i < size; i++ {
+		n := start + i
+		ints[i] = int64(n)
+		floats[i] = float64(n) / 2
+		strs[i] = fmt.Sprintf("s%03d", n)
+		times[i] = t0.Add(time.Duration(n) * time.Millisecond)
+		bools[i] = n%2 == 0
+		nulls[i] = n%5 == 0
+		keys[i] = fmt.Sprintf("k%d", n)
+	}
+
+	var cols []frames.Column
+	add := func(name string, data interface{}, nulls []bool) {
+		col, err := frames.NewNullableSliceColumn(name, data, nulls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cols = append(cols, col)
+	}
+
+	add("ints", ints, nil)
+	add("floats", floats, nulls)
+	add("strings", strs, nulls)
+	add("times", times, nil)
+	add("bools", bools, nulls)
+
+	index, err := frames.NewSliceColumn("key", keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, err := frames.NewFrame(cols, []frames.Column{index}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return frame
+}
+
+func writeFrames(t *testing.T, backend *Backend, table string, frs ...frames.Frame) {
+	appender, err := backend.Write(context.Background(), &frames.WriteRequest{Table: table})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, frame := range frs {
+		if err := appender.Add(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := appender.WaitForComplete(time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFrames(t *testing.T, backend *Backend, request *frames.ReadRequest) []frames.Frame {
+	it, err := backend.Read(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frs []frames.Frame
+	for it.Next() {
+		frs = append(frs, it.At())
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return frs
+}
+
+func rows(t *testing.T, frs []frames.Frame) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, frame := range frs {
+		it := frame.IterRows(true)
+		for it.Next() {
+			row := it.Row()
+			for name := range row {
+				col, err := frame.Column(name)
+				if err == nil && col.IsNull(it.RowNum()) {
+					row[name] = nil
+				}
+			}
+			out = append(out, row)
+		}
+
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return out
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, compression := range []string{"snappy", "gzip", "none"} {
+		options := map[string]interface{}{
+			"compression":  compression,
+			"rowGroupSize": 30,
+		}
+		backend := newBackend(t, options)
+
+		frame1, frame2 := makeFrame(t, 0, 40), makeFrame(t, 40, 27)
+		writeFrames(t, backend, "t.parquet", frame1, frame2)
+
+		// Tables are readable by others
+		info, err := os.Stat(backend.tablePath("t.parquet"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if mode := info.Mode().Perm(); mode != 0644 {
+			t.Fatalf("%s: bad table file mode - %v", compression, mode)
+		}
+
+		frs := readFrames(t, backend, &frames.ReadRequest{Table: "t.parquet", MessageLimit: 7})
+		for _, frame := range frs {
+			if frame.Len() > 7 {
+				t.Fatalf("%s: frame too big - %d", compression, frame.Len())
+			}
+
+			if len(frame.Indices()) != 1 || frame.Indices()[0].Name() != "key" {
+				t.Fatalf("%s: bad indices", compression)
+			}
+		}
+
+		expected := rows(t, []frames.Frame{frame1, frame2})
+		if actual := rows(t, frs); !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("%s: data mismatch\n%v\n%v", compression, actual, expected)
+		}
+
+		col, err := frs[0].Column("floats")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !col.IsNull(0) || col.IsNull(1) {
+			t.Fatalf("%s: bad nulls", compression)
+		}
+	}
+}
+
+func TestLimitColumns(t *testing.T) {
+	backend := newBackend(t, map[string]interface{}{"rowGroupSize": 10})
+	writeFrames(t, backend, "t", makeFrame(t, 0, 35))
+
+	request := &frames.ReadRequest{
+		Table:   "t",
+		Columns: []string{"strings", "ints"},
+		Limit:   23,
+	}
+
+	frs := readFrames(t, backend, request)
+	nRows := 0
+	for _, frame := range frs {
+		if !reflect.DeepEqual(frame.Names(), request.Columns) {
+			t.Fatalf("bad columns - %v", frame.Names())
+		}
+		nRows += frame.Len()
+	}
+
+	if nRows != 23 {
+		t.Fatalf("bad number of rows - %d", nRows)
+	}
+
+	request.Columns = []string{"nosuch"}
+	if _, err := backend.Read(context.Background(), request); err == nil {
+		t.Fatal("no error on missing column")
+	}
+}
+
+func TestRowGroupSkip(t *testing.T) {
+	backend := newBackend(t, map[string]interface{}{"rowGroupSize": 10})
+	writeFrames(t, backend, "t", makeFrame(t, 0, 10), makeFrame(t, 10, 10), makeFrame(t, 20, 10))
+
+	testCases := []struct {
+		filter string
+		rows   int
+	}{
+		{"ints >= 25", 10},
+		{"ints > 9 and ints < 20", 10},
+		{"ints < 3 or ints > 27", 20},
+		{"17 < ints", 20},
+		{"ints in (1, 2, 21)", 20},
+		{"ints between 12 and 15", 10},
+		{"strings = 's005'", 10},
+		{"times > '2018-12-01 10:11:12'", 30},
+		{"times >= '2018-12-02'", 0},
+		{"ints = 100", 0},
+		{"not ints = 100", 30},
+		{"floats + 1 > 100", 30},
+	}
+
+	for _, tc := range testCases {
+		request := &frames.ReadRequest{Table: "t", Filter: tc.filter}
+		nRows := 0
+		for _, frame := range readFrames(t, backend, request) {
+			nRows += frame.Len()
+		}
+
+		if nRows != tc.rows {
+			t.Fatalf("%s: bad number of rows - %d != %d", tc.filter, nRows, tc.rows)
+		}
+	}
+}
+
+func TestCreateDescribeDelete(t *testing.T) {
+	backend := newBackend(t, nil)
+	notNull := map[string]*pb.Value{"nullable": &pb.Value{Value: &pb.Value_Bval{Bval: false}}}
+	index := map[string]*pb.Value{"index": &pb.Value{Value: &pb.Value_Bval{Bval: true}}}
+	schema := &frames.TableSchema{
+		Fields: []*frames.SchemaField{
+			{Name: "key", Type: "string", Properties: index},
+			{Name: "ints", Type: "integer", Properties: notNull},
+			{Name: "floats", Type: "double"},
+			{Name: "strings", Type: "string"},
+			{Name: "times", Type: "timestamp"},
+			{Name: "bools", Type: "boolean"},
+		},
+	}
+
+	ctx := context.Background()
+	table := "created"
+	if err := backend.Create(ctx, &frames.CreateRequest{Table: table, Schema: schema}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := backend.Create(ctx, &frames.CreateRequest{Table: table, Schema: schema}); err == nil {
+		t.Fatal("no error on existing table")
+	}
+
+	info, err := backend.Describe(ctx, &frames.DescribeRequest{Table: table})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := make(map[string]string)
+	for _, field := range info.Schema.Fields {
+		types[field.Name] = field.Type
+	}
+
+	expected := map[string]string{
+		"key":     "string",
+		"ints":    "integer",
+		"floats":  "float",
+		"strings": "string",
+		"times":   "time",
+		"bools":   "boolean",
+	}
+
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("bad types - %v", types)
+	}
+
+	if nullable, _ := info.Schema.Fields[1].Property("nullable"); nullable != false {
+		t.Fatalf("ints is nullable")
+	}
+
+	// Empty table from Create, frames must match the schema
+	appender, err := backend.Write(ctx, &frames.WriteRequest{Table: table})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	col, _ := frames.NewSliceColumn("other", []int64{1})
+	frame, _ := frames.NewFrame([]frames.Column{col}, nil, nil)
+	if err := appender.Add(frame); err == nil {
+		t.Fatal("no error on schema mismatch")
+	}
+
+	writeFrames(t, backend, table, makeFrame(t, 0, 5))
+	info, err = backend.Describe(ctx, &frames.DescribeRequest{Table: table})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if numRows := info.Attributes()["num_rows"]; numRows != int64(5) {
+		t.Fatalf("bad num_rows - %v", numRows)
+	}
+
+	if err := backend.Delete(ctx, &frames.DeleteRequest{Table: table}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := backend.Delete(ctx, &frames.DeleteRequest{Table: table}); err == nil {
+		t.Fatal("no error on missing table")
+	}
+
+	if err := backend.Delete(ctx, &frames.DeleteRequest{Table: table, IfMissing: frames.IgnoreError}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeHybrid(t *testing.T) {
+	// Example from the Parquet encodings spec, 0-7 bit packed with bit width 3
+	data := []byte{3, 0x88, 0xC6, 0xFA}
+	values, _, err := decodeHybrid(data, 3, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(values, []uint32{0, 1, 2, 3, 4, 5, 6, 7}) {
+		t.Fatalf("bad bit packed values - %v", values)
+	}
+
+	// RLE run of 5 7's
+	values, _, err = decodeHybrid([]byte{10, 7}, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(values, []uint32{7, 7, 7, 7, 7}) {
+		t.Fatalf("bad RLE values - %v", values)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	factory := func(t *testing.T) frames.DataBackend {
+		return newBackend(t, nil)
+	}
+
+	conformance.Run(t, factory, nil)
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/pkg/errors"
+)
+
+var byteOrder = binary.LittleEndian
+
+// Julian day of the Unix epoch (for INT96 timestamps)
+const julianEpoch = 2440588
+
+// Values are decoded to []int64 (integer types and INT96 as nanoseconds),
+// []float64, []string or []bool
+
+// decodePlain decodes n PLAIN encoded values
+func decodePlain(physical int32, data []byte, n int) (interface{}, error) {
+	fixed := map[int32]int{int32Type: 4, int64Type: 8, int96Type: 12, floatType: 4, doubleType: 8}
+	if size, ok := fixed[physical]; ok && len(data) < size*n {
+		return nil, fmt.Errorf("page too small for %d values", n)
+	}
+
+	switch physical {
+	case booleanType:
+		if len(data) < (n+7)/8 {
+			return nil, fmt.Errorf("page too small for %d values", n)
+		}
+		out := make([]bool, n)
+		for i := range out {
+			out[i] = data[i/8]&(1<<uint(i%8)) != 0
+		}
+		return out, nil
+	case int32Type:
+		out := make([]int64, n)
+		for i := range out {
+			out[i] = int64(int32(byteOrder.Uint32(data[4*i:])))
+		}
+		return out, nil
+	case int64Type:
+		out := make([]int64, n)
+		for i := range out {
+			out[i] = int64(byteOrder.Uint64(data[8*i:]))
+		}
+		return out, nil
+	case int96Type:
+		out := make([]int64, n)
+		for i := range out {
+			nsOfDay := int64(byteOrder.Uint64(data[12*i:]))
+			day := int64(byteOrder.Uint32(data[12*i+8:]))
+			out[i] = (day-julianEpoch)*24*3600*1e9 + nsOfDay
+		}
+		return out, nil
+	case floatType:
+		out := make([]float64, n)
+		for i := range out {
+			out[i] = float64(math.Float32frombits(byteOrder.Uint32(data[4*i:])))
+		}
+		return out, nil
+	case doubleType:
+		out := make([]float64, n)
+		for i := range out {
+			out[i] = math.Float64frombits(byteOrder.Uint64(data[8*i:]))
+		}
+		return out, nil
+	case byteArrayType:
+		out := make([]string, n)
+		for i := range out {
+			if len(data) < 4 {
+				return nil, fmt.Errorf("page too small for %d values", n)
+			}
+			size := int(byteOrder.Uint32(data))
+			if size < 0 || len(data)-4 < size {
+				return nil, fmt.Errorf("page too small for %d values", n)
+			}
+			out[i] = string(data[4 : 4+size])
+			data = data[4+size:]
+		}
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("unsupported physical type - %d", physical)
+}
+
+// encodePlain encodes values PLAIN
+func encodePlain(values interface{}) []byte {
+	var buf bytes.Buffer
+	var tmp [8]byte
+	switch values := values.(type) {
+	case []int64:
+		for _, v := range values {
+			byteOrder.PutUint64(tmp[:], uint64(v))
+			buf.Write(tmp[:])
+		}
+	case []float64:
+		for _, v := range values {
+			byteOrder.PutUint64(tmp[:], math.Float64bits(v))
+			buf.Write(tmp[:])
+		}
+	case []string:
+		for _, v := range values {
+			byteOrder.PutUint32(tmp[:], uint32(len(v)))
+			buf.Write(tmp[:4])
+			buf.WriteString(v)
+		}
+	case []bool:
+		data := make([]byte, (len(values)+7)/8)
+		for i, v := range values {
+			if v {
+				data[i/8] |= 1 << uint(i%8)
+			}
+		}
+		buf.Write(data)
+	}
+
+	return buf.Bytes()
+}
+
+// decodeHybrid decodes n values in RLE/bit packed hybrid encoding, it returns
+// the values and the number of bytes read
+func decodeHybrid(data []byte, bitWidth int, n int) ([]uint32, int, error) {
+	if bitWidth < 0 || bitWidth > 32 {
+		return nil, 0, fmt.Errorf("bad bit width - %d", bitWidth)
+	}
+
+	out := make([]uint32, 0, n)
+	pos := 0
+	byteWidth := (bitWidth + 7) / 8
+	for len(out) < n {
+		header, size := binary.Uvarint(data[pos:])
+		if size <= 0 {
+			return nil, 0, fmt.Errorf("bad run header")
+		}
+		pos += size
+
+		if header&1 == 0 { // RLE run
+			count := int(header >> 1)
+			if pos+byteWidth > len(data) {
+				return nil, 0, fmt.Errorf("truncated RLE run")
+			}
+			var value uint32
+			for i := 0; i < byteWidth; i++ {
+				value |= uint32(data[pos+i]) << uint(8*i)
+			}
+			pos += byteWidth
+			for i := 0; i < count && len(out) < n; i++ {
+				out = append(out, value)
+			}
+			continue
+		}
+
+		// Bit packed run of groups of 8 values
+		count := int(header>>1) * 8
+		nBytes := int(header>>1) * bitWidth
+		if nBytes < 0 || pos+nBytes > len(data) {
+			return nil, 0, fmt.Errorf("truncated bit packed run")
+		}
+		packed := data[pos : pos+nBytes]
+		for i := 0; i < count && len(out) < n; i++ {
+			var value uint32
+			for b := 0; b < bitWidth; b++ {
+				bit := i*bitWidth + b
+				if packed[bit/8]&(1<<uint(bit%8)) != 0 {
+					value |= 1 << uint(b)
+				}
+			}
+			out = append(out, value)
+		}
+		pos += nBytes
+	}
+
+	return out, pos, nil
+}
+
+// encodeLevels encodes definition levels (bit width 1) in RLE/bit packed
+// hybrid encoding
+func encodeLevels(defined []bool) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	allSame := true
+	for _, d := range defined {
+		if d != defined[0] {
+			allSame = false
+			break
+		}
+	}
+
+	if allSame {
+		n := binary.PutUvarint(tmp[:], uint64(len(defined))<<1)
+		value := byte(0)
+		if len(defined) > 0 && defined[0] {
+			value = 1
+		}
+		return append(tmp[:n], value)
+	}
+
+	groups := (len(defined) + 7) / 8
+	n := binary.PutUvarint(tmp[:], uint64(groups)<<1|1)
+	buf := append([]byte{}, tmp[:n]...)
+	packed := make([]byte, groups)
+	for i, d := range defined {
+		if d {
+			packed[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return append(buf, packed...)
+}
+
+// decodeDictIndices decodes dictionary indices (bit width byte followed by
+// RLE/bit packed hybrid)
+func decodeDictIndices(data []byte, n int) ([]uint32, error) {
+	if n == 0 {
+		return nil, nil
+	}
+
+	if len(data) < 1 {
+		return nil, fmt.Errorf("missing bit width")
+	}
+
+	indices, _, err := decodeHybrid(data[1:], int(data[0]), n)
+	return indices, err
+}
+
+// takeDict returns dict values at indices
+func takeDict(dict interface{}, indices []uint32) (interface{}, error) {
+	size := valuesLen(dict)
+	for _, i := range indices {
+		if int(i) >= size {
+			return nil, fmt.Errorf("dictionary index out of range - %d >= %d", i, size)
+		}
+	}
+
+	switch dict := dict.(type) {
+	case []int64:
+		out := make([]int64, len(indices))
+		for i, idx := range indices {
+			out[i] = dict[idx]
+		}
+		return out, nil
+	case []float64:
+		out := make([]float64, len(indices))
+		for i, idx := range indices {
+			out[i] = dict[idx]
+		}
+		return out, nil
+	case []string:
+		out := make([]string, len(indices))
+		for i, idx := range indices {
+			out[i] = dict[idx]
+		}
+		return out, nil
+	case []bool:
+		out := make([]bool, len(indices))
+		for i, idx := range indices {
+			out[i] = dict[idx]
+		}
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("unsupported dictionary type - %T", dict)
+}
+
+func valuesLen(values interface{}) int {
+	switch values := values.(type) {
+	case []int64:
+		return len(values)
+	case []float64:
+		return len(values)
+	case []string:
+		return len(values)
+	case []bool:
+		return len(values)
+	}
+	return 0
+}
+
+func compress(codec int32, data []byte) ([]byte, error) {
+	switch codec {
+	case uncompressed:
+		return data, nil
+	case snappyCodec:
+		return snappy.Encode(nil, data), nil
+	case gzipCodec:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported compression codec - %d", codec)
+}
+
+func decompress(codec int32, data []byte, size int) ([]byte, error) {
+	var out []byte
+	var err error
+	switch codec {
+	case uncompressed:
+		out = data
+	case snappyCodec:
+		var n int
+		n, err = snappy.DecodedLen(data)
+		if err == nil && n != size {
+			return nil, fmt.Errorf("bad page size after decompression (%d != %d)", n, size)
+		}
+		if err == nil {
+			out, err = snappy.Decode(nil, data)
+		}
+	case gzipCodec:
+		var r *gzip.Reader
+		r, err = gzip.NewReader(bytes.NewReader(data))
+		if err == nil {
+			out, err = ioutil.ReadAll(io.LimitReader(r, int64(size)+1))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported compression codec - %d", codec)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "can't decompress page")
+	}
+
+	if len(out) != size {
+		return nil, fmt.Errorf("bad page size after decompression (%d != %d)", len(out), size)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,648 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+)
+
+// Unsigned integer converted types (UINT_8 ... UINT_64)
+const (
+	uint8Converted  = 11
+	uint64Converted = 14
+)
+
+// dtype returns the frames data type of the column
+func (c *columnSchema) dtype() (frames.DType, error) {
+	switch c.physical {
+	case booleanType:
+		return frames.BoolType, nil
+	case int32Type:
+		if c.converted == dateConverted || c.logical == dateLogical {
+			return frames.TimeType, nil
+		}
+		return frames.IntType, nil
+	case int64Type:
+		if c.isTimestamp() {
+			return frames.TimeType, nil
+		}
+		return frames.IntType, nil
+	case int96Type:
+		return frames.TimeType, nil
+	case floatType, doubleType:
+		return frames.FloatType, nil
+	case byteArrayType:
+		return frames.StringType, nil
+	}
+
+	return 0, fmt.Errorf("%q - unsupported physical type %d", c.name, c.physical)
+}
+
+func (c *columnSchema) isTimestamp() bool {
+	if c.logical == timestampLogical {
+		return true
+	}
+
+	return c.converted == timestampMillisConv || c.converted == timestampMicrosConv
+}
+
+// unsigned returns true if the column is an unsigned integer, statistics of
+// these columns have unsigned order
+func (c *columnSchema) unsigned() bool {
+	if c.converted >= uint8Converted && c.converted <= uint64Converted {
+		return true
+	}
+
+	return c.logical == integerLogical && c.unsignedInt
+}
+
+// timeUnit returns the duration of a time column unit
+func (c *columnSchema) unit() time.Duration {
+	switch {
+	case c.physical == int96Type:
+		return time.Nanosecond
+	case c.physical == int32Type: // date
+		return 24 * time.Hour
+	case c.logical == timestampLogical:
+		switch c.timeUnit {
+		case millis:
+			return time.Millisecond
+		case micros:
+			return time.Microsecond
+		}
+		return time.Nanosecond
+	case c.converted == timestampMillisConv:
+		return time.Millisecond
+	}
+
+	return time.Microsecond
+}
+
+// toTimes converts time column values to time.Time
+func (c *columnSchema) toTimes(values []int64) []time.Time {
+	unit := int64(c.unit())
+	times := make([]time.Time, len(values))
+	for i, v := range values {
+		times[i] = time.Unix(0, v*unit).UTC()
+	}
+	return times
+}
+
+// fileReader reads a Parquet file
+type fileReader struct {
+	file *os.File
+	size int64
+	meta *fileMetadata
+}
+
+func openFile(path string) (*fileReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &fileReader{file: file}
+	if err := r.readMetadata(); err != nil {
+		file.Close()
+		return nil, errors.Wrapf(err, "%s", path)
+	}
+
+	return r, nil
+}
+
+func (r *fileReader) readMetadata() error {
+	info, err := r.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	r.size = info.Size()
+	if r.size < int64(2*len(magic)+4) {
+		return fmt.Errorf("not a parquet file (too small)")
+	}
+
+	head := make([]byte, len(magic))
+	if _, err := r.file.ReadAt(head, 0); err != nil {
+		return err
+	}
+
+	tail := make([]byte, 4+len(magic))
+	if _, err := r.file.ReadAt(tail, r.size-int64(len(tail))); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(head, magic) || !bytes.Equal(tail[4:], magic) {
+		return fmt.Errorf("not a parquet file (bad magic)")
+	}
+
+	footerSize := int64(byteOrder.Uint32(tail))
+	if footerSize > r.size-int64(2*len(magic)+4) {
+		return fmt.Errorf("bad footer size - %d", footerSize)
+	}
+
+	footer := make([]byte, footerSize)
+	if _, err := r.file.ReadAt(footer, r.size-int64(len(tail))-footerSize); err != nil {
+		return err
+	}
+
+	r.meta, err = decodeFileMetadata(footer)
+	if err != nil {
+		return errors.Wrap(err, "can't decode metadata")
+	}
+
+	return nil
+}
+
+func (r *fileReader) Close() error {
+	return r.file.Close()
+}
+
+// readColumn reads column i in row group rg. It returns the values ([]int64,
+// []float64, []string or []bool) with zero values in null positions, and the
+// nulls (nil if there are no nulls)
+func (r *fileReader) readColumn(rg *rowGroup, i int) (interface{}, []bool, error) {
+	col, chunk := r.meta.columns[i], rg.columns[i]
+	start := chunk.dataPageOffset
+	if chunk.dictPageOffset > 0 && chunk.dictPageOffset < start {
+		start = chunk.dictPageOffset
+	}
+
+	if start < int64(len(magic)) || chunk.compressedSize < 0 || start+chunk.compressedSize > r.size {
+		return nil, nil, fmt.Errorf("%q - column chunk out of file bounds", col.name)
+	}
+
+	buf := make([]byte, chunk.compressedSize)
+	if _, err := r.file.ReadAt(buf, start); err != nil {
+		return nil, nil, errors.Wrapf(err, "%q - can't read column chunk", col.name)
+	}
+
+	cr := &chunkReader{
+		col:    col,
+		chunk:  chunk,
+		reader: bytes.NewReader(buf),
+	}
+
+	if err := cr.read(); err != nil {
+		return nil, nil, errors.Wrapf(err, "%q - can't read column chunk", col.name)
+	}
+
+	if int64(valuesLen(cr.values)) != rg.numRows {
+		return nil, nil, fmt.Errorf("%q - column has %d values, expected %d", col.name, valuesLen(cr.values), rg.numRows)
+	}
+
+	if !cr.hasNulls {
+		cr.nulls = nil
+	}
+
+	return cr.values, cr.nulls, nil
+}
+
+// chunkReader reads the pages of a column chunk
+type chunkReader struct {
+	col      *columnSchema
+	chunk    *columnChunk
+	reader   *bytes.Reader
+	dict     interface{}
+	values   interface{}
+	nulls    []bool
+	hasNulls bool
+}
+
+func (cr *chunkReader) read() error {
+	numValues := 0
+	for int64(numValues) < cr.chunk.numValues {
+		header, err := decodePageHeader(cr.reader)
+		if err != nil {
+			return errors.Wrap(err, "can't read page header")
+		}
+
+		if int(header.compressedSize) > cr.reader.Len() {
+			return fmt.Errorf("page out of column chunk bounds")
+		}
+
+		page := make([]byte, header.compressedSize)
+		if _, err := io.ReadFull(cr.reader, page); err != nil {
+			return err
+		}
+
+		switch header.typ {
+		case dictionaryPage:
+			data, err := decompress(cr.chunk.codec, page, int(header.uncompressedSize))
+			if err != nil {
+				return err
+			}
+
+			if header.encoding != plainEncoding && header.encoding != plainDictionary {
+				return fmt.Errorf("unsupported dictionary encoding - %d", header.encoding)
+			}
+
+			cr.dict, err = decodePlain(cr.col.physical, data, int(header.numValues))
+			if err != nil {
+				return errors.Wrap(err, "can't decode dictionary")
+			}
+		case dataPage:
+			data, err := decompress(cr.chunk.codec, page, int(header.uncompressedSize))
+			if err != nil {
+				return err
+			}
+
+			defined, err := cr.readLevelsV1(&data, int(header.numValues))
+			if err != nil {
+				return err
+			}
+
+			if err := cr.readValues(header, data, defined); err != nil {
+				return err
+			}
+			numValues += int(header.numValues)
+		case dataPageV2:
+			levelsSize := int(header.repLevelsSize + header.defLevelsSize)
+			if header.repLevelsSize < 0 || header.defLevelsSize < 0 || levelsSize > len(page) {
+				return fmt.Errorf("bad levels size")
+			}
+
+			var defined []bool
+			if cr.col.repetition == optional {
+				levels, _, err := decodeHybrid(page[header.repLevelsSize:levelsSize], 1, int(header.numValues))
+				if err != nil {
+					return errors.Wrap(err, "can't decode definition levels")
+				}
+				defined = levelsToDefined(levels)
+			}
+
+			data := page[levelsSize:]
+			if header.compressed {
+				data, err = decompress(cr.chunk.codec, data, int(header.uncompressedSize)-levelsSize)
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := cr.readValues(header, data, defined); err != nil {
+				return err
+			}
+			numValues += int(header.numValues)
+		default:
+			// Index pages and unknown pages are skipped
+		}
+	}
+
+	return nil
+}
+
+// readLevelsV1 reads definition levels from a v1 data page and advances data
+// past them, it returns nil for required columns
+func (cr *chunkReader) readLevelsV1(data *[]byte, n int) ([]bool, error) {
+	if cr.col.repetition != optional {
+		return nil, nil
+	}
+
+	buf := *data
+	if len(buf) < 4 {
+		return nil, fmt.Errorf("missing definition levels")
+	}
+
+	size := int(byteOrder.Uint32(buf))
+	if size < 0 || size > len(buf)-4 {
+		return nil, fmt.Errorf("bad definition levels size - %d", size)
+	}
+
+	levels, _, err := decodeHybrid(buf[4:4+size], 1, n)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't decode definition levels")
+	}
+
+	*data = buf[4+size:]
+	return levelsToDefined(levels), nil
+}
+
+func levelsToDefined(levels []uint32) []bool {
+	defined := make([]bool, len(levels))
+	for i, level := range levels {
+		defined[i] = level == 1
+	}
+	return defined
+}
+
+// readValues decodes page values and appends them (with nulls) to cr.values
+func (cr *chunkReader) readValues(header *pageHeader, data []byte, defined []bool) error {
+	n := int(header.numValues)
+	numDefined := n
+	if defined != nil {
+		numDefined = 0
+		for _, d := range defined {
+			if d {
+				numDefined++
+			}
+		}
+	}
+
+	var values interface{}
+	var err error
+	switch header.encoding {
+	case plainEncoding:
+		values, err = decodePlain(cr.col.physical, data, numDefined)
+	case plainDictionary, rleDictionaryEncoding:
+		if cr.dict == nil {
+			return fmt.Errorf("dictionary encoded page without dictionary")
+		}
+		var indices []uint32
+		indices, err = decodeDictIndices(data, numDefined)
+		if err == nil {
+			values, err = takeDict(cr.dict, indices)
+		}
+	case rleEncoding:
+		if cr.col.physical != booleanType {
+			return fmt.Errorf("RLE encoding of non boolean values")
+		}
+		if len(data) < 4 {
+			return fmt.Errorf("missing RLE values size")
+		}
+		var levels []uint32
+		levels, _, err = decodeHybrid(data[4:], 1, numDefined)
+		if err == nil {
+			values = levelsToDefined(levels)
+		}
+	default:
+		return fmt.Errorf("unsupported encoding - %d", header.encoding)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "can't decode values")
+	}
+
+	if defined == nil {
+		defined = make([]bool, n)
+		for i := range defined {
+			defined[i] = true
+		}
+	}
+
+	return cr.appendValues(values, defined)
+}
+
+// appendValues appends values to cr.values, spreading them over the defined
+// positions
+func (cr *chunkReader) appendValues(values interface{}, defined []bool) error {
+	j := 0
+	for _, d := range defined {
+		cr.nulls = append(cr.nulls, !d)
+		if !d {
+			cr.hasNulls = true
+		}
+	}
+
+	switch values := values.(type) {
+	case []int64:
+		out, _ := cr.values.([]int64)
+		for _, d := range defined {
+			var v int64
+			if d {
+				v, j = values[j], j+1
+			}
+			out = append(out, v)
+		}
+		cr.values = out
+	case []float64:
+		out, _ := cr.values.([]float64)
+		for _, d := range defined {
+			var v float64
+			if d {
+				v, j = values[j], j+1
+			} else {
+				v = math.NaN()
+			}
+			out = append(out, v)
+		}
+		cr.values = out
+	case []string:
+		out, _ := cr.values.([]string)
+		for _, d := range defined {
+			var v string
+			if d {
+				v, j = values[j], j+1
+			}
+			out = append(out, v)
+		}
+		cr.values = out
+	case []bool:
+		out, _ := cr.values.([]bool)
+		for _, d := range defined {
+			var v bool
+			if d {
+				v, j = values[j], j+1
+			}
+			out = append(out, v)
+		}
+		cr.values = out
+	default:
+		return fmt.Errorf("unsupported values type - %T", values)
+	}
+
+	return nil
+}
+
+// fileWriter writes a Parquet file
+type fileWriter struct {
+	w      io.Writer
+	offset int64
+	codec  int32
+	meta   *fileMetadata
+}
+
+func newFileWriter(w io.Writer, columns []*columnSchema, kv map[string]string, codec int32) (*fileWriter, error) {
+	fw := &fileWriter{
+		w:     w,
+		codec: codec,
+		meta: &fileMetadata{
+			columns:   columns,
+			kv:        kv,
+			createdBy: "frames",
+		},
+	}
+
+	if err := fw.write(magic); err != nil {
+		return nil, err
+	}
+
+	return fw, nil
+}
+
+func (fw *fileWriter) write(data []byte) error {
+	n, err := fw.w.Write(data)
+	fw.offset += int64(n)
+	return err
+}
+
+// columnData is column data for a row group. values has only the non null
+// values, defined is nil for required columns
+type columnData struct {
+	values  interface{}
+	defined []bool
+}
+
+func (fw *fileWriter) writeRowGroup(columns []*columnData, numRows int) error {
+	rg := &rowGroup{numRows: int64(numRows)}
+	for i, data := range columns {
+		chunk, err := fw.writeColumnChunk(fw.meta.columns[i], data, numRows)
+		if err != nil {
+			return errors.Wrapf(err, "%q - can't write column", fw.meta.columns[i].name)
+		}
+		rg.columns = append(rg.columns, chunk)
+	}
+
+	fw.meta.rowGroups = append(fw.meta.rowGroups, rg)
+	fw.meta.numRows += int64(numRows)
+	return nil
+}
+
+func (fw *fileWriter) writeColumnChunk(col *columnSchema, data *columnData, numRows int) (*columnChunk, error) {
+	var page []byte
+	if col.repetition == optional {
+		levels := encodeLevels(data.defined)
+		var size [4]byte
+		byteOrder.PutUint32(size[:], uint32(len(levels)))
+		page = append(size[:], levels...)
+	}
+	page = append(page, encodePlain(data.values)...)
+
+	compressed, err := compress(fw.codec, page)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &pageHeader{
+		typ:              dataPage,
+		uncompressedSize: int32(len(page)),
+		compressedSize:   int32(len(compressed)),
+		numValues:        int32(numRows),
+		encoding:         plainEncoding,
+	}
+	headerData := header.encode()
+
+	chunk := &columnChunk{
+		physical:         col.physical,
+		encodings:        []int32{plainEncoding, rleEncoding},
+		path:             []string{col.name},
+		codec:            fw.codec,
+		numValues:        int64(numRows),
+		uncompressedSize: int64(len(headerData) + len(page)),
+		compressedSize:   int64(len(headerData) + len(compressed)),
+		dataPageOffset:   fw.offset,
+		stats:            columnStats(data.values, numRows),
+	}
+
+	if err := fw.write(headerData); err != nil {
+		return nil, err
+	}
+
+	if err := fw.write(compressed); err != nil {
+		return nil, err
+	}
+
+	return chunk, nil
+}
+
+// Maximal size of string statistics values
+const maxStatsSize = 64
+
+func columnStats(values interface{}, numRows int) *statistics {
+	stats := &statistics{nullCount: int64(numRows - valuesLen(values))}
+	var tmp [8]byte
+	switch values := values.(type) {
+	case []int64:
+		if len(values) == 0 {
+			break
+		}
+		min, max := values[0], values[0]
+		for _, v := range values {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		byteOrder.PutUint64(tmp[:], uint64(min))
+		stats.min = append([]byte{}, tmp[:]...)
+		byteOrder.PutUint64(tmp[:], uint64(max))
+		stats.max = append([]byte{}, tmp[:]...)
+	case []float64:
+		if len(values) == 0 {
+			break
+		}
+		min, max := values[0], values[0]
+		for _, v := range values {
+			if math.IsNaN(v) {
+				return stats
+			}
+			min, max = math.Min(min, v), math.Max(max, v)
+		}
+		byteOrder.PutUint64(tmp[:], math.Float64bits(min))
+		stats.min = append([]byte{}, tmp[:]...)
+		byteOrder.PutUint64(tmp[:], math.Float64bits(max))
+		stats.max = append([]byte{}, tmp[:]...)
+	case []string:
+		if len(values) == 0 {
+			break
+		}
+		min, max := values[0], values[0]
+		for _, v := range values {
+			if len(v) > maxStatsSize {
+				return stats
+			}
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		stats.min, stats.max = []byte(min), []byte(max)
+	}
+
+	return stats
+}
+
+// Close writes the file footer, it does not close the underlying writer
+func (fw *fileWriter) Close() error {
+	footer := fw.meta.encode()
+	if err := fw.write(footer); err != nil {
+		return err
+	}
+
+	var size [4]byte
+	byteOrder.PutUint32(size[:], uint32(len(footer)))
+	if err := fw.write(size[:]); err != nil {
+		return err
+	}
+
+	return fw.write(magic)
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package parquet
+
+// Row group skipping by column statistics. The filter is still evaluated in
+// process (see frames.Pushdown.FilterHint), we only skip row groups where no
+// row can match
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xwb1989/sqlparser"
+
+	"github.com/v3io/frames"
+)
+
+// Time formats of filter literals compared with time columns (same as the
+// in process filter)
+var timeFormats = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+type rowGroupFilter struct {
+	expr    sqlparser.Expr
+	meta    *fileMetadata
+	columns map[string]int // name -> column index
+}
+
+func newRowGroupFilter(filter string, meta *fileMetadata) (*rowGroupFilter, error) {
+	stmt, err := sqlparser.Parse("SELECT * FROM t WHERE " + filter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "bad filter - %q", filter)
+	}
+
+	slct, ok := stmt.(*sqlparser.Select)
+	if !ok || slct.Where == nil {
+		return nil, errors.Errorf("bad filter - %q", filter)
+	}
+
+	f := &rowGroupFilter{
+		expr:    slct.Where.Expr,
+		meta:    meta,
+		columns: make(map[string]int),
+	}
+
+	for i, col := range meta.columns {
+		f.columns[col.name] = i
+	}
+
+	return f, nil
+}
+
+// skip returns true if no row in rg matches the filter
+func (f *rowGroupFilter) skip(rg *rowGroup) bool {
+	return !f.mayMatch(f.expr, rg)
+}
+
+func (f *rowGroupFilter) mayMatch(expr sqlparser.Expr, rg *rowGroup) bool {
+	switch e := expr.(type) {
+	case *sqlparser.ParenExpr:
+		return f.mayMatch(e.Expr, rg)
+	case *sqlparser.AndExpr:
+		return f.mayMatch(e.Left, rg) && f.mayMatch(e.Right, rg)
+	case *sqlparser.OrExpr:
+		return f.mayMatch(e.Left, rg) || f.mayMatch(e.Right, rg)
+	case *sqlparser.ComparisonExpr:
+		return f.mayMatchComparison(e, rg)
+	case *sqlparser.RangeCond:
+		if e.Operator != sqlparser.BetweenStr {
+			return true
+		}
+
+		colRange, ok := f.columnRange(e.Left, rg)
+		if !ok {
+			return true
+		}
+
+		if colRange.allNull {
+			return false
+		}
+
+		from, ok1 := colRange.literal(e.From)
+		to, ok2 := colRange.literal(e.To)
+		if !ok1 || !ok2 {
+			return true
+		}
+		return !(colRange.less(colRange.max, from) || colRange.less(to, colRange.min))
+	}
+
+	return true
+}
+
+func (f *rowGroupFilter) mayMatchComparison(expr *sqlparser.ComparisonExpr, rg *rowGroup) bool {
+	left, right, op := expr.Left, expr.Right, expr.Operator
+	if _, ok := left.(*sqlparser.SQLVal); ok {
+		// literal on the left, flip
+		left, right = right, left
+		switch op {
+		case sqlparser.LessThanStr:
+			op = sqlparser.GreaterThanStr
+		case sqlparser.LessEqualStr:
+			op = sqlparser.GreaterEqualStr
+		case sqlparser.GreaterThanStr:
+			op = sqlparser.LessThanStr
+		case sqlparser.GreaterEqualStr:
+			op = sqlparser.LessEqualStr
+		}
+	}
+
+	switch op {
+	case sqlparser.EqualStr, sqlparser.LessThanStr, sqlparser.LessEqualStr,
+		sqlparser.GreaterThanStr, sqlparser.GreaterEqualStr, sqlparser.InStr:
+	default:
+		return true
+	}
+
+	colRange, ok := f.columnRange(left, rg)
+	if !ok {
+		return true
+	}
+
+	if colRange.allNull {
+		// Comparison with null is unknown, which is filtered out
+		return false
+	}
+
+	if op == sqlparser.InStr {
+		tuple, ok := right.(sqlparser.ValTuple)
+		if !ok {
+			return true
+		}
+
+		for _, expr := range tuple {
+			value, ok := colRange.literal(expr)
+			if !ok || colRange.contains(value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	value, ok := colRange.literal(right)
+	if !ok {
+		return true
+	}
+
+	switch op {
+	case sqlparser.EqualStr:
+		return colRange.contains(value)
+	case sqlparser.LessThanStr:
+		return colRange.less(colRange.min, value)
+	case sqlparser.LessEqualStr:
+		return !colRange.less(value, colRange.min)
+	case sqlparser.GreaterThanStr:
+		return colRange.less(value, colRange.max)
+	case sqlparser.GreaterEqualStr:
+		return !colRange.less(colRange.max, value)
+	}
+
+	return true
+}
+
+// valueRange is column min/max in a row group, values are float64 (numeric
+// columns), string or time.Time
+type valueRange struct {
+	dtype    frames.DType
+	min, max interface{}
+	allNull  bool
+}
+
+func (f *rowGroupFilter) columnRange(expr sqlparser.Expr, rg *rowGroup) (*valueRange, bool) {
+	colName, ok := expr.(*sqlparser.ColName)
+	if !ok {
+		return nil, false
+	}
+
+	i, ok := f.columns[colName.Name.String()]
+	if !ok {
+		return nil, false
+	}
+
+	col, chunk := f.meta.columns[i], rg.columns[i]
+	if chunk.stats == nil {
+		return nil, false
+	}
+
+	dtype, err := col.dtype()
+	if err != nil {
+		return nil, false
+	}
+
+	vr := &valueRange{dtype: dtype}
+	if chunk.stats.nullCount == rg.numRows && rg.numRows > 0 {
+		vr.allNull = true
+		return vr, true
+	}
+
+	if chunk.stats.min == nil || chunk.stats.max == nil || col.unsigned() {
+		return nil, false
+	}
+
+	var ok1, ok2 bool
+	vr.min, ok1 = statValue(col, dtype, chunk.stats.min)
+	vr.max, ok2 = statValue(col, dtype, chunk.stats.max)
+	return vr, ok1 && ok2
+}
+
+func statValue(col *columnSchema, dtype frames.DType, data []byte) (interface{}, bool) {
+	var i int64
+	switch col.physical {
+	case int32Type:
+		if len(data) != 4 {
+			return nil, false
+		}
+		i = int64(int32(byteOrder.Uint32(data)))
+	case int64Type:
+		if len(data) != 8 {
+			return nil, false
+		}
+		i = int64(byteOrder.Uint64(data))
+	case floatType:
+		if len(data) != 4 {
+			return nil, false
+		}
+		return float64(math.Float32frombits(byteOrder.Uint32(data))), true
+	case doubleType:
+		if len(data) != 8 {
+			return nil, false
+		}
+		return math.Float64frombits(byteOrder.Uint64(data)), true
+	case byteArrayType:
+		return string(data), true
+	default:
+		return nil, false
+	}
+
+	if dtype == frames.TimeType {
+		return col.toTimes([]int64{i})[0], true
+	}
+
+	return float64(i), true
+}
+
+// literal returns the value of a literal comparable with the column values
+func (vr *valueRange) literal(expr sqlparser.Expr) (interface{}, bool) {
+	val, ok := expr.(*sqlparser.SQLVal)
+	if !ok {
+		return nil, false
+	}
+
+	switch vr.dtype {
+	case frames.IntType, frames.FloatType:
+		if val.Type != sqlparser.IntVal && val.Type != sqlparser.FloatVal {
+			return nil, false
+		}
+		f, err := strconv.ParseFloat(string(val.Val), 64)
+		return f, err == nil
+	case frames.StringType:
+		return string(val.Val), val.Type == sqlparser.StrVal
+	case frames.TimeType:
+		if val.Type != sqlparser.StrVal {
+			return nil, false
+		}
+		for _, format := range timeFormats {
+			if t, err := time.Parse(format, string(val.Val)); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func (vr *valueRange) less(a, b interface{}) bool {
+	switch a := a.(type) {
+	case float64:
+		return a < b.(float64)
+	case string:
+		return strings.Compare(a, b.(string)) < 0
+	case time.Time:
+		return a.Before(b.(time.Time))
+	}
+	return false
+}
+
+func (vr *valueRange) contains(value interface{}) bool {
+	return !vr.less(value, vr.min) && !vr.less(vr.max, value)
+}
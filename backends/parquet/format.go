@@ -0,0 +1,461 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package parquet
+
+// Parquet file metadata (see
+// https://github.com/apache/parquet-format/blob/master/src/main/thrift/parquet.thrift),
+// only the parts we use
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+)
+
+var magic = []byte("PAR1")
+
+// Physical types
+const (
+	booleanType   = 0
+	int32Type     = 1
+	int64Type     = 2
+	int96Type     = 3
+	floatType     = 4
+	doubleType    = 5
+	byteArrayType = 6
+	fixedLenType  = 7
+)
+
+// Repetition types
+const (
+	required = 0
+	optional = 1
+	repeated = 2
+)
+
+// Converted types
+const (
+	noConverted         = -1
+	utf8Converted       = 0
+	enumConverted       = 4
+	dateConverted       = 6
+	timestampMillisConv = 9
+	timestampMicrosConv = 10
+	jsonConverted       = 19
+)
+
+// Logical types (LogicalType union field ids)
+const (
+	noLogical        = 0
+	stringLogical    = 1
+	enumLogical      = 4
+	dateLogical      = 6
+	timestampLogical = 8
+	integerLogical   = 10
+	jsonLogical      = 12
+)
+
+// Time units (TimeUnit union field ids)
+const (
+	millis = 1
+	micros = 2
+	nanos  = 3
+)
+
+// Encodings
+const (
+	plainEncoding          = 0
+	plainDictionary        = 2
+	rleEncoding            = 3
+	bitPackedEncoding      = 4
+	rleDictionaryEncoding  = 8
+	deltaBinaryPacked      = 5
+	deltaLengthByteArray   = 6
+	deltaByteArrayEncoding = 7
+)
+
+// Compression codecs
+const (
+	uncompressed = 0
+	snappyCodec  = 1
+	gzipCodec    = 2
+)
+
+// Page types
+const (
+	dataPage       = 0
+	indexPage      = 1
+	dictionaryPage = 2
+	dataPageV2     = 3
+)
+
+// columnSchema is a leaf column in the file schema
+type columnSchema struct {
+	name       string
+	physical   int32
+	repetition int32
+	converted  int32
+	logical    int16 // LogicalType union field id
+	timeUnit   int16 // for timestampLogical
+	// for integerLogical
+	unsignedInt bool
+}
+
+// fileMetadata is the parquet footer
+type fileMetadata struct {
+	columns   []*columnSchema
+	numRows   int64
+	rowGroups []*rowGroup
+	kv        map[string]string
+	createdBy string
+}
+
+type rowGroup struct {
+	columns []*columnChunk
+	numRows int64
+}
+
+type columnChunk struct {
+	physical         int32
+	encodings        []int32
+	path             []string
+	codec            int32
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+	dataPageOffset   int64
+	dictPageOffset   int64 // 0 if there's no dictionary page
+	stats            *statistics
+}
+
+type statistics struct {
+	min, max  []byte // nil if not set
+	nullCount int64  // -1 if not set
+}
+
+// pageHeader is a page header, values are from the data/dictionary header
+type pageHeader struct {
+	typ              int32
+	uncompressedSize int32
+	compressedSize   int32
+	numValues        int32
+	encoding         int32
+	// Data page v2
+	numNulls      int32
+	defLevelsSize int32
+	repLevelsSize int32
+	compressed    bool
+}
+
+func (m *fileMetadata) encode() []byte {
+	// Root schema element, then columns
+	schema := []tStruct{
+		{{4, "schema"}, {5, int32(len(m.columns))}},
+	}
+	var orders []tStruct
+	for _, col := range m.columns {
+		elem := tStruct{
+			{1, col.physical},
+			{3, col.repetition},
+			{4, col.name},
+		}
+		if col.converted != noConverted {
+			elem = append(elem, tField{6, col.converted})
+		}
+		if col.logical != noLogical {
+			elem = append(elem, tField{10, encodeLogical(col)})
+		}
+		schema = append(schema, elem)
+		orders = append(orders, tStruct{{1, tStruct{}}}) // TYPE_ORDER
+	}
+
+	var rowGroups []tStruct
+	for _, rg := range m.rowGroups {
+		var columns []tStruct
+		var totalSize int64
+		for _, chunk := range rg.columns {
+			columns = append(columns, tStruct{
+				{2, chunk.dataPageOffset},
+				{3, chunk.encode()},
+			})
+			totalSize += chunk.uncompressedSize
+		}
+		rowGroups = append(rowGroups, tStruct{
+			{1, columns},
+			{2, totalSize},
+			{3, rg.numRows},
+		})
+	}
+
+	msg := tStruct{
+		{1, int32(1)},
+		{2, schema},
+		{3, m.numRows},
+		{4, rowGroups},
+	}
+
+	if len(m.kv) > 0 {
+		var kvs []tStruct
+		for _, key := range sortedKeys(m.kv) {
+			kvs = append(kvs, tStruct{{1, key}, {2, m.kv[key]}})
+		}
+		msg = append(msg, tField{5, kvs})
+	}
+
+	msg = append(msg, tField{6, m.createdBy}, tField{7, orders})
+	return msg.encode()
+}
+
+func encodeLogical(col *columnSchema) tStruct {
+	switch col.logical {
+	case timestampLogical:
+		ts := tStruct{
+			{1, true}, // isAdjustedToUTC
+			{2, tStruct{{col.timeUnit, tStruct{}}}},
+		}
+		return tStruct{{timestampLogical, ts}}
+	}
+
+	return tStruct{{col.logical, tStruct{}}}
+}
+
+func (c *columnChunk) encode() tStruct {
+	msg := tStruct{
+		{1, c.physical},
+		{2, c.encodings},
+		{3, c.path},
+		{4, c.codec},
+		{5, c.numValues},
+		{6, c.uncompressedSize},
+		{7, c.compressedSize},
+		{9, c.dataPageOffset},
+	}
+
+	if c.dictPageOffset > 0 {
+		msg = append(msg, tField{11, c.dictPageOffset})
+	}
+
+	if c.stats != nil {
+		stats := tStruct{}
+		if c.stats.nullCount >= 0 {
+			stats = append(stats, tField{3, c.stats.nullCount})
+		}
+		if c.stats.min != nil && c.stats.max != nil {
+			stats = append(stats, tField{5, c.stats.max}, tField{6, c.stats.min})
+		}
+		msg = append(msg, tField{12, stats})
+	}
+
+	return msg
+}
+
+func (h *pageHeader) encode() []byte {
+	msg := tStruct{
+		{1, h.typ},
+		{2, h.uncompressedSize},
+		{3, h.compressedSize},
+	}
+
+	switch h.typ {
+	case dataPage:
+		msg = append(msg, tField{5, tStruct{
+			{1, h.numValues},
+			{2, h.encoding},
+			{3, int32(rleEncoding)}, // definition levels
+			{4, int32(rleEncoding)}, // repetition levels
+		}})
+	case dictionaryPage:
+		msg = append(msg, tField{7, tStruct{
+			{1, h.numValues},
+			{2, h.encoding},
+		}})
+	}
+
+	return msg.encode()
+}
+
+func decodeFileMetadata(data []byte) (*fileMetadata, error) {
+	msg, err := decodeStruct(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &fileMetadata{
+		numRows:   msg.int(3, 0),
+		createdBy: msg.string(6),
+	}
+
+	schema := msg.structs(2)
+	if len(schema) == 0 {
+		return nil, fmt.Errorf("empty schema")
+	}
+
+	if int(schema[0].int(5, 0)) != len(schema)-1 {
+		return nil, fmt.Errorf("nested schemas are not supported")
+	}
+
+	for _, elem := range schema[1:] {
+		col := &columnSchema{
+			name:       elem.string(4),
+			physical:   int32(elem.int(1, -1)),
+			repetition: int32(elem.int(3, required)),
+			converted:  int32(elem.int(6, noConverted)),
+		}
+
+		if elem.int(5, 0) > 0 || col.physical == -1 {
+			return nil, fmt.Errorf("%q - nested columns are not supported", col.name)
+		}
+
+		if col.repetition == repeated {
+			return nil, fmt.Errorf("%q - repeated columns are not supported", col.name)
+		}
+
+		if logical, ok := elem.strct(10); ok {
+			for id, val := range logical {
+				col.logical = id
+				typ, _ := val.(tValues)
+				switch id {
+				case timestampLogical:
+					if unit, ok := typ.strct(2); ok {
+						for uid := range unit {
+							col.timeUnit = uid
+						}
+					}
+				case integerLogical:
+					col.unsignedInt = typ.has(2) && !typ.bool(2)
+				}
+			}
+		}
+
+		m.columns = append(m.columns, col)
+	}
+
+	for _, rgMsg := range msg.structs(4) {
+		rg := &rowGroup{numRows: rgMsg.int(3, 0)}
+		for _, ccMsg := range rgMsg.structs(1) {
+			if ccMsg.has(1) {
+				return nil, fmt.Errorf("external column chunks are not supported")
+			}
+
+			meta, ok := ccMsg.strct(3)
+			if !ok {
+				return nil, fmt.Errorf("column chunk without metadata")
+			}
+
+			chunk := &columnChunk{
+				physical:         int32(meta.int(1, -1)),
+				path:             meta.strings(3),
+				codec:            int32(meta.int(4, uncompressed)),
+				numValues:        meta.int(5, 0),
+				uncompressedSize: meta.int(6, 0),
+				compressedSize:   meta.int(7, 0),
+				dataPageOffset:   meta.int(9, 0),
+				dictPageOffset:   meta.int(11, 0),
+			}
+
+			for _, enc := range meta.list(2) {
+				if i, ok := enc.(int64); ok {
+					chunk.encodings = append(chunk.encodings, int32(i))
+				}
+			}
+
+			if stats, ok := meta.strct(12); ok {
+				chunk.stats = &statistics{nullCount: stats.int(3, -1)}
+				switch {
+				case stats.has(5) && stats.has(6):
+					chunk.stats.max, chunk.stats.min = stats.bytes(5), stats.bytes(6)
+				case stats.has(1) && stats.has(2) && chunk.physical != byteArrayType:
+					// Deprecated min/max have signed order, which is the same
+					// for numeric types
+					chunk.stats.max, chunk.stats.min = stats.bytes(1), stats.bytes(2)
+				}
+			}
+
+			rg.columns = append(rg.columns, chunk)
+		}
+
+		if len(rg.columns) != len(m.columns) {
+			return nil, fmt.Errorf("row group has %d columns but schema has %d", len(rg.columns), len(m.columns))
+		}
+
+		m.rowGroups = append(m.rowGroups, rg)
+	}
+
+	for _, kv := range msg.structs(5) {
+		if m.kv == nil {
+			m.kv = make(map[string]string)
+		}
+		m.kv[kv.string(1)] = kv.string(2)
+	}
+
+	return m, nil
+}
+
+func decodePageHeader(r io.ByteReader) (*pageHeader, error) {
+	msg, err := decodeStruct(r)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &pageHeader{
+		typ:              int32(msg.int(1, -1)),
+		uncompressedSize: int32(msg.int(2, 0)),
+		compressedSize:   int32(msg.int(3, 0)),
+	}
+
+	if h.uncompressedSize < 0 || h.compressedSize < 0 {
+		return nil, fmt.Errorf("bad page size")
+	}
+
+	switch h.typ {
+	case dataPage:
+		dh, _ := msg.strct(5)
+		h.numValues = int32(dh.int(1, 0))
+		h.encoding = int32(dh.int(2, plainEncoding))
+	case dictionaryPage:
+		dh, _ := msg.strct(7)
+		h.numValues = int32(dh.int(1, 0))
+		h.encoding = int32(dh.int(2, plainEncoding))
+	case dataPageV2:
+		dh, _ := msg.strct(8)
+		h.numValues = int32(dh.int(1, 0))
+		h.numNulls = int32(dh.int(2, 0))
+		h.encoding = int32(dh.int(4, plainEncoding))
+		h.defLevelsSize = int32(dh.int(5, 0))
+		h.repLevelsSize = int32(dh.int(6, 0))
+		h.compressed = true
+		if dh.has(7) {
+			h.compressed = dh.bool(7)
+		}
+	}
+
+	return h, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
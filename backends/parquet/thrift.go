@@ -0,0 +1,407 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package parquet
+
+// Minimal Thrift compact protocol, enough for Parquet metadata
+// (see https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md)
+//
+// Structs are encoded from tStruct (ordered fields with Go typed values) and
+// decoded to tValues (field id -> value) which ignores unknown fields
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Compact protocol types
+const (
+	tStop      = 0
+	tTrue      = 1
+	tFalse     = 2
+	tByte      = 3
+	tI16       = 4
+	tI32       = 5
+	tI64       = 6
+	tDouble    = 7
+	tBinary    = 8
+	tList      = 9
+	tSet       = 10
+	tMap       = 11
+	tStructure = 12
+)
+
+// Maximal container size we accept when decoding
+const maxThriftSize = 1 << 28
+
+// tField is a struct field, val type determines the Thrift type:
+// bool, int8, int16, int32, int64, float64, string, []byte, tStruct, []tStruct,
+// []int32, []int64 and []string
+type tField struct {
+	id  int16
+	val interface{}
+}
+
+type tStruct []tField
+
+func (s tStruct) encode() []byte {
+	enc := &thriftEncoder{}
+	enc.writeStruct(s)
+	return enc.buf
+}
+
+type thriftEncoder struct {
+	buf []byte
+}
+
+func (e *thriftEncoder) writeUvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	e.buf = append(e.buf, tmp[:n]...)
+}
+
+func (e *thriftEncoder) writeVarint(v int64) {
+	e.writeUvarint(uint64((v << 1) ^ (v >> 63))) // zigzag
+}
+
+func (e *thriftEncoder) writeStruct(s tStruct) {
+	var lastID int16
+	for _, f := range s {
+		typ := valueType(f.val)
+		if b, ok := f.val.(bool); ok {
+			typ = tFalse
+			if b {
+				typ = tTrue
+			}
+		}
+
+		if delta := f.id - lastID; delta > 0 && delta <= 15 {
+			e.buf = append(e.buf, byte(delta<<4)|typ)
+		} else {
+			e.buf = append(e.buf, typ)
+			e.writeVarint(int64(f.id))
+		}
+		lastID = f.id
+
+		if _, ok := f.val.(bool); !ok {
+			e.writeValue(f.val)
+		}
+	}
+	e.buf = append(e.buf, tStop)
+}
+
+func (e *thriftEncoder) writeListHeader(typ byte, size int) {
+	if size < 15 {
+		e.buf = append(e.buf, byte(size<<4)|typ)
+		return
+	}
+
+	e.buf = append(e.buf, 0xF0|typ)
+	e.writeUvarint(uint64(size))
+}
+
+func (e *thriftEncoder) writeValue(val interface{}) {
+	switch val := val.(type) {
+	case bool: // in lists
+		if val {
+			e.buf = append(e.buf, tTrue)
+		} else {
+			e.buf = append(e.buf, tFalse)
+		}
+	case int8:
+		e.buf = append(e.buf, byte(val))
+	case int16:
+		e.writeVarint(int64(val))
+	case int32:
+		e.writeVarint(int64(val))
+	case int64:
+		e.writeVarint(val)
+	case float64:
+		var tmp [8]byte
+		binary.LittleEndian.PutUint64(tmp[:], math.Float64bits(val))
+		e.buf = append(e.buf, tmp[:]...)
+	case string:
+		e.writeUvarint(uint64(len(val)))
+		e.buf = append(e.buf, val...)
+	case []byte:
+		e.writeUvarint(uint64(len(val)))
+		e.buf = append(e.buf, val...)
+	case tStruct:
+		e.writeStruct(val)
+	case []tStruct:
+		e.writeListHeader(tStructure, len(val))
+		for _, s := range val {
+			e.writeStruct(s)
+		}
+	case []int32:
+		e.writeListHeader(tI32, len(val))
+		for _, v := range val {
+			e.writeVarint(int64(v))
+		}
+	case []int64:
+		e.writeListHeader(tI64, len(val))
+		for _, v := range val {
+			e.writeVarint(v)
+		}
+	case []string:
+		e.writeListHeader(tBinary, len(val))
+		for _, v := range val {
+			e.writeValue(v)
+		}
+	default:
+		panic(fmt.Sprintf("unsupported thrift value - %T", val))
+	}
+}
+
+func valueType(val interface{}) byte {
+	switch val.(type) {
+	case bool:
+		return tTrue
+	case int8:
+		return tByte
+	case int16:
+		return tI16
+	case int32:
+		return tI32
+	case int64:
+		return tI64
+	case float64:
+		return tDouble
+	case string, []byte:
+		return tBinary
+	case tStruct:
+		return tStructure
+	case []tStruct, []int32, []int64, []string:
+		return tList
+	}
+
+	panic(fmt.Sprintf("unsupported thrift value - %T", val))
+}
+
+// tValues is a decoded struct, integers are int64, binary is []byte, lists are
+// []interface{} and structs are tValues
+type tValues map[int16]interface{}
+
+func (v tValues) has(id int16) bool {
+	_, ok := v[id]
+	return ok
+}
+
+func (v tValues) int(id int16, def int64) int64 {
+	if i, ok := v[id].(int64); ok {
+		return i
+	}
+	return def
+}
+
+func (v tValues) bool(id int16) bool {
+	b, _ := v[id].(bool)
+	return b
+}
+
+func (v tValues) bytes(id int16) []byte {
+	b, _ := v[id].([]byte)
+	return b
+}
+
+func (v tValues) string(id int16) string {
+	return string(v.bytes(id))
+}
+
+func (v tValues) strct(id int16) (tValues, bool) {
+	s, ok := v[id].(tValues)
+	return s, ok
+}
+
+func (v tValues) list(id int16) []interface{} {
+	l, _ := v[id].([]interface{})
+	return l
+}
+
+func (v tValues) structs(id int16) []tValues {
+	var out []tValues
+	for _, elem := range v.list(id) {
+		if s, ok := elem.(tValues); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (v tValues) strings(id int16) []string {
+	var out []string
+	for _, elem := range v.list(id) {
+		if b, ok := elem.([]byte); ok {
+			out = append(out, string(b))
+		}
+	}
+	return out
+}
+
+// thriftDecoder decodes from a byte reader, it's used both for buffers and
+// for files (page headers)
+type thriftDecoder struct {
+	r io.ByteReader
+}
+
+func decodeStruct(r io.ByteReader) (tValues, error) {
+	dec := &thriftDecoder{r}
+	return dec.readStruct(0)
+}
+
+func (d *thriftDecoder) readByte() byte {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		panic(thriftError{err})
+	}
+	return b
+}
+
+// thriftError is used to abort decoding (panic) on read errors
+type thriftError struct {
+	err error
+}
+
+func (d *thriftDecoder) readUvarint() uint64 {
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		panic(thriftError{err})
+	}
+	return v
+}
+
+func (d *thriftDecoder) readVarint() int64 {
+	v := d.readUvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (d *thriftDecoder) readSize() int {
+	size := d.readUvarint()
+	if size > maxThriftSize {
+		panic(thriftError{fmt.Errorf("size too big - %d", size)})
+	}
+	return int(size)
+}
+
+func (d *thriftDecoder) readStruct(depth int) (values tValues, err error) {
+	if depth == 0 {
+		defer func() {
+			if e := recover(); e != nil {
+				terr, ok := e.(thriftError)
+				if !ok {
+					panic(e)
+				}
+				if terr.err == io.EOF {
+					terr.err = io.ErrUnexpectedEOF
+				}
+				err = terr.err
+			}
+		}()
+	}
+
+	if depth > 64 {
+		panic(thriftError{fmt.Errorf("structs nested too deep")})
+	}
+
+	values = make(tValues)
+	var lastID int16
+	for {
+		header := d.readByte()
+		typ := header & 0x0F
+		if typ == tStop {
+			return values, nil
+		}
+
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(d.readVarint())
+		}
+		lastID = id
+
+		switch typ {
+		case tTrue:
+			values[id] = true
+		case tFalse:
+			values[id] = false
+		default:
+			values[id] = d.readValue(typ, depth)
+		}
+	}
+}
+
+func (d *thriftDecoder) readValue(typ byte, depth int) interface{} {
+	switch typ {
+	case tTrue, tFalse: // in lists
+		return d.readByte() == tTrue
+	case tByte:
+		return int64(int8(d.readByte()))
+	case tI16, tI32, tI64:
+		return d.readVarint()
+	case tDouble:
+		var tmp [8]byte
+		for i := range tmp {
+			tmp[i] = d.readByte()
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(tmp[:]))
+	case tBinary:
+		size := d.readSize()
+		buf := make([]byte, 0, minInt(size, 4096))
+		for i := 0; i < size; i++ {
+			buf = append(buf, d.readByte())
+		}
+		return buf
+	case tList, tSet:
+		header := d.readByte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = d.readSize()
+		}
+		etyp := header & 0x0F
+		list := make([]interface{}, 0, minInt(size, 4096))
+		for i := 0; i < size; i++ {
+			list = append(list, d.readValue(etyp, depth+1))
+		}
+		return list
+	case tMap:
+		size := d.readSize()
+		if size > 0 {
+			types := d.readByte()
+			for i := 0; i < size; i++ {
+				d.readValue(types>>4, depth+1)
+				d.readValue(types&0x0F, depth+1)
+			}
+		}
+		return nil // we don't use maps
+	case tStructure:
+		values, _ := d.readStruct(depth + 1)
+		return values
+	}
+
+	panic(thriftError{fmt.Errorf("unknown thrift type - %d", typ)})
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
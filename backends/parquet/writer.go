@@ -0,0 +1,373 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package parquet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/backends/utils"
+)
+
+// appender writes frames to a temporary file which replaces the table in
+// WaitForComplete, rows are buffered to row groups of rowGroupSize
+type appender struct {
+	ctx     context.Context
+	logger  logger.Logger
+	backend *Backend
+	path    string
+	file    *os.File
+	writer  *fileWriter // nil until the schema is known
+	indices []string
+	dtypes  []frames.DType
+	buffers []*columnBuffer
+	numRows int
+}
+
+// columnBuffer is column data of the next row group
+type columnBuffer struct {
+	values  interface{} // non null values
+	defined []bool
+}
+
+func (b *Backend) newWriter(ctx context.Context, table string, columns []*columnSchema, indices []string) (*appender, error) {
+	filePath := b.tablePath(table)
+	file, err := utils.TempFile(filePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't create file")
+	}
+
+	a := &appender{
+		ctx:     ctx,
+		logger:  b.logger,
+		backend: b,
+		path:    filePath,
+		file:    file,
+	}
+
+	if columns != nil {
+		if err := a.init(columns, indices); err != nil {
+			a.abort()
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+func (a *appender) init(columns []*columnSchema, indices []string) error {
+	var kv map[string]string
+	if len(indices) > 0 {
+		data, err := json.Marshal(indices)
+		if err != nil {
+			return err
+		}
+		kv = map[string]string{indicesKey: string(data)}
+	}
+
+	a.dtypes = make([]frames.DType, len(columns))
+	for i, col := range columns {
+		dtype, err := col.dtype()
+		if err != nil {
+			return err
+		}
+		a.dtypes[i] = dtype
+	}
+
+	writer, err := newFileWriter(a.file, columns, kv, a.backend.codec)
+	if err != nil {
+		return errors.Wrap(err, "can't write file header")
+	}
+
+	a.writer, a.indices = writer, indices
+	a.resetBuffers()
+	return nil
+}
+
+// initFromFrame sets the schema from frame, indices first
+func (a *appender) initFromFrame(frame frames.Frame) error {
+	var columns []*columnSchema
+	var indices []string
+	for _, col := range frame.Indices() {
+		columns = append(columns, newColumnSchema(col.Name(), col.DType(), true))
+		indices = append(indices, col.Name())
+	}
+
+	for _, name := range frame.Names() {
+		col, err := frame.Column(name)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, newColumnSchema(name, col.DType(), true))
+	}
+
+	return a.init(columns, indices)
+}
+
+func (a *appender) resetBuffers() {
+	a.buffers = make([]*columnBuffer, len(a.dtypes))
+	for i := range a.buffers {
+		a.buffers[i] = &columnBuffer{}
+	}
+	a.numRows = 0
+}
+
+// Add adds a frame, frame columns (and indices) are matched to the table
+// columns by name, missing columns are null
+func (a *appender) Add(frame frames.Frame) error {
+	if err := a.ctx.Err(); err != nil {
+		return err
+	}
+
+	if a.writer == nil {
+		if err := a.initFromFrame(frame); err != nil {
+			return err
+		}
+	}
+
+	byName := make(map[string]frames.Column)
+	for _, col := range frame.Indices() {
+		byName[col.Name()] = col
+	}
+
+	for _, name := range frame.Names() {
+		col, err := frame.Column(name)
+		if err != nil {
+			return err
+		}
+		byName[name] = col
+	}
+
+	columns := a.writer.meta.columns
+	frameCols := make([]frames.Column, len(columns))
+	for i, schemaCol := range columns {
+		col, ok := byName[schemaCol.name]
+		if !ok {
+			if schemaCol.repetition != optional {
+				return fmt.Errorf("missing non nullable column %q", schemaCol.name)
+			}
+			continue
+		}
+
+		if col.DType() != a.dtypes[i] {
+			return fmt.Errorf("column %q type mismatch (%v != %v)", schemaCol.name, col.DType(), a.dtypes[i])
+		}
+
+		if col.NullCount() > 0 && schemaCol.repetition != optional {
+			return fmt.Errorf("null values in non nullable column %q", schemaCol.name)
+		}
+
+		frameCols[i] = col
+		delete(byName, schemaCol.name)
+	}
+
+	for name := range byName {
+		return fmt.Errorf("column %q is not in table schema", name)
+	}
+
+	for i, col := range frameCols {
+		if err := a.buffers[i].append(col, a.dtypes[i], frame.Len()); err != nil {
+			return errors.Wrapf(err, "%q - can't append", columns[i].name)
+		}
+	}
+
+	a.numRows += frame.Len()
+	if a.numRows >= a.backend.rowGroupSize {
+		return a.flush()
+	}
+
+	return nil
+}
+
+// append appends col values, col is nil for a missing (null) column
+func (b *columnBuffer) append(col frames.Column, dtype frames.DType, size int) error {
+	for i := 0; i < size; i++ {
+		b.defined = append(b.defined, col != nil && !col.IsNull(i))
+	}
+
+	if col == nil {
+		if b.values == nil {
+			b.values = emptyValues(dtype)
+		}
+		return nil
+	}
+
+	var err error
+	switch dtype {
+	case frames.IntType:
+		var data []int64
+		data, err = col.Ints()
+		out, _ := b.values.([]int64)
+		for i, v := range data {
+			if !col.IsNull(i) {
+				out = append(out, v)
+			}
+		}
+		b.values = out
+	case frames.FloatType:
+		var data []float64
+		data, err = col.Floats()
+		out, _ := b.values.([]float64)
+		for i, v := range data {
+			if !col.IsNull(i) {
+				out = append(out, v)
+			}
+		}
+		b.values = out
+	case frames.StringType:
+		out, _ := b.values.([]string)
+		for i, v := range col.Strings() {
+			if !col.IsNull(i) {
+				out = append(out, v)
+			}
+		}
+		b.values = out
+	case frames.TimeType:
+		var data []time.Time
+		data, err = col.Times()
+		out, _ := b.values.([]int64)
+		for i, v := range data {
+			if !col.IsNull(i) {
+				out = append(out, v.UnixNano())
+			}
+		}
+		b.values = out
+	case frames.BoolType:
+		var data []bool
+		data, err = col.Bools()
+		out, _ := b.values.([]bool)
+		for i, v := range data {
+			if !col.IsNull(i) {
+				out = append(out, v)
+			}
+		}
+		b.values = out
+	default:
+		return fmt.Errorf("unsupported type - %v", dtype)
+	}
+
+	return err
+}
+
+func emptyValues(dtype frames.DType) interface{} {
+	switch dtype {
+	case frames.FloatType:
+		return []float64{}
+	case frames.StringType:
+		return []string{}
+	case frames.BoolType:
+		return []bool{}
+	}
+	return []int64{} // int & time
+}
+
+// flush writes buffered rows as a row group
+func (a *appender) flush() error {
+	if a.numRows == 0 {
+		return nil
+	}
+
+	data := make([]*columnData, len(a.buffers))
+	for i, buf := range a.buffers {
+		values := buf.values
+		if values == nil {
+			values = emptyValues(a.dtypes[i])
+		}
+		data[i] = &columnData{values: values, defined: buf.defined}
+	}
+
+	if err := a.writer.writeRowGroup(data, a.numRows); err != nil {
+		return err
+	}
+
+	a.resetBuffers()
+	return nil
+}
+
+// WaitForComplete writes the buffered rows and replaces the table file
+func (a *appender) WaitForComplete(timeout time.Duration) error {
+	if a.file == nil {
+		return fmt.Errorf("appender is closed")
+	}
+
+	if err := a.complete(); err != nil {
+		a.abort()
+		return err
+	}
+
+	return nil
+}
+
+func (a *appender) complete() error {
+	if err := a.ctx.Err(); err != nil {
+		return err
+	}
+
+	if a.writer == nil {
+		// No frames, table without columns
+		if err := a.init(nil, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := a.flush(); err != nil {
+		return err
+	}
+
+	if err := a.writer.Close(); err != nil {
+		return errors.Wrap(err, "can't write footer")
+	}
+
+	if err := a.file.Sync(); err != nil {
+		return errors.Wrap(err, "can't sync file")
+	}
+
+	if err := a.file.Close(); err != nil {
+		return errors.Wrap(err, "can't close file")
+	}
+
+	if err := os.Rename(a.file.Name(), a.path); err != nil {
+		return errors.Wrap(err, "can't replace table file")
+	}
+
+	a.file = nil
+	return nil
+}
+
+// abort removes the temporary file
+func (a *appender) abort() {
+	if a.file == nil {
+		return
+	}
+
+	a.file.Close()
+	if err := os.Remove(a.file.Name()); err != nil {
+		a.logger.WarnWith("can't remove temporary file", "path", a.file.Name(), "error", err)
+	}
+	a.file = nil
+}
@@ -21,8 +21,11 @@ such restriction.
 package stream
 
 import (
+	"context"
 	"fmt"
+	"path"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/nuclio/logger"
@@ -30,6 +33,7 @@ import (
 
 	"github.com/v3io/frames"
 	"github.com/v3io/frames/backends"
+	"github.com/v3io/frames/pb"
 	"github.com/v3io/frames/v3ioutils"
 	v3io "github.com/v3io/v3io-go-http"
 )
@@ -39,6 +43,7 @@ type Backend struct {
 	backendConfig *frames.BackendConfig
 	framesConfig  *frames.Config
 	logger        logger.Logger
+	retry         *v3ioutils.RetryPolicy
 }
 
 // NewBackend return a new v3io stream backend
@@ -49,13 +54,14 @@ func NewBackend(logger logger.Logger, cfg *frames.BackendConfig, framesConfig *f
 		logger:        logger.GetChild("stream"),
 		backendConfig: cfg,
 		framesConfig:  framesConfig,
+		retry:         v3ioutils.NewRetryPolicy(cfg.Retry),
 	}
 
 	return &newBackend, nil
 }
 
 // Create creates a table
-func (b *Backend) Create(request *frames.CreateRequest) error {
+func (b *Backend) Create(ctx context.Context, request *frames.CreateRequest) error {
 
 	// TODO: check if Stream exist, if it already has the desired params can silently ignore, may need a -silent flag
 
@@ -100,7 +106,7 @@ func (b *Backend) Create(request *frames.CreateRequest) error {
 }
 
 // Delete deletes a table or part of it
-func (b *Backend) Delete(request *frames.DeleteRequest) error {
+func (b *Backend) Delete(ctx context.Context, request *frames.DeleteRequest) error {
 
 	if !strings.HasSuffix(request.Table, "/") {
 		request.Table += "/"
@@ -120,9 +126,90 @@ func (b *Backend) Delete(request *frames.DeleteRequest) error {
 }
 
 // Exec executes a command
-func (b *Backend) Exec(request *frames.ExecRequest) error {
+func (b *Backend) Exec(ctx context.Context, request *frames.ExecRequest) (frames.Frame, error) {
 	// FIXME
-	return fmt.Errorf("KV backend does not support Exec")
+	return nil, fmt.Errorf("KV backend does not support Exec")
+}
+
+// List lists the streams (directories with only shard objects)
+func (b *Backend) List(ctx context.Context, request *frames.ListRequest) ([]string, error) {
+	container, err := b.newContainer(request.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	_, dirs, err := v3ioutils.ListDir(container, request.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var streams []string
+	for _, dir := range dirs {
+		objects, subDirs, err := v3ioutils.ListDir(container, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(objects) > 0 && len(subDirs) == 0 && allShards(objects) {
+			streams = append(streams, dir)
+		}
+	}
+
+	return streams, nil
+}
+
+// Describe returns the stream shard count and retention
+func (b *Backend) Describe(ctx context.Context, request *frames.DescribeRequest) (*frames.TableInfo, error) {
+	session := frames.InitSessionDefaults(request.Session, b.framesConfig)
+	streamInfo, err := v3ioutils.DescribeStream(ctx, session, request.Table)
+	if err != nil {
+		b.logger.ErrorWith("DescribeStream failed", "path", request.Table, "err", err)
+		return nil, err
+	}
+
+	info := &frames.TableInfo{Name: request.Table}
+	if err := info.SetAttribute("shards", int64(streamInfo.ShardCount)); err != nil {
+		return nil, err
+	}
+
+	if err := info.SetAttribute("retention_hours", int64(streamInfo.RetentionPeriodHours)); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// allShards returns true if all objects are stream shards (numeric names)
+func allShards(objects []string) bool {
+	for _, obj := range objects {
+		if _, err := strconv.Atoi(path.Base(obj)); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Capabilities returns the backend capabilities
+func (b *Backend) Capabilities() *frames.Capabilities {
+	return &frames.Capabilities{
+		Operations: []string{
+			frames.ReadOperation, frames.WriteOperation, frames.CreateOperation,
+			frames.DeleteOperation, frames.ListOperation, frames.DescribeOperation,
+		},
+		FilterDialect:   "sql",
+		FilterOperators: b.Pushdown().FilterOperators,
+		Dtypes:          frames.AllDTypes(),
+		CreateAttributes: []*frames.ArgumentInfo{
+			{Name: "shards", Dtype: pb.DType_INTEGER, Doc: "number of shards (default 1)"},
+			{Name: "retention_hours", Dtype: pb.DType_INTEGER, Doc: "retention period in hours (default 24)"},
+		},
+	}
+}
+
+// Pushdown returns the read request parts handled by the backend (none)
+func (b *Backend) Pushdown() *frames.Pushdown {
+	return &frames.Pushdown{}
 }
 
 func (b *Backend) newContainer(session *frames.Session) (*v3io.Container, error) {
@@ -21,6 +21,7 @@ such restriction.
 package stream
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -33,6 +34,7 @@ import (
 )
 
 type streamIterator struct {
+	ctx          context.Context
 	request      *frames.ReadRequest
 	container    *v3io.Container
 	err          error
@@ -43,14 +45,14 @@ type streamIterator struct {
 	isLast       bool
 }
 
-func (b *Backend) Read(request *frames.ReadRequest) (frames.FrameIterator, error) {
+func (b *Backend) Read(ctx context.Context, request *frames.ReadRequest) (frames.FrameIterator, error) {
 
 	container, err := b.newContainer(request.Session)
 	if err != nil {
 		return nil, err
 	}
 
-	iter := streamIterator{request: request, b: b, container: container}
+	iter := streamIterator{ctx: ctx, request: request, b: b, container: container}
 
 	if request.Table == "" || request.Seek == "" || request.ShardId == "" {
 		return nil, fmt.Errorf("missing essential paramaters, need: table, seek, shard parameters")
@@ -105,6 +107,11 @@ func (i *streamIterator) Next() bool {
 		return false
 	}
 
+	if err := i.ctx.Err(); err != nil {
+		i.err = err
+		return false
+	}
+
 	resp, err := i.container.Sync.GetRecords(&v3io.GetRecordsInput{
 		Path:     i.request.Table + "/" + i.request.ShardId,
 		Location: i.nextLocation,
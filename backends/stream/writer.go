@@ -21,6 +21,7 @@ such restriction.
 package stream
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"time"
@@ -30,9 +31,10 @@ import (
 	v3io "github.com/v3io/v3io-go-http"
 
 	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils"
 )
 
-func (b *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, error) {
+func (b *Backend) Write(ctx context.Context, request *frames.WriteRequest) (frames.FrameAppender, error) {
 
 	tablePath := request.Table
 	if !strings.HasSuffix(tablePath, "/") {
@@ -45,12 +47,14 @@ func (b *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, err
 	}
 
 	appender := streamAppender{
+		ctx:          ctx,
 		request:      request,
 		container:    container,
 		tablePath:    tablePath,
 		responseChan: make(chan *v3io.Response, 1000),
 		commChan:     make(chan int, 2),
 		logger:       b.logger,
+		retry:        b.retry,
 	}
 
 	if request.ImmidiateData != nil {
@@ -64,16 +68,22 @@ func (b *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, err
 }
 
 type streamAppender struct {
+	ctx          context.Context
 	request      *frames.WriteRequest
 	container    *v3io.Container
 	tablePath    string
 	responseChan chan *v3io.Response
 	commChan     chan int
 	logger       logger.Logger
+	retry        *v3ioutils.RetryPolicy
 }
 
 // TODO: make it async
 func (a *streamAppender) Add(frame frames.Frame) error {
+	if err := a.ctx.Err(); err != nil {
+		return err
+	}
+
 	records := make([]*v3io.StreamRecord, 0, frame.Len())
 	iter := frame.IterRows(true)
 	for iter.Next() {
@@ -89,10 +99,43 @@ func (a *streamAppender) Add(frame frames.Frame) error {
 		return errors.Wrap(err, "row iteration error")
 	}
 
-	_, err := a.container.Sync.PutRecords(&v3io.PutRecordsInput{
-		Path: a.tablePath, Records: records})
+	input := &v3io.PutRecordsInput{Path: a.tablePath, Records: records}
+	return a.retry.Do(a.ctx, a.logger, func() error {
+		return a.putRecords(input)
+	})
+}
+
+// putRecords puts the records of input, on partial failure input is left with
+// the failed records
+func (a *streamAppender) putRecords(input *v3io.PutRecordsInput) error {
+	resp, err := a.container.Sync.PutRecords(input)
+	if err != nil {
+		return err
+	}
+	defer resp.Release()
+
+	out := resp.Output.(*v3io.PutRecordsOutput)
+	if out.FailedRecordCount == 0 {
+		return nil
+	}
+
+	var failed []*v3io.StreamRecord
+	code, message := 0, ""
+	for i, result := range out.Records {
+		if result.ErrorCode == 0 {
+			continue
+		}
+
+		failed = append(failed, input.Records[i])
+		// Fail without retries if one of the records can't be retried
+		if code == 0 || !a.retry.RetryStatus(result.ErrorCode) {
+			code, message = result.ErrorCode, result.ErrorMessage
+		}
+	}
 
-	return err
+	input.Records = failed
+	return v3io.NewErrorWithStatusCode(
+		code, "failed to put %d records (%d - %s)", len(failed), code, message)
 }
 
 func (a *streamAppender) WaitForComplete(timeout time.Duration) error {
@@ -21,6 +21,7 @@ such restriction.
 package tsdb
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -28,6 +29,7 @@ import (
 
 	"github.com/v3io/frames"
 	"github.com/v3io/frames/backends"
+	"github.com/v3io/frames/pb"
 
 	"github.com/pkg/errors"
 	"github.com/v3io/frames/v3ioutils"
@@ -113,7 +115,7 @@ func (b *Backend) GetAdapter(session *frames.Session, path string) (*tsdb.V3ioAd
 }
 
 // Create creates a table
-func (b *Backend) Create(request *frames.CreateRequest) error {
+func (b *Backend) Create(ctx context.Context, request *frames.CreateRequest) error {
 
 	attrs := request.Attributes()
 
@@ -163,7 +165,7 @@ func (b *Backend) Create(request *frames.CreateRequest) error {
 }
 
 // Delete deletes a table or part of it
-func (b *Backend) Delete(request *frames.DeleteRequest) error {
+func (b *Backend) Delete(ctx context.Context, request *frames.DeleteRequest) error {
 
 	start, err := tsdbutils.Str2duration(request.Start)
 	if err != nil {
@@ -195,9 +197,72 @@ func (b *Backend) Delete(request *frames.DeleteRequest) error {
 }
 
 // Exec executes a command
-func (b *Backend) Exec(request *frames.ExecRequest) error {
+func (b *Backend) Exec(ctx context.Context, request *frames.ExecRequest) (frames.Frame, error) {
 	// FIXME
-	return fmt.Errorf("TSDB backend does not support Exec")
+	return nil, fmt.Errorf("TSDB backend does not support Exec")
+}
+
+// List lists the tables (directories with a TSDB schema)
+func (b *Backend) List(ctx context.Context, request *frames.ListRequest) ([]string, error) {
+	session := frames.InitSessionDefaults(request.Session, b.framesConfig)
+	container, err := v3ioutils.CreateContainer(b.logger,
+		session.Url, session.Container, session.User, session.Password, b.backendConfig.Workers)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create V3IO data container")
+	}
+
+	return v3ioutils.ListTables(container, request.Path, config.SchemaConfigFileName)
+}
+
+// Describe returns the table schema and TSDB attributes
+func (b *Backend) Describe(ctx context.Context, request *frames.DescribeRequest) (*frames.TableInfo, error) {
+	adapter, err := b.GetAdapter(request.Session, request.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	dbSchema := adapter.GetSchema()
+	schema := &frames.TableSchema{}
+	for _, field := range dbSchema.Fields {
+		schema.Fields = append(schema.Fields, &frames.SchemaField{Name: field.Name, Type: field.Type})
+	}
+
+	info := &frames.TableInfo{Name: request.Table, Schema: schema}
+	partition := dbSchema.PartitionSchemaInfo
+	attrs := map[string]interface{}{
+		"aggregates":              strings.Join(partition.Aggregates, ","),
+		"aggregation-granularity": partition.AggregationGranularity,
+		"partitioner-interval":    partition.PartitionerInterval,
+		"chunk-interval":          partition.ChunckerInterval,
+		"sharding-buckets":        int64(dbSchema.TableSchemaInfo.ShardingBucketsCount),
+	}
+
+	for key, value := range attrs {
+		if err := info.SetAttribute(key, value); err != nil {
+			return nil, errors.Wrapf(err, "can't set %q attribute", key)
+		}
+	}
+
+	return info, nil
+}
+
+// Capabilities returns the backend capabilities
+func (b *Backend) Capabilities() *frames.Capabilities {
+	return &frames.Capabilities{
+		Operations: []string{
+			frames.ReadOperation, frames.WriteOperation, frames.CreateOperation,
+			frames.DeleteOperation, frames.ListOperation, frames.DescribeOperation,
+		},
+		// Filter is passed as is to the TSDB query
+		FilterDialect: "tsdb",
+		// Values are int or float, labels are strings and the index is time
+		Dtypes: []pb.DType{pb.DType_INTEGER, pb.DType_FLOAT, pb.DType_STRING, pb.DType_TIME},
+		CreateAttributes: []*frames.ArgumentInfo{
+			{Name: "rate", Dtype: pb.DType_STRING, Required: true, Doc: "maximum sample rate (e.g. 1/m)"},
+			{Name: "aggregation-granularity", Dtype: pb.DType_STRING, Doc: "aggregation granularity (e.g. 1h)"},
+			{Name: "aggregates", Dtype: pb.DType_STRING, Doc: "default aggregates (e.g. count,avg)"},
+		},
+	}
 }
 
 func (b *Backend) ignoreCreateExists(request *frames.CreateRequest, err error) bool {
@@ -21,6 +21,7 @@ such restriction.
 package tsdb
 
 import (
+	"context"
 	"time"
 
 	"github.com/pkg/errors"
@@ -29,6 +30,7 @@ import (
 )
 
 type tsdbIterator struct {
+	ctx         context.Context
 	request     *frames.ReadRequest
 	set         tsdbutils.SeriesSet
 	err         error
@@ -36,7 +38,7 @@ type tsdbIterator struct {
 	currFrame   frames.Frame
 }
 
-func (b *Backend) Read(request *frames.ReadRequest) (frames.FrameIterator, error) {
+func (b *Backend) Read(ctx context.Context, request *frames.ReadRequest) (frames.FrameIterator, error) {
 
 	step, err := tsdbutils.Str2duration(request.Step)
 	if err != nil {
@@ -68,12 +70,12 @@ func (b *Backend) Read(request *frames.ReadRequest) (frames.FrameIterator, error
 		return nil, errors.Wrap(err, "failed to create adapter")
 	}
 
-	qry, err := adapter.Querier(nil, from, to)
+	qry, err := adapter.Querier(ctx, from, to)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to initialize Querier")
 	}
 
-	iter := tsdbIterator{request: request}
+	iter := tsdbIterator{ctx: ctx, request: request}
 	name := ""
 	if len(request.Columns) > 0 {
 		name = request.Columns[0]
@@ -89,6 +91,11 @@ func (b *Backend) Read(request *frames.ReadRequest) (frames.FrameIterator, error
 }
 
 func (i *tsdbIterator) Next() bool {
+	if err := i.ctx.Err(); err != nil {
+		i.err = err
+		return false
+	}
+
 	if i.set.Next() {
 		series := i.set.At()
 		labels := map[string]interface{}{}
@@ -21,6 +21,7 @@ such restriction.
 package tsdb
 
 import (
+	"context"
 	"fmt"
 	"github.com/nuclio/logger"
 	"github.com/pkg/errors"
@@ -31,7 +32,7 @@ import (
 	"time"
 )
 
-func (b *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, error) {
+func (b *Backend) Write(ctx context.Context, request *frames.WriteRequest) (frames.FrameAppender, error) {
 	b.logger.InfoWith("write request", "request", request)
 	adapter, err := b.GetAdapter(request.Session, request.Table)
 	if err != nil {
@@ -44,6 +45,7 @@ func (b *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, err
 	}
 
 	newTsdbAppender := tsdbAppender{
+		ctx:      ctx,
 		request:  request,
 		appender: appender,
 		logger:   b.logger,
@@ -61,6 +63,7 @@ func (b *Backend) Write(request *frames.WriteRequest) (frames.FrameAppender, err
 
 // Appender is key/value appender
 type tsdbAppender struct {
+	ctx      context.Context
 	request  *frames.WriteRequest
 	appender tsdb.Appender
 	logger   logger.Logger
@@ -72,6 +75,9 @@ type metricCtx struct {
 }
 
 func (a *tsdbAppender) Add(frame frames.Frame) error {
+	if err := a.ctx.Err(); err != nil {
+		return err
+	}
 
 	if frame.Len() == 0 {
 		return nil
@@ -246,6 +252,17 @@ func newLset(labels map[string]interface{}, name string, singleCol bool, extraId
 }
 
 func (a *tsdbAppender) WaitForComplete(timeout time.Duration) error {
+	// Don't wait past ctx deadline
+	if deadline, ok := a.ctx.Deadline(); ok {
+		if left := time.Until(deadline); left < timeout {
+			timeout = left
+		}
+	}
+
 	_, err := a.appender.WaitForCompletion(timeout)
+	if ctxErr := a.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
 	return err
 }
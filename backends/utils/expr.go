@@ -0,0 +1,907 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package utils
+
+// SQL expression evaluation over frame rows
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xwb1989/sqlparser"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/ops"
+)
+
+// ParseExpr parses a single SQL expression (e.g. "cpu * 100")
+func ParseExpr(text string) (sqlparser.Expr, error) {
+	stmt, err := sqlparser.Parse("SELECT " + text + " FROM t")
+	if err != nil {
+		return nil, errors.Wrapf(err, "bad expression - %q", text)
+	}
+
+	slct, ok := stmt.(*sqlparser.Select)
+	if !ok || len(slct.SelectExprs) != 1 {
+		return nil, fmt.Errorf("bad expression - %q", text)
+	}
+
+	aliased, ok := slct.SelectExprs[0].(*sqlparser.AliasedExpr)
+	if !ok {
+		return nil, fmt.Errorf("bad expression - %q", text)
+	}
+
+	return aliased.Expr, nil
+}
+
+// Evaluator evaluates SQL expressions on frame rows, it is used by both the
+// API (query expressions and filters) and the backends that filter in process
+type Evaluator struct {
+	columns map[string]frames.Column
+	regexps map[string]*regexp.Regexp
+}
+
+// NewEvaluator returns an evaluator over the columns and indices of frame
+func NewEvaluator(frame frames.Frame) *Evaluator {
+	columns := make(map[string]frames.Column)
+	for _, col := range frame.Indices() {
+		columns[col.Name()] = col
+	}
+
+	for _, name := range frame.Names() {
+		if col, err := frame.Column(name); err == nil {
+			columns[name] = col
+		}
+	}
+
+	return &Evaluator{
+		columns: columns,
+		regexps: make(map[string]*regexp.Regexp),
+	}
+}
+
+// Column evaluates expr on all rows, the result is a column called name
+func (e *Evaluator) Column(expr sqlparser.Expr, name string, size int) (frames.Column, error) {
+	if col, ok := e.columnRef(expr); ok {
+		if col.Name() == name {
+			return col, nil
+		}
+		return ops.Rename(col, name)
+	}
+
+	values := make([]interface{}, size)
+	for i := range values {
+		var err error
+		values[i], err = e.Eval(expr, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewColumnFromValues(name, values)
+}
+
+// columnRef returns the column expr refers to. Aggregations are columns
+// computed by the group by stage
+func (e *Evaluator) columnRef(expr sqlparser.Expr) (frames.Column, bool) {
+	var col frames.Column
+	var ok bool
+	switch expr := expr.(type) {
+	case *sqlparser.ColName:
+		col, ok = e.columns[expr.Name.String()]
+	case *sqlparser.FuncExpr:
+		col, ok = e.columns[sqlparser.String(expr)]
+	}
+
+	return col, ok
+}
+
+// Eval evaluates expr on row, nil is SQL NULL
+func (e *Evaluator) Eval(expr sqlparser.Expr, row int) (interface{}, error) {
+	if col, ok := e.columnRef(expr); ok {
+		return ops.ValueAt(col, row)
+	}
+
+	switch expr := expr.(type) {
+	case *sqlparser.ColName:
+		return nil, fmt.Errorf("unknown column - %q", expr.Name.String())
+	case *sqlparser.SQLVal:
+		return sqlValue(expr)
+	case sqlparser.BoolVal:
+		return bool(expr), nil
+	case *sqlparser.NullVal:
+		return nil, nil
+	case *sqlparser.ParenExpr:
+		return e.Eval(expr.Expr, row)
+	case *sqlparser.UnaryExpr:
+		return e.evalUnary(expr, row)
+	case *sqlparser.BinaryExpr:
+		left, right, err := e.evalPair(expr.Left, expr.Right, row)
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic(expr.Operator, left, right)
+	case *sqlparser.ComparisonExpr:
+		return e.evalComparison(expr, row)
+	case *sqlparser.RangeCond:
+		return e.evalRange(expr, row)
+	case *sqlparser.IsExpr:
+		return e.evalIs(expr, row)
+	case *sqlparser.AndExpr:
+		left, right, err := e.evalPair(expr.Left, expr.Right, row)
+		if err != nil {
+			return nil, err
+		}
+		return and(left, right)
+	case *sqlparser.OrExpr:
+		left, right, err := e.evalPair(expr.Left, expr.Right, row)
+		if err != nil {
+			return nil, err
+		}
+		return or(left, right)
+	case *sqlparser.NotExpr:
+		value, err := e.Eval(expr.Expr, row)
+		if err != nil {
+			return nil, err
+		}
+		return not(value)
+	case *sqlparser.FuncExpr:
+		return e.evalFunc(expr, row)
+	}
+
+	return nil, fmt.Errorf("unsupported expression - %s", sqlparser.String(expr))
+}
+
+func (e *Evaluator) evalPair(left, right sqlparser.Expr, row int) (interface{}, interface{}, error) {
+	lval, err := e.Eval(left, row)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rval, err := e.Eval(right, row)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return lval, rval, nil
+}
+
+func (e *Evaluator) evalUnary(expr *sqlparser.UnaryExpr, row int) (interface{}, error) {
+	value, err := e.Eval(expr.Expr, row)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	switch expr.Operator {
+	case sqlparser.UPlusStr:
+		return value, nil
+	case sqlparser.UMinusStr:
+		switch value.(type) {
+		case int64:
+			return -value.(int64), nil
+		case float64:
+			return -value.(float64), nil
+		}
+		return nil, fmt.Errorf("can't negate %T", value)
+	case sqlparser.BangStr:
+		return not(value)
+	}
+
+	return nil, fmt.Errorf("unsupported operator - %s", expr.Operator)
+}
+
+func (e *Evaluator) evalComparison(expr *sqlparser.ComparisonExpr, row int) (interface{}, error) {
+	left, err := e.Eval(expr.Left, row)
+	if err != nil {
+		return nil, err
+	}
+
+	switch expr.Operator {
+	case sqlparser.InStr, sqlparser.NotInStr:
+		result, err := e.evalIn(left, expr.Right, row)
+		if err != nil || expr.Operator == sqlparser.InStr {
+			return result, err
+		}
+		return not(result)
+	}
+
+	right, err := e.Eval(expr.Right, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if expr.Operator == sqlparser.NullSafeEqualStr {
+		if left == nil || right == nil {
+			return left == nil && right == nil, nil
+		}
+		cmp, err := compareValues(left, right)
+		return cmp == 0, err
+	}
+
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	switch expr.Operator {
+	case sqlparser.LikeStr, sqlparser.NotLikeStr:
+		matched, err := e.match(left, right, true)
+		return matched != (expr.Operator == sqlparser.NotLikeStr), err
+	case sqlparser.RegexpStr, sqlparser.NotRegexpStr:
+		matched, err := e.match(left, right, false)
+		return matched != (expr.Operator == sqlparser.NotRegexpStr), err
+	}
+
+	cmp, err := compareValues(left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch expr.Operator {
+	case sqlparser.EqualStr:
+		return cmp == 0, nil
+	case sqlparser.NotEqualStr:
+		return cmp != 0, nil
+	case sqlparser.LessThanStr:
+		return cmp < 0, nil
+	case sqlparser.LessEqualStr:
+		return cmp <= 0, nil
+	case sqlparser.GreaterThanStr:
+		return cmp > 0, nil
+	case sqlparser.GreaterEqualStr:
+		return cmp >= 0, nil
+	}
+
+	return nil, fmt.Errorf("unsupported operator - %s", expr.Operator)
+}
+
+func (e *Evaluator) evalIn(left interface{}, right sqlparser.Expr, row int) (interface{}, error) {
+	tuple, ok := right.(sqlparser.ValTuple)
+	if !ok {
+		return nil, fmt.Errorf("unsupported IN expression - %s", sqlparser.String(right))
+	}
+
+	if left == nil {
+		return nil, nil
+	}
+
+	hasNull := false
+	for _, expr := range tuple {
+		value, err := e.Eval(expr, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if value == nil {
+			hasNull = true
+			continue
+		}
+
+		cmp, err := compareValues(left, value)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp == 0 {
+			return true, nil
+		}
+	}
+
+	if hasNull {
+		return nil, nil
+	}
+	return false, nil
+}
+
+func (e *Evaluator) evalRange(expr *sqlparser.RangeCond, row int) (interface{}, error) {
+	from := &sqlparser.ComparisonExpr{
+		Operator: sqlparser.GreaterEqualStr,
+		Left:     expr.Left,
+		Right:    expr.From,
+	}
+
+	to := &sqlparser.ComparisonExpr{
+		Operator: sqlparser.LessEqualStr,
+		Left:     expr.Left,
+		Right:    expr.To,
+	}
+
+	between, err := e.Eval(&sqlparser.AndExpr{Left: from, Right: to}, row)
+	if err != nil || expr.Operator == sqlparser.BetweenStr {
+		return between, err
+	}
+
+	return not(between)
+}
+
+func (e *Evaluator) evalIs(expr *sqlparser.IsExpr, row int) (interface{}, error) {
+	value, err := e.Eval(expr.Expr, row)
+	if err != nil {
+		return nil, err
+	}
+
+	switch expr.Operator {
+	case sqlparser.IsNullStr:
+		return value == nil, nil
+	case sqlparser.IsNotNullStr:
+		return value != nil, nil
+	}
+
+	var isTrue bool
+	if value != nil {
+		isTrue, err = asBool(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch expr.Operator {
+	case sqlparser.IsTrueStr:
+		return value != nil && isTrue, nil
+	case sqlparser.IsNotTrueStr:
+		return value == nil || !isTrue, nil
+	case sqlparser.IsFalseStr:
+		return value != nil && !isTrue, nil
+	case sqlparser.IsNotFalseStr:
+		return value == nil || isTrue, nil
+	}
+
+	return nil, fmt.Errorf("unsupported operator - %s", expr.Operator)
+}
+
+func (e *Evaluator) evalFunc(expr *sqlparser.FuncExpr, row int) (interface{}, error) {
+	name := expr.Name.Lowered()
+	args := make([]interface{}, len(expr.Exprs))
+	for i, sexpr := range expr.Exprs {
+		aliased, ok := sexpr.(*sqlparser.AliasedExpr)
+		if !ok {
+			return nil, fmt.Errorf("%s - bad argument %d", name, i)
+		}
+
+		var err error
+		args[i], err = e.Eval(aliased.Expr, row)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	value, err := callFunc(name, args)
+	if err != nil {
+		return nil, errors.Wrap(err, sqlparser.String(expr))
+	}
+
+	return value, nil
+}
+
+// match matches value against a LIKE (if like is true) or a regular
+// expression pattern
+func (e *Evaluator) match(value, pattern interface{}, like bool) (bool, error) {
+	str, ok := value.(string)
+	if !ok {
+		return false, fmt.Errorf("can't match %T", value)
+	}
+
+	expr, ok := pattern.(string)
+	if !ok {
+		return false, fmt.Errorf("bad pattern type - %T", pattern)
+	}
+
+	if like {
+		expr = likeToRegexp(expr)
+	}
+
+	re, ok := e.regexps[expr]
+	if !ok {
+		var err error
+		re, err = regexp.Compile(expr)
+		if err != nil {
+			return false, err
+		}
+		e.regexps[expr] = re
+	}
+
+	return re.MatchString(str), nil
+}
+
+func likeToRegexp(pattern string) string {
+	var buf strings.Builder
+	buf.WriteString("^(?s)")
+	for _, c := range pattern {
+		switch c {
+		case '%':
+			buf.WriteString(".*")
+		case '_':
+			buf.WriteString(".")
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buf.WriteString("$")
+	return buf.String()
+}
+
+func sqlValue(val *sqlparser.SQLVal) (interface{}, error) {
+	switch val.Type {
+	case sqlparser.StrVal:
+		return string(val.Val), nil
+	case sqlparser.IntVal:
+		return strconv.ParseInt(string(val.Val), 10, 64)
+	case sqlparser.FloatVal:
+		return strconv.ParseFloat(string(val.Val), 64)
+	}
+
+	return nil, fmt.Errorf("unsupported value - %s", sqlparser.String(val))
+}
+
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	li, lok := left.(int64)
+	ri, rok := right.(int64)
+	if lok && rok {
+		switch op {
+		case sqlparser.PlusStr:
+			return li + ri, nil
+		case sqlparser.MinusStr:
+			return li - ri, nil
+		case sqlparser.MultStr:
+			return li * ri, nil
+		case sqlparser.IntDivStr, sqlparser.ModStr:
+			if ri == 0 { // SQL division by zero is NULL
+				return nil, nil
+			}
+
+			if op == sqlparser.ModStr {
+				return li % ri, nil
+			}
+			return li / ri, nil
+		}
+	}
+
+	lf, err := AsFloat(left)
+	if err != nil {
+		return nil, err
+	}
+
+	rf, err := AsFloat(right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case sqlparser.PlusStr:
+		return lf + rf, nil
+	case sqlparser.MinusStr:
+		return lf - rf, nil
+	case sqlparser.MultStr:
+		return lf * rf, nil
+	case sqlparser.DivStr, sqlparser.IntDivStr, sqlparser.ModStr:
+		if rf == 0 {
+			return nil, nil
+		}
+
+		switch op {
+		case sqlparser.DivStr:
+			return lf / rf, nil
+		case sqlparser.IntDivStr:
+			return int64(lf / rf), nil
+		}
+		return math.Mod(lf, rf), nil
+	}
+
+	return nil, fmt.Errorf("unsupported operator - %s", op)
+}
+
+// compareValues returns <0, 0 or >0. Times can be compared to strings in
+// RFC3339 format
+func compareValues(a, b interface{}) (int, error) {
+	switch a.(type) {
+	case int64, float64:
+		if ai, ok := a.(int64); ok {
+			if bi, ok := b.(int64); ok {
+				return compareFloats(float64(ai), float64(bi)), nil
+			}
+		}
+
+		af, _ := AsFloat(a)
+		bf, err := AsFloat(b)
+		if err != nil {
+			break
+		}
+		return compareFloats(af, bf), nil
+	case string:
+		switch b.(type) {
+		case string:
+			return strings.Compare(a.(string), b.(string)), nil
+		case time.Time:
+			cmp, err := compareValues(b, a)
+			return -cmp, err
+		}
+	case time.Time:
+		bt, ok := b.(time.Time)
+		if s, isStr := b.(string); isStr {
+			var err error
+			bt, err = parseTime(s)
+			if err != nil {
+				return 0, err
+			}
+			ok = true
+		}
+
+		if ok {
+			at := a.(time.Time)
+			switch {
+			case at.Before(bt):
+				return -1, nil
+			case at.After(bt):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case bool:
+		if bb, ok := b.(bool); ok {
+			ab := a.(bool)
+			switch {
+			case ab == bb:
+				return 0, nil
+			case bb:
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("can't compare %T and %T", a, b)
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("bad time - %q", value)
+}
+
+func asBool(value interface{}) (bool, error) {
+	switch value.(type) {
+	case bool:
+		return value.(bool), nil
+	case int64:
+		return value.(int64) != 0, nil
+	case float64:
+		return value.(float64) != 0, nil
+	}
+
+	return false, fmt.Errorf("not a boolean - %T", value)
+}
+
+// and, or & not use SQL three valued logic (nil is unknown)
+
+func and(left, right interface{}) (interface{}, error) {
+	lval, lnull, err := boolOrNull(left)
+	if err != nil {
+		return nil, err
+	}
+
+	rval, rnull, err := boolOrNull(right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case (!lnull && !lval) || (!rnull && !rval):
+		return false, nil
+	case lnull || rnull:
+		return nil, nil
+	}
+	return true, nil
+}
+
+func or(left, right interface{}) (interface{}, error) {
+	lval, lnull, err := boolOrNull(left)
+	if err != nil {
+		return nil, err
+	}
+
+	rval, rnull, err := boolOrNull(right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case (!lnull && lval) || (!rnull && rval):
+		return true, nil
+	case lnull || rnull:
+		return nil, nil
+	}
+	return false, nil
+}
+
+func not(value interface{}) (interface{}, error) {
+	val, null, err := boolOrNull(value)
+	if err != nil || null {
+		return nil, err
+	}
+
+	return !val, nil
+}
+
+func boolOrNull(value interface{}) (bool, bool, error) {
+	if value == nil {
+		return false, true, nil
+	}
+
+	val, err := asBool(value)
+	return val, false, err
+}
+
+// callFunc calls an SQL function, KV filter functions (exists, starts, ends &
+// contains) are supported as well
+func callFunc(name string, args []interface{}) (interface{}, error) {
+	switch name {
+	case "exists":
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		return args[0] != nil, nil
+	case "coalesce", "ifnull":
+		for _, arg := range args {
+			if arg != nil {
+				return arg, nil
+			}
+		}
+		return nil, nil
+	case "concat":
+		var buf strings.Builder
+		for _, arg := range args {
+			if arg == nil {
+				return nil, nil
+			}
+			fmt.Fprintf(&buf, "%v", arg)
+		}
+		return buf.String(), nil
+	}
+
+	for _, arg := range args {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+
+	switch name {
+	case "abs":
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		if i, ok := args[0].(int64); ok {
+			if i < 0 {
+				return -i, nil
+			}
+			return i, nil
+		}
+		return mathFunc(math.Abs, args[0])
+	case "ceil", "ceiling":
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		return mathFunc(math.Ceil, args[0])
+	case "floor":
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		return mathFunc(math.Floor, args[0])
+	case "sqrt":
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		return mathFunc(math.Sqrt, args[0])
+	case "round":
+		if len(args) == 2 {
+			digits, ok := args[1].(int64)
+			if !ok {
+				return nil, fmt.Errorf("bad number of digits - %v", args[1])
+			}
+
+			scale := math.Pow(10, float64(digits))
+			return mathFunc(func(v float64) float64 { return math.Round(v*scale) / scale }, args[0])
+		}
+
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+		return mathFunc(math.Round, args[0])
+	case "pow", "power":
+		if err := checkArgs(args, 2); err != nil {
+			return nil, err
+		}
+
+		exp, err := AsFloat(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return mathFunc(func(v float64) float64 { return math.Pow(v, exp) }, args[0])
+	case "lower", "upper", "length":
+		if err := checkArgs(args, 1); err != nil {
+			return nil, err
+		}
+
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("not a string - %T", args[0])
+		}
+
+		switch name {
+		case "lower":
+			return strings.ToLower(s), nil
+		case "upper":
+			return strings.ToUpper(s), nil
+		}
+		return int64(len(s)), nil
+	case "starts", "ends", "contains":
+		if err := checkArgs(args, 2); err != nil {
+			return nil, err
+		}
+
+		s, ok := args[0].(string)
+		sub, subOK := args[1].(string)
+		if !ok || !subOK {
+			return nil, fmt.Errorf("not a string - %T, %T", args[0], args[1])
+		}
+
+		switch name {
+		case "starts":
+			return strings.HasPrefix(s, sub), nil
+		case "ends":
+			return strings.HasSuffix(s, sub), nil
+		}
+		return strings.Contains(s, sub), nil
+	}
+
+	return nil, fmt.Errorf("unknown function - %q", name)
+}
+
+func checkArgs(args []interface{}, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("wrong number of arguments (%d != %d)", len(args), n)
+	}
+
+	return nil
+}
+
+func mathFunc(fn func(float64) float64, value interface{}) (interface{}, error) {
+	v, err := AsFloat(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return fn(v), nil
+}
+
+// NewColumnFromValues creates a column from values, nil values are nulls
+func NewColumnFromValues(name string, values []interface{}) (frames.Column, error) {
+	dtype, found := frames.StringType, false // all null columns are strings
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+
+		vtype, err := valueDType(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%q", name)
+		}
+
+		switch {
+		case !found:
+			dtype, found = vtype, true
+		case vtype == dtype:
+		case isNumeric(vtype) && isNumeric(dtype):
+			dtype = frames.FloatType
+		default:
+			return nil, fmt.Errorf("%q - mixed types (%d and %d)", name, dtype, vtype)
+		}
+	}
+
+	col, err := frames.NewSliceColumn(name, EmptyData(dtype))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, value := range values {
+		if i, ok := value.(int64); ok && dtype == frames.FloatType {
+			value = float64(i)
+		}
+
+		if err := AppendColumn(col, value); err != nil {
+			return nil, errors.Wrapf(err, "%q", name)
+		}
+	}
+
+	return col, nil
+}
+
+func valueDType(value interface{}) (frames.DType, error) {
+	switch value.(type) {
+	case int64:
+		return frames.IntType, nil
+	case float64:
+		return frames.FloatType, nil
+	case string:
+		return frames.StringType, nil
+	case time.Time:
+		return frames.TimeType, nil
+	case bool:
+		return frames.BoolType, nil
+	}
+
+	return 0, fmt.Errorf("unsupported type - %T", value)
+}
+
+func isNumeric(dtype frames.DType) bool {
+	return dtype == frames.IntType || dtype == frames.FloatType
+}
+
+// AsFloat returns a numeric value as float64
+func AsFloat(value interface{}) (float64, error) {
+	switch value.(type) {
+	case int64:
+		return float64(value.(int64)), nil
+	case float64:
+		return value.(float64), nil
+	}
+
+	return 0, fmt.Errorf("not a number - %T", value)
+}
+
+// EmptyData returns an empty slice for dtype column data
+func EmptyData(dtype frames.DType) interface{} {
+	switch dtype {
+	case frames.IntType:
+		return []int64{}
+	case frames.FloatType:
+		return []float64{}
+	case frames.StringType:
+		return []string{}
+	case frames.TimeType:
+		return []time.Time{}
+	case frames.BoolType:
+		return []bool{}
+	}
+
+	return nil
+}
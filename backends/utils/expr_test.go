@@ -0,0 +1,106 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/v3io/frames"
+)
+
+func TestEval(t *testing.T) {
+	columns := []frames.Column{}
+	for name, data := range map[string]interface{}{
+		"host": []string{"a"},
+		"cpu":  []int64{3},
+		"user": []string{"x"},
+	} {
+		col, err := frames.NewSliceColumn(name, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, col)
+	}
+
+	frame, err := frames.NewFrame(columns, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ev := NewEvaluator(frame)
+
+	testCases := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"1 + 2 * 3", int64(7)},
+		{"7 / 2", 3.5},
+		{"7 div 2", int64(3)},
+		{"-cpu", int64(-3)},
+		{"cpu / 0", nil},
+		{"host = 'a' and cpu > 2", true},
+		{"host like 'a%'", true},
+		{"host not in ('b', 'c')", true},
+		{"cpu between 1 and 2", false},
+		{"null is null", true},
+		{"null > 1", nil},
+		{"null or true", true},
+		{"coalesce(null, user)", "x"},
+		{"round(2.345, 2)", 2.35},
+		{"concat(host, '-', cpu)", "a-3"},
+		{"contains(user, 'x') and starts(host, 'a')", true},
+	}
+
+	for _, tc := range testCases {
+		expr, err := ParseExpr(tc.expr)
+		if err != nil {
+			t.Fatalf("%s: can't parse - %s", tc.expr, err)
+		}
+
+		value, err := ev.Eval(expr, 0)
+		if err != nil {
+			t.Fatalf("%s: can't eval - %s", tc.expr, err)
+		}
+
+		if value != tc.expected {
+			t.Fatalf("%s: %v (%T) != %v", tc.expr, value, value, tc.expected)
+		}
+	}
+}
+
+func TestNewColumnFromValues(t *testing.T) {
+	col, err := NewColumnFromValues("a", []interface{}{int64(1), nil, 2.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if col.DType() != frames.FloatType {
+		t.Fatalf("bad dtype - %d", col.DType())
+	}
+
+	if !col.IsNull(1) {
+		t.Fatal("nil is not null")
+	}
+
+	if _, err := NewColumnFromValues("b", []interface{}{int64(1), "a"}); err == nil {
+		t.Fatal("no error on mixed types")
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/xwb1989/sqlparser"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/ops"
+)
+
+var (
+	wherePrefixRe = regexp.MustCompile(`(?i)^\s*where\s+`)
+	existsRe      = regexp.MustCompile(`(?i)^exists\s*\(`)
+
+	// FilterOperators are the operators and functions Filter supports, in
+	// frames.Pushdown format
+	FilterOperators = []string{
+		"=", "!=", "<", "<=", ">", ">=", "in", "and", "or", "not",
+		"exists", "starts", "ends", "contains",
+	}
+)
+
+// Filter is a row filter for backends that evaluate filters in process. The
+// syntax is the KV backend filter syntax (e.g. "a == 1 AND starts(b, 'x')"),
+// SQL WHERE syntax is accepted as well. Rows are evaluated with Evaluator, the
+// same way the API evaluates filters backends don't handle
+type Filter struct {
+	expr    sqlparser.Expr
+	columns []string
+}
+
+// ParseFilter parses a filter expression
+func ParseFilter(text string) (*Filter, error) {
+	sql := wherePrefixRe.ReplaceAllString(normalizeFilter(text), "")
+	stmt, err := sqlparser.Parse("SELECT * FROM t WHERE " + sql)
+	if err != nil {
+		return nil, errors.Wrapf(err, "bad filter - %q", text)
+	}
+
+	slct, ok := stmt.(*sqlparser.Select)
+	if !ok || slct.Where == nil {
+		return nil, fmt.Errorf("bad filter - %q", text)
+	}
+
+	filter := &Filter{expr: slct.Where.Expr}
+	seen := make(map[string]bool)
+	sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if col, ok := node.(*sqlparser.ColName); ok && !seen[col.Name.String()] {
+			seen[col.Name.String()] = true
+			filter.columns = append(filter.columns, col.Name.String())
+		}
+		return true, nil
+	}, filter.expr)
+
+	return filter, nil
+}
+
+// normalizeFilter converts KV filter syntax to SQL ("==" -> "=" and the exists
+// function, which is an SQL keyword, is quoted), quoted strings are left as is
+func normalizeFilter(text string) string {
+	var buf strings.Builder
+	var quote rune
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(runes) {
+				buf.WriteRune(c)
+				i++
+				c = runes[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '=' && i+1 < len(runes) && runes[i+1] == '=':
+			i++
+		case (c == 'e' || c == 'E') && (i == 0 || !isIdentRune(runes[i-1])) && existsRe.MatchString(string(runes[i:])):
+			buf.WriteString("`exists`")
+			i += len("exists") - 1
+			continue
+		}
+		buf.WriteRune(c)
+	}
+
+	return buf.String()
+}
+
+func isIdentRune(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// Expr returns the parsed filter expression
+func (f *Filter) Expr() sqlparser.Expr {
+	return f.expr
+}
+
+// Columns returns the names of the columns the filter uses
+func (f *Filter) Columns() []string {
+	return f.columns
+}
+
+// Restrict returns a filter with the conditions of f (joined by AND) that use
+// only columns, nil if there are none. Rows that don't match the restricted
+// filter don't match f
+func (f *Filter) Restrict(columns []string) *Filter {
+	allowed := make(map[string]bool)
+	for _, name := range columns {
+		allowed[name] = true
+	}
+
+	var restricted *Filter
+	for _, cond := range splitAnd(f.expr) {
+		ok := true
+		var condColumns []string
+		sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if col, isCol := node.(*sqlparser.ColName); isCol {
+				ok = ok && allowed[col.Name.String()]
+				condColumns = append(condColumns, col.Name.String())
+			}
+			return ok, nil
+		}, cond)
+
+		if !ok {
+			continue
+		}
+
+		if restricted == nil {
+			restricted = &Filter{expr: cond}
+		} else {
+			restricted.expr = &sqlparser.AndExpr{Left: restricted.expr, Right: cond}
+		}
+
+		for _, name := range condColumns {
+			if indexOf(name, restricted.columns) == -1 {
+				restricted.columns = append(restricted.columns, name)
+			}
+		}
+	}
+
+	return restricted
+}
+
+// Predicate returns the filter as a frame predicate, rows where the filter is
+// NULL (e.g. compared to a null value) don't match
+func (f *Filter) Predicate() ops.Predicate {
+	return func(frame frames.Frame) (ops.RowPredicate, error) {
+		ev := NewEvaluator(frame)
+		for _, name := range f.columns {
+			if _, ok := ev.columns[name]; !ok {
+				return nil, fmt.Errorf("unknown column - %q", name)
+			}
+		}
+
+		matches := make([]bool, frame.Len())
+		for i := range matches {
+			value, err := ev.Eval(f.expr, i)
+			if err != nil {
+				return nil, errors.Wrapf(err, "row %d", i)
+			}
+
+			if value != nil {
+				match, err := asBool(value)
+				if err != nil {
+					return nil, errors.Wrap(err, "bad filter value")
+				}
+				matches[i] = match
+			}
+		}
+
+		return func(i int) bool { return matches[i] }, nil
+	}
+}
+
+// splitAnd splits expr to conditions joined by AND
+func splitAnd(expr sqlparser.Expr) []sqlparser.Expr {
+	switch e := expr.(type) {
+	case *sqlparser.AndExpr:
+		return append(splitAnd(e.Left), splitAnd(e.Right)...)
+	case *sqlparser.ParenExpr:
+		return splitAnd(e.Expr)
+	}
+
+	return []sqlparser.Expr{expr}
+}
+
+func indexOf(name string, names []string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
+}
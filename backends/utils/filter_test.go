@@ -0,0 +1,155 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+)
+
+func TestFilter(t *testing.T) {
+	t0 := time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC)
+	columns := []frames.Column{}
+	addColumn := func(name string, data interface{}, nulls []bool) {
+		col, err := frames.NewNullableSliceColumn(name, data, nulls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, col)
+	}
+
+	addColumn("i", []int64{1, 2, 3, 4}, []bool{false, false, false, true})
+	addColumn("f", []float64{1.5, 2.5, 3.5, 4.5}, nil)
+	addColumn("s", []string{"alpha", "beta", "gamma", "a=='b'"}, nil)
+	addColumn("t", []time.Time{t0, t0.Add(time.Hour), t0.Add(48 * time.Hour), t0}, nil)
+	addColumn("b", []bool{true, false, true, false}, nil)
+
+	frame, err := frames.NewFrame(columns, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		filter   string
+		expected []bool
+	}{
+		{"i == 2", []bool{false, true, false, false}},
+		{"i = 2 OR f > 3", []bool{false, true, true, true}},
+		{"where i >= 2 and b == true", []bool{false, false, true, false}},
+		{"not (i < 3)", []bool{false, false, true, false}},
+		{"i != 1", []bool{false, true, true, false}},
+		{"i in (1, 3)", []bool{true, false, true, false}},
+		{"s not in ('beta')", []bool{true, false, true, true}},
+		{"starts(s, 'a')", []bool{true, false, false, true}},
+		{"ends(s, 'ta') or contains(s, 'mm')", []bool{false, true, true, false}},
+		{"exists(i)", []bool{true, true, true, false}},
+		{"s == 'a==\\'b\\''", []bool{false, false, false, true}},
+		{"t > '2018-12-01 00:30:00'", []bool{false, true, true, false}},
+		{"t >= '2018-12-02'", []bool{false, false, true, false}},
+		{"f between 2 and 4", []bool{false, true, true, false}},
+		{"i is null or i > -1", []bool{true, true, true, true}},
+		{"s like 'a%' and upper(s) != 'ALPHA'", []bool{false, false, false, true}},
+		{"i not in (1, null)", []bool{false, false, false, false}},
+	}
+
+	for _, tc := range testCases {
+		filter, err := ParseFilter(tc.filter)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.filter, err)
+		}
+
+		match, err := filter.Predicate()(frame)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.filter, err)
+		}
+
+		actual := make([]bool, frame.Len())
+		for i := range actual {
+			actual[i] = match(i)
+		}
+
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Fatalf("%s: %v != %v", tc.filter, actual, tc.expected)
+		}
+	}
+
+	filter, err := ParseFilter("x > 1 and i < 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(filter.Columns(), []string{"x", "i"}) {
+		t.Fatalf("bad columns - %v", filter.Columns())
+	}
+
+	if _, err := filter.Predicate()(frame); err == nil {
+		t.Fatal("no error on unknown column")
+	}
+
+	for _, text := range []string{"i >", "i > 'a'", "median(s) = 'a'"} {
+		filter, err := ParseFilter(text)
+		if err != nil {
+			continue
+		}
+
+		if _, err := filter.Predicate()(frame); err == nil {
+			t.Fatalf("%s: no error", text)
+		}
+	}
+}
+
+func TestFilterRestrict(t *testing.T) {
+	filter, err := ParseFilter("a == 1 and (b > 2 or c < 3) and (d = 'x' and a < 7)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		columns  []string
+		expected []string // restricted filter columns, nil for no filter
+	}{
+		{[]string{"a"}, []string{"a"}},
+		{[]string{"a", "d"}, []string{"a", "d"}},
+		{[]string{"b", "c"}, []string{"b", "c"}},
+		{[]string{"b"}, nil},
+	}
+
+	for _, tc := range testCases {
+		restricted := filter.Restrict(tc.columns)
+		if tc.expected == nil {
+			if restricted != nil {
+				t.Fatalf("%v: got filter on %v", tc.columns, restricted.Columns())
+			}
+			continue
+		}
+
+		if restricted == nil {
+			t.Fatalf("%v: no filter", tc.columns)
+		}
+
+		if !reflect.DeepEqual(restricted.Columns(), tc.expected) {
+			t.Fatalf("%v: bad columns - %v", tc.columns, restricted.Columns())
+		}
+	}
+}
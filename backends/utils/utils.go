@@ -22,10 +22,17 @@ package utils
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
+
 	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
 )
 
 // AppendValue appends a value to data
@@ -91,25 +98,14 @@ func NewColumn(value interface{}, size int) (interface{}, error) {
 	return nil, fmt.Errorf("unknown type - %T", value)
 }
 
-// AppendNil appends an empty value to data
+// AppendNil appends a null value to col
 func AppendNil(col frames.Column) error {
-	var value interface{}
 	switch col.DType() {
-	case frames.IntType:
-		value = int64(0)
-	case frames.FloatType:
-		value = math.NaN()
-	case frames.StringType:
-		value = ""
-	case frames.TimeType:
-		value = time.Unix(0, 0)
-	case frames.BoolType:
-		value = false
-	default:
-		return fmt.Errorf("unsupported data type - %d", col.DType())
+	case frames.IntType, frames.FloatType, frames.StringType, frames.TimeType, frames.BoolType:
+		return AppendColumn(col, nil)
 	}
 
-	return AppendColumn(col, value)
+	return fmt.Errorf("unsupported data type - %d", col.DType())
 }
 
 // ColAt return value at index i in column as interface{}
@@ -169,3 +165,133 @@ func AppendColumn(col frames.Column, value interface{}) error {
 
 	return ca.Append(value)
 }
+
+// SchemaDType returns the data type of a schema field type name. Both frames
+// names (e.g. "integer", see DTypeName) and v3io names (e.g. "long") are accepted
+func SchemaDType(name string) (frames.DType, error) {
+	switch strings.ToLower(name) {
+	case "integer", "int", "long":
+		return frames.IntType, nil
+	case "float", "double":
+		return frames.FloatType, nil
+	case "string":
+		return frames.StringType, nil
+	case "time", "timestamp":
+		return frames.TimeType, nil
+	case "boolean", "bool":
+		return frames.BoolType, nil
+	}
+
+	return 0, fmt.Errorf("unknown type - %q", name)
+}
+
+// DTypeName returns the schema field type name of dtype (e.g. "integer")
+func DTypeName(dtype frames.DType) string {
+	return strings.ToLower(pb.DType(dtype).String())
+}
+
+// IntOption returns an integer backend option (frames.BackendConfig.Options),
+// def if it's missing
+func IntOption(options map[string]interface{}, name string, def int) (int, error) {
+	val, ok := options[name]
+	if !ok {
+		return def, nil
+	}
+
+	switch val := val.(type) {
+	case int:
+		return val, nil
+	case int64:
+		return int(val), nil
+	case float64: // JSON numbers
+		if val == math.Trunc(val) {
+			return int(val), nil
+		}
+	}
+
+	return 0, fmt.Errorf("option %q - bad integer value - %v", name, val)
+}
+
+// StringOption returns a string backend option (frames.BackendConfig.Options),
+// def if it's missing
+func StringOption(options map[string]interface{}, name string, def string) (string, error) {
+	val, ok := options[name]
+	if !ok {
+		return def, nil
+	}
+
+	str, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("option %q - bad string value - %v", name, val)
+	}
+
+	return str, nil
+}
+
+// BoolOption returns a boolean backend option (frames.BackendConfig.Options),
+// def if it's missing
+func BoolOption(options map[string]interface{}, name string, def bool) (bool, error) {
+	val, ok := options[name]
+	if !ok {
+		return def, nil
+	}
+
+	b, ok := val.(bool)
+	if !ok {
+		return false, fmt.Errorf("option %q - bad boolean value - %v", name, val)
+	}
+
+	return b, nil
+}
+
+// StringsOption returns a list of strings backend option
+// (frames.BackendConfig.Options), a single string is a list of one. def is
+// returned if the option is missing
+func StringsOption(options map[string]interface{}, name string, def []string) ([]string, error) {
+	val, ok := options[name]
+	if !ok {
+		return def, nil
+	}
+
+	switch val := val.(type) {
+	case string:
+		return []string{val}, nil
+	case []string:
+		return val, nil
+	case []interface{}: // YAML & JSON lists
+		strs := make([]string, len(val))
+		for i, v := range val {
+			str, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("option %q - bad string value at %d - %v", name, i, v)
+			}
+			strs[i] = str
+		}
+		return strs, nil
+	}
+
+	return nil, fmt.Errorf("option %q - bad list of strings value - %v", name, val)
+}
+
+// TempFile creates a temporary file in the directory of path, to replace path
+// (by renaming) when it's complete. The file has the mode of path, or 0644 if
+// path doesn't exist, so replacing keeps path readable by others
+func TempFile(path string) (*os.File, error) {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := file.Chmod(mode); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, errors.Wrap(err, "can't set file mode")
+	}
+
+	return file, nil
+}
@@ -22,7 +22,10 @@ package utils
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -94,6 +97,18 @@ func TestAppendNil(t *testing.T) {
 	if !math.IsNaN(val) {
 		t.Fatalf("AppendNil didn't add NaN to floats (got %v)", val)
 	}
+
+	if !col.IsNull(size) {
+		t.Fatalf("AppendNil didn't add null")
+	}
+
+	if col.IsNull(size - 1) {
+		t.Fatalf("existing value marked as null")
+	}
+
+	if count := col.NullCount(); count != 1 {
+		t.Fatalf("bad null count - %d != 1", count)
+	}
 }
 
 func TestRemoveColumn(t *testing.T) {
@@ -128,3 +143,137 @@ func TestRemoveColumn(t *testing.T) {
 		t.Fatal("no existing column removed")
 	}
 }
+
+func TestSchemaDType(t *testing.T) {
+	for _, dtype := range []frames.DType{frames.IntType, frames.FloatType, frames.StringType, frames.TimeType, frames.BoolType} {
+		out, err := SchemaDType(DTypeName(dtype))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if out != dtype {
+			t.Fatalf("%s: dtype mismatch - %v != %v", DTypeName(dtype), out, dtype)
+		}
+	}
+
+	if dtype, err := SchemaDType("Long"); err != nil || dtype != frames.IntType {
+		t.Fatalf("bad long - %v (%v)", dtype, err)
+	}
+
+	if _, err := SchemaDType("complex"); err == nil {
+		t.Fatal("no error on unknown type")
+	}
+}
+
+func TestIntOption(t *testing.T) {
+	options := map[string]interface{}{
+		"i": 7,
+		"f": 8.0,
+		"s": "9",
+	}
+
+	testCases := []struct {
+		name     string
+		expected int
+		err      bool
+	}{
+		{"i", 7, false},
+		{"f", 8, false},
+		{"s", 0, true},
+		{"missing", 10, false},
+	}
+
+	for _, tc := range testCases {
+		val, err := IntOption(options, tc.name, 10)
+		if tc.err {
+			if err == nil {
+				t.Fatalf("%s: no error", tc.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+
+		if val != tc.expected {
+			t.Fatalf("%s: %d != %d", tc.name, val, tc.expected)
+		}
+	}
+}
+
+func TestStringsOption(t *testing.T) {
+	options := map[string]interface{}{
+		"s":  "a",
+		"ss": []string{"a", "b"},
+		"l":  []interface{}{"a", "b"},
+		"bl": []interface{}{"a", 1},
+		"i":  1,
+	}
+
+	testCases := []struct {
+		name     string
+		expected []string
+		err      bool
+	}{
+		{"s", []string{"a"}, false},
+		{"ss", []string{"a", "b"}, false},
+		{"l", []string{"a", "b"}, false},
+		{"bl", nil, true},
+		{"i", nil, true},
+		{"missing", []string{"x"}, false},
+	}
+
+	for _, tc := range testCases {
+		val, err := StringsOption(options, tc.name, []string{"x"})
+		if tc.err {
+			if err == nil {
+				t.Fatalf("%s: no error", tc.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+
+		if !reflect.DeepEqual(val, tc.expected) {
+			t.Fatalf("%s: %v != %v", tc.name, val, tc.expected)
+		}
+	}
+}
+
+func TestTempFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "frames-utils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "table")
+	for _, mode := range []os.FileMode{0644, 0640} {
+		file, err := TempFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Close()
+
+		info, err := os.Stat(file.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// New files are 0644, replacing files keep their mode
+		if info.Mode().Perm() != mode {
+			t.Fatalf("bad mode - %v != %v", info.Mode().Perm(), mode)
+		}
+
+		if err := os.Rename(file.Name(), path); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chmod(path, 0640); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
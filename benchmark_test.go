@@ -63,7 +63,7 @@ func read(c frames.Client, b *testing.B) {
 func BenchmarkRead_gRPC(b *testing.B) {
 	b.StopTimer()
 	info := setupTest(b)
-	defer info.process.Kill()
+	defer info.close()
 	c, err := grpc.NewClient(info.grpcAddr, nil, nil)
 	if err != nil {
 		b.Fatal(err)
@@ -78,7 +78,7 @@ func BenchmarkRead_gRPC(b *testing.B) {
 func BenchmarkRead_HTTP(b *testing.B) {
 	b.StopTimer()
 	info := setupTest(b)
-	defer info.process.Kill()
+	defer info.close()
 	c, err := http.NewClient(info.httpAddr, nil, nil)
 	if err != nil {
 		b.Fatal(err)
@@ -108,7 +108,7 @@ func write(c frames.Client, req *frames.WriteRequest, frame frames.Frame, b *tes
 func BenchmarkWrite_gRPC(b *testing.B) {
 	b.StopTimer()
 	info := setupTest(b)
-	defer info.process.Kill()
+	defer info.close()
 	c, err := grpc.NewClient(info.grpcAddr, nil, nil)
 	if err != nil {
 		b.Fatal(err)
@@ -124,7 +124,7 @@ func BenchmarkWrite_gRPC(b *testing.B) {
 func BenchmarkWrite_HTTP(b *testing.B) {
 	b.StopTimer()
 	info := setupTest(b)
-	defer info.process.Kill()
+	defer info.close()
 	c, err := http.NewClient(info.httpAddr, nil, nil)
 	if err != nil {
 		b.Fatal(err)
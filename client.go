@@ -40,8 +40,15 @@ type Client interface {
 	Create(request *CreateRequest) error
 	// Delete deletes data or table
 	Delete(request *DeleteRequest) error
-	// Exec executes a command on the backend
-	Exec(request *ExecRequest) error
+	// Exec executes a command on the backend, returns the command result (nil
+	// for commands without a result)
+	Exec(request *ExecRequest) (Frame, error)
+	// List lists tables
+	List(request *ListRequest) ([]string, error)
+	// Describe returns table schema and attributes
+	Describe(request *DescribeRequest) (*TableInfo, error)
+	// Backends returns the server backends and their capabilities
+	Backends() ([]*BackendInfo, error)
 }
 
 // SessionFromEnv return a session from V3IO_SESSION environment variable (JSON encoded)
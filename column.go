@@ -247,6 +247,40 @@ func (c *colImpl) BoolAt(i int) (bool, error) {
 	return c.msg.Bools[i], nil
 }
 
+// IsNull returns true if the value at index i is null
+func (c *colImpl) IsNull(i int) bool {
+	if len(c.msg.Nulls) == 0 || i < 0 || i >= c.Len() {
+		return false
+	}
+
+	if c.msg.Kind == pb.Column_LABEL {
+		i = 0
+	}
+	return c.msg.Nulls[i]
+}
+
+// NullCount returns the number of null values
+func (c *colImpl) NullCount() int {
+	if len(c.msg.Nulls) == 0 {
+		return 0
+	}
+
+	if c.msg.Kind == pb.Column_LABEL {
+		if c.msg.Nulls[0] {
+			return c.Len()
+		}
+		return 0
+	}
+
+	count := 0
+	for _, isNull := range c.msg.Nulls {
+		if isNull {
+			count++
+		}
+	}
+	return count
+}
+
 func (c *colImpl) Slice(start int, end int) (Column, error) {
 	if start > end {
 		return nil, fmt.Errorf("start %d bigger than end %d", start, end)
@@ -305,18 +339,38 @@ func (c *colImpl) Slice(start int, end int) (Column, error) {
 		msg.Bools = data
 	}
 
+	if len(c.msg.Nulls) > 0 {
+		nulls := c.msg.Nulls
+		if c.msg.Kind == pb.Column_SLICE {
+			nulls = nulls[start:end]
+		}
+		msg.Nulls = nulls
+	}
+
 	col := &colImpl{
 		msg: msg,
 	}
 	return col, nil
 }
 
+// Append appends a value to the column, a nil value is appended as null
 func (c *colImpl) Append(value interface{}) error {
 	if c.msg.Kind == pb.Column_LABEL {
 		return c.appendLabel(value)
 	}
 
-	return c.appendSlice(value)
+	if value == nil {
+		return c.appendNull()
+	}
+
+	if err := c.appendSlice(value); err != nil {
+		return err
+	}
+
+	if len(c.msg.Nulls) > 0 {
+		c.msg.Nulls = append(c.msg.Nulls, false)
+	}
+	return nil
 }
 
 // NewSliceColumn returns a new slice column
@@ -361,6 +415,27 @@ func NewSliceColumn(name string, data interface{}) (Column, error) {
 	return col, nil
 }
 
+// NewNullableSliceColumn returns a new slice column with null values.
+// nulls must be the same length as data (or nil if there are no nulls), a true
+// value in nulls marks the value at the same index as null
+func NewNullableSliceColumn(name string, data interface{}, nulls []bool) (Column, error) {
+	col, err := NewSliceColumn(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if nulls == nil {
+		return col, nil
+	}
+
+	if len(nulls) != col.Len() {
+		return nil, fmt.Errorf("%q - nulls size mismatch (%d != %d)", name, len(nulls), col.Len())
+	}
+
+	col.(*colImpl).msg.Nulls = nulls
+	return col, nil
+}
+
 // NewLabelColumn returns a new slabel column
 func NewLabelColumn(name string, value interface{}, size int) (Column, error) {
 	msg := &pb.Column{
@@ -398,6 +473,13 @@ func NewLabelColumn(name string, value interface{}, size int) (Column, error) {
 	return col, nil
 }
 
+// IsLabelColumn returns true if col is a label column (a single value repeated
+// Len() times)
+func IsLabelColumn(col Column) bool {
+	c, ok := col.(*colImpl)
+	return ok && c.msg.Kind == pb.Column_LABEL
+}
+
 func (c *colImpl) appendSlice(value interface{}) error {
 	switch c.msg.Dtype {
 	case pb.DType_INTEGER:
@@ -440,6 +522,24 @@ func (c *colImpl) appendSlice(value interface{}) error {
 	return fmt.Errorf("unknown dtype - %s", c.msg.Dtype)
 }
 
+func (c *colImpl) appendNull() error {
+	value, err := zeroValue(DType(c.msg.Dtype))
+	if err != nil {
+		return err
+	}
+
+	size := c.Len()
+	if err := c.appendSlice(value); err != nil {
+		return err
+	}
+
+	if len(c.msg.Nulls) == 0 {
+		c.msg.Nulls = make([]bool, size, size+1)
+	}
+	c.msg.Nulls = append(c.msg.Nulls, true)
+	return nil
+}
+
 func (c *colImpl) appendLabel(value interface{}) error {
 	if !c.sameLabelValue(value) {
 		return fmt.Errorf("append - wrong type or value mismatch - %v", value)
@@ -450,6 +550,10 @@ func (c *colImpl) appendLabel(value interface{}) error {
 }
 
 func (c *colImpl) sameLabelValue(value interface{}) bool {
+	if value == nil || c.IsNull(0) {
+		return value == nil && c.IsNull(0)
+	}
+
 	switch c.msg.Dtype {
 	case pb.DType_INTEGER:
 		v, ok := pb.AsInt64(value)
@@ -47,3 +47,35 @@ func TestLabelColTimeAt(t *testing.T) {
 		t.Fatalf("bad time %v != %v", ts1, ts)
 	}
 }
+
+func TestColumnNulls(t *testing.T) {
+	data := []float64{1, 0, 3, 0}
+	nulls := []bool{false, true, false, true}
+	col, err := NewNullableSliceColumn("a", data, nulls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := col.NullCount(); n != 2 {
+		t.Fatalf("bad null count %d != 2", n)
+	}
+
+	for i, isNull := range nulls {
+		if col.IsNull(i) != isNull {
+			t.Fatalf("%d: null mismatch %v != %v", i, col.IsNull(i), isNull)
+		}
+	}
+
+	slice, err := col.Slice(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slice.IsNull(0) || slice.IsNull(1) {
+		t.Fatalf("bad nulls in slice")
+	}
+
+	if _, err := NewNullableSliceColumn("a", data, nulls[:2]); err == nil {
+		t.Fatal("no error on bad nulls length")
+	}
+}
@@ -36,6 +36,8 @@ type Config struct {
 	Log            LogConfig `json:"log"`
 	DefaultLimit   int       `json:"limit,omitempty"`
 	DefaultTimeout int       `json:"timeout,omitempty"`
+	// Maximal number of groups in group by queries (0 = no limit)
+	MaxGroups int `json:"maxGroups,omitempty"`
 
 	// default V3IO connection details
 	WebAPIEndpoint string `json:"webApiEndpoint"`
@@ -45,6 +47,8 @@ type Config struct {
 
 	// Number of parallel V3IO worker routines
 	Workers int `json:"workers"`
+	// Retry policy for V3IO requests
+	Retry *RetryConfig `json:"retry,omitempty"`
 
 	Backends []*BackendConfig `json:"backends,omitempty"`
 }
@@ -55,6 +59,10 @@ func (c *Config) InitDefaults() error {
 		c.DefaultTimeout = 30
 	}
 
+	if c.MaxGroups == 0 {
+		c.MaxGroups = 100000
+	}
+
 	return nil
 }
 
@@ -89,6 +97,10 @@ func InitBackendDefaults(cfg *BackendConfig, framesConfig *Config) {
 			cfg.Workers = 8
 		}
 	}
+
+	if cfg.Retry == nil {
+		cfg.Retry = framesConfig.Retry
+	}
 }
 
 // Validate validates the configuration
@@ -128,6 +140,21 @@ type BackendConfig struct {
 
 	// CSV backend
 	RootDir string `json:"rootdir,omitempty"`
+	// Retry policy for V3IO requests (default to the server retry policy)
+	Retry *RetryConfig `json:"retry,omitempty"`
+}
+
+// RetryConfig is the retry policy for failed V3IO requests, zero values are
+// replaced by defaults (see v3ioutils.NewRetryPolicy)
+type RetryConfig struct {
+	// Maximal number of attempts, 1 disables retries
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// Delay (in milliseconds) before the first retry, doubled on every retry
+	Backoff int `json:"backoff,omitempty"`
+	// Maximal delay (in milliseconds) between attempts
+	MaxBackoff int `json:"maxBackoff,omitempty"`
+	// HTTP status codes of failures to retry
+	StatusCodes []int `json:"statusCodes,omitempty"`
 }
 
 // NewSession will create a new session. It will populate missing values from
@@ -95,13 +95,21 @@ func NewFrameFromMap(columns map[string]interface{}, indices map[string]interfac
 	return NewFrame(cols, idx, nil)
 }
 
-// NewFrameFromRows creates a new frame from rows
+// NewFrameFromRows creates a new frame from rows, missing values and nil
+// values are stored as nulls
 func NewFrameFromRows(rows []map[string]interface{}, indices []string, labels map[string]interface{}) (Frame, error) {
 	frameCols := make(map[string]Column)
+	allNulls := make(map[string]bool) // columns with only nil values so far
 	for rowNum, row := range rows {
 		for name, value := range row {
-			col, ok := frameCols[name]
+			if value == nil {
+				if _, ok := frameCols[name]; !ok {
+					allNulls[name] = true
+				}
+				continue
+			}
 
+			col, ok := frameCols[name]
 			if !ok {
 				var err error
 				col, err = newColumn(name, value)
@@ -109,22 +117,39 @@ func NewFrameFromRows(rows []map[string]interface{}, indices []string, labels ma
 					return nil, err
 				}
 				frameCols[name] = col
+				delete(allNulls, name)
+			}
+
+			if err := extendCol(col, rowNum); err != nil {
+				return nil, err
 			}
 
-			extendCol(col, rowNum)
 			if err := colAppend(col, value); err != nil {
 				return nil, err
 			}
 		}
 
 		// Extend columns not in row
-		for name, col := range frameCols {
-			if _, ok := row[name]; !ok {
-				extendCol(col, rowNum+1)
+		for _, col := range frameCols {
+			if err := extendCol(col, rowNum+1); err != nil {
+				return nil, err
 			}
 		}
 	}
 
+	// We can't tell the type of columns with only nil values, use string
+	for name := range allNulls {
+		col, err := NewSliceColumn(name, []string{})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := extendCol(col, len(rows)); err != nil {
+			return nil, err
+		}
+		frameCols[name] = col
+	}
+
 	var dataCols, indexCols []Column
 	for name, col := range frameCols {
 		if inSlice(name, indices) {
@@ -289,6 +314,7 @@ func sliceCols(columns []Column, start int, end int) ([]Column, error) {
 	return slices, nil
 }
 
+// zeroValue is the value stored in place of nulls
 func zeroValue(dtype DType) (interface{}, error) {
 	switch dtype {
 	case IntType:
@@ -306,19 +332,10 @@ func zeroValue(dtype DType) (interface{}, error) {
 	return nil, fmt.Errorf("unsupported data type - %d", dtype)
 }
 
-// TODO: Unite with backend/utils.AppendNil
+// extendCol extends col with nulls up to size
 func extendCol(col Column, size int) error {
-	if col.Len() >= size {
-		return nil
-	}
-
-	value, err := zeroValue(col.DType())
-	if err != nil {
-		return err
-	}
-
 	for col.Len() < size {
-		if err := colAppend(col, value); err != nil {
+		if err := colAppend(col, nil); err != nil {
 			return err
 		}
 	}
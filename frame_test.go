@@ -160,6 +160,15 @@ func TestNewFrameFromRowsMissing(t *testing.T) {
 	if frame.Len() != 2 {
 		t.Fatalf("frame length mismatch: %d != 2", frame.Len())
 	}
+
+	col, err := frame.Column("y")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if col.IsNull(0) || !col.IsNull(1) {
+		t.Fatalf("bad nulls in %q", col.Name())
+	}
 }
 
 func newIntCols(t *testing.T, numCols int, size int) []Column {
@@ -119,6 +119,10 @@ func (c *Client) Write(request *frames.WriteRequest) (frames.FrameAppender, erro
 		InitialData: frame,
 		Expression:  request.Expression,
 		More:        request.HaveMore,
+		SaveMode:    request.SaveMode,
+		Timeout:     request.Timeout,
+		Condition:   request.Condition,
+		WriteMode:   request.WriteMode,
 	}
 
 	req := &pb.WriteRequest{
@@ -161,13 +165,60 @@ func (c *Client) Delete(request *frames.DeleteRequest) error {
 }
 
 // Exec executes a command on the backend
-func (c *Client) Exec(request *frames.ExecRequest) error {
+func (c *Client) Exec(request *frames.ExecRequest) (frames.Frame, error) {
 	if request.Session == nil {
 		request.Session = c.session
 	}
 
-	_, err := c.client.Exec(context.Background(), request)
-	return err
+	resp, err := c.client.Exec(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+
+	var frame frames.Frame
+	if resp.Frame != nil {
+		frame = frames.NewFrameFromProto(resp.Frame)
+	}
+
+	return frame, nil
+}
+
+// List lists tables
+func (c *Client) List(request *frames.ListRequest) ([]string, error) {
+	if request.Session == nil {
+		request.Session = c.session
+	}
+
+	resp, err := c.client.List(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Tables, nil
+}
+
+// Describe returns table schema and attributes
+func (c *Client) Describe(request *frames.DescribeRequest) (*frames.TableInfo, error) {
+	if request.Session == nil {
+		request.Session = c.session
+	}
+
+	resp, err := c.client.Describe(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Table, nil
+}
+
+// Backends returns the server backends and their capabilities
+func (c *Client) Backends() ([]*frames.BackendInfo, error) {
+	resp, err := c.client.Backends(context.Background(), &pb.BackendsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Backends, nil
 }
 
 type frameIterator struct {
@@ -206,6 +257,7 @@ func (it *frameIterator) At() frames.Frame {
 type frameAppender struct {
 	stream pb.Frames_WriteClient
 	closed bool
+	report *frames.WriteReport
 }
 
 func (fa *frameAppender) Add(frame frames.Frame) error {
@@ -240,6 +292,16 @@ func (fa *frameAppender) WaitForComplete(timeout time.Duration) error {
 	}
 
 	// TODO: timeout
-	_, err := fa.stream.CloseAndRecv()
-	return err
+	resp, err := fa.stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	fa.report = resp.Report
+	return nil
+}
+
+// Report returns the write report, nil if the backend doesn't report rows
+func (fa *frameAppender) Report() *frames.WriteReport {
+	return fa.report
 }
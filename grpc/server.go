@@ -105,12 +105,16 @@ func (s *Server) Start() error {
 }
 
 func (s *Server) Read(request *pb.ReadRequest, stream pb.Frames_ReadServer) error {
+	// Cancel the API read if we return early (e.g. on send error)
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
 	ch := make(chan frames.Frame)
 
 	var apiError error
 	go func() {
 		defer close(ch)
-		apiError = s.api.Read(request, ch)
+		apiError = s.api.Read(ctx, request, ch)
 		if apiError != nil {
 			s.logger.ErrorWith("API error reading", "error", apiError)
 		}
@@ -153,24 +157,34 @@ func (s *Server) Write(stream pb.Frames_WriteServer) error {
 		Backend:       pbReq.Backend,
 		Expression:    pbReq.Expression,
 		HaveMore:      pbReq.More,
+		SaveMode:      pbReq.SaveMode,
 		ImmidiateData: frame,
 		Table:         pbReq.Table,
+		Timeout:       pbReq.Timeout,
+		Condition:     pbReq.Condition,
+		WriteMode:     pbReq.WriteMode,
 	}
 
+	// Cancel the API write if we return early (e.g. on stream error)
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
 	// TODO: Unite with the code in HTTP server
 	var (
 		writeError     error
 		nFrames, nRows int
+		report         *frames.WriteReport
 		ch             = make(chan frames.Frame, 1)
 		done           = make(chan bool)
 	)
 
 	go func() {
 		defer close(done)
-		nFrames, nRows, writeError = s.api.Write(req, ch)
+		nFrames, nRows, report, writeError = s.api.Write(ctx, req, ch)
 	}()
 
-	for writeError == nil {
+loop:
+	for {
 		msg, err := stream.Recv()
 		if err != nil {
 			if err != io.EOF {
@@ -187,7 +201,11 @@ func (s *Server) Write(stream pb.Frames_WriteServer) error {
 		}
 
 		frame := frames.NewFrameFromProto(frameMessage)
-		ch <- frame
+		select {
+		case ch <- frame:
+		case <-done: // API write failed
+			break loop
+		}
 	}
 
 	close(ch)
@@ -202,6 +220,7 @@ func (s *Server) Write(stream pb.Frames_WriteServer) error {
 	resp := &pb.WriteRespose{
 		Frames: int64(nFrames),
 		Rows:   int64(nRows),
+		Report: report,
 	}
 
 	return stream.SendAndClose(resp)
@@ -209,8 +228,7 @@ func (s *Server) Write(stream pb.Frames_WriteServer) error {
 
 // Create creates a table
 func (s *Server) Create(ctx context.Context, req *pb.CreateRequest) (*pb.CreateResponse, error) {
-	// TODO: Use ctx for timeout
-	if err := s.api.Create(req); err != nil {
+	if err := s.api.Create(ctx, req); err != nil {
 		return nil, err
 	}
 
@@ -219,18 +237,54 @@ func (s *Server) Create(ctx context.Context, req *pb.CreateRequest) (*pb.CreateR
 
 // Delete deletes a table
 func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
-	if err := s.api.Delete(req); err != nil {
+	if err := s.api.Delete(ctx, req); err != nil {
 		return nil, err
 	}
 
 	return &pb.DeleteResponse{}, nil
 }
 
+// List lists tables
+func (s *Server) List(ctx context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
+	tables, err := s.api.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListResponse{Tables: tables}, nil
+}
+
+// Describe returns table schema and attributes
+func (s *Server) Describe(ctx context.Context, req *pb.DescribeRequest) (*pb.DescribeResponse, error) {
+	info, err := s.api.Describe(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DescribeResponse{Table: info}, nil
+}
+
+// Backends returns the backends and their capabilities
+func (s *Server) Backends(ctx context.Context, req *pb.BackendsRequest) (*pb.BackendsResponse, error) {
+	return &pb.BackendsResponse{Backends: s.api.Backends()}, nil
+}
+
 // Exec executes a command
 func (s *Server) Exec(ctx context.Context, req *pb.ExecRequest) (*pb.ExecResponse, error) {
-	if err := s.api.Exec(req); err != nil {
+	frame, err := s.api.Exec(ctx, req)
+	if err != nil {
 		return nil, err
 	}
 
-	return &pb.ExecResponse{}, nil
+	resp := &pb.ExecResponse{}
+	if frame != nil {
+		fpb, ok := frame.(pb.Framed)
+		if !ok {
+			s.logger.Error("unknown frame type")
+			return nil, errors.New("unknown frame type")
+		}
+		resp.Frame = fpb.Proto()
+	}
+
+	return resp, nil
 }
@@ -30,10 +30,12 @@ import (
 	"os"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/nuclio/logger"
 	"github.com/pkg/errors"
 
 	"github.com/v3io/frames"
+	"github.com/v3io/frames/arrow"
 	"github.com/v3io/frames/pb"
 )
 
@@ -122,6 +124,15 @@ func (c *Client) Read(request *frames.ReadRequest) (frames.FrameIterator, error)
 		return nil, fmt.Errorf("API returned with bad code - %d\n%s", resp.StatusCode, buf.String())
 	}
 
+	if request.DataFormat == frames.ArrowFormat {
+		it := &arrowFrameIterator{
+			Reader: arrow.NewReader(resp.Body),
+			body:   resp.Body,
+			logger: c.logger,
+		}
+		return it, nil
+	}
+
 	it := &streamFrameIterator{
 		reader:  resp.Body,
 		decoder: frames.NewDecoder(resp.Body),
@@ -165,6 +176,10 @@ func (c *Client) Write(request *frames.WriteRequest) (frames.FrameAppender, erro
 		logger:  c.logger,
 	}
 
+	if request.DataFormat == frames.ArrowFormat {
+		appender.arrowWriter = arrow.NewWriter(writer)
+	}
+
 	// Call API in a goroutine since it's going to block reading from pipe
 	go func() {
 		resp, err := http.DefaultClient.Do(req)
@@ -184,7 +199,7 @@ func (c *Client) Delete(request *frames.DeleteRequest) error {
 		request.Session = c.session
 	}
 
-	return c.jsonCall("/delete", request)
+	return c.jsonCall("/delete", request, nil)
 }
 
 // Create creates a table
@@ -193,19 +208,85 @@ func (c *Client) Create(request *frames.CreateRequest) error {
 		request.Session = c.session
 	}
 
-	return c.jsonCall("/create", request)
+	return c.jsonCall("/create", request, nil)
 }
 
 // Exec executes a command
-func (c *Client) Exec(request *frames.ExecRequest) error {
+func (c *Client) Exec(request *frames.ExecRequest) (frames.Frame, error) {
 	if request.Session == nil {
 		request.Session = c.session
 	}
 
-	return c.jsonCall("/exec", request)
+	var reply struct {
+		Frame []byte `json:"frame"` // Protobuf encoded
+	}
+	if err := c.jsonCall("/exec", request, &reply); err != nil {
+		return nil, err
+	}
+
+	if reply.Frame == nil {
+		return nil, nil
+	}
+
+	msg := &pb.Frame{}
+	if err := proto.Unmarshal(reply.Frame, msg); err != nil {
+		return nil, errors.Wrap(err, "can't decode frame")
+	}
+
+	return frames.NewFrameFromProto(msg), nil
 }
 
-func (c *Client) jsonCall(path string, request interface{}) error {
+// List lists tables
+func (c *Client) List(request *frames.ListRequest) ([]string, error) {
+	if request.Session == nil {
+		request.Session = c.session
+	}
+
+	reply := &pb.ListResponse{}
+	if err := c.jsonCall("/list", request, reply); err != nil {
+		return nil, err
+	}
+
+	return reply.Tables, nil
+}
+
+// Describe returns table schema and attributes
+func (c *Client) Describe(request *frames.DescribeRequest) (*frames.TableInfo, error) {
+	if request.Session == nil {
+		request.Session = c.session
+	}
+
+	info := &frames.TableInfo{}
+	if err := c.jsonCall("/describe", request, info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// Backends returns the server backends and their capabilities
+func (c *Client) Backends() ([]*frames.BackendInfo, error) {
+	resp, err := http.Get(c.URL + "/_/backends")
+	if err != nil {
+		return nil, errors.Wrap(err, "can't call server")
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error calling server - %q", resp.Status)
+	}
+
+	var infos []*frames.BackendInfo
+	if err := json.NewDecoder(resp.Body).Decode(&infos); err != nil {
+		return nil, errors.Wrap(err, "can't decode reply")
+	}
+
+	return infos, nil
+}
+
+// jsonCall POSTs request as JSON to path, and decodes the JSON reply if reply
+// is not nil
+func (c *Client) jsonCall(path string, request interface{}, reply interface{}) error {
 	var buf bytes.Buffer
 
 	if err := json.NewEncoder(&buf).Encode(request); err != nil {
@@ -224,10 +305,19 @@ func (c *Client) jsonCall(path string, request interface{}) error {
 		return errors.Wrap(err, "can't call server")
 	}
 
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("error calling server - %q", resp.Status)
 	}
 
+	if reply == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(reply); err != nil {
+		return errors.Wrap(err, "can't decode reply")
+	}
+
 	return nil
 }
 
@@ -273,6 +363,25 @@ func (it *streamFrameIterator) Err() error {
 	return it.err
 }
 
+// arrowFrameIterator implements FrameIterator over Arrow IPC response body
+type arrowFrameIterator struct {
+	*arrow.Reader
+	body   io.Closer
+	logger logger.Logger
+}
+
+func (it *arrowFrameIterator) Next() bool {
+	if it.Reader.Next() {
+		return true
+	}
+
+	if err := it.body.Close(); err != nil {
+		it.logger.WarnWith("can't close reader", "error", err)
+	}
+
+	return false
+}
+
 type appenderHTTPResponse struct {
 	resp *http.Response
 	err  error
@@ -280,13 +389,22 @@ type appenderHTTPResponse struct {
 
 // streamFrameAppender implements FrameAppender over io.Writer
 type streamFrameAppender struct {
-	writer  io.Writer
-	encoder *frames.Encoder
-	ch      chan *appenderHTTPResponse
-	logger  logger.Logger
+	writer      io.Writer
+	encoder     *frames.Encoder
+	arrowWriter *arrow.Writer // Used instead of encoder if not nil
+	ch          chan *appenderHTTPResponse
+	logger      logger.Logger
+	report      *frames.WriteReport
 }
 
 func (a *streamFrameAppender) Add(frame frames.Frame) error {
+	if a.arrowWriter != nil {
+		if err := a.arrowWriter.Write(frame); err != nil {
+			return errors.Wrap(err, "can't encode frame")
+		}
+		return nil
+	}
+
 	iface, ok := frame.(pb.Framed)
 	if !ok {
 		return errors.New("unknown frame type")
@@ -305,6 +423,12 @@ func (a *streamFrameAppender) WaitForComplete(timeout time.Duration) error {
 		return fmt.Errorf("writer is not a closer")
 	}
 
+	if a.arrowWriter != nil {
+		if err := a.arrowWriter.Close(); err != nil {
+			return errors.Wrap(err, "can't close Arrow stream")
+		}
+	}
+
 	if err := closer.Close(); err != nil {
 		return errors.Wrap(err, "can't close writer")
 	}
@@ -318,13 +442,29 @@ func (a *streamFrameAppender) WaitForComplete(timeout time.Duration) error {
 			return fmt.Errorf("server returned error - %d\n%s", hr.resp.StatusCode, buf.String())
 		}
 
-		hr.resp.Body.Close()
-		return hr.err
+		defer hr.resp.Body.Close()
+		if hr.err != nil {
+			return hr.err
+		}
+
+		var reply struct {
+			Report *frames.WriteReport `json:"report"`
+		}
+		if err := json.NewDecoder(hr.resp.Body).Decode(&reply); err != nil {
+			return errors.Wrap(err, "bad write reply")
+		}
+		a.report = reply.Report
+		return nil
 	case <-time.After(timeout):
 		return fmt.Errorf("timeout after %s", timeout)
 	}
 }
 
+// Report returns the write report, nil if the backend doesn't report rows
+func (a *streamFrameAppender) Report() *frames.WriteReport {
+	return a.report
+}
+
 func pbWriteReq(req *frames.WriteRequest) (*pb.InitialWriteRequest, error) {
 	var frMsg *pb.Frame
 	if req.ImmidiateData != nil {
@@ -342,6 +482,11 @@ func pbWriteReq(req *frames.WriteRequest) (*pb.InitialWriteRequest, error) {
 		InitialData: frMsg,
 		Expression:  req.Expression,
 		More:        req.HaveMore,
+		DataFormat:  req.DataFormat,
+		SaveMode:    req.SaveMode,
+		Timeout:     req.Timeout,
+		Condition:   req.Condition,
+		WriteMode:   req.WriteMode,
 	}
 
 	return msg, nil
@@ -132,6 +132,16 @@ func TestEnd2End(t *testing.T) {
 
 	testGrafana(t, url, backendName, tableName)
 
+	testNulls(t, client, backendName)
+
+	testQuery(t, client, backendName, tableName)
+
+	testBackends(t, client, backendName)
+
+	testListDescribe(t, client, backendName, tableName)
+
+	testArrow(t, client, backendName)
+
 	// Exec
 	execReq := &frames.ExecRequest{
 		Backend: backendName,
@@ -139,7 +149,7 @@ func TestEnd2End(t *testing.T) {
 		Command: "ping",
 	}
 
-	if err := client.Exec(execReq); err != nil {
+	if _, err := client.Exec(execReq); err != nil {
 		t.Fatalf("can't exec - %s", err)
 	}
 }
@@ -166,6 +176,130 @@ func testGrafana(t *testing.T, baseURL string, backend string, table string) {
 	}
 }
 
+func testNulls(t *testing.T, client frames.Client, backend string) {
+	size := 10
+	data := make([]float64, size)
+	nulls := make([]bool, size)
+	for i := 0; i < size; i++ {
+		data[i] = float64(i)
+		nulls[i] = i%3 == 0
+	}
+
+	col, err := frames.NewNullableSliceColumn("vals", data, nulls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame, err := frames.NewFrame([]frames.Column{col}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := "e2e-nulls"
+	appender, err := client.Write(&frames.WriteRequest{Backend: backend, Table: table})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Add(frame); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.WaitForComplete(10 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	it, err := client.Read(&frames.ReadRequest{Backend: backend, Table: table})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !it.Next() {
+		t.Fatalf("no frames - %v", it.Err())
+	}
+
+	rcol, err := it.At().Column("vals")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, isNull := range nulls {
+		if rcol.IsNull(i) != isNull {
+			t.Fatalf("%d: null mismatch - %v != %v", i, rcol.IsNull(i), isNull)
+		}
+	}
+}
+
+func testArrow(t *testing.T, client frames.Client, backend string) {
+	frame, err := makeFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := "e2e-arrow"
+	writeReq := &frames.WriteRequest{
+		Backend:    backend,
+		Table:      table,
+		DataFormat: frames.ArrowFormat,
+	}
+
+	appender, err := client.Write(writeReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.Add(frame); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := appender.WaitForComplete(10 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	readReq := &frames.ReadRequest{
+		Backend:      backend,
+		Table:        table,
+		MessageLimit: 100,
+		DataFormat:   frames.ArrowFormat,
+	}
+
+	it, err := client.Read(readReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nRows := 0
+	for it.Next() {
+		iFrame := it.At()
+		if !reflect.DeepEqual(iFrame.Names(), frame.Names()) {
+			t.Fatalf("columns mismatch: %v != %v", iFrame.Names(), frame.Names())
+		}
+		nRows += iFrame.Len()
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if nRows != frame.Len() {
+		t.Fatalf("# of rows mismatch - %d != %d", nRows, frame.Len())
+	}
+
+	// Errors are sent in the stream
+	readReq.Table = "no-such-table"
+	it, err = client.Read(readReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for it.Next() {
+	}
+
+	if it.Err() == nil {
+		t.Fatal("no error reading missing table")
+	}
+}
+
 func freePort() (int, error) {
 	l, err := net.Listen("tcp", ":0")
 	if err != nil {
@@ -202,3 +336,118 @@ func makeFrame() (frames.Frame, error) {
 	}
 	return frames.NewFrameFromMap(columns, nil)
 }
+
+func testQuery(t *testing.T, client frames.Client, backend string, table string) {
+	query := fmt.Sprintf(`
+	SELECT ints, floats * 2 AS f2
+	FROM %s
+	WHERE ints > 10 AND strings LIKE 'val1%%'
+	ORDER BY ints DESC
+	LIMIT 3`, table)
+
+	it, err := client.Read(&frames.ReadRequest{Backend: backend, Query: query})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ints []int64
+	for it.Next() {
+		frame := it.At()
+		if names := frame.Names(); !reflect.DeepEqual(names, []string{"ints", "f2"}) {
+			t.Fatalf("query: bad columns - %v", names)
+		}
+
+		col, err := frame.Column("ints")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		values, err := col.Ints()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ints = append(ints, values...)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []int64{1026, 1025, 1024}; !reflect.DeepEqual(ints, expected) {
+		t.Fatalf("query: bad result - %v != %v", ints, expected)
+	}
+}
+
+func testBackends(t *testing.T, client frames.Client, backend string) {
+	infos, err := client.Backends()
+	if err != nil {
+		t.Fatalf("can't get backends - %s", err)
+	}
+
+	if len(infos) != 1 {
+		t.Fatalf("wrong number of backends - %d != 1", len(infos))
+	}
+
+	info := infos[0]
+	if info.Name != backend || info.Type != "csv" {
+		t.Fatalf("bad backend info - %+v", info)
+	}
+
+	caps := info.Capabilities
+	if caps == nil {
+		t.Fatalf("no capabilities")
+	}
+
+	if !reflect.DeepEqual(caps.Operations, []string{"read", "write", "create", "delete", "exec", "list", "describe"}) {
+		t.Fatalf("bad operations - %v", caps.Operations)
+	}
+
+	if len(caps.ExecCommands) != 1 || caps.ExecCommands[0].Name != "ping" {
+		t.Fatalf("bad exec commands - %v", caps.ExecCommands)
+	}
+
+	if len(caps.Dtypes) != 5 {
+		t.Fatalf("bad dtypes - %v", caps.Dtypes)
+	}
+}
+
+func testListDescribe(t *testing.T, client frames.Client, backend string, table string) {
+	tables, err := client.List(&frames.ListRequest{Backend: backend})
+	if err != nil {
+		t.Fatalf("can't list - %s", err)
+	}
+
+	found := false
+	for _, name := range tables {
+		if name == table {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("%q not in %v", table, tables)
+	}
+
+	info, err := client.Describe(&frames.DescribeRequest{Backend: backend, Table: table})
+	if err != nil {
+		t.Fatalf("can't describe - %s", err)
+	}
+
+	types := map[string]string{}
+	for _, field := range info.Schema.Fields {
+		types[field.Name] = field.Type
+	}
+
+	// CSV types are saved in the table schema file on write
+	expected := map[string]string{
+		"ints":    "integer",
+		"floats":  "float",
+		"strings": "string",
+		"times":   "time",
+		"bools":   "boolean",
+	}
+
+	if !reflect.DeepEqual(types, expected) {
+		t.Fatalf("bad schema - %v != %v", types, expected)
+	}
+}
@@ -21,6 +21,7 @@ such restriction.
 package http
 
 import (
+	"reflect"
 	"time"
 
 	"github.com/pkg/errors"
@@ -95,9 +96,27 @@ func columnToJSON(col frames.Column) (*JSONColumn, error) {
 		return nil, err
 	}
 
+	if col.NullCount() > 0 {
+		data = withNulls(col, data)
+	}
+
 	jcol := &JSONColumn{
 		Name: col.Name(),
 		Data: data,
 	}
 	return jcol, nil
 }
+
+// withNulls returns data as []interface{} with nil in place of null values
+func withNulls(col frames.Column, data interface{}) []interface{} {
+	val := reflect.ValueOf(data)
+	out := make([]interface{}, val.Len())
+	for i := range out {
+		if col.IsNull(i) {
+			continue
+		}
+		out[i] = val.Index(i).Interface()
+	}
+
+	return out
+}
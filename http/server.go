@@ -22,6 +22,7 @@ package http
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,8 +30,10 @@ import (
 
 	"github.com/v3io/frames"
 	"github.com/v3io/frames/api"
+	"github.com/v3io/frames/arrow"
 	"github.com/v3io/frames/pb"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/nuclio/logger"
 	"github.com/pkg/errors"
 	"github.com/valyala/fasthttp"
@@ -150,47 +153,118 @@ func (s *Server) handleRead(ctx *fasthttp.RequestCtx) {
 	// TODO: Validate request
 	s.logger.InfoWith("read request", "request", request)
 
+	switch request.DataFormat {
+	case "", frames.ProtobufFormat, frames.ArrowFormat:
+	default:
+		ctx.Error(fmt.Sprintf("unknown data format - %q", request.DataFormat), http.StatusBadRequest)
+		return
+	}
+
+	// Canceled when the client goes away (write error) or we're done
+	apiCtx, cancel := s.requestContext()
+
 	ch := make(chan frames.Frame)
 	var apiError error
 	go func() {
 		defer close(ch)
-		apiError = s.api.Read(request, ch)
+		apiError = s.api.Read(apiCtx, request, ch)
 		if apiError != nil {
 			s.logger.ErrorWith("error reading", "error", apiError)
 		}
 	}()
 
+	if request.DataFormat == frames.ArrowFormat {
+		ctx.Response.Header.SetContentType(arrow.ContentType)
+	}
+
 	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
-		enc := frames.NewEncoder(w)
-		for frame := range ch {
-			iface, ok := frame.(pb.Framed)
-			if !ok {
-				s.logger.Error("unknown frame type")
-				s.writeError(enc, fmt.Errorf("unknown frame type"))
-			}
+		defer cancel()
+
+		var fw frameWriter
+		if request.DataFormat == frames.ArrowFormat {
+			fw = arrow.NewWriter(w)
+		} else {
+			fw = &pbFrameWriter{frames.NewEncoder(w)}
+		}
 
-			if err := enc.Encode(iface.Proto()); err != nil {
+		for frame := range ch {
+			if err := fw.Write(frame); err != nil {
 				s.logger.ErrorWith("can't encode result", "error", err)
-				s.writeError(enc, err)
+				fw.WriteError(err)
 			}
 
 			if err := w.Flush(); err != nil {
+				// Client is gone, stop reading (ch is drained until closed)
 				s.logger.ErrorWith("can't flush", "error", err)
-				s.writeError(enc, err)
+				cancel()
 			}
 		}
 
 		if apiError != nil {
-			s.writeError(enc, apiError)
+			fw.WriteError(apiError)
 		}
+		fw.Close()
 	})
 }
 
-func (s *Server) writeError(enc *frames.Encoder, err error) {
+// frameWriter writes frames in read responses
+type frameWriter interface {
+	Write(frame frames.Frame) error
+	WriteError(err error) error
+	Close() error
+}
+
+// pbFrameWriter writes length prefixed protobuf frames
+type pbFrameWriter struct {
+	enc *frames.Encoder
+}
+
+func (w *pbFrameWriter) Write(frame frames.Frame) error {
+	iface, ok := frame.(pb.Framed)
+	if !ok {
+		return fmt.Errorf("unknown frame type")
+	}
+
+	return w.enc.Encode(iface.Proto())
+}
+
+func (w *pbFrameWriter) WriteError(err error) error {
 	msg := &pb.Frame{
 		Error: err.Error(),
 	}
-	enc.Encode(msg)
+	return w.enc.Encode(msg)
+}
+
+func (w *pbFrameWriter) Close() error {
+	return nil
+}
+
+// pbFrameIterator iterates over length prefixed protobuf frames
+type pbFrameIterator struct {
+	dec   *frames.Decoder
+	frame frames.Frame
+	err   error
+}
+
+func (it *pbFrameIterator) Next() bool {
+	msg := &pb.Frame{}
+	if err := it.dec.Decode(msg); err != nil {
+		if err != io.EOF {
+			it.err = err
+		}
+		return false
+	}
+
+	it.frame = frames.NewFrameFromProto(msg)
+	return true
+}
+
+func (it *pbFrameIterator) At() frames.Frame {
+	return it.frame
+}
+
+func (it *pbFrameIterator) Err() error {
+	return it.err
 }
 
 func (s *Server) handleWrite(ctx *fasthttp.RequestCtx) {
@@ -199,6 +273,7 @@ func (s *Server) handleWrite(ctx *fasthttp.RequestCtx) {
 	}
 
 	reader, writer := io.Pipe()
+	defer reader.Close() // Unblock body writer if we return early
 	go func() {
 		ctx.Request.BodyWriteTo(writer)
 		writer.Close()
@@ -226,35 +301,67 @@ func (s *Server) handleWrite(ctx *fasthttp.RequestCtx) {
 		ImmidiateData: frame,
 		Expression:    req.Expression,
 		HaveMore:      req.More,
+		DataFormat:    req.DataFormat,
+		SaveMode:      req.SaveMode,
+		Timeout:       req.Timeout,
+		Condition:     req.Condition,
+		WriteMode:     req.WriteMode,
+	}
+
+	var it frames.FrameIterator
+	switch req.DataFormat {
+	case "", frames.ProtobufFormat:
+		it = &pbFrameIterator{dec: dec}
+	case frames.ArrowFormat:
+		it = arrow.NewReader(reader)
+	default:
+		ctx.Error(fmt.Sprintf("unknown data format - %q", req.DataFormat), http.StatusBadRequest)
+		return
 	}
 
 	var nFrames, nRows int
-	var writeError error
+	var report *frames.WriteReport
+	var writeError, decodeError error
+
+	apiCtx, cancel := s.requestContext()
+	defer cancel()
 
 	ch := make(chan frames.Frame, 1)
 	done := make(chan bool)
 	go func() {
 		defer close(done)
-		nFrames, nRows, writeError = s.api.Write(request, ch)
+		nFrames, nRows, report, writeError = s.api.Write(apiCtx, request, ch)
 	}()
 
-	for writeError == nil {
-		msg := &pb.Frame{}
-		err := dec.Decode(msg)
-		if err != nil {
-			if err != io.EOF {
-				s.logger.ErrorWith("decode error", "error", err)
-				ctx.Error("decode error", http.StatusInternalServerError)
-			}
+loop:
+	for it.Next() {
+		frame := it.At()
+		if frame == nil { // Conversion error
 			break
 		}
 
-		ch <- frames.NewFrameFromProto(msg)
+		select {
+		case ch <- frame:
+		case <-done: // API write failed
+			break loop
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		// Broken request, don't write partial data
+		s.logger.ErrorWith("decode error", "error", err)
+		decodeError = err
+		cancel()
 	}
 
 	close(ch)
 	<-done
 
+	if decodeError != nil {
+		ctx.Error("decode error", http.StatusInternalServerError)
+		return
+	}
+
 	// We can't handle writeError right after .Write since it's done in a goroutine
 	if writeError != nil {
 		s.logger.ErrorWith("write error", "error", writeError)
@@ -266,6 +373,9 @@ func (s *Server) handleWrite(ctx *fasthttp.RequestCtx) {
 		"num_frames": nFrames,
 		"num_rows":   nRows,
 	}
+	if report != nil {
+		reply["report"] = report
+	}
 	s.replyJSON(ctx, reply)
 }
 
@@ -282,7 +392,10 @@ func (s *Server) handleCreate(ctx *fasthttp.RequestCtx) {
 	}
 
 	s.logger.InfoWith("create", "request", request)
-	if err := s.api.Create(request); err != nil {
+	apiCtx, cancel := s.requestContext()
+	defer cancel()
+
+	if err := s.api.Create(apiCtx, request); err != nil {
 		ctx.Error(err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -302,7 +415,10 @@ func (s *Server) handleDelete(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	if err := s.api.Delete(request); err != nil {
+	apiCtx, cancel := s.requestContext()
+	defer cancel()
+
+	if err := s.api.Delete(apiCtx, request); err != nil {
 		ctx.Error("can't delete", http.StatusInternalServerError)
 		return
 	}
@@ -310,6 +426,66 @@ func (s *Server) handleDelete(ctx *fasthttp.RequestCtx) {
 	s.replyOK(ctx)
 }
 
+func (s *Server) handleList(ctx *fasthttp.RequestCtx) {
+	if !ctx.IsPost() { // ctx.PostBody() blocks on GET
+		ctx.Error("unsupported method", http.StatusMethodNotAllowed)
+	}
+
+	request := &frames.ListRequest{}
+	if err := json.Unmarshal(ctx.PostBody(), request); err != nil {
+		s.logger.ErrorWith("can't decode request", "error", err)
+		ctx.Error(fmt.Sprintf("bad request - %s", err), http.StatusBadRequest)
+		return
+	}
+
+	apiCtx, cancel := s.requestContext()
+	defer cancel()
+
+	tables, err := s.api.List(apiCtx, request)
+	if err != nil {
+		ctx.Error(err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.replyJSON(ctx, &pb.ListResponse{Tables: tables})
+}
+
+func (s *Server) handleDescribe(ctx *fasthttp.RequestCtx) {
+	if !ctx.IsPost() { // ctx.PostBody() blocks on GET
+		ctx.Error("unsupported method", http.StatusMethodNotAllowed)
+	}
+
+	request := &frames.DescribeRequest{}
+	if err := json.Unmarshal(ctx.PostBody(), request); err != nil {
+		s.logger.ErrorWith("can't decode request", "error", err)
+		ctx.Error(fmt.Sprintf("bad request - %s", err), http.StatusBadRequest)
+		return
+	}
+
+	apiCtx, cancel := s.requestContext()
+	defer cancel()
+
+	info, err := s.api.Describe(apiCtx, request)
+	if err != nil {
+		ctx.Error(err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.replyJSON(ctx, info)
+}
+
+// requestContext returns the context for API calls made by a handler, the
+// handler cancels it when it's done with the request. fasthttp doesn't notify
+// handlers when the client goes away, handlers that stream replies should also
+// cancel it on write errors (see handleRead)
+func (s *Server) requestContext() (context.Context, context.CancelFunc) {
+	return context.WithCancel(context.Background())
+}
+
+func (s *Server) handleBackends(ctx *fasthttp.RequestCtx) {
+	s.replyJSON(ctx, s.api.Backends())
+}
+
 func (s *Server) handleConfig(ctx *fasthttp.RequestCtx) {
 	s.replyJSON(ctx, s.config)
 }
@@ -346,11 +522,14 @@ func (s *Server) handleGrafana(ctx *fasthttp.RequestCtx) {
 	// TODO: Validate request
 	s.logger.InfoWith("grafana request", "request", request)
 
+	apiCtx, cancel := s.requestContext()
+	defer cancel()
+
 	ch := make(chan frames.Frame)
 	var apiError error
 	go func() {
 		defer close(ch)
-		apiError = s.api.Read(request, ch)
+		apiError = s.api.Read(apiCtx, request, ch)
 		if apiError != nil {
 			s.logger.ErrorWith("error reading (grafana)", "error", apiError)
 		}
@@ -391,23 +570,50 @@ func (s *Server) handleExec(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	if err := s.api.Exec(request); err != nil {
+	apiCtx, cancel := s.requestContext()
+	defer cancel()
+
+	frame, err := s.api.Exec(apiCtx, request)
+	if err != nil {
 		ctx.Error("can't exec", http.StatusInternalServerError)
 		return
 	}
 
-	s.replyOK(ctx)
+	reply := map[string]interface{}{}
+	if frame != nil {
+		fpb, ok := frame.(pb.Framed)
+		if !ok {
+			s.logger.Error("unknown frame type")
+			ctx.Error("unknown frame type", http.StatusInternalServerError)
+			return
+		}
+
+		data, err := proto.Marshal(fpb.Proto())
+		if err != nil {
+			s.logger.ErrorWith("can't encode frame", "error", err)
+			ctx.Error("can't encode frame", http.StatusInternalServerError)
+			return
+		}
+
+		// Frame is base64 encoded protobuf
+		reply["frame"] = data
+	}
+
+	s.replyJSON(ctx, reply)
 }
 
 func (s *Server) initRoutes() {
 	s.routes = map[string]func(*fasthttp.RequestCtx){
-		"/_/config": s.handleConfig,
-		"/_/status": s.handleStatus,
-		"/create":   s.handleCreate,
-		"/delete":   s.handleDelete,
-		"/grafana":  s.handleGrafana,
-		"/read":     s.handleRead,
-		"/write":    s.handleWrite,
-		"/exec":     s.handleExec,
+		"/_/backends": s.handleBackends,
+		"/_/config":   s.handleConfig,
+		"/_/status":   s.handleStatus,
+		"/create":     s.handleCreate,
+		"/delete":     s.handleDelete,
+		"/grafana":    s.handleGrafana,
+		"/read":       s.handleRead,
+		"/write":      s.handleWrite,
+		"/exec":       s.handleExec,
+		"/list":       s.handleList,
+		"/describe":   s.handleDescribe,
 	}
 }
@@ -38,6 +38,7 @@ import (
 	"github.com/v3io/frames"
 	"github.com/v3io/frames/grpc"
 	"github.com/v3io/frames/http"
+	"github.com/v3io/frames/v3ioutils/v3iotest"
 )
 
 const (
@@ -385,6 +386,7 @@ func integrationTest(t *testing.T, client frames.Client, backend string) {
 		t.Fatal(err)
 	}
 
+	kvRows := 0
 	for it.Next() {
 		// TODO: More checks
 		fr := it.At()
@@ -394,10 +396,8 @@ func integrationTest(t *testing.T, client frames.Client, backend string) {
 				t.Fatalf("empty frame")
 			}
 		case strings.Contains(t.Name(), "kv"):
-			// FIXME: kv sometimes return extra "na"
-			if !(fr.Len() == frame.Len() || fr.Len()-1 == frame.Len()) {
-				t.Fatalf("wrong length: %d != %d", fr.Len(), frame.Len())
-			}
+			// kv frames are scan pages
+			kvRows += fr.Len()
 		default:
 			if fr.Len() != frame.Len() {
 				t.Fatalf("wrong length: %d != %d", fr.Len(), frame.Len())
@@ -409,6 +409,11 @@ func integrationTest(t *testing.T, client frames.Client, backend string) {
 		t.Fatal(err)
 	}
 
+	// FIXME: kv sometimes return extra "na"
+	if strings.Contains(t.Name(), "kv") && !(kvRows == frame.Len() || kvRows-1 == frame.Len()) {
+		t.Fatalf("wrong length: %d != %d", kvRows, frame.Len())
+	}
+
 	t.Log("exec")
 	if cfg.exec != nil {
 		ereq := &frames.ExecRequest{
@@ -416,7 +421,7 @@ func integrationTest(t *testing.T, client frames.Client, backend string) {
 			Table:   table,
 		}
 		cfg.exec(ereq)
-		if err := client.Exec(ereq); err != nil {
+		if _, err := client.Exec(ereq); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -443,6 +448,15 @@ type testInfo struct {
 	process  *os.Process
 	root     string
 	session  *frames.Session
+	v3io     *v3iotest.Server // local v3io server when there's no V3IO_SESSION
+}
+
+// close stops the frames server and the local v3io server
+func (info *testInfo) close() {
+	info.process.Kill()
+	if info.v3io != nil {
+		info.v3io.Close()
+	}
 }
 
 func setupTest(t testing.TB) *testInfo {
@@ -450,6 +464,15 @@ func setupTest(t testing.TB) *testInfo {
 	info.root = setupRoot(t)
 	t.Logf("root: %s", info.root)
 	info.session = sessionInfo(t)
+	if info.session == nil {
+		info.v3io = v3iotest.NewServer()
+		info.session = &frames.Session{
+			Url:       info.v3io.Addr,
+			Container: "bigdata",
+			User:      "frames",
+			Password:  "frames",
+		}
+	}
 	t.Logf("session: %+v", info.session)
 	info.config = genConfig(info.root, info.session)
 	t.Logf("config: %+v", info.config)
@@ -468,7 +491,7 @@ func setupTest(t testing.TB) *testInfo {
 
 func TestIntegration(t *testing.T) {
 	info := setupTest(t)
-	defer info.process.Kill()
+	defer info.close()
 
 	logger, err := frames.NewLogger("debug")
 	if err != nil {
@@ -508,6 +531,9 @@ func init() {
 		},
 		"kv": &testConfig{
 			frameFn: kvFrame,
+			exec: func(req *frames.ExecRequest) {
+				req.Command = "infer"
+			},
 		},
 		"stream": &testConfig{
 			frameFn: streamFrame,
@@ -0,0 +1,347 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package ops
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+)
+
+// Concat concatenates frames row wise.
+//
+// The result has the union of the frames columns, in order of first
+// appearance. Missing columns are filled with nulls and int columns are
+// widened to float when concatenated with float columns. Index columns are
+// matched by position and must have the same names. Only labels that have the
+// same value in all frames are kept.
+func Concat(frs ...frames.Frame) (frames.Frame, error) {
+	if len(frs) == 0 {
+		return frames.NewFrame(nil, nil, nil)
+	}
+
+	if len(frs) == 1 {
+		return frs[0], nil
+	}
+
+	sizes := make([]int, len(frs))
+	for i, frame := range frs {
+		sizes[i] = frame.Len()
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, frame := range frs {
+		for _, name := range frame.Names() {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	columns := make([]frames.Column, len(names))
+	for i, name := range names {
+		parts := make([]frames.Column, len(frs))
+		for j, frame := range frs {
+			if col, err := frame.Column(name); err == nil {
+				parts[j] = col
+			}
+		}
+
+		col, err := concatColumn(name, parts, sizes)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't concat")
+		}
+		columns[i] = col
+	}
+
+	indices, err := concatIndices(frs, sizes)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't concat")
+	}
+
+	return frames.NewFrame(columns, indices, commonLabels(frs))
+}
+
+// Collect reads all frames from it and concatenates them to a single frame
+func Collect(it frames.FrameIterator) (frames.Frame, error) {
+	var frs []frames.Frame
+	for it.Next() {
+		frs = append(frs, it.At())
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return Concat(frs...)
+}
+
+func concatIndices(frs []frames.Frame, sizes []int) ([]frames.Column, error) {
+	numIndices := len(frs[0].Indices())
+	for _, frame := range frs[1:] {
+		if n := len(frame.Indices()); n != numIndices {
+			return nil, fmt.Errorf("number of indices mismatch (%d != %d)", n, numIndices)
+		}
+	}
+
+	indices := make([]frames.Column, numIndices)
+	for i := range indices {
+		name := frs[0].Indices()[i].Name()
+		parts := make([]frames.Column, len(frs))
+		for j, frame := range frs {
+			col := frame.Indices()[i]
+			if col.Name() != name {
+				return nil, fmt.Errorf("index %d name mismatch (%q != %q)", i, col.Name(), name)
+			}
+			parts[j] = col
+		}
+
+		col, err := concatColumn(name, parts, sizes)
+		if err != nil {
+			return nil, err
+		}
+		indices[i] = col
+	}
+
+	return indices, nil
+}
+
+func commonLabels(frs []frames.Frame) map[string]interface{} {
+	labels := make(map[string]interface{})
+	for name, value := range frs[0].Labels() {
+		common := true
+		for _, frame := range frs[1:] {
+			other, ok := frame.Labels()[name]
+			if !ok || !reflect.DeepEqual(value, other) {
+				common = false
+				break
+			}
+		}
+
+		if common {
+			labels[name] = value
+		}
+	}
+
+	if len(labels) == 0 {
+		return nil
+	}
+
+	return labels
+}
+
+// concatDType returns the dtype of the concatenated parts, nil parts are
+// missing
+func concatDType(parts []frames.Column) (frames.DType, error) {
+	var dtype frames.DType
+	found := false
+	for _, col := range parts {
+		if col == nil {
+			continue
+		}
+
+		if !found {
+			dtype, found = col.DType(), true
+			continue
+		}
+
+		switch {
+		case col.DType() == dtype:
+		case isNumeric(col.DType()) && isNumeric(dtype):
+			dtype = frames.FloatType
+		default:
+			return 0, fmt.Errorf("%q - dtype mismatch (%d != %d)", col.Name(), col.DType(), dtype)
+		}
+	}
+
+	if !found {
+		return 0, fmt.Errorf("no columns")
+	}
+
+	return dtype, nil
+}
+
+func isNumeric(dtype frames.DType) bool {
+	return dtype == frames.IntType || dtype == frames.FloatType
+}
+
+func concatColumn(name string, parts []frames.Column, sizes []int) (frames.Column, error) {
+	dtype, err := concatDType(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, size := range sizes {
+		total += size
+	}
+
+	if col, ok := concatLabels(name, parts, total); ok {
+		return col, nil
+	}
+
+	nulls := make([]bool, 0, total)
+	hasNulls := false
+	for i, col := range parts {
+		for j := 0; j < sizes[i]; j++ {
+			isNull := col == nil || col.IsNull(j)
+			hasNulls = hasNulls || isNull
+			nulls = append(nulls, isNull)
+		}
+	}
+
+	if !hasNulls {
+		nulls = nil
+	}
+
+	var data interface{}
+	switch dtype {
+	case frames.IntType:
+		out := make([]int64, 0, total)
+		for i, col := range parts {
+			if col == nil {
+				out = append(out, make([]int64, sizes[i])...)
+				continue
+			}
+
+			values, err := col.Ints()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, values...)
+		}
+		data = out
+	case frames.FloatType:
+		out := make([]float64, 0, total)
+		for i, col := range parts {
+			switch {
+			case col == nil:
+				for j := 0; j < sizes[i]; j++ {
+					out = append(out, math.NaN())
+				}
+			case col.DType() == frames.IntType:
+				values, err := col.Ints()
+				if err != nil {
+					return nil, err
+				}
+
+				for _, value := range values {
+					out = append(out, float64(value))
+				}
+			default:
+				values, err := col.Floats()
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, values...)
+			}
+		}
+		data = out
+	case frames.StringType:
+		out := make([]string, 0, total)
+		for i, col := range parts {
+			if col == nil {
+				out = append(out, make([]string, sizes[i])...)
+				continue
+			}
+			out = append(out, col.Strings()...)
+		}
+		data = out
+	case frames.TimeType:
+		out := make([]time.Time, 0, total)
+		for i, col := range parts {
+			if col == nil {
+				for j := 0; j < sizes[i]; j++ {
+					out = append(out, time.Unix(0, 0))
+				}
+				continue
+			}
+
+			values, err := col.Times()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, values...)
+		}
+		data = out
+	case frames.BoolType:
+		out := make([]bool, 0, total)
+		for i, col := range parts {
+			if col == nil {
+				out = append(out, make([]bool, sizes[i])...)
+				continue
+			}
+
+			// Bools() of label columns is not expanded
+			for j := 0; j < sizes[i]; j++ {
+				value, err := col.BoolAt(j)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, value)
+			}
+		}
+		data = out
+	default:
+		return nil, fmt.Errorf("%q - unknown dtype - %d", name, dtype)
+	}
+
+	return frames.NewNullableSliceColumn(name, data, nulls)
+}
+
+// concatLabels returns a label column if all parts are label columns with
+// the same value
+func concatLabels(name string, parts []frames.Column, total int) (frames.Column, bool) {
+	var value interface{}
+	for i, col := range parts {
+		if col == nil || !frames.IsLabelColumn(col) || col.Len() == 0 {
+			return nil, false
+		}
+
+		v, err := ValueAt(col, 0)
+		if err != nil || v == nil {
+			return nil, false
+		}
+
+		if i == 0 {
+			value = v
+			continue
+		}
+
+		if col.DType() != parts[0].DType() || !reflect.DeepEqual(v, value) {
+			return nil, false
+		}
+	}
+
+	col, err := frames.NewLabelColumn(name, value, total)
+	if err != nil {
+		return nil, false
+	}
+
+	return col, true
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package ops
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+)
+
+// RowPredicate reports whether row i should be kept
+type RowPredicate func(i int) bool
+
+// Predicate binds a row predicate to a frame
+type Predicate func(frame frames.Frame) (RowPredicate, error)
+
+// Filter returns a new frame with the rows of frame that match pred
+func Filter(frame frames.Frame, pred Predicate) (frames.Frame, error) {
+	keep, err := pred(frame)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't filter")
+	}
+
+	var indices []int
+	for i := 0; i < frame.Len(); i++ {
+		if keep(i) {
+			indices = append(indices, i)
+		}
+	}
+
+	if len(indices) == frame.Len() {
+		return frame, nil
+	}
+
+	return Take(frame, indices)
+}
+
+// IntPredicate matches rows where fn returns true for the value of an int
+// column. Null values never match
+func IntPredicate(name string, fn func(value int64) bool) Predicate {
+	return typedPredicate(name, frames.IntType, func(col frames.Column) (RowPredicate, error) {
+		values, err := col.Ints()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(i int) bool { return fn(values[i]) }, nil
+	})
+}
+
+// FloatPredicate matches rows where fn returns true for the value of a float
+// column. Null values never match
+func FloatPredicate(name string, fn func(value float64) bool) Predicate {
+	return typedPredicate(name, frames.FloatType, func(col frames.Column) (RowPredicate, error) {
+		values, err := col.Floats()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(i int) bool { return fn(values[i]) }, nil
+	})
+}
+
+// StringPredicate matches rows where fn returns true for the value of a string
+// column. Null values never match
+func StringPredicate(name string, fn func(value string) bool) Predicate {
+	return typedPredicate(name, frames.StringType, func(col frames.Column) (RowPredicate, error) {
+		values := col.Strings()
+		return func(i int) bool { return fn(values[i]) }, nil
+	})
+}
+
+// TimePredicate matches rows where fn returns true for the value of a time
+// column. Null values never match
+func TimePredicate(name string, fn func(value time.Time) bool) Predicate {
+	return typedPredicate(name, frames.TimeType, func(col frames.Column) (RowPredicate, error) {
+		values, err := col.Times()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(i int) bool { return fn(values[i]) }, nil
+	})
+}
+
+// BoolPredicate matches rows where fn returns true for the value of a bool
+// column. Null values never match
+func BoolPredicate(name string, fn func(value bool) bool) Predicate {
+	return typedPredicate(name, frames.BoolType, func(col frames.Column) (RowPredicate, error) {
+		values, err := col.Bools()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(i int) bool { return fn(values[i]) }, nil
+	})
+}
+
+// IsNull matches rows where the value of column is null
+func IsNull(name string) Predicate {
+	return func(frame frames.Frame) (RowPredicate, error) {
+		col, err := LookupColumn(frame, name)
+		if err != nil {
+			return nil, err
+		}
+
+		return col.IsNull, nil
+	}
+}
+
+// NotNull matches rows where the value of column is not null
+func NotNull(name string) Predicate {
+	return Not(IsNull(name))
+}
+
+// And matches rows that match all predicates
+func And(preds ...Predicate) Predicate {
+	return func(frame frames.Frame) (RowPredicate, error) {
+		rowPreds, err := bindAll(frame, preds)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(i int) bool {
+			for _, pred := range rowPreds {
+				if !pred(i) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	}
+}
+
+// Or matches rows that match at least one of the predicates
+func Or(preds ...Predicate) Predicate {
+	return func(frame frames.Frame) (RowPredicate, error) {
+		rowPreds, err := bindAll(frame, preds)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(i int) bool {
+			for _, pred := range rowPreds {
+				if pred(i) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+}
+
+// Not matches rows that don't match pred
+func Not(pred Predicate) Predicate {
+	return func(frame frames.Frame) (RowPredicate, error) {
+		rowPred, err := pred(frame)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(i int) bool { return !rowPred(i) }, nil
+	}
+}
+
+func bindAll(frame frames.Frame, preds []Predicate) ([]RowPredicate, error) {
+	rowPreds := make([]RowPredicate, len(preds))
+	for i, pred := range preds {
+		var err error
+		rowPreds[i], err = pred(frame)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rowPreds, nil
+}
+
+// typedPredicate looks up the column and checks its dtype before calling bind.
+// Nulls are filtered out before calling the bound predicate
+func typedPredicate(name string, dtype frames.DType, bind func(col frames.Column) (RowPredicate, error)) Predicate {
+	return func(frame frames.Frame) (RowPredicate, error) {
+		col, err := LookupColumn(frame, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if col.DType() != dtype {
+			return nil, fmt.Errorf("%q - wrong dtype %d (expected %d)", name, col.DType(), dtype)
+		}
+
+		if frames.IsLabelColumn(col) {
+			// Label columns have a single value, evaluate it once
+			keep := false
+			if col.Len() > 0 && !col.IsNull(0) {
+				first, err := col.Slice(0, 1)
+				if err != nil {
+					return nil, err
+				}
+
+				pred, err := bind(first)
+				if err != nil {
+					return nil, err
+				}
+				keep = pred(0)
+			}
+
+			return func(int) bool { return keep }, nil
+		}
+
+		pred, err := bind(col)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(i int) bool { return !col.IsNull(i) && pred(i) }, nil
+	}
+}
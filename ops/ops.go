@@ -0,0 +1,316 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+/*Package ops provides in-process operations over frames.
+
+Operations never modify their input, they return a new frame that keeps the
+indices and labels of the original. Label columns are kept as label columns
+whenever possible.
+*/
+package ops
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+)
+
+// Select returns a new frame with only the named columns, in the given order
+func Select(frame frames.Frame, names ...string) (frames.Frame, error) {
+	columns := make([]frames.Column, len(names))
+	for i, name := range names {
+		col, err := frame.Column(name)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't select")
+		}
+		columns[i] = col
+	}
+
+	return frames.NewFrame(columns, frame.Indices(), frame.Labels())
+}
+
+// Head returns the first n rows of frame (or all of them if frame is shorter)
+func Head(frame frames.Frame, n int) (frames.Frame, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("negative size - %d", n)
+	}
+
+	if n > frame.Len() {
+		n = frame.Len()
+	}
+
+	return sliceFrame(frame, 0, n)
+}
+
+// Tail returns the last n rows of frame (or all of them if frame is shorter)
+func Tail(frame frames.Frame, n int) (frames.Frame, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("negative size - %d", n)
+	}
+
+	if n > frame.Len() {
+		n = frame.Len()
+	}
+
+	return sliceFrame(frame, frame.Len()-n, frame.Len())
+}
+
+// Slice returns rows [start:end) of frame
+func Slice(frame frames.Frame, start int, end int) (frames.Frame, error) {
+	if start < 0 || end > frame.Len() || start > end {
+		return nil, fmt.Errorf("bad slice [%d:%d] of frame with %d rows", start, end, frame.Len())
+	}
+
+	return sliceFrame(frame, start, end)
+}
+
+// Take returns a new frame with the rows of frame at indices, in order
+func Take(frame frames.Frame, indices []int) (frames.Frame, error) {
+	size := frame.Len()
+	for _, i := range indices {
+		if i < 0 || i >= size {
+			return nil, fmt.Errorf("index %d out of bounds [0:%d]", i, size)
+		}
+	}
+
+	columns, err := frameColumns(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err = takeColumns(columns, indices)
+	if err != nil {
+		return nil, err
+	}
+
+	idxCols, err := takeColumns(frame.Indices(), indices)
+	if err != nil {
+		return nil, err
+	}
+
+	return frames.NewFrame(columns, idxCols, frame.Labels())
+}
+
+// Rename returns a copy of col with a new name
+func Rename(col frames.Column, name string) (frames.Column, error) {
+	if frames.IsLabelColumn(col) && col.Len() > 0 && !col.IsNull(0) {
+		value, err := ValueAt(col, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return frames.NewLabelColumn(name, value, col.Len())
+	}
+
+	indices := make([]int, col.Len())
+	for i := range indices {
+		indices[i] = i
+	}
+
+	return takeAs(col, name, indices)
+}
+
+// ValueAt returns the value at index i of col as interface{}, nil if it's null
+func ValueAt(col frames.Column, i int) (interface{}, error) {
+	var value interface{}
+	var err error
+	switch col.DType() {
+	case frames.IntType:
+		value, err = col.IntAt(i)
+	case frames.FloatType:
+		value, err = col.FloatAt(i)
+	case frames.StringType:
+		value, err = col.StringAt(i)
+	case frames.TimeType:
+		value, err = col.TimeAt(i)
+	case frames.BoolType:
+		value, err = col.BoolAt(i)
+	default:
+		err = fmt.Errorf("%s - unknown dtype - %d", col.Name(), col.DType())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if col.IsNull(i) {
+		return nil, nil
+	}
+
+	return value, nil
+}
+
+// LookupColumn finds a column by name in the frame columns or indices
+func LookupColumn(frame frames.Frame, name string) (frames.Column, error) {
+	if col, err := frame.Column(name); err == nil {
+		return col, nil
+	}
+
+	for _, col := range frame.Indices() {
+		if col.Name() == name {
+			return col, nil
+		}
+	}
+
+	return nil, fmt.Errorf("column %q not found", name)
+}
+
+func frameColumns(frame frames.Frame) ([]frames.Column, error) {
+	names := frame.Names()
+	columns := make([]frames.Column, len(names))
+	for i, name := range names {
+		col, err := frame.Column(name)
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = col
+	}
+
+	return columns, nil
+}
+
+func sliceFrame(frame frames.Frame, start int, end int) (frames.Frame, error) {
+	if start == end {
+		// Column.Slice doesn't support empty slices
+		return Take(frame, nil)
+	}
+
+	columns, err := frameColumns(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err = sliceColumns(columns, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	idxCols, err := sliceColumns(frame.Indices(), start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return frames.NewFrame(columns, idxCols, frame.Labels())
+}
+
+func sliceColumns(columns []frames.Column, start int, end int) ([]frames.Column, error) {
+	slices := make([]frames.Column, len(columns))
+	for i, col := range columns {
+		slice, err := col.Slice(start, end)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't get slice from %q", col.Name())
+		}
+		slices[i] = slice
+	}
+
+	return slices, nil
+}
+
+func takeColumns(columns []frames.Column, indices []int) ([]frames.Column, error) {
+	out := make([]frames.Column, len(columns))
+	for i, col := range columns {
+		var err error
+		out[i], err = take(col, indices)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't take from %q", col.Name())
+		}
+	}
+
+	return out, nil
+}
+
+// take returns a new column with values of col at indices
+func take(col frames.Column, indices []int) (frames.Column, error) {
+	if frames.IsLabelColumn(col) && len(indices) > 0 {
+		// All values are the same, only the size changes
+		return col.Slice(0, len(indices))
+	}
+
+	return takeAs(col, col.Name(), indices)
+}
+
+// takeAs returns a new column called name with values of col at indices
+func takeAs(col frames.Column, name string, indices []int) (frames.Column, error) {
+	var data interface{}
+	switch col.DType() {
+	case frames.IntType:
+		values, err := col.Ints()
+		if err != nil {
+			return nil, err
+		}
+		out := make([]int64, len(indices))
+		for j, i := range indices {
+			out[j] = values[i]
+		}
+		data = out
+	case frames.FloatType:
+		values, err := col.Floats()
+		if err != nil {
+			return nil, err
+		}
+		out := make([]float64, len(indices))
+		for j, i := range indices {
+			out[j] = values[i]
+		}
+		data = out
+	case frames.StringType:
+		values := col.Strings()
+		out := make([]string, len(indices))
+		for j, i := range indices {
+			out[j] = values[i]
+		}
+		data = out
+	case frames.TimeType:
+		values, err := col.Times()
+		if err != nil {
+			return nil, err
+		}
+		out := make([]time.Time, len(indices))
+		for j, i := range indices {
+			out[j] = values[i]
+		}
+		data = out
+	case frames.BoolType:
+		values, err := col.Bools()
+		if err != nil {
+			return nil, err
+		}
+		out := make([]bool, len(indices))
+		for j, i := range indices {
+			out[j] = values[i]
+		}
+		data = out
+	default:
+		return nil, fmt.Errorf("unknown dtype - %d", col.DType())
+	}
+
+	var nulls []bool
+	if col.NullCount() > 0 {
+		nulls = make([]bool, len(indices))
+		for j, i := range indices {
+			nulls[j] = col.IsNull(i)
+		}
+	}
+
+	return frames.NewNullableSliceColumn(name, data, nulls)
+}
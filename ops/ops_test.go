@@ -0,0 +1,279 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package ops
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/v3io/frames"
+)
+
+func TestSelect(t *testing.T) {
+	frame := testFrame(t)
+	out, err := Select(frame, "s", "i")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := out.Names(); !reflect.DeepEqual(names, []string{"s", "i"}) {
+		t.Fatalf("bad names - %v", names)
+	}
+
+	if len(out.Indices()) != 1 {
+		t.Fatalf("index not preserved")
+	}
+
+	if _, err := Select(frame, "nope"); err == nil {
+		t.Fatal("no error on unknown column")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	frame := testFrame(t)
+	pred := Or(
+		IntPredicate("i", func(v int64) bool { return v > 3 }),
+		StringPredicate("s", func(v string) bool { return v == "a" }),
+	)
+
+	out, err := Filter(frame, pred)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertInts(t, out, "i", []int64{3, 4, 5})
+	assertLabel(t, out, "l", 3)
+
+	out, err = Filter(frame, IsNull("f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertInts(t, out, "i", []int64{2})
+
+	out, err = Filter(frame, IntPredicate("l", func(v int64) bool { return v == 7 }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Len() != frame.Len() {
+		t.Fatalf("label filter - bad length %d", out.Len())
+	}
+
+	if _, err := Filter(frame, IntPredicate("s", nil)); err == nil {
+		t.Fatal("no error on wrong dtype")
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	frame := testFrame(t)
+	out, err := SortBy(frame, SortKey{Column: "s"}, SortKey{Column: "i", Descending: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertInts(t, out, "i", []int64{3, 5, 1, 4, 2})
+
+	// Nulls are last
+	out, err = SortBy(frame, SortKey{Column: "f", Descending: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertInts(t, out, "i", []int64{5, 4, 3, 1, 2})
+
+	// Sort by index
+	out, err = SortBy(frame, SortKey{Column: "idx", Descending: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertInts(t, out, "i", []int64{5, 4, 3, 2, 1})
+}
+
+func TestHeadTail(t *testing.T) {
+	frame := testFrame(t)
+	out, err := Head(frame, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertInts(t, out, "i", []int64{1, 2})
+
+	out, err = Tail(frame, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertInts(t, out, "i", []int64{4, 5})
+	assertLabel(t, out, "l", 2)
+
+	out, err = Head(frame, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertInts(t, out, "i", []int64{1, 2, 3, 4, 5})
+
+	out, err = Tail(frame, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Len() != 0 {
+		t.Fatalf("bad length - %d", out.Len())
+	}
+}
+
+func TestConcat(t *testing.T) {
+	frame1 := testFrame(t)
+	i, err := frames.NewSliceColumn("i", []float64{6.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := frames.NewSliceColumn("x", []string{"x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := frames.NewLabelColumn("l", 7, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := frames.NewSliceColumn("idx", []int64{60})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame2, err := frames.NewFrame([]frames.Column{i, x, l}, []frames.Column{idx}, map[string]interface{}{"host": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := Concat(frame1, frame2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Len() != 6 {
+		t.Fatalf("bad length - %d", out.Len())
+	}
+
+	if names := out.Names(); !reflect.DeepEqual(names, []string{"i", "s", "f", "l", "x"}) {
+		t.Fatalf("bad names - %v", names)
+	}
+
+	col, err := out.Column("i")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if col.DType() != frames.FloatType {
+		t.Fatalf("int column not widened to float")
+	}
+
+	col, err = out.Column("s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if col.NullCount() != 1 || !col.IsNull(5) {
+		t.Fatalf("missing values are not null")
+	}
+
+	assertLabel(t, out, "l", 6)
+
+	if len(out.Indices()) != 1 || out.Indices()[0].Len() != 6 {
+		t.Fatalf("bad indices")
+	}
+
+	if labels := out.Labels(); labels["host"] != "a" {
+		t.Fatalf("bad labels - %v", labels)
+	}
+}
+
+// testFrame returns a frame with 5 rows
+// i: 1, 2, 3, 4, 5
+// s: b, c, a, c, b
+// f: 1.1, null, 3.3, 4.4, 5.5
+// l: label 7
+// idx: 10, 20, 30, 40, 50
+func testFrame(t *testing.T) frames.Frame {
+	i, err := frames.NewSliceColumn("i", []int64{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := frames.NewSliceColumn("s", []string{"b", "c", "a", "c", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nulls := []bool{false, true, false, false, false}
+	f, err := frames.NewNullableSliceColumn("f", []float64{1.1, 0, 3.3, 4.4, 5.5}, nulls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := frames.NewLabelColumn("l", 7, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := frames.NewSliceColumn("idx", []int64{10, 20, 30, 40, 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := map[string]interface{}{"host": "a", "shard": 1}
+	frame, err := frames.NewFrame([]frames.Column{i, s, f, l}, []frames.Column{idx}, labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return frame
+}
+
+func assertInts(t *testing.T, frame frames.Frame, name string, expected []int64) {
+	col, err := frame.Column(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := col.Ints()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(values, expected) {
+		t.Fatalf("%s: bad values %v != %v", name, values, expected)
+	}
+}
+
+func assertLabel(t *testing.T, frame frames.Frame, name string, size int) {
+	col, err := frame.Column(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !frames.IsLabelColumn(col) {
+		t.Fatalf("%s: not a label column", name)
+	}
+
+	if col.Len() != size {
+		t.Fatalf("%s: bad size %d != %d", name, col.Len(), size)
+	}
+}
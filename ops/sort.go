@@ -0,0 +1,169 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package ops
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+)
+
+// SortKey is a sort key
+type SortKey struct {
+	Column     string // Column (or index) name
+	Descending bool
+}
+
+// compareFunc compares rows i and j, returns <0, 0 or >0
+type compareFunc func(i, j int) int
+
+// SortBy returns a new frame sorted by keys. The sort is stable, rows with
+// equal keys keep their original order. Null values are placed last
+func SortBy(frame frames.Frame, keys ...SortKey) (frames.Frame, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no sort keys")
+	}
+
+	var cmps []compareFunc
+	for _, key := range keys {
+		col, err := LookupColumn(frame, key.Column)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't sort")
+		}
+
+		if frames.IsLabelColumn(col) {
+			// All values are the same
+			continue
+		}
+
+		cmp, err := newCompareFunc(col, key.Descending)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't sort by %q", key.Column)
+		}
+		cmps = append(cmps, cmp)
+	}
+
+	indices := make([]int, frame.Len())
+	for i := range indices {
+		indices[i] = i
+	}
+
+	sort.SliceStable(indices, func(i, j int) bool {
+		for _, cmp := range cmps {
+			if c := cmp(indices[i], indices[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	return Take(frame, indices)
+}
+
+func newCompareFunc(col frames.Column, descending bool) (compareFunc, error) {
+	var cmp compareFunc
+	switch col.DType() {
+	case frames.IntType:
+		values, err := col.Ints()
+		if err != nil {
+			return nil, err
+		}
+		cmp = func(i, j int) int {
+			switch {
+			case values[i] < values[j]:
+				return -1
+			case values[i] > values[j]:
+				return 1
+			}
+			return 0
+		}
+	case frames.FloatType:
+		values, err := col.Floats()
+		if err != nil {
+			return nil, err
+		}
+		cmp = func(i, j int) int {
+			switch {
+			case values[i] < values[j]:
+				return -1
+			case values[i] > values[j]:
+				return 1
+			}
+			return 0
+		}
+	case frames.StringType:
+		values := col.Strings()
+		cmp = func(i, j int) int {
+			return strings.Compare(values[i], values[j])
+		}
+	case frames.TimeType:
+		values, err := col.Times()
+		if err != nil {
+			return nil, err
+		}
+		cmp = func(i, j int) int {
+			switch {
+			case values[i].Before(values[j]):
+				return -1
+			case values[i].After(values[j]):
+				return 1
+			}
+			return 0
+		}
+	case frames.BoolType:
+		values, err := col.Bools()
+		if err != nil {
+			return nil, err
+		}
+		cmp = func(i, j int) int {
+			switch {
+			case values[i] == values[j]:
+				return 0
+			case values[j]: // false < true
+				return -1
+			}
+			return 1
+		}
+	default:
+		return nil, fmt.Errorf("unknown dtype - %d", col.DType())
+	}
+
+	return func(i, j int) int {
+		iNull, jNull := col.IsNull(i), col.IsNull(j)
+		switch {
+		case iNull && jNull:
+			return 0
+		case iNull:
+			return 1
+		case jNull:
+			return -1
+		}
+
+		if descending {
+			return -cmp(i, j)
+		}
+		return cmp(i, j)
+	}, nil
+}
@@ -52,7 +52,33 @@ func (x DType) String() string {
 	return proto.EnumName(DType_name, int32(x))
 }
 func (DType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{0}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{0}
+}
+
+type RowStatus int32
+
+const (
+	RowStatus_APPLIED          RowStatus = 0
+	RowStatus_CONDITION_FAILED RowStatus = 1
+	RowStatus_FAILED           RowStatus = 2
+)
+
+var RowStatus_name = map[int32]string{
+	0: "APPLIED",
+	1: "CONDITION_FAILED",
+	2: "FAILED",
+}
+var RowStatus_value = map[string]int32{
+	"APPLIED":          0,
+	"CONDITION_FAILED": 1,
+	"FAILED":           2,
+}
+
+func (x RowStatus) String() string {
+	return proto.EnumName(RowStatus_name, int32(x))
+}
+func (RowStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{1}
 }
 
 type ErrorOptions int32
@@ -75,7 +101,65 @@ func (x ErrorOptions) String() string {
 	return proto.EnumName(ErrorOptions_name, int32(x))
 }
 func (ErrorOptions) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{1}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{2}
+}
+
+// WriteMode is how rows are written to items (NoSQL). Writes with a condition
+// or a mode other than PUT are conditional writes, rows that fail are reported
+// and don't fail the write
+type WriteMode int32
+
+const (
+	WriteMode_PUT    WriteMode = 0
+	WriteMode_UPSERT WriteMode = 1
+	WriteMode_UPDATE WriteMode = 2
+	WriteMode_INSERT WriteMode = 3
+)
+
+var WriteMode_name = map[int32]string{
+	0: "PUT",
+	1: "UPSERT",
+	2: "UPDATE",
+	3: "INSERT",
+}
+var WriteMode_value = map[string]int32{
+	"PUT":    0,
+	"UPSERT": 1,
+	"UPDATE": 2,
+	"INSERT": 3,
+}
+
+func (x WriteMode) String() string {
+	return proto.EnumName(WriteMode_name, int32(x))
+}
+func (WriteMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{3}
+}
+
+type SaveMode int32
+
+const (
+	SaveMode_OVERWRITE          SaveMode = 0
+	SaveMode_APPEND             SaveMode = 1
+	SaveMode_APPEND_NEW_COLUMNS SaveMode = 2
+)
+
+var SaveMode_name = map[int32]string{
+	0: "OVERWRITE",
+	1: "APPEND",
+	2: "APPEND_NEW_COLUMNS",
+}
+var SaveMode_value = map[string]int32{
+	"OVERWRITE":          0,
+	"APPEND":             1,
+	"APPEND_NEW_COLUMNS": 2,
+}
+
+func (x SaveMode) String() string {
+	return proto.EnumName(SaveMode_name, int32(x))
+}
+func (SaveMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{4}
 }
 
 type Column_Kind int32
@@ -98,7 +182,7 @@ func (x Column_Kind) String() string {
 	return proto.EnumName(Column_Kind_name, int32(x))
 }
 func (Column_Kind) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{0, 0}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{0, 0}
 }
 
 type Column struct {
@@ -107,21 +191,24 @@ type Column struct {
 	Dtype DType       `protobuf:"varint,3,opt,name=dtype,proto3,enum=pb.DType" json:"dtype,omitempty"`
 	Size  int64       `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
 	// In slice columns these arrays will be of length 1
-	Ints                 []int64   `protobuf:"varint,5,rep,packed,name=ints,proto3" json:"ints,omitempty"`
-	Floats               []float64 `protobuf:"fixed64,6,rep,packed,name=floats,proto3" json:"floats,omitempty"`
-	Strings              []string  `protobuf:"bytes,7,rep,name=strings,proto3" json:"strings,omitempty"`
-	Times                []int64   `protobuf:"varint,8,rep,packed,name=times,proto3" json:"times,omitempty"`
-	Bools                []bool    `protobuf:"varint,9,rep,packed,name=bools,proto3" json:"bools,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	Ints    []int64   `protobuf:"varint,5,rep,packed,name=ints,proto3" json:"ints,omitempty"`
+	Floats  []float64 `protobuf:"fixed64,6,rep,packed,name=floats,proto3" json:"floats,omitempty"`
+	Strings []string  `protobuf:"bytes,7,rep,name=strings,proto3" json:"strings,omitempty"`
+	Times   []int64   `protobuf:"varint,8,rep,packed,name=times,proto3" json:"times,omitempty"`
+	Bools   []bool    `protobuf:"varint,9,rep,packed,name=bools,proto3" json:"bools,omitempty"`
+	// Null mask, true marks a null value. Empty if there are no nulls
+	// In label columns this array will be of length 1
+	Nulls                []bool   `protobuf:"varint,10,rep,packed,name=nulls,proto3" json:"nulls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Column) Reset()         { *m = Column{} }
 func (m *Column) String() string { return proto.CompactTextString(m) }
 func (*Column) ProtoMessage()    {}
 func (*Column) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{0}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{0}
 }
 func (m *Column) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Column.Unmarshal(m, b)
@@ -204,6 +291,13 @@ func (m *Column) GetBools() []bool {
 	return nil
 }
 
+func (m *Column) GetNulls() []bool {
+	if m != nil {
+		return m.Nulls
+	}
+	return nil
+}
+
 // Union of values
 type Value struct {
 	// Types that are valid to be assigned to Value:
@@ -222,7 +316,7 @@ func (m *Value) Reset()         { *m = Value{} }
 func (m *Value) String() string { return proto.CompactTextString(m) }
 func (*Value) ProtoMessage()    {}
 func (*Value) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{1}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{1}
 }
 func (m *Value) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Value.Unmarshal(m, b)
@@ -443,7 +537,7 @@ func (m *Frame) Reset()         { *m = Frame{} }
 func (m *Frame) String() string { return proto.CompactTextString(m) }
 func (*Frame) ProtoMessage()    {}
 func (*Frame) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{2}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{2}
 }
 func (m *Frame) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Frame.Unmarshal(m, b)
@@ -507,7 +601,7 @@ func (m *SchemaField) Reset()         { *m = SchemaField{} }
 func (m *SchemaField) String() string { return proto.CompactTextString(m) }
 func (*SchemaField) ProtoMessage()    {}
 func (*SchemaField) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{3}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{3}
 }
 func (m *SchemaField) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SchemaField.Unmarshal(m, b)
@@ -574,7 +668,7 @@ func (m *SchemaKey) Reset()         { *m = SchemaKey{} }
 func (m *SchemaKey) String() string { return proto.CompactTextString(m) }
 func (*SchemaKey) ProtoMessage()    {}
 func (*SchemaKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{4}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{4}
 }
 func (m *SchemaKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SchemaKey.Unmarshal(m, b)
@@ -626,7 +720,7 @@ func (m *TableSchema) Reset()         { *m = TableSchema{} }
 func (m *TableSchema) String() string { return proto.CompactTextString(m) }
 func (*TableSchema) ProtoMessage()    {}
 func (*TableSchema) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{5}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{5}
 }
 func (m *TableSchema) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TableSchema.Unmarshal(m, b)
@@ -705,7 +799,7 @@ func (m *JoinStruct) Reset()         { *m = JoinStruct{} }
 func (m *JoinStruct) String() string { return proto.CompactTextString(m) }
 func (*JoinStruct) ProtoMessage()    {}
 func (*JoinStruct) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{6}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{6}
 }
 func (m *JoinStruct) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_JoinStruct.Unmarshal(m, b)
@@ -742,7 +836,7 @@ func (m *Session) Reset()         { *m = Session{} }
 func (m *Session) String() string { return proto.CompactTextString(m) }
 func (*Session) ProtoMessage()    {}
 func (*Session) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{7}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{7}
 }
 func (m *Session) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Session.Unmarshal(m, b)
@@ -826,13 +920,16 @@ type ReadRequest struct {
 	Join         []*JoinStruct `protobuf:"bytes,12,rep,name=join,proto3" json:"join,omitempty"`
 	Limit        int64         `protobuf:"varint,13,opt,name=limit,proto3" json:"limit,omitempty"`
 	MessageLimit int64         `protobuf:"varint,14,opt,name=message_limit,json=messageLimit,proto3" json:"message_limit,omitempty"`
-	Marker       string        `protobuf:"bytes,15,opt,name=marker,proto3" json:"marker,omitempty"`
+	// Continue a single scan (NoSQL "marker" frame label, of the scan in the
+	// frame "segment"/"total_segments" or "sharding_key" labels)
+	Marker string `protobuf:"bytes,15,opt,name=marker,proto3" json:"marker,omitempty"`
 	// NoSQL
-	Segments          []int64  `protobuf:"varint,16,rep,packed,name=segments,proto3" json:"segments,omitempty"`
-	TotoalSegments    int64    `protobuf:"varint,17,opt,name=totoal_segments,json=totoalSegments,proto3" json:"totoal_segments,omitempty"`
-	ShardingKeys      []string `protobuf:"bytes,18,rep,name=sharding_keys,json=shardingKeys,proto3" json:"sharding_keys,omitempty"`
-	SortKeyRangeStart string   `protobuf:"bytes,19,opt,name=sort_key_range_start,json=sortKeyRangeStart,proto3" json:"sort_key_range_start,omitempty"`
-	SortKeyRangeEnd   string   `protobuf:"bytes,20,opt,name=sort_key_range_end,json=sortKeyRangeEnd,proto3" json:"sort_key_range_end,omitempty"`
+	Segments       []int64  `protobuf:"varint,16,rep,packed,name=segments,proto3" json:"segments,omitempty"`
+	TotoalSegments int64    `protobuf:"varint,17,opt,name=totoal_segments,json=totoalSegments,proto3" json:"totoal_segments,omitempty"`
+	ShardingKeys   []string `protobuf:"bytes,18,rep,name=sharding_keys,json=shardingKeys,proto3" json:"sharding_keys,omitempty"`
+	// Sorting key range [start, end) within the sharding keys
+	SortKeyRangeStart string `protobuf:"bytes,19,opt,name=sort_key_range_start,json=sortKeyRangeStart,proto3" json:"sort_key_range_start,omitempty"`
+	SortKeyRangeEnd   string `protobuf:"bytes,20,opt,name=sort_key_range_end,json=sortKeyRangeEnd,proto3" json:"sort_key_range_end,omitempty"`
 	// TSDB
 	Start       string `protobuf:"bytes,21,opt,name=start,proto3" json:"start,omitempty"`
 	End         string `protobuf:"bytes,22,opt,name=end,proto3" json:"end,omitempty"`
@@ -842,6 +939,7 @@ type ReadRequest struct {
 	Seek                 string   `protobuf:"bytes,25,opt,name=seek,proto3" json:"seek,omitempty"`
 	ShardId              string   `protobuf:"bytes,26,opt,name=shard_id,json=shardId,proto3" json:"shard_id,omitempty"`
 	Sequence             int64    `protobuf:"varint,27,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Timeout              int64    `protobuf:"varint,28,opt,name=timeout,proto3" json:"timeout,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -851,7 +949,7 @@ func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
 func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
 func (*ReadRequest) ProtoMessage()    {}
 func (*ReadRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{8}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{8}
 }
 func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ReadRequest.Unmarshal(m, b)
@@ -1060,23 +1158,37 @@ func (m *ReadRequest) GetSequence() int64 {
 	return 0
 }
 
+func (m *ReadRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
 type InitialWriteRequest struct {
-	Session              *Session `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
-	Backend              string   `protobuf:"bytes,2,opt,name=backend,proto3" json:"backend,omitempty"`
-	Table                string   `protobuf:"bytes,3,opt,name=table,proto3" json:"table,omitempty"`
-	InitialData          *Frame   `protobuf:"bytes,4,opt,name=initial_data,json=initialData,proto3" json:"initial_data,omitempty"`
-	Expression           string   `protobuf:"bytes,5,opt,name=expression,proto3" json:"expression,omitempty"`
-	More                 bool     `protobuf:"varint,6,opt,name=more,proto3" json:"more,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Session     *Session `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Backend     string   `protobuf:"bytes,2,opt,name=backend,proto3" json:"backend,omitempty"`
+	Table       string   `protobuf:"bytes,3,opt,name=table,proto3" json:"table,omitempty"`
+	InitialData *Frame   `protobuf:"bytes,4,opt,name=initial_data,json=initialData,proto3" json:"initial_data,omitempty"`
+	Expression  string   `protobuf:"bytes,5,opt,name=expression,proto3" json:"expression,omitempty"`
+	More        bool     `protobuf:"varint,6,opt,name=more,proto3" json:"more,omitempty"`
+	DataFormat  string   `protobuf:"bytes,7,opt,name=data_format,json=dataFormat,proto3" json:"data_format,omitempty"`
+	SaveMode    SaveMode `protobuf:"varint,8,opt,name=save_mode,json=saveMode,proto3,enum=pb.SaveMode" json:"save_mode,omitempty"`
+	Timeout     int64    `protobuf:"varint,9,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Per row condition (NoSQL), a template like expression. String values
+	// with both quote characters can't be used (v3io has no string escapes)
+	Condition            string    `protobuf:"bytes,10,opt,name=condition,proto3" json:"condition,omitempty"`
+	WriteMode            WriteMode `protobuf:"varint,11,opt,name=write_mode,json=writeMode,proto3,enum=pb.WriteMode" json:"write_mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *InitialWriteRequest) Reset()         { *m = InitialWriteRequest{} }
 func (m *InitialWriteRequest) String() string { return proto.CompactTextString(m) }
 func (*InitialWriteRequest) ProtoMessage()    {}
 func (*InitialWriteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{9}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{9}
 }
 func (m *InitialWriteRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InitialWriteRequest.Unmarshal(m, b)
@@ -1138,6 +1250,41 @@ func (m *InitialWriteRequest) GetMore() bool {
 	return false
 }
 
+func (m *InitialWriteRequest) GetDataFormat() string {
+	if m != nil {
+		return m.DataFormat
+	}
+	return ""
+}
+
+func (m *InitialWriteRequest) GetSaveMode() SaveMode {
+	if m != nil {
+		return m.SaveMode
+	}
+	return SaveMode_OVERWRITE
+}
+
+func (m *InitialWriteRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *InitialWriteRequest) GetCondition() string {
+	if m != nil {
+		return m.Condition
+	}
+	return ""
+}
+
+func (m *InitialWriteRequest) GetWriteMode() WriteMode {
+	if m != nil {
+		return m.WriteMode
+	}
+	return WriteMode_PUT
+}
+
 type WriteRequest struct {
 	// Types that are valid to be assigned to Type:
 	//	*WriteRequest_Request
@@ -1152,7 +1299,7 @@ func (m *WriteRequest) Reset()         { *m = WriteRequest{} }
 func (m *WriteRequest) String() string { return proto.CompactTextString(m) }
 func (*WriteRequest) ProtoMessage()    {}
 func (*WriteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{10}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{10}
 }
 func (m *WriteRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_WriteRequest.Unmarshal(m, b)
@@ -1284,18 +1431,19 @@ func _WriteRequest_OneofSizer(msg proto.Message) (n int) {
 }
 
 type WriteRespose struct {
-	Frames               int64    `protobuf:"varint,1,opt,name=frames,proto3" json:"frames,omitempty"`
-	Rows                 int64    `protobuf:"varint,2,opt,name=rows,proto3" json:"rows,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Frames               int64        `protobuf:"varint,1,opt,name=frames,proto3" json:"frames,omitempty"`
+	Rows                 int64        `protobuf:"varint,2,opt,name=rows,proto3" json:"rows,omitempty"`
+	Report               *WriteReport `protobuf:"bytes,3,opt,name=report,proto3" json:"report,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
 func (m *WriteRespose) Reset()         { *m = WriteRespose{} }
 func (m *WriteRespose) String() string { return proto.CompactTextString(m) }
 func (*WriteRespose) ProtoMessage()    {}
 func (*WriteRespose) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{11}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{11}
 }
 func (m *WriteRespose) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_WriteRespose.Unmarshal(m, b)
@@ -1329,6 +1477,140 @@ func (m *WriteRespose) GetRows() int64 {
 	return 0
 }
 
+func (m *WriteRespose) GetReport() *WriteReport {
+	if m != nil {
+		return m.Report
+	}
+	return nil
+}
+
+// RowOutcome is the outcome of a row that wasn't applied
+type RowOutcome struct {
+	Row                  int64     `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Key                  string    `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Status               RowStatus `protobuf:"varint,3,opt,name=status,proto3,enum=pb.RowStatus" json:"status,omitempty"`
+	Error                string    `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *RowOutcome) Reset()         { *m = RowOutcome{} }
+func (m *RowOutcome) String() string { return proto.CompactTextString(m) }
+func (*RowOutcome) ProtoMessage()    {}
+func (*RowOutcome) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{12}
+}
+func (m *RowOutcome) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RowOutcome.Unmarshal(m, b)
+}
+func (m *RowOutcome) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RowOutcome.Marshal(b, m, deterministic)
+}
+func (dst *RowOutcome) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RowOutcome.Merge(dst, src)
+}
+func (m *RowOutcome) XXX_Size() int {
+	return xxx_messageInfo_RowOutcome.Size(m)
+}
+func (m *RowOutcome) XXX_DiscardUnknown() {
+	xxx_messageInfo_RowOutcome.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RowOutcome proto.InternalMessageInfo
+
+func (m *RowOutcome) GetRow() int64 {
+	if m != nil {
+		return m.Row
+	}
+	return 0
+}
+
+func (m *RowOutcome) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *RowOutcome) GetStatus() RowStatus {
+	if m != nil {
+		return m.Status
+	}
+	return RowStatus_APPLIED
+}
+
+func (m *RowOutcome) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// WriteReport is the outcome of a conditional write, rows are only the rows
+// that weren't applied
+type WriteReport struct {
+	Applied              int64         `protobuf:"varint,1,opt,name=applied,proto3" json:"applied,omitempty"`
+	ConditionFailed      int64         `protobuf:"varint,2,opt,name=condition_failed,json=conditionFailed,proto3" json:"condition_failed,omitempty"`
+	Failed               int64         `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	Rows                 []*RowOutcome `protobuf:"bytes,4,rep,name=rows,proto3" json:"rows,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *WriteReport) Reset()         { *m = WriteReport{} }
+func (m *WriteReport) String() string { return proto.CompactTextString(m) }
+func (*WriteReport) ProtoMessage()    {}
+func (*WriteReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{13}
+}
+func (m *WriteReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriteReport.Unmarshal(m, b)
+}
+func (m *WriteReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriteReport.Marshal(b, m, deterministic)
+}
+func (dst *WriteReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriteReport.Merge(dst, src)
+}
+func (m *WriteReport) XXX_Size() int {
+	return xxx_messageInfo_WriteReport.Size(m)
+}
+func (m *WriteReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriteReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriteReport proto.InternalMessageInfo
+
+func (m *WriteReport) GetApplied() int64 {
+	if m != nil {
+		return m.Applied
+	}
+	return 0
+}
+
+func (m *WriteReport) GetConditionFailed() int64 {
+	if m != nil {
+		return m.ConditionFailed
+	}
+	return 0
+}
+
+func (m *WriteReport) GetFailed() int64 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *WriteReport) GetRows() []*RowOutcome {
+	if m != nil {
+		return m.Rows
+	}
+	return nil
+}
+
 // CreateRequest is a table creation request
 type CreateRequest struct {
 	Session              *Session          `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
@@ -1337,6 +1619,7 @@ type CreateRequest struct {
 	AttributeMap         map[string]*Value `protobuf:"bytes,4,rep,name=attribute_map,json=attributeMap,proto3" json:"attribute_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	Schema               *TableSchema      `protobuf:"bytes,5,opt,name=schema,proto3" json:"schema,omitempty"`
 	IfExists             ErrorOptions      `protobuf:"varint,6,opt,name=if_exists,json=ifExists,proto3,enum=pb.ErrorOptions" json:"if_exists,omitempty"`
+	Timeout              int64             `protobuf:"varint,7,opt,name=timeout,proto3" json:"timeout,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -1346,7 +1629,7 @@ func (m *CreateRequest) Reset()         { *m = CreateRequest{} }
 func (m *CreateRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateRequest) ProtoMessage()    {}
 func (*CreateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{12}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{14}
 }
 func (m *CreateRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateRequest.Unmarshal(m, b)
@@ -1408,6 +1691,13 @@ func (m *CreateRequest) GetIfExists() ErrorOptions {
 	return ErrorOptions_FAIL
 }
 
+func (m *CreateRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
 type CreateResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -1418,7 +1708,7 @@ func (m *CreateResponse) Reset()         { *m = CreateResponse{} }
 func (m *CreateResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateResponse) ProtoMessage()    {}
 func (*CreateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{13}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{15}
 }
 func (m *CreateResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateResponse.Unmarshal(m, b)
@@ -1448,6 +1738,7 @@ type DeleteRequest struct {
 	// TSDB and Stream specific fields
 	Start                string   `protobuf:"bytes,6,opt,name=start,proto3" json:"start,omitempty"`
 	End                  string   `protobuf:"bytes,7,opt,name=end,proto3" json:"end,omitempty"`
+	Timeout              int64    `protobuf:"varint,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1457,7 +1748,7 @@ func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()    {}
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{14}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{16}
 }
 func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
@@ -1526,6 +1817,13 @@ func (m *DeleteRequest) GetEnd() string {
 	return ""
 }
 
+func (m *DeleteRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
 type DeleteResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -1536,7 +1834,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{15}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{17}
 }
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteResponse.Unmarshal(m, b)
@@ -1563,6 +1861,7 @@ type ExecRequest struct {
 	Command              string            `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`
 	Args                 map[string]*Value `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	Expression           string            `protobuf:"bytes,6,opt,name=expression,proto3" json:"expression,omitempty"`
+	Timeout              int64             `protobuf:"varint,7,opt,name=timeout,proto3" json:"timeout,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -1572,7 +1871,7 @@ func (m *ExecRequest) Reset()         { *m = ExecRequest{} }
 func (m *ExecRequest) String() string { return proto.CompactTextString(m) }
 func (*ExecRequest) ProtoMessage()    {}
 func (*ExecRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{16}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{18}
 }
 func (m *ExecRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecRequest.Unmarshal(m, b)
@@ -1634,7 +1933,15 @@ func (m *ExecRequest) GetExpression() string {
 	return ""
 }
 
+func (m *ExecRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
 type ExecResponse struct {
+	Frame                *Frame   `protobuf:"bytes,1,opt,name=frame,proto3" json:"frame,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1644,7 +1951,7 @@ func (m *ExecResponse) Reset()         { *m = ExecResponse{} }
 func (m *ExecResponse) String() string { return proto.CompactTextString(m) }
 func (*ExecResponse) ProtoMessage()    {}
 func (*ExecResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_frames_19fe9b420ca9a674, []int{17}
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{19}
 }
 func (m *ExecResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecResponse.Unmarshal(m, b)
@@ -1664,83 +1971,694 @@ func (m *ExecResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ExecResponse proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterType((*Column)(nil), "pb.Column")
-	proto.RegisterType((*Value)(nil), "pb.Value")
-	proto.RegisterType((*Frame)(nil), "pb.Frame")
-	proto.RegisterMapType((map[string]*Value)(nil), "pb.Frame.LabelsEntry")
-	proto.RegisterType((*SchemaField)(nil), "pb.SchemaField")
-	proto.RegisterMapType((map[string]*Value)(nil), "pb.SchemaField.PropertiesEntry")
-	proto.RegisterType((*SchemaKey)(nil), "pb.SchemaKey")
-	proto.RegisterType((*TableSchema)(nil), "pb.TableSchema")
-	proto.RegisterType((*JoinStruct)(nil), "pb.JoinStruct")
-	proto.RegisterType((*Session)(nil), "pb.Session")
-	proto.RegisterType((*ReadRequest)(nil), "pb.ReadRequest")
-	proto.RegisterType((*InitialWriteRequest)(nil), "pb.InitialWriteRequest")
-	proto.RegisterType((*WriteRequest)(nil), "pb.WriteRequest")
-	proto.RegisterType((*WriteRespose)(nil), "pb.WriteRespose")
-	proto.RegisterType((*CreateRequest)(nil), "pb.CreateRequest")
-	proto.RegisterMapType((map[string]*Value)(nil), "pb.CreateRequest.AttributeMapEntry")
-	proto.RegisterType((*CreateResponse)(nil), "pb.CreateResponse")
-	proto.RegisterType((*DeleteRequest)(nil), "pb.DeleteRequest")
-	proto.RegisterType((*DeleteResponse)(nil), "pb.DeleteResponse")
-	proto.RegisterType((*ExecRequest)(nil), "pb.ExecRequest")
-	proto.RegisterMapType((map[string]*Value)(nil), "pb.ExecRequest.ArgsEntry")
-	proto.RegisterType((*ExecResponse)(nil), "pb.ExecResponse")
-	proto.RegisterEnum("pb.DType", DType_name, DType_value)
-	proto.RegisterEnum("pb.ErrorOptions", ErrorOptions_name, ErrorOptions_value)
-	proto.RegisterEnum("pb.Column_Kind", Column_Kind_name, Column_Kind_value)
+func (m *ExecResponse) GetFrame() *Frame {
+	if m != nil {
+		return m.Frame
+	}
+	return nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// FramesClient is the client API for Frames service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type FramesClient interface {
-	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (Frames_ReadClient, error)
-	Write(ctx context.Context, opts ...grpc.CallOption) (Frames_WriteClient, error)
-	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
-	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
-	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*ExecResponse, error)
+// ListRequest is a table listing request
+type ListRequest struct {
+	Session              *Session `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Backend              string   `protobuf:"bytes,2,opt,name=backend,proto3" json:"backend,omitempty"`
+	Path                 string   `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Timeout              int64    `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type framesClient struct {
-	cc *grpc.ClientConn
+func (m *ListRequest) Reset()         { *m = ListRequest{} }
+func (m *ListRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()    {}
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{20}
 }
-
-func NewFramesClient(cc *grpc.ClientConn) FramesClient {
-	return &framesClient{cc}
+func (m *ListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
+}
+func (m *ListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRequest.Merge(dst, src)
+}
+func (m *ListRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRequest.Size(m)
+}
+func (m *ListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRequest.DiscardUnknown(m)
 }
 
-func (c *framesClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (Frames_ReadClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Frames_serviceDesc.Streams[0], "/pb.Frames/Read", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &framesReadClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+var xxx_messageInfo_ListRequest proto.InternalMessageInfo
+
+func (m *ListRequest) GetSession() *Session {
+	if m != nil {
+		return m.Session
 	}
-	return x, nil
+	return nil
 }
 
-type Frames_ReadClient interface {
-	Recv() (*Frame, error)
-	grpc.ClientStream
+func (m *ListRequest) GetBackend() string {
+	if m != nil {
+		return m.Backend
+	}
+	return ""
 }
 
-type framesReadClient struct {
-	grpc.ClientStream
+func (m *ListRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ListRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+type ListResponse struct {
+	Tables               []string `protobuf:"bytes,1,rep,name=tables,proto3" json:"tables,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListResponse) Reset()         { *m = ListResponse{} }
+func (m *ListResponse) String() string { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()    {}
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{21}
+}
+func (m *ListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
+}
+func (m *ListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListResponse.Merge(dst, src)
+}
+func (m *ListResponse) XXX_Size() int {
+	return xxx_messageInfo_ListResponse.Size(m)
+}
+func (m *ListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListResponse proto.InternalMessageInfo
+
+func (m *ListResponse) GetTables() []string {
+	if m != nil {
+		return m.Tables
+	}
+	return nil
+}
+
+// DescribeRequest is a table description request
+type DescribeRequest struct {
+	Session              *Session `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Backend              string   `protobuf:"bytes,2,opt,name=backend,proto3" json:"backend,omitempty"`
+	Table                string   `protobuf:"bytes,3,opt,name=table,proto3" json:"table,omitempty"`
+	Timeout              int64    `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DescribeRequest) Reset()         { *m = DescribeRequest{} }
+func (m *DescribeRequest) String() string { return proto.CompactTextString(m) }
+func (*DescribeRequest) ProtoMessage()    {}
+func (*DescribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{22}
+}
+func (m *DescribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeRequest.Unmarshal(m, b)
+}
+func (m *DescribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeRequest.Marshal(b, m, deterministic)
+}
+func (dst *DescribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeRequest.Merge(dst, src)
+}
+func (m *DescribeRequest) XXX_Size() int {
+	return xxx_messageInfo_DescribeRequest.Size(m)
+}
+func (m *DescribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeRequest proto.InternalMessageInfo
+
+func (m *DescribeRequest) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *DescribeRequest) GetBackend() string {
+	if m != nil {
+		return m.Backend
+	}
+	return ""
+}
+
+func (m *DescribeRequest) GetTable() string {
+	if m != nil {
+		return m.Table
+	}
+	return ""
+}
+
+func (m *DescribeRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+// TableInfo is a table schema and backend specific attributes
+type TableInfo struct {
+	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Schema               *TableSchema      `protobuf:"bytes,2,opt,name=schema,proto3" json:"schema,omitempty"`
+	AttributeMap         map[string]*Value `protobuf:"bytes,3,rep,name=attribute_map,json=attributeMap,proto3" json:"attribute_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *TableInfo) Reset()         { *m = TableInfo{} }
+func (m *TableInfo) String() string { return proto.CompactTextString(m) }
+func (*TableInfo) ProtoMessage()    {}
+func (*TableInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{23}
+}
+func (m *TableInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TableInfo.Unmarshal(m, b)
+}
+func (m *TableInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TableInfo.Marshal(b, m, deterministic)
+}
+func (dst *TableInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TableInfo.Merge(dst, src)
+}
+func (m *TableInfo) XXX_Size() int {
+	return xxx_messageInfo_TableInfo.Size(m)
+}
+func (m *TableInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_TableInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TableInfo proto.InternalMessageInfo
+
+func (m *TableInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TableInfo) GetSchema() *TableSchema {
+	if m != nil {
+		return m.Schema
+	}
+	return nil
+}
+
+func (m *TableInfo) GetAttributeMap() map[string]*Value {
+	if m != nil {
+		return m.AttributeMap
+	}
+	return nil
+}
+
+type DescribeResponse struct {
+	Table                *TableInfo `protobuf:"bytes,1,opt,name=table,proto3" json:"table,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *DescribeResponse) Reset()         { *m = DescribeResponse{} }
+func (m *DescribeResponse) String() string { return proto.CompactTextString(m) }
+func (*DescribeResponse) ProtoMessage()    {}
+func (*DescribeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{24}
+}
+func (m *DescribeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DescribeResponse.Unmarshal(m, b)
+}
+func (m *DescribeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DescribeResponse.Marshal(b, m, deterministic)
+}
+func (dst *DescribeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DescribeResponse.Merge(dst, src)
+}
+func (m *DescribeResponse) XXX_Size() int {
+	return xxx_messageInfo_DescribeResponse.Size(m)
+}
+func (m *DescribeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DescribeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DescribeResponse proto.InternalMessageInfo
+
+func (m *DescribeResponse) GetTable() *TableInfo {
+	if m != nil {
+		return m.Table
+	}
+	return nil
+}
+
+// ArgumentInfo describes an Exec command argument or a Create attribute
+type ArgumentInfo struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Dtype                DType    `protobuf:"varint,2,opt,name=dtype,proto3,enum=pb.DType" json:"dtype,omitempty"`
+	Required             bool     `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+	Doc                  string   `protobuf:"bytes,4,opt,name=doc,proto3" json:"doc,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArgumentInfo) Reset()         { *m = ArgumentInfo{} }
+func (m *ArgumentInfo) String() string { return proto.CompactTextString(m) }
+func (*ArgumentInfo) ProtoMessage()    {}
+func (*ArgumentInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{25}
+}
+func (m *ArgumentInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArgumentInfo.Unmarshal(m, b)
+}
+func (m *ArgumentInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArgumentInfo.Marshal(b, m, deterministic)
+}
+func (dst *ArgumentInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArgumentInfo.Merge(dst, src)
+}
+func (m *ArgumentInfo) XXX_Size() int {
+	return xxx_messageInfo_ArgumentInfo.Size(m)
+}
+func (m *ArgumentInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArgumentInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArgumentInfo proto.InternalMessageInfo
+
+func (m *ArgumentInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ArgumentInfo) GetDtype() DType {
+	if m != nil {
+		return m.Dtype
+	}
+	return DType_INTEGER
+}
+
+func (m *ArgumentInfo) GetRequired() bool {
+	if m != nil {
+		return m.Required
+	}
+	return false
+}
+
+func (m *ArgumentInfo) GetDoc() string {
+	if m != nil {
+		return m.Doc
+	}
+	return ""
+}
+
+// ExecCommandInfo describes an Exec command
+type ExecCommandInfo struct {
+	Name                 string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Args                 []*ArgumentInfo `protobuf:"bytes,2,rep,name=args,proto3" json:"args,omitempty"`
+	Doc                  string          `protobuf:"bytes,3,opt,name=doc,proto3" json:"doc,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ExecCommandInfo) Reset()         { *m = ExecCommandInfo{} }
+func (m *ExecCommandInfo) String() string { return proto.CompactTextString(m) }
+func (*ExecCommandInfo) ProtoMessage()    {}
+func (*ExecCommandInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{26}
+}
+func (m *ExecCommandInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecCommandInfo.Unmarshal(m, b)
+}
+func (m *ExecCommandInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecCommandInfo.Marshal(b, m, deterministic)
+}
+func (dst *ExecCommandInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecCommandInfo.Merge(dst, src)
+}
+func (m *ExecCommandInfo) XXX_Size() int {
+	return xxx_messageInfo_ExecCommandInfo.Size(m)
+}
+func (m *ExecCommandInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecCommandInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecCommandInfo proto.InternalMessageInfo
+
+func (m *ExecCommandInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ExecCommandInfo) GetArgs() []*ArgumentInfo {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+func (m *ExecCommandInfo) GetDoc() string {
+	if m != nil {
+		return m.Doc
+	}
+	return ""
+}
+
+// Capabilities are what a backend supports
+type Capabilities struct {
+	Operations           []string           `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	ExecCommands         []*ExecCommandInfo `protobuf:"bytes,2,rep,name=exec_commands,json=execCommands,proto3" json:"exec_commands,omitempty"`
+	FilterDialect        string             `protobuf:"bytes,3,opt,name=filter_dialect,json=filterDialect,proto3" json:"filter_dialect,omitempty"`
+	FilterOperators      []string           `protobuf:"bytes,4,rep,name=filter_operators,json=filterOperators,proto3" json:"filter_operators,omitempty"`
+	Dtypes               []DType            `protobuf:"varint,5,rep,packed,name=dtypes,proto3,enum=pb.DType" json:"dtypes,omitempty"`
+	CreateAttributes     []*ArgumentInfo    `protobuf:"bytes,6,rep,name=create_attributes,json=createAttributes,proto3" json:"create_attributes,omitempty"`
+	ConditionalWrites    bool               `protobuf:"varint,7,opt,name=conditional_writes,json=conditionalWrites,proto3" json:"conditional_writes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *Capabilities) Reset()         { *m = Capabilities{} }
+func (m *Capabilities) String() string { return proto.CompactTextString(m) }
+func (*Capabilities) ProtoMessage()    {}
+func (*Capabilities) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{27}
+}
+func (m *Capabilities) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Capabilities.Unmarshal(m, b)
+}
+func (m *Capabilities) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Capabilities.Marshal(b, m, deterministic)
+}
+func (dst *Capabilities) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Capabilities.Merge(dst, src)
+}
+func (m *Capabilities) XXX_Size() int {
+	return xxx_messageInfo_Capabilities.Size(m)
+}
+func (m *Capabilities) XXX_DiscardUnknown() {
+	xxx_messageInfo_Capabilities.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Capabilities proto.InternalMessageInfo
+
+func (m *Capabilities) GetOperations() []string {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+func (m *Capabilities) GetExecCommands() []*ExecCommandInfo {
+	if m != nil {
+		return m.ExecCommands
+	}
+	return nil
+}
+
+func (m *Capabilities) GetFilterDialect() string {
+	if m != nil {
+		return m.FilterDialect
+	}
+	return ""
+}
+
+func (m *Capabilities) GetFilterOperators() []string {
+	if m != nil {
+		return m.FilterOperators
+	}
+	return nil
+}
+
+func (m *Capabilities) GetDtypes() []DType {
+	if m != nil {
+		return m.Dtypes
+	}
+	return nil
+}
+
+func (m *Capabilities) GetCreateAttributes() []*ArgumentInfo {
+	if m != nil {
+		return m.CreateAttributes
+	}
+	return nil
+}
+
+func (m *Capabilities) GetConditionalWrites() bool {
+	if m != nil {
+		return m.ConditionalWrites
+	}
+	return false
+}
+
+type BackendInfo struct {
+	Name                 string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string        `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Capabilities         *Capabilities `protobuf:"bytes,3,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *BackendInfo) Reset()         { *m = BackendInfo{} }
+func (m *BackendInfo) String() string { return proto.CompactTextString(m) }
+func (*BackendInfo) ProtoMessage()    {}
+func (*BackendInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{28}
+}
+func (m *BackendInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackendInfo.Unmarshal(m, b)
+}
+func (m *BackendInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackendInfo.Marshal(b, m, deterministic)
+}
+func (dst *BackendInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackendInfo.Merge(dst, src)
+}
+func (m *BackendInfo) XXX_Size() int {
+	return xxx_messageInfo_BackendInfo.Size(m)
+}
+func (m *BackendInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackendInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackendInfo proto.InternalMessageInfo
+
+func (m *BackendInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BackendInfo) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *BackendInfo) GetCapabilities() *Capabilities {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+type BackendsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackendsRequest) Reset()         { *m = BackendsRequest{} }
+func (m *BackendsRequest) String() string { return proto.CompactTextString(m) }
+func (*BackendsRequest) ProtoMessage()    {}
+func (*BackendsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{29}
+}
+func (m *BackendsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackendsRequest.Unmarshal(m, b)
+}
+func (m *BackendsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackendsRequest.Marshal(b, m, deterministic)
+}
+func (dst *BackendsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackendsRequest.Merge(dst, src)
+}
+func (m *BackendsRequest) XXX_Size() int {
+	return xxx_messageInfo_BackendsRequest.Size(m)
+}
+func (m *BackendsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackendsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackendsRequest proto.InternalMessageInfo
+
+type BackendsResponse struct {
+	Backends             []*BackendInfo `protobuf:"bytes,1,rep,name=backends,proto3" json:"backends,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *BackendsResponse) Reset()         { *m = BackendsResponse{} }
+func (m *BackendsResponse) String() string { return proto.CompactTextString(m) }
+func (*BackendsResponse) ProtoMessage()    {}
+func (*BackendsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_frames_6c37051373f3aa3f, []int{30}
+}
+func (m *BackendsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackendsResponse.Unmarshal(m, b)
+}
+func (m *BackendsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackendsResponse.Marshal(b, m, deterministic)
+}
+func (dst *BackendsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackendsResponse.Merge(dst, src)
+}
+func (m *BackendsResponse) XXX_Size() int {
+	return xxx_messageInfo_BackendsResponse.Size(m)
+}
+func (m *BackendsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackendsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackendsResponse proto.InternalMessageInfo
+
+func (m *BackendsResponse) GetBackends() []*BackendInfo {
+	if m != nil {
+		return m.Backends
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Column)(nil), "pb.Column")
+	proto.RegisterType((*Value)(nil), "pb.Value")
+	proto.RegisterType((*Frame)(nil), "pb.Frame")
+	proto.RegisterMapType((map[string]*Value)(nil), "pb.Frame.LabelsEntry")
+	proto.RegisterType((*SchemaField)(nil), "pb.SchemaField")
+	proto.RegisterMapType((map[string]*Value)(nil), "pb.SchemaField.PropertiesEntry")
+	proto.RegisterType((*SchemaKey)(nil), "pb.SchemaKey")
+	proto.RegisterType((*TableSchema)(nil), "pb.TableSchema")
+	proto.RegisterType((*JoinStruct)(nil), "pb.JoinStruct")
+	proto.RegisterType((*Session)(nil), "pb.Session")
+	proto.RegisterType((*ReadRequest)(nil), "pb.ReadRequest")
+	proto.RegisterType((*InitialWriteRequest)(nil), "pb.InitialWriteRequest")
+	proto.RegisterType((*WriteRequest)(nil), "pb.WriteRequest")
+	proto.RegisterType((*WriteRespose)(nil), "pb.WriteRespose")
+	proto.RegisterType((*RowOutcome)(nil), "pb.RowOutcome")
+	proto.RegisterType((*WriteReport)(nil), "pb.WriteReport")
+	proto.RegisterType((*CreateRequest)(nil), "pb.CreateRequest")
+	proto.RegisterMapType((map[string]*Value)(nil), "pb.CreateRequest.AttributeMapEntry")
+	proto.RegisterType((*CreateResponse)(nil), "pb.CreateResponse")
+	proto.RegisterType((*DeleteRequest)(nil), "pb.DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "pb.DeleteResponse")
+	proto.RegisterType((*ExecRequest)(nil), "pb.ExecRequest")
+	proto.RegisterMapType((map[string]*Value)(nil), "pb.ExecRequest.ArgsEntry")
+	proto.RegisterType((*ExecResponse)(nil), "pb.ExecResponse")
+	proto.RegisterType((*ListRequest)(nil), "pb.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "pb.ListResponse")
+	proto.RegisterType((*DescribeRequest)(nil), "pb.DescribeRequest")
+	proto.RegisterType((*TableInfo)(nil), "pb.TableInfo")
+	proto.RegisterMapType((map[string]*Value)(nil), "pb.TableInfo.AttributeMapEntry")
+	proto.RegisterType((*DescribeResponse)(nil), "pb.DescribeResponse")
+	proto.RegisterType((*ArgumentInfo)(nil), "pb.ArgumentInfo")
+	proto.RegisterType((*ExecCommandInfo)(nil), "pb.ExecCommandInfo")
+	proto.RegisterType((*Capabilities)(nil), "pb.Capabilities")
+	proto.RegisterType((*BackendInfo)(nil), "pb.BackendInfo")
+	proto.RegisterType((*BackendsRequest)(nil), "pb.BackendsRequest")
+	proto.RegisterType((*BackendsResponse)(nil), "pb.BackendsResponse")
+	proto.RegisterEnum("pb.DType", DType_name, DType_value)
+	proto.RegisterEnum("pb.RowStatus", RowStatus_name, RowStatus_value)
+	proto.RegisterEnum("pb.ErrorOptions", ErrorOptions_name, ErrorOptions_value)
+	proto.RegisterEnum("pb.WriteMode", WriteMode_name, WriteMode_value)
+	proto.RegisterEnum("pb.SaveMode", SaveMode_name, SaveMode_value)
+	proto.RegisterEnum("pb.Column_Kind", Column_Kind_name, Column_Kind_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// FramesClient is the client API for Frames service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FramesClient interface {
+	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (Frames_ReadClient, error)
+	Write(ctx context.Context, opts ...grpc.CallOption) (Frames_WriteClient, error)
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*ExecResponse, error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error)
+	Backends(ctx context.Context, in *BackendsRequest, opts ...grpc.CallOption) (*BackendsResponse, error)
+}
+
+type framesClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFramesClient(cc *grpc.ClientConn) FramesClient {
+	return &framesClient{cc}
+}
+
+func (c *framesClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (Frames_ReadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Frames_serviceDesc.Streams[0], "/pb.Frames/Read", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &framesReadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Frames_ReadClient interface {
+	Recv() (*Frame, error)
+	grpc.ClientStream
+}
+
+type framesReadClient struct {
+	grpc.ClientStream
 }
 
 func (x *framesReadClient) Recv() (*Frame, error) {
@@ -1812,6 +2730,33 @@ func (c *framesClient) Exec(ctx context.Context, in *ExecRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *framesClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := c.cc.Invoke(ctx, "/pb.Frames/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *framesClient) Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error) {
+	out := new(DescribeResponse)
+	err := c.cc.Invoke(ctx, "/pb.Frames/Describe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *framesClient) Backends(ctx context.Context, in *BackendsRequest, opts ...grpc.CallOption) (*BackendsResponse, error) {
+	out := new(BackendsResponse)
+	err := c.cc.Invoke(ctx, "/pb.Frames/Backends", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FramesServer is the server API for Frames service.
 type FramesServer interface {
 	Read(*ReadRequest, Frames_ReadServer) error
@@ -1819,6 +2764,9 @@ type FramesServer interface {
 	Create(context.Context, *CreateRequest) (*CreateResponse, error)
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
 	Exec(context.Context, *ExecRequest) (*ExecResponse, error)
+	List(context.Context, *ListRequest) (*ListResponse, error)
+	Describe(context.Context, *DescribeRequest) (*DescribeResponse, error)
+	Backends(context.Context, *BackendsRequest) (*BackendsResponse, error)
 }
 
 func RegisterFramesServer(s *grpc.Server, srv FramesServer) {
@@ -1926,6 +2874,60 @@ func _Frames_Exec_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Frames_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FramesServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Frames/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FramesServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Frames_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FramesServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Frames/Describe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FramesServer).Describe(ctx, req.(*DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Frames_Backends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FramesServer).Backends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Frames/Backends",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FramesServer).Backends(ctx, req.(*BackendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Frames_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.Frames",
 	HandlerType: (*FramesServer)(nil),
@@ -1942,6 +2944,18 @@ var _Frames_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Exec",
 			Handler:    _Frames_Exec_Handler,
 		},
+		{
+			MethodName: "List",
+			Handler:    _Frames_List_Handler,
+		},
+		{
+			MethodName: "Describe",
+			Handler:    _Frames_Describe_Handler,
+		},
+		{
+			MethodName: "Backends",
+			Handler:    _Frames_Backends_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1958,109 +2972,155 @@ var _Frames_serviceDesc = grpc.ServiceDesc{
 	Metadata: "frames.proto",
 }
 
-func init() { proto.RegisterFile("frames.proto", fileDescriptor_frames_19fe9b420ca9a674) }
-
-var fileDescriptor_frames_19fe9b420ca9a674 = []byte{
-	// 1612 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xcd, 0x72, 0xdb, 0xc8,
-	0x11, 0x26, 0xf8, 0x8f, 0x26, 0x25, 0xd1, 0xb3, 0x5e, 0x2f, 0xcc, 0x6c, 0x62, 0x2e, 0xb4, 0xc9,
-	0xb2, 0x76, 0x63, 0x39, 0xd1, 0x5e, 0x52, 0x7b, 0x49, 0x49, 0x36, 0x65, 0x71, 0x45, 0x5b, 0xa9,
-	0x91, 0x2a, 0x39, 0xb2, 0x86, 0xc4, 0x90, 0x9e, 0x08, 0x04, 0xe0, 0x99, 0xe1, 0xda, 0xcc, 0x21,
-	0xc7, 0xdc, 0xf3, 0x0c, 0x79, 0x8a, 0x3c, 0x42, 0x2e, 0x39, 0xa5, 0x2a, 0xb7, 0x3c, 0x45, 0xaa,
-	0x72, 0x4d, 0x75, 0x0f, 0x40, 0xc2, 0x92, 0x2b, 0x07, 0x57, 0x7c, 0x9b, 0xfe, 0xba, 0x7b, 0xd0,
-	0xfd, 0x4d, 0x77, 0xcf, 0x00, 0xba, 0x0b, 0x2d, 0x56, 0xd2, 0x1c, 0x65, 0x3a, 0xb5, 0x29, 0xab,
-	0x66, 0xb3, 0xf0, 0x4f, 0x55, 0x68, 0x3e, 0x4d, 0xe3, 0xf5, 0x2a, 0x61, 0x87, 0x50, 0xbf, 0x51,
-	0x49, 0x14, 0x78, 0x03, 0x6f, 0xb8, 0x7f, 0x7c, 0x70, 0x94, 0xcd, 0x8e, 0x9c, 0xe6, 0xe8, 0x42,
-	0x25, 0x11, 0x27, 0x25, 0x63, 0x50, 0x4f, 0xc4, 0x4a, 0x06, 0xd5, 0x81, 0x37, 0xf4, 0x39, 0xad,
-	0xd9, 0x23, 0x68, 0x44, 0x76, 0x93, 0xc9, 0xa0, 0x46, 0x9e, 0x3e, 0x7a, 0x3e, 0xbb, 0xde, 0x64,
-	0x92, 0x3b, 0x1c, 0x9d, 0x8c, 0xfa, 0x83, 0x0c, 0xea, 0x03, 0x6f, 0x58, 0xe3, 0xb4, 0x46, 0x4c,
-	0x25, 0xd6, 0x04, 0x8d, 0x41, 0x0d, 0x31, 0x5c, 0xb3, 0x07, 0xd0, 0x5c, 0xc4, 0xa9, 0xb0, 0x26,
-	0x68, 0x0e, 0x6a, 0x43, 0x8f, 0xe7, 0x12, 0x0b, 0xa0, 0x65, 0xac, 0x56, 0xc9, 0xd2, 0x04, 0xad,
-	0x41, 0x6d, 0xe8, 0xf3, 0x42, 0x64, 0xf7, 0xa1, 0x61, 0xd5, 0x4a, 0x9a, 0xa0, 0x4d, 0xdb, 0x38,
-	0x01, 0xd1, 0x59, 0x9a, 0xc6, 0x26, 0xf0, 0x07, 0xb5, 0x61, 0x9b, 0x3b, 0x21, 0xfc, 0x1c, 0xea,
-	0x98, 0x08, 0xf3, 0xa1, 0x71, 0x35, 0x19, 0x3f, 0x1d, 0xf5, 0x2a, 0xb8, 0x9c, 0x9c, 0x9c, 0x8e,
-	0x26, 0x3d, 0x2f, 0xfc, 0x23, 0x34, 0x7e, 0x2b, 0xe2, 0xb5, 0x64, 0xf7, 0xa1, 0xae, 0x7e, 0x10,
-	0x31, 0xd1, 0x50, 0x3b, 0xaf, 0x70, 0x92, 0x10, 0x5d, 0x20, 0x8a, 0x79, 0x7b, 0x88, 0x2e, 0x72,
-	0xd4, 0x20, 0x8a, 0x89, 0xfb, 0x88, 0x9a, 0x1c, 0xb5, 0x88, 0xd6, 0x8b, 0x1d, 0x6c, 0x8e, 0xce,
-	0x10, 0x6d, 0x0c, 0xbc, 0x61, 0x1b, 0x51, 0x94, 0x4e, 0x5b, 0xd0, 0xf8, 0x01, 0x3f, 0x1b, 0xfe,
-	0xcb, 0x83, 0xc6, 0x19, 0x9e, 0x0e, 0xfb, 0x12, 0x5a, 0x73, 0xe2, 0xdd, 0x04, 0xde, 0xa0, 0x36,
-	0xec, 0x1c, 0xc3, 0xee, 0x28, 0x78, 0xa1, 0x42, 0x2b, 0x95, 0x44, 0x6a, 0x2e, 0x4d, 0x50, 0xbd,
-	0x6b, 0x95, 0xab, 0xd8, 0x63, 0x68, 0xc6, 0x62, 0x26, 0x63, 0x13, 0xd4, 0xc8, 0xe8, 0x53, 0x34,
-	0xa2, 0xcf, 0x1c, 0x4d, 0x08, 0x1f, 0x25, 0x56, 0x6f, 0x78, 0x6e, 0x84, 0xc4, 0x49, 0xad, 0x53,
-	0x4d, 0xa1, 0xfb, 0xdc, 0x09, 0xfd, 0x67, 0xd0, 0x29, 0x19, 0xb3, 0x1e, 0xd4, 0x6e, 0xe4, 0x86,
-	0xf8, 0xf1, 0x39, 0x2e, 0xb1, 0x00, 0x28, 0x09, 0x62, 0xa7, 0xe3, 0x0a, 0x80, 0xc8, 0xe4, 0x0e,
-	0xff, 0xae, 0xfa, 0x2b, 0x2f, 0xfc, 0x8f, 0x07, 0x9d, 0xab, 0xf9, 0x2b, 0xb9, 0x12, 0x67, 0x4a,
-	0xc6, 0xbb, 0x4a, 0xf2, 0x4a, 0x95, 0xd4, 0x83, 0x5a, 0x94, 0xce, 0xf3, 0xe2, 0xc2, 0x25, 0x3b,
-	0x84, 0x56, 0x24, 0x17, 0x62, 0x1d, 0x5b, 0x22, 0xf9, 0x9d, 0xcd, 0x0b, 0x0d, 0x6e, 0x45, 0xf5,
-	0xe7, 0xa2, 0xa6, 0x35, 0xfb, 0x35, 0x40, 0xa6, 0xd3, 0x4c, 0x6a, 0xab, 0xa4, 0xab, 0xb2, 0xce,
-	0xf1, 0x23, 0xf4, 0x2d, 0xc5, 0x70, 0xf4, 0x9b, 0xad, 0x85, 0xe3, 0xa1, 0xe4, 0xd2, 0x3f, 0x87,
-	0x83, 0x5b, 0xea, 0x0f, 0xcd, 0xfc, 0x12, 0x7c, 0xf7, 0xd1, 0x0b, 0xb9, 0x61, 0x5f, 0x40, 0xd7,
-	0xbc, 0x12, 0x3a, 0x52, 0xc9, 0x72, 0xea, 0x36, 0xc3, 0x82, 0xee, 0x14, 0xd8, 0x05, 0x6d, 0xda,
-	0x31, 0xa9, 0xb6, 0x85, 0x45, 0x95, 0x2c, 0x20, 0x87, 0x2e, 0xe4, 0x26, 0xfc, 0x9b, 0x07, 0x9d,
-	0x6b, 0x31, 0x8b, 0xa5, 0xdb, 0x76, 0x9b, 0xbf, 0x57, 0xca, 0xff, 0x73, 0xf0, 0x91, 0x52, 0x93,
-	0x89, 0x79, 0xd1, 0xad, 0x3b, 0x60, 0x4b, 0x7e, 0xed, 0x2e, 0xf9, 0xf5, 0x1d, 0xf9, 0x01, 0xb4,
-	0x44, 0xac, 0x84, 0xc9, 0x09, 0xf4, 0x79, 0x21, 0xb2, 0xaf, 0xa0, 0xb9, 0x40, 0x06, 0x5d, 0xa7,
-	0x76, 0xdc, 0xb4, 0x28, 0x31, 0xcb, 0x73, 0x35, 0x7b, 0xe4, 0x28, 0x6b, 0x11, 0x3d, 0x7b, 0x3b,
-	0xab, 0x0b, 0xb9, 0x21, 0x06, 0xc3, 0x2e, 0xc0, 0xf7, 0xa9, 0x4a, 0xae, 0xac, 0x5e, 0xcf, 0x6d,
-	0xf8, 0x17, 0x0f, 0x5a, 0x57, 0xd2, 0x18, 0x95, 0x26, 0x18, 0xcf, 0x5a, 0xc7, 0x05, 0xdb, 0x6b,
-	0x1d, 0x63, 0x4e, 0xf3, 0x34, 0xb1, 0x42, 0x25, 0x52, 0x17, 0x39, 0x6d, 0x01, 0xcc, 0x29, 0x13,
-	0xf6, 0x55, 0x91, 0x13, 0xae, 0x11, 0x5b, 0x1b, 0x59, 0xd4, 0x33, 0xad, 0x59, 0x1f, 0xda, 0x99,
-	0x30, 0xe6, 0x4d, 0xaa, 0x23, 0x6a, 0x46, 0x9f, 0x6f, 0x65, 0x9a, 0x27, 0xe9, 0x8d, 0x4c, 0x82,
-	0xa6, 0x6b, 0x00, 0x12, 0xd8, 0x3e, 0x54, 0x55, 0x44, 0x39, 0xf8, 0xbc, 0xaa, 0xa2, 0xf0, 0xaf,
-	0x4d, 0xe8, 0x70, 0x29, 0x22, 0x2e, 0x5f, 0xaf, 0xa5, 0xb1, 0xec, 0xa7, 0xd0, 0x32, 0x2e, 0x68,
-	0x8a, 0xb6, 0x73, 0xdc, 0xa1, 0x44, 0x1d, 0xc4, 0x0b, 0x1d, 0xd2, 0x39, 0x13, 0xf3, 0x1b, 0x99,
-	0x44, 0x79, 0xf0, 0x85, 0x88, 0x74, 0x1a, 0xa2, 0x25, 0x2f, 0x72, 0xa2, 0xb3, 0x74, 0xc2, 0x3c,
-	0x57, 0x63, 0x69, 0x44, 0xc2, 0x8a, 0xe9, 0x22, 0xd5, 0x2b, 0x61, 0xf3, 0xb4, 0x00, 0xa1, 0x33,
-	0x42, 0xd8, 0x8f, 0x01, 0x74, 0xfa, 0x66, 0x1a, 0x8b, 0x4d, 0xba, 0xb6, 0x6e, 0xd6, 0x70, 0x5f,
-	0xa7, 0x6f, 0x26, 0x04, 0xa0, 0xff, 0x6a, 0x1d, 0x5b, 0x35, 0x55, 0x49, 0x24, 0xdf, 0x52, 0x96,
-	0x6d, 0x0e, 0x04, 0x8d, 0x11, 0x41, 0x02, 0x5e, 0xaf, 0xa5, 0xde, 0xe4, 0xd9, 0x3a, 0x81, 0x68,
-	0xc1, 0x68, 0x82, 0x76, 0x4e, 0x0b, 0x0a, 0x98, 0x4f, 0x31, 0xa8, 0x7c, 0x57, 0x1e, 0xc5, 0x70,
-	0xc2, 0x41, 0xae, 0x62, 0x2b, 0x75, 0x00, 0xe4, 0x90, 0x4b, 0xec, 0x21, 0xb4, 0x97, 0x3a, 0x5d,
-	0x67, 0xd3, 0xd9, 0x26, 0xe8, 0x38, 0x0a, 0x48, 0x3e, 0xdd, 0xb0, 0x10, 0xea, 0xbf, 0x4f, 0x55,
-	0x12, 0x74, 0xa9, 0x9e, 0xf6, 0x91, 0x80, 0x5d, 0x5d, 0x70, 0xd2, 0x61, 0x18, 0xb1, 0x5a, 0x29,
-	0x1b, 0xec, 0xd1, 0x45, 0xe2, 0x04, 0x76, 0x08, 0x7b, 0x2b, 0x69, 0x8c, 0x58, 0xca, 0xa9, 0xd3,
-	0xee, 0x93, 0xb6, 0x9b, 0x83, 0x13, 0x32, 0x7a, 0x00, 0xcd, 0x95, 0xd0, 0x37, 0x52, 0x07, 0x07,
-	0x2e, 0x22, 0x27, 0x61, 0x31, 0x18, 0xb9, 0x5c, 0x49, 0xbc, 0x8a, 0x7a, 0x74, 0x87, 0x6c, 0x65,
-	0xf6, 0x15, 0x1c, 0xd8, 0xd4, 0xa6, 0x22, 0x9e, 0x6e, 0x4d, 0xee, 0xd1, 0xd6, 0xfb, 0x0e, 0xbe,
-	0x2a, 0x0c, 0x0f, 0x61, 0xaf, 0xdc, 0xd3, 0x26, 0x60, 0x44, 0x47, 0xb7, 0xd4, 0xd4, 0x86, 0x3d,
-	0x81, 0xfb, 0xd8, 0xc2, 0x68, 0x30, 0xd5, 0x22, 0x59, 0xca, 0xa9, 0xb1, 0x42, 0xdb, 0xe0, 0x13,
-	0x8a, 0xe7, 0x1e, 0xea, 0xb0, 0x29, 0x50, 0x73, 0x85, 0x0a, 0xf6, 0x0d, 0xb0, 0x5b, 0x0e, 0x58,
-	0x39, 0xf7, 0xc9, 0xfc, 0xa0, 0x6c, 0x3e, 0x4a, 0xa8, 0x70, 0xdd, 0x76, 0x9f, 0xba, 0x13, 0x22,
-	0x01, 0x5b, 0x08, 0x7d, 0x1e, 0xb8, 0x16, 0x92, 0xee, 0xfe, 0x36, 0x56, 0x66, 0xc1, 0x67, 0xae,
-	0x21, 0x70, 0xcd, 0x06, 0xd0, 0x11, 0xcb, 0xa5, 0x16, 0x4b, 0x61, 0x53, 0x6d, 0x82, 0x80, 0x54,
-	0x65, 0x88, 0xbc, 0xa4, 0xbc, 0x09, 0x1e, 0xe6, 0x5e, 0x52, 0xde, 0xe0, 0x59, 0x52, 0x7e, 0x53,
-	0x15, 0x05, 0x7d, 0x77, 0x96, 0x24, 0x8f, 0x23, 0x47, 0xea, 0xeb, 0xb5, 0x4c, 0xe6, 0x32, 0xf8,
-	0x11, 0x31, 0xb6, 0x95, 0xc3, 0xbf, 0x7b, 0xf0, 0xc9, 0x38, 0x51, 0x56, 0x89, 0xf8, 0x77, 0x5a,
-	0x59, 0xf9, 0x7f, 0xeb, 0xa1, 0x6d, 0x8d, 0xd6, 0xca, 0x35, 0xfa, 0x73, 0xe8, 0x2a, 0xf7, 0xb5,
-	0x29, 0x76, 0x09, 0x75, 0x4c, 0x3e, 0xa7, 0xe9, 0x1a, 0xe4, 0x9d, 0x5c, 0xfd, 0x4c, 0x58, 0xc1,
-	0x7e, 0x02, 0x20, 0xdf, 0x66, 0x3a, 0x8f, 0xc3, 0x0d, 0x87, 0x12, 0x82, 0x3c, 0xac, 0x52, 0x2d,
-	0xf3, 0xbe, 0xa1, 0x75, 0x98, 0x40, 0xf7, 0x9d, 0x44, 0xbe, 0x85, 0x96, 0x76, 0xcb, 0x3c, 0x91,
-	0xcf, 0xf0, 0x63, 0xef, 0x49, 0xf9, 0xbc, 0xc2, 0x0b, 0x4b, 0xf6, 0x05, 0x34, 0xe8, 0x69, 0x56,
-	0xbe, 0x47, 0x28, 0xbe, 0xf3, 0x0a, 0x77, 0x9a, 0xd3, 0xa6, 0x1b, 0xf2, 0xe1, 0x77, 0xdb, 0xef,
-	0x99, 0x2c, 0x35, 0x92, 0x7a, 0x8d, 0x5e, 0x75, 0xee, 0xc5, 0xc2, 0x73, 0x09, 0x63, 0xd5, 0xe9,
-	0x1b, 0x43, 0x3b, 0xd6, 0x38, 0xad, 0xc3, 0x7f, 0x54, 0x61, 0xef, 0xa9, 0x96, 0xe2, 0xa3, 0xd3,
-	0x7e, 0x0e, 0x7b, 0xc2, 0x5a, 0xad, 0x66, 0x6b, 0x2b, 0xa7, 0x2b, 0x91, 0x05, 0x75, 0x6a, 0xeb,
-	0x43, 0x7a, 0xa3, 0x94, 0x03, 0x38, 0x3a, 0x29, 0xcc, 0x5e, 0x88, 0xcc, 0x5d, 0xc2, 0x5d, 0x51,
-	0x82, 0x4a, 0xa3, 0xb1, 0xf1, 0xbf, 0x47, 0xe3, 0x63, 0xf0, 0xd5, 0x62, 0x2a, 0xdf, 0x2a, 0x43,
-	0xef, 0x47, 0x7c, 0x89, 0xf6, 0xd0, 0x76, 0x84, 0x6f, 0x98, 0xcb, 0xcc, 0xaa, 0x34, 0x31, 0xbc,
-	0xad, 0x16, 0x23, 0xb2, 0xe8, 0x7f, 0x0f, 0xf7, 0xee, 0x7c, 0xfa, 0x43, 0x2f, 0xf8, 0x1e, 0xec,
-	0x17, 0x49, 0x99, 0x2c, 0x4d, 0x8c, 0x0c, 0xff, 0xe9, 0xc1, 0xde, 0x33, 0x19, 0xcb, 0x8f, 0x4e,
-	0xf4, 0x6e, 0xd2, 0xd6, 0xdf, 0x99, 0xb4, 0x4f, 0x00, 0xd4, 0x62, 0xba, 0x52, 0xc6, 0xa8, 0x64,
-	0x49, 0xd4, 0xbd, 0x8f, 0x0e, 0x5f, 0x2d, 0x5e, 0x38, 0x93, 0xdd, 0x00, 0x69, 0xbe, 0x67, 0x80,
-	0xb4, 0xb6, 0x03, 0x04, 0x73, 0x2d, 0x12, 0xcb, 0x73, 0xfd, 0x73, 0x15, 0x3a, 0xa3, 0xb7, 0x72,
-	0xfe, 0x91, 0x33, 0xa5, 0xdb, 0x66, 0xb5, 0x12, 0x49, 0x94, 0xa7, 0x5a, 0x88, 0xec, 0x31, 0xd4,
-	0x85, 0x5e, 0x16, 0x8f, 0xbc, 0x87, 0x94, 0xe5, 0x2e, 0x9e, 0xa3, 0x13, 0xbd, 0xcc, 0x9f, 0x77,
-	0x64, 0x76, 0xab, 0xc9, 0x9b, 0xb7, 0x9b, 0xbc, 0x7f, 0x0a, 0xfe, 0xd6, 0xe5, 0x43, 0x2b, 0x62,
-	0x1f, 0xba, 0x2e, 0x04, 0xc7, 0xd1, 0xd7, 0xa7, 0xd0, 0xa0, 0x3f, 0x22, 0xd6, 0x81, 0xd6, 0xf8,
-	0xe5, 0xf5, 0xe8, 0xf9, 0x88, 0xbb, 0xdf, 0x8f, 0xb3, 0xc9, 0xe5, 0xc9, 0x75, 0xcf, 0x63, 0x00,
-	0xcd, 0xab, 0x6b, 0x3e, 0x7e, 0xf9, 0xbc, 0x57, 0x65, 0x6d, 0xa8, 0x5f, 0x8f, 0x5f, 0x8c, 0x7a,
-	0x35, 0xb4, 0x3e, 0xbd, 0xbc, 0x9c, 0x8c, 0x4e, 0x5e, 0xf6, 0xea, 0x5f, 0x7f, 0x09, 0xdd, 0xf2,
-	0xe1, 0xa1, 0xd9, 0xd9, 0xc9, 0x78, 0xd2, 0xab, 0xa0, 0xf3, 0xf8, 0xf9, 0xcb, 0x4b, 0x3e, 0xea,
-	0x79, 0xc7, 0xff, 0xf6, 0xa0, 0x79, 0xe6, 0x26, 0xc0, 0xcf, 0xa0, 0x8e, 0xaf, 0x14, 0x46, 0x2d,
-	0x53, 0x7a, 0xaf, 0xf4, 0x77, 0xe3, 0x25, 0xac, 0xfc, 0xc2, 0x63, 0x4f, 0xa0, 0x41, 0x13, 0x85,
-	0x51, 0x81, 0x94, 0x47, 0x54, 0xbf, 0x8c, 0xd0, 0xb8, 0x09, 0x2b, 0x43, 0x8f, 0xfd, 0x12, 0x9a,
-	0xae, 0xde, 0xd9, 0xbd, 0x3b, 0x0d, 0xdd, 0x67, 0x65, 0x28, 0x2f, 0x91, 0x0a, 0xba, 0xb8, 0xb2,
-	0x71, 0x2e, 0xef, 0xf4, 0x86, 0x73, 0xb9, 0x55, 0x55, 0x15, 0xf6, 0x0d, 0xd4, 0x91, 0x43, 0x17,
-	0x7e, 0xe9, 0x40, 0x5d, 0x50, 0x65, 0x7a, 0xc3, 0xca, 0xac, 0x49, 0xbf, 0xb4, 0xdf, 0xfe, 0x37,
-	0x00, 0x00, 0xff, 0xff, 0x23, 0xaa, 0x32, 0x13, 0xe2, 0x0e, 0x00, 0x00,
+func init() { proto.RegisterFile("frames.proto", fileDescriptor_frames_6c37051373f3aa3f) }
+
+var fileDescriptor_frames_6c37051373f3aa3f = []byte{
+	// 2344 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xcd, 0x6f, 0x1b, 0xc7,
+	0x15, 0xd7, 0x72, 0xf9, 0xb5, 0x8f, 0x94, 0xb4, 0x1a, 0x2b, 0xce, 0x86, 0x4d, 0x6b, 0x79, 0x65,
+	0x27, 0x8a, 0x1d, 0xcb, 0xad, 0x52, 0x20, 0x46, 0x80, 0x20, 0xd0, 0x07, 0x65, 0x33, 0xa6, 0x45,
+	0x61, 0x25, 0xc7, 0xa7, 0x82, 0x18, 0x92, 0x43, 0x7a, 0xab, 0xe5, 0x2e, 0xbd, 0xb3, 0xb4, 0xac,
+	0xa2, 0x28, 0x8a, 0xfe, 0x01, 0xbd, 0xf4, 0xda, 0x5b, 0x8f, 0xfd, 0x4b, 0x8a, 0x1e, 0x7b, 0xeb,
+	0xa1, 0x7f, 0x42, 0x81, 0x9e, 0x7a, 0x2d, 0xde, 0x9b, 0xd9, 0x0f, 0x4a, 0x56, 0x0a, 0x18, 0xf6,
+	0x6d, 0xde, 0x6f, 0xde, 0xcc, 0xbc, 0xf9, 0xcd, 0xfb, 0xda, 0x85, 0xe6, 0x38, 0xe6, 0x53, 0x21,
+	0xb7, 0x67, 0x71, 0x94, 0x44, 0xac, 0x34, 0x1b, 0xb8, 0x7f, 0x2e, 0x41, 0x75, 0x3f, 0x0a, 0xe6,
+	0xd3, 0x90, 0x6d, 0x42, 0xf9, 0xcc, 0x0f, 0x47, 0x8e, 0xb1, 0x61, 0x6c, 0xad, 0xec, 0xac, 0x6e,
+	0xcf, 0x06, 0xdb, 0x6a, 0x66, 0xfb, 0xa9, 0x1f, 0x8e, 0x3c, 0x9a, 0x64, 0x0c, 0xca, 0x21, 0x9f,
+	0x0a, 0xa7, 0xb4, 0x61, 0x6c, 0x59, 0x1e, 0x8d, 0xd9, 0x2d, 0xa8, 0x8c, 0x92, 0x8b, 0x99, 0x70,
+	0x4c, 0x5a, 0x69, 0xe1, 0xca, 0x83, 0xd3, 0x8b, 0x99, 0xf0, 0x14, 0x8e, 0x8b, 0xa4, 0xff, 0x1b,
+	0xe1, 0x94, 0x37, 0x8c, 0x2d, 0xd3, 0xa3, 0x31, 0x62, 0x7e, 0x98, 0x48, 0xa7, 0xb2, 0x61, 0x22,
+	0x86, 0x63, 0x76, 0x13, 0xaa, 0xe3, 0x20, 0xe2, 0x89, 0x74, 0xaa, 0x1b, 0xe6, 0x96, 0xe1, 0x69,
+	0x89, 0x39, 0x50, 0x93, 0x49, 0xec, 0x87, 0x13, 0xe9, 0xd4, 0x36, 0xcc, 0x2d, 0xcb, 0x4b, 0x45,
+	0xb6, 0x0e, 0x95, 0xc4, 0x9f, 0x0a, 0xe9, 0xd4, 0x69, 0x1b, 0x25, 0x20, 0x3a, 0x88, 0xa2, 0x40,
+	0x3a, 0xd6, 0x86, 0xb9, 0x55, 0xf7, 0x94, 0x80, 0x68, 0x38, 0x0f, 0x02, 0xe9, 0x80, 0x42, 0x49,
+	0x70, 0x3f, 0x85, 0x32, 0x5e, 0x8f, 0x59, 0x50, 0x39, 0xe9, 0x76, 0xf6, 0xdb, 0xf6, 0x12, 0x0e,
+	0xbb, 0xbb, 0x7b, 0xed, 0xae, 0x6d, 0xb8, 0xbf, 0x83, 0xca, 0x0f, 0x3c, 0x98, 0x0b, 0xb6, 0x0e,
+	0x65, 0xff, 0x35, 0x0f, 0x88, 0x1c, 0xf3, 0xc9, 0x92, 0x47, 0x12, 0xa2, 0x63, 0x44, 0x91, 0x0d,
+	0x03, 0xd1, 0xb1, 0x46, 0x25, 0xa2, 0x48, 0x87, 0x85, 0xa8, 0xd4, 0x68, 0x82, 0x68, 0x39, 0xdd,
+	0x21, 0xd1, 0xe8, 0x00, 0xd1, 0xca, 0x86, 0xb1, 0x55, 0x47, 0x14, 0xa5, 0xbd, 0x1a, 0x54, 0x5e,
+	0xe3, 0xb1, 0xee, 0xbf, 0x0c, 0xa8, 0x1c, 0xe2, 0x9b, 0xb1, 0x3b, 0x50, 0x1b, 0xd2, 0x6b, 0x48,
+	0xc7, 0xd8, 0x30, 0xb7, 0x1a, 0x3b, 0x90, 0x3f, 0x90, 0x97, 0x4e, 0xa1, 0x96, 0x1f, 0x8e, 0xfc,
+	0xa1, 0x90, 0x4e, 0xe9, 0xaa, 0x96, 0x9e, 0x62, 0x0f, 0xa0, 0x1a, 0xf0, 0x81, 0x08, 0xa4, 0x63,
+	0x92, 0xd2, 0x47, 0xa8, 0x44, 0xc7, 0x6c, 0x77, 0x09, 0x6f, 0x87, 0x49, 0x7c, 0xe1, 0x69, 0x25,
+	0x24, 0x4e, 0xc4, 0x71, 0x14, 0x93, 0xe9, 0x96, 0xa7, 0x84, 0xd6, 0x01, 0x34, 0x0a, 0xca, 0xcc,
+	0x06, 0xf3, 0x4c, 0x5c, 0x10, 0x3f, 0x96, 0x87, 0x43, 0x76, 0x4b, 0x5f, 0x82, 0xd8, 0x69, 0x28,
+	0xb7, 0x20, 0x32, 0x3d, 0x85, 0x7f, 0x53, 0x7a, 0x64, 0xb8, 0xff, 0x35, 0xa0, 0x71, 0x32, 0x7c,
+	0x29, 0xa6, 0xfc, 0xd0, 0x17, 0x41, 0xee, 0x5f, 0x46, 0xc1, 0xbf, 0x6c, 0x30, 0x47, 0xd1, 0x50,
+	0xbb, 0x1c, 0x0e, 0xd9, 0x26, 0xd4, 0x46, 0x62, 0xcc, 0xe7, 0x41, 0xe2, 0x98, 0x97, 0x37, 0x4f,
+	0x67, 0x70, 0x2b, 0xf2, 0x4a, 0x65, 0x35, 0x8d, 0xd9, 0x77, 0x00, 0xb3, 0x38, 0x9a, 0x89, 0x38,
+	0xf1, 0x85, 0xf2, 0xbd, 0xc6, 0xce, 0x2d, 0x5c, 0x5b, 0xb0, 0x61, 0xfb, 0x38, 0xd3, 0x50, 0x3c,
+	0x14, 0x96, 0xb4, 0x9e, 0xc0, 0xea, 0xa5, 0xe9, 0x77, 0xbd, 0x79, 0x0f, 0x2c, 0x75, 0xe8, 0x53,
+	0x71, 0xc1, 0x6e, 0x43, 0x53, 0xbe, 0xe4, 0xf1, 0xc8, 0x0f, 0x27, 0x7d, 0xb5, 0x19, 0xba, 0x79,
+	0x23, 0xc5, 0x9e, 0xd2, 0xa6, 0x0d, 0x19, 0xc5, 0x49, 0xaa, 0x51, 0x22, 0x0d, 0xd0, 0xd0, 0x53,
+	0x71, 0xe1, 0xfe, 0xcd, 0x80, 0xc6, 0x29, 0x1f, 0x04, 0x42, 0x6d, 0x9b, 0xdd, 0xdf, 0x28, 0xdc,
+	0xff, 0x53, 0xb0, 0x90, 0x52, 0x39, 0xe3, 0xc3, 0x34, 0x86, 0x73, 0x20, 0x23, 0xdf, 0xbc, 0x4a,
+	0x7e, 0x39, 0x27, 0xdf, 0x81, 0x1a, 0x0f, 0x7c, 0x2e, 0x35, 0x81, 0x96, 0x97, 0x8a, 0xec, 0x73,
+	0xa8, 0x8e, 0x91, 0x41, 0x15, 0xbf, 0x0d, 0x95, 0x43, 0x0a, 0xcc, 0x7a, 0x7a, 0x9a, 0xdd, 0x52,
+	0x94, 0xd5, 0x88, 0x9e, 0xe5, 0x5c, 0xeb, 0xa9, 0xb8, 0x20, 0x06, 0xdd, 0x26, 0xc0, 0xf7, 0x91,
+	0x1f, 0x9e, 0x24, 0xf1, 0x7c, 0x98, 0xb8, 0x7f, 0x31, 0xa0, 0x76, 0x22, 0xa4, 0xf4, 0xa3, 0x10,
+	0xed, 0x99, 0xc7, 0x41, 0xca, 0xf6, 0x3c, 0x0e, 0xf0, 0x4e, 0xc3, 0x28, 0x4c, 0xb8, 0x1f, 0x8a,
+	0x38, 0xbd, 0x53, 0x06, 0xe0, 0x9d, 0x66, 0x3c, 0x79, 0x99, 0xde, 0x09, 0xc7, 0x88, 0xcd, 0xa5,
+	0x48, 0xfd, 0x99, 0xc6, 0xac, 0x05, 0xf5, 0x19, 0x97, 0xf2, 0x3c, 0x8a, 0x47, 0x14, 0x8c, 0x96,
+	0x97, 0xc9, 0x94, 0x65, 0xa2, 0x33, 0x11, 0x3a, 0x55, 0x15, 0x00, 0x24, 0xb0, 0x15, 0x28, 0xf9,
+	0x23, 0xba, 0x83, 0xe5, 0x95, 0xfc, 0x91, 0xfb, 0x8f, 0x2a, 0x34, 0x3c, 0xc1, 0x47, 0x9e, 0x78,
+	0x35, 0x17, 0x32, 0x61, 0x77, 0xa1, 0x26, 0x95, 0xd1, 0x64, 0x6d, 0x63, 0xa7, 0x41, 0x17, 0x55,
+	0x90, 0x97, 0xce, 0x21, 0x9d, 0x03, 0x3e, 0x3c, 0x13, 0xe1, 0x48, 0x1b, 0x9f, 0x8a, 0x48, 0xa7,
+	0x24, 0x5a, 0xb4, 0x93, 0x13, 0x9d, 0x85, 0x17, 0xf6, 0xf4, 0x34, 0xba, 0xc6, 0x88, 0x27, 0xbc,
+	0x3f, 0x8e, 0xe2, 0x29, 0x4f, 0xf4, 0xb5, 0x00, 0xa1, 0x43, 0x42, 0xd8, 0x4f, 0x01, 0xe2, 0xe8,
+	0xbc, 0x1f, 0xf0, 0x8b, 0x68, 0x9e, 0xa8, 0x5c, 0xe3, 0x59, 0x71, 0x74, 0xde, 0x25, 0x00, 0xd7,
+	0x4f, 0xe7, 0x41, 0xe2, 0xf7, 0xfd, 0x70, 0x24, 0xde, 0xd0, 0x2d, 0xeb, 0x1e, 0x10, 0xd4, 0x41,
+	0x04, 0x09, 0x78, 0x35, 0x17, 0xf1, 0x85, 0xbe, 0xad, 0x12, 0x10, 0x4d, 0xd0, 0x1a, 0xa7, 0xae,
+	0x50, 0x12, 0xf0, 0x3e, 0x69, 0xa2, 0xb2, 0x94, 0x7b, 0x68, 0x91, 0xd2, 0xbb, 0x1f, 0x24, 0x22,
+	0x76, 0x80, 0x16, 0x68, 0x89, 0x7d, 0x02, 0xf5, 0x49, 0x1c, 0xcd, 0x67, 0xfd, 0xc1, 0x85, 0xd3,
+	0x50, 0x14, 0x90, 0xbc, 0x77, 0xc1, 0x5c, 0x28, 0xff, 0x3a, 0xf2, 0x43, 0xa7, 0x49, 0xfe, 0xb4,
+	0x82, 0x04, 0xe4, 0x7e, 0xe1, 0xd1, 0x1c, 0x9a, 0x11, 0xf8, 0x53, 0x3f, 0x71, 0x96, 0xa9, 0xbc,
+	0x28, 0x81, 0x6d, 0xc2, 0xf2, 0x54, 0x48, 0xc9, 0x27, 0xa2, 0xaf, 0x66, 0x57, 0x68, 0xb6, 0xa9,
+	0xc1, 0x2e, 0x29, 0xdd, 0x84, 0xea, 0x94, 0xc7, 0x67, 0x22, 0x76, 0x56, 0x95, 0x45, 0x4a, 0x42,
+	0x67, 0x90, 0x62, 0x32, 0x15, 0x58, 0xa0, 0x6c, 0xaa, 0x2c, 0x99, 0xcc, 0x3e, 0x87, 0xd5, 0x24,
+	0x4a, 0x22, 0x1e, 0xf4, 0x33, 0x95, 0x35, 0xda, 0x7a, 0x45, 0xc1, 0x27, 0xa9, 0xe2, 0x26, 0x2c,
+	0x17, 0x63, 0x5a, 0x3a, 0x8c, 0xe8, 0x68, 0x16, 0x82, 0x5a, 0xb2, 0x87, 0xb0, 0x8e, 0x21, 0x8c,
+	0x0a, 0xfd, 0x98, 0x87, 0x13, 0xd1, 0x97, 0x09, 0x8f, 0x13, 0xe7, 0x06, 0xd9, 0xb3, 0x86, 0x73,
+	0x18, 0x14, 0x38, 0x73, 0x82, 0x13, 0xec, 0x3e, 0xb0, 0x4b, 0x0b, 0xd0, 0x73, 0xd6, 0x49, 0x7d,
+	0xb5, 0xa8, 0xde, 0x0e, 0xc9, 0x71, 0xd5, 0x76, 0x1f, 0xa9, 0x17, 0x22, 0x01, 0x43, 0x08, 0xd7,
+	0xdc, 0x54, 0x21, 0x24, 0x54, 0x55, 0x97, 0x89, 0x98, 0x39, 0x1f, 0xab, 0x80, 0xc0, 0x31, 0xdb,
+	0x80, 0x06, 0x9f, 0x4c, 0x62, 0x3e, 0xe1, 0x49, 0x14, 0x4b, 0xc7, 0xa1, 0xa9, 0x22, 0x44, 0xab,
+	0x84, 0x38, 0x73, 0x3e, 0xd1, 0xab, 0x84, 0x38, 0xc3, 0xb7, 0xa4, 0xfb, 0xf5, 0xfd, 0x91, 0xd3,
+	0x52, 0x6f, 0x49, 0x72, 0x67, 0xa4, 0x48, 0x7d, 0x35, 0x17, 0xe1, 0x50, 0x38, 0x3f, 0x21, 0xc6,
+	0x32, 0x19, 0x9d, 0x06, 0x4b, 0x37, 0x7a, 0xe7, 0xa7, 0x34, 0x95, 0x8a, 0xee, 0xef, 0x4d, 0xb8,
+	0xd1, 0x09, 0xfd, 0xc4, 0xe7, 0xc1, 0x8b, 0xd8, 0x4f, 0xc4, 0x7b, 0x8b, 0xae, 0xcc, 0x7b, 0xcd,
+	0xa2, 0xf7, 0x7e, 0x09, 0x4d, 0x5f, 0x9d, 0xd6, 0xc7, 0xf8, 0x71, 0xca, 0x79, 0x06, 0xa7, 0x02,
+	0xe9, 0x35, 0xf4, 0xf4, 0x01, 0x4f, 0x38, 0xfb, 0x19, 0x80, 0x78, 0x33, 0x8b, 0xb5, 0x1d, 0x2a,
+	0x6d, 0x14, 0x10, 0x64, 0x68, 0x1a, 0xc5, 0x42, 0x47, 0x14, 0x8d, 0x2f, 0x07, 0x6b, 0xed, 0x4a,
+	0xb0, 0x7e, 0x01, 0x96, 0xe4, 0xaf, 0x45, 0x7f, 0x1a, 0x8d, 0x54, 0x68, 0xad, 0xec, 0x34, 0xe9,
+	0x6e, 0xfc, 0xb5, 0x78, 0x16, 0x8d, 0x84, 0x57, 0x97, 0x7a, 0x54, 0xa4, 0xcd, 0x5a, 0xa0, 0x4d,
+	0x27, 0xc5, 0x91, 0x9f, 0xa0, 0x61, 0x90, 0x25, 0x45, 0x05, 0xb0, 0x2f, 0x01, 0xce, 0x91, 0x4c,
+	0x75, 0x46, 0x83, 0xce, 0xa0, 0x34, 0x4c, 0x14, 0xd3, 0x21, 0xd6, 0x79, 0x3a, 0x74, 0x43, 0x68,
+	0x2e, 0x50, 0xff, 0x15, 0xd4, 0x62, 0x35, 0xd4, 0xd4, 0x7f, 0x8c, 0x4b, 0xdf, 0xf2, 0x48, 0x4f,
+	0x96, 0xbc, 0x54, 0x93, 0xdd, 0x86, 0x0a, 0x35, 0x9f, 0x4e, 0xe9, 0x12, 0xa3, 0x4f, 0x96, 0x3c,
+	0x35, 0xb3, 0x57, 0x55, 0x05, 0xcb, 0x1d, 0x66, 0xe7, 0xc9, 0x59, 0x24, 0x05, 0xe5, 0x0d, 0x54,
+	0x90, 0xaa, 0xfb, 0xf2, 0xb4, 0x84, 0xec, 0xc6, 0xd1, 0xb9, 0xa4, 0x1d, 0x4d, 0x8f, 0xc6, 0x98,
+	0x33, 0x63, 0x31, 0x8b, 0xe2, 0xa4, 0x98, 0x33, 0xf5, 0x6e, 0x08, 0x7b, 0x7a, 0xda, 0x9d, 0x02,
+	0x78, 0xd1, 0x79, 0x6f, 0x9e, 0x0c, 0x23, 0x55, 0xe5, 0xe2, 0xe8, 0x5c, 0xef, 0x8f, 0xc3, 0xb4,
+	0xaa, 0x97, 0xf2, 0xaa, 0x7e, 0x17, 0xaa, 0x32, 0xe1, 0xc9, 0x5c, 0x3a, 0x66, 0x4e, 0x98, 0x17,
+	0x9d, 0x9f, 0x10, 0xe8, 0xe9, 0xc9, 0xb7, 0x77, 0x4b, 0xee, 0x1f, 0x0d, 0x68, 0x14, 0xcc, 0xa0,
+	0x22, 0x3a, 0x9b, 0x05, 0xbe, 0x18, 0xe9, 0x43, 0x53, 0x91, 0x7d, 0x01, 0x76, 0xf6, 0x50, 0xfd,
+	0x31, 0xf7, 0x03, 0x31, 0xd2, 0x37, 0x5c, 0xcd, 0xf0, 0x43, 0x82, 0x89, 0x18, 0xa5, 0x60, 0x6a,
+	0x62, 0x14, 0xee, 0x6a, 0x62, 0xca, 0x79, 0xd6, 0xcc, 0xef, 0xaa, 0x88, 0x72, 0xff, 0x5d, 0x82,
+	0xe5, 0xfd, 0x58, 0xf0, 0x0f, 0x1e, 0x51, 0x4f, 0x60, 0x99, 0x27, 0x49, 0xec, 0x0f, 0xe6, 0xe8,
+	0x6f, 0x7c, 0xa6, 0xad, 0xda, 0xa4, 0xc6, 0xb4, 0x68, 0xc0, 0xf6, 0x6e, 0xaa, 0xf6, 0x8c, 0xcf,
+	0x54, 0xe7, 0xd5, 0xe4, 0x05, 0xa8, 0x50, 0x0f, 0x2b, 0x3f, 0x5e, 0x0f, 0x1f, 0x80, 0xe5, 0x8f,
+	0xfb, 0xe2, 0x8d, 0x2f, 0xe9, 0x53, 0x02, 0x1f, 0xcb, 0x46, 0xdd, 0x36, 0x3e, 0x45, 0x6f, 0x86,
+	0x0c, 0x4a, 0xaf, 0xee, 0x8f, 0xdb, 0xa4, 0x51, 0x8c, 0xa2, 0xda, 0x42, 0x14, 0xb5, 0xbe, 0x87,
+	0xb5, 0x2b, 0x46, 0xbd, 0x6b, 0xbf, 0x67, 0xc3, 0x4a, 0x7a, 0x5d, 0x39, 0x8b, 0x42, 0x29, 0xdc,
+	0xff, 0x18, 0xb0, 0x7c, 0x20, 0x02, 0xf1, 0xc1, 0x9f, 0x20, 0x2f, 0xbc, 0xe5, 0x85, 0xc2, 0xfb,
+	0x10, 0xc0, 0x1f, 0xf7, 0xa7, 0xbe, 0x94, 0x7e, 0x38, 0x71, 0x2a, 0xd7, 0x10, 0x65, 0xf9, 0xe3,
+	0x67, 0x4a, 0x25, 0xaf, 0x27, 0xd5, 0xb7, 0xd4, 0x93, 0x5a, 0x5e, 0x4f, 0x0a, 0x8c, 0xd6, 0x17,
+	0xd3, 0xb9, 0x0d, 0x2b, 0xe9, 0x95, 0x35, 0x0b, 0x7f, 0x2d, 0x41, 0xa3, 0xfd, 0x46, 0x0c, 0x3f,
+	0x30, 0x07, 0xd4, 0x96, 0x4c, 0xa7, 0x3c, 0x1c, 0x69, 0x12, 0x52, 0x91, 0x3d, 0x80, 0x32, 0x8f,
+	0x27, 0xe9, 0xd7, 0xc0, 0x27, 0x74, 0xff, 0xdc, 0x9e, 0xed, 0xdd, 0x78, 0xa2, 0xbf, 0x03, 0x48,
+	0xed, 0x52, 0xce, 0xaf, 0x5e, 0xc9, 0xf9, 0xd7, 0x7b, 0xd3, 0x1e, 0x58, 0xd9, 0x66, 0xef, 0xea,
+	0x45, 0x0f, 0xa1, 0xa9, 0x8c, 0x53, 0xec, 0xe1, 0x22, 0x95, 0x56, 0x8d, 0xcb, 0x85, 0x4a, 0xe1,
+	0xee, 0x6f, 0xa1, 0xd1, 0xf5, 0x65, 0xf2, 0xde, 0xd8, 0x7d, 0x5b, 0x3f, 0x5d, 0xb8, 0x72, 0x79,
+	0xf1, 0xb9, 0x3f, 0x83, 0xa6, 0x3a, 0x5d, 0x9b, 0x7b, 0x13, 0xaa, 0xf4, 0x1c, 0x52, 0x7f, 0xe1,
+	0x68, 0xc9, 0xfd, 0x83, 0x01, 0xab, 0x07, 0x42, 0x0e, 0x63, 0x7f, 0x20, 0x3e, 0xbc, 0x23, 0x5c,
+	0x63, 0xec, 0x3f, 0x0d, 0xb0, 0x28, 0x9d, 0x74, 0xc2, 0x71, 0xf4, 0xd6, 0x2f, 0xd1, 0x3c, 0x03,
+	0x95, 0x7e, 0x3c, 0x03, 0x1d, 0x5c, 0x4e, 0x7a, 0x66, 0xfe, 0xa9, 0x99, 0x1d, 0xf1, 0xff, 0x12,
+	0xde, 0x7b, 0x4d, 0x3f, 0x5f, 0x83, 0x9d, 0x13, 0xac, 0x5f, 0x63, 0x33, 0x25, 0xc8, 0xc8, 0x3f,
+	0xc4, 0x32, 0xeb, 0x34, 0x5f, 0xee, 0x2b, 0x68, 0xee, 0xc6, 0x93, 0x39, 0xf6, 0xb4, 0xd7, 0xf2,
+	0x92, 0xfd, 0x01, 0x2a, 0x5d, 0xf3, 0x07, 0xa8, 0x05, 0x75, 0x6c, 0x04, 0xfc, 0x58, 0xd7, 0xaa,
+	0xba, 0x97, 0xc9, 0x57, 0xbf, 0x30, 0xdd, 0x5f, 0xc1, 0x2a, 0x3a, 0xf9, 0xbe, 0x0a, 0xd0, 0x6b,
+	0x4f, 0xbd, 0xa3, 0x03, 0x57, 0xfd, 0xe9, 0xa0, 0xc4, 0x55, 0xb4, 0x54, 0xc7, 0xab, 0xde, 0xde,
+	0xcc, 0xb7, 0xff, 0x7b, 0x09, 0x9a, 0xfb, 0x7c, 0xc6, 0x07, 0x7e, 0xe0, 0x27, 0xbe, 0xa0, 0x90,
+	0xc6, 0x4f, 0x7a, 0x4e, 0xf9, 0x4e, 0x7b, 0x66, 0x01, 0x61, 0x8f, 0x60, 0x59, 0xbc, 0x11, 0xc3,
+	0xbe, 0xce, 0x18, 0xe9, 0x89, 0x37, 0xd2, 0x54, 0x51, 0x30, 0xd4, 0x6b, 0x8a, 0x1c, 0x90, 0xec,
+	0x2e, 0xac, 0xa8, 0x5c, 0xdb, 0x1f, 0xf9, 0x3c, 0x10, 0xc3, 0x44, 0xdb, 0xb1, 0xac, 0xd0, 0x03,
+	0x05, 0x62, 0xcd, 0xd7, 0x6a, 0xea, 0x54, 0x6c, 0xb8, 0xcb, 0x64, 0xc6, 0xaa, 0xc2, 0x7b, 0x29,
+	0xcc, 0x6e, 0x43, 0x95, 0x28, 0x55, 0xf9, 0x6a, 0x81, 0x6b, 0x3d, 0xc1, 0xbe, 0x85, 0xb5, 0x21,
+	0x55, 0x9a, 0x7e, 0xe6, 0x4d, 0xe9, 0x17, 0xf9, 0x55, 0x92, 0x6c, 0xa5, 0x9a, 0xf9, 0x19, 0xfe,
+	0x1d, 0x62, 0x59, 0xa3, 0xc1, 0x83, 0x3e, 0xf5, 0x81, 0x92, 0x72, 0x59, 0xdd, 0x5b, 0x2b, 0xcc,
+	0x50, 0x3b, 0x23, 0xdd, 0x33, 0x68, 0xec, 0xa9, 0x80, 0xbb, 0xf6, 0xa1, 0xd2, 0x3f, 0x11, 0xa5,
+	0xc2, 0x9f, 0x88, 0x5f, 0x42, 0x73, 0x58, 0x78, 0x03, 0xdd, 0xae, 0x91, 0x7d, 0xc5, 0xb7, 0xf1,
+	0x16, 0xb4, 0xdc, 0x35, 0x58, 0xd5, 0x87, 0x49, 0x9d, 0x26, 0xdc, 0xef, 0xc0, 0xce, 0x21, 0xed,
+	0xd8, 0xf7, 0xa1, 0xae, 0x93, 0x40, 0xfa, 0xb7, 0x8c, 0x22, 0xb5, 0x60, 0xa7, 0x97, 0x29, 0xdc,
+	0xdb, 0x83, 0x0a, 0xf1, 0xc7, 0x1a, 0x50, 0xeb, 0x1c, 0x9d, 0xb6, 0x1f, 0xb7, 0x3d, 0xf5, 0x13,
+	0xf0, 0xb0, 0xdb, 0xdb, 0x3d, 0xb5, 0x0d, 0x06, 0x50, 0x3d, 0x39, 0xf5, 0x3a, 0x47, 0x8f, 0xed,
+	0x12, 0xab, 0x43, 0xf9, 0xb4, 0xf3, 0xac, 0x6d, 0x9b, 0xa8, 0xbd, 0xd7, 0xeb, 0x75, 0xdb, 0xbb,
+	0x47, 0x76, 0xf9, 0xde, 0x37, 0x60, 0x65, 0x9d, 0x20, 0xce, 0xec, 0x1e, 0x1f, 0x77, 0x3b, 0xed,
+	0x03, 0x7b, 0x89, 0xad, 0x83, 0xbd, 0xdf, 0x3b, 0x3a, 0xe8, 0x9c, 0x76, 0x7a, 0x47, 0xfd, 0xc3,
+	0xdd, 0x4e, 0xb7, 0x7d, 0xa0, 0xb6, 0xd4, 0xe3, 0xd2, 0xbd, 0x3b, 0xd0, 0x2c, 0xd6, 0x5b, 0x3c,
+	0x02, 0xe7, 0xec, 0x25, 0xd4, 0xea, 0x3c, 0x3e, 0xea, 0x79, 0x6d, 0xdb, 0xb8, 0xf7, 0x08, 0xac,
+	0xac, 0x39, 0x67, 0x35, 0x30, 0x8f, 0x9f, 0x9f, 0x2a, 0x8d, 0xe7, 0xc7, 0x27, 0x6d, 0x4f, 0x9b,
+	0xf9, 0xfc, 0xf8, 0x60, 0xf7, 0xb4, 0x6d, 0x97, 0x68, 0xe5, 0x11, 0xe1, 0xe6, 0xbd, 0x6f, 0xa1,
+	0x9e, 0x7e, 0x3a, 0xb0, 0x65, 0xb0, 0x7a, 0x3f, 0xb4, 0xbd, 0x17, 0x5e, 0xe7, 0xb4, 0xad, 0x96,
+	0xef, 0x1e, 0x1f, 0xb7, 0x8f, 0xd0, 0xa4, 0x9b, 0xc0, 0xd4, 0xb8, 0x7f, 0xd4, 0x7e, 0xd1, 0xdf,
+	0xef, 0x75, 0x9f, 0x3f, 0x3b, 0x3a, 0xb1, 0x4b, 0x3b, 0x7f, 0x32, 0xa1, 0x7a, 0xa8, 0x1a, 0xee,
+	0xcf, 0xa0, 0x8c, 0x3f, 0x38, 0x18, 0x91, 0x59, 0xf8, 0xd5, 0xd1, 0xca, 0xcb, 0x8e, 0xbb, 0xf4,
+	0x73, 0x83, 0x3d, 0x84, 0x0a, 0xd9, 0xca, 0xec, 0x42, 0xf7, 0xad, 0x34, 0x8b, 0x08, 0x75, 0xf7,
+	0xee, 0xd2, 0x96, 0xc1, 0x7e, 0x01, 0x55, 0xd5, 0x1b, 0xb1, 0xb5, 0x2b, 0x6d, 0x61, 0x8b, 0x15,
+	0x21, 0xdd, 0x34, 0x2c, 0xe1, 0x12, 0xd5, 0x48, 0xa8, 0x25, 0x0b, 0x7d, 0x54, 0x8b, 0x15, 0xa1,
+	0x6c, 0xc9, 0x7d, 0x28, 0x63, 0xb4, 0x2a, 0xf3, 0x0b, 0x25, 0xbe, 0x65, 0xe7, 0x40, 0x51, 0x19,
+	0x2b, 0x97, 0x52, 0x2e, 0x54, 0xd0, 0x96, 0x9d, 0x03, 0x99, 0xf2, 0xd7, 0x50, 0x4f, 0x93, 0x2b,
+	0xbb, 0xa1, 0xce, 0x5e, 0xa8, 0x65, 0xad, 0xf5, 0x45, 0xb0, 0xb8, 0x30, 0x75, 0x5e, 0xb5, 0xf0,
+	0x92, 0x77, 0xb7, 0xd6, 0x17, 0xc1, 0x74, 0xe1, 0xa0, 0x4a, 0xbf, 0xf0, 0xbf, 0xfa, 0xdf, 0x00,
+	0x04, 0xd9, 0x8e, 0x76, 0xd2, 0x17, 0x00, 0x00,
 }
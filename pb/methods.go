@@ -109,6 +109,26 @@ func (r *CreateRequest) SetAttribute(key string, value interface{}) error {
 	return nil
 }
 
+// Attributes return the attibutes
+func (t *TableInfo) Attributes() map[string]interface{} {
+	return AsGoMap(t.AttributeMap)
+}
+
+// SetAttribute sets an attribute
+func (t *TableInfo) SetAttribute(key string, value interface{}) error {
+	if t.AttributeMap == nil {
+		t.AttributeMap = make(map[string]*Value)
+	}
+
+	pbVal := &Value{}
+	if err := pbVal.SetValue(value); err != nil {
+		return err
+	}
+
+	t.AttributeMap[key] = pbVal
+	return nil
+}
+
 // NSToTime returns time from epoch nanoseconds
 func NSToTime(ns int64) time.Time {
 	return time.Unix(ns/1e9, ns%1e9)
@@ -160,6 +160,10 @@ func (it *rowIterator) getRow() (map[string]interface{}, error) {
 			return nil, err
 		}
 
+		if col.IsNull(it.rowNum) {
+			value = nil
+		}
+
 		name := col.Name()
 		if name == "" {
 			name = it.noNames[colNum]
@@ -22,17 +22,34 @@ package frames
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/xwb1989/sqlparser"
 )
 
 // Query is query structure
 type Query struct {
-	Table   string
+	Table string
+	// Selected columns, either column names or SQL expressions (e.g. "avg(cpu)",
+	// "cpu * 100"). Empty for SELECT *
 	Columns []string
+	// Output name per column ("" if there's no AS), nil if there are no aliases
+	Aliases []string
 	Filter  string
 	GroupBy string
+	OrderBy []OrderBy
+	Limit   int64 // Valid only if HasLimit is set
+	Offset  int64
+	// Query has a LIMIT clause (LIMIT 0 returns no rows)
+	HasLimit bool
+}
+
+// OrderBy is an ORDER BY clause key
+type OrderBy struct {
+	Column     string // Column name, alias or SQL expression
+	Descending bool
 }
 
 // ParseSQL parsers SQL query to a Query struct
@@ -65,28 +82,33 @@ func ParseSQL(sql string) (*Query, error) {
 		Table: table.Name.String(),
 	}
 
+	var aliases []string
+	hasAlias, selectAll := false, false
 	for _, sexpr := range slct.SelectExprs {
 		switch col := sexpr.(type) {
+		case *sqlparser.StarExpr:
+			selectAll = true
 		case *sqlparser.AliasedExpr:
-			if !col.As.IsEmpty() {
-				return nil, fmt.Errorf("SELECT ... AS ... is not supported")
-			}
-
-			colName, ok := col.Expr.(*sqlparser.ColName)
-			if !ok {
-				return nil, fmt.Errorf("unknown columns type - %T", col.Expr)
-			}
-
-			query.Columns = append(query.Columns, colName.Name.String())
+			query.Columns = append(query.Columns, exprString(col.Expr))
+			aliases = append(aliases, col.As.String())
+			hasAlias = hasAlias || !col.As.IsEmpty()
 		default:
 			return nil, fmt.Errorf("unknown SELECT column type - %T", sexpr)
 		}
 	}
 
-	if len(query.Columns) == 0 {
+	if selectAll && len(query.Columns) > 0 {
+		return nil, fmt.Errorf("SELECT * can't be used with other columns")
+	}
+
+	if !selectAll && len(query.Columns) == 0 {
 		return nil, fmt.Errorf("no columns")
 	}
 
+	if hasAlias {
+		query.Aliases = aliases
+	}
+
 	if slct.Where != nil {
 		query.Filter = strings.TrimSpace(sqlparser.String(slct.Where))
 	}
@@ -96,5 +118,48 @@ func ParseSQL(sql string) (*Query, error) {
 		query.GroupBy = strings.TrimSpace(sqlparser.String(slct.GroupBy))
 	}
 
+	for _, order := range slct.OrderBy {
+		key := OrderBy{
+			Column:     exprString(order.Expr),
+			Descending: order.Direction == sqlparser.DescScr,
+		}
+		query.OrderBy = append(query.OrderBy, key)
+	}
+
+	if slct.Limit != nil {
+		query.HasLimit = true
+		query.Limit, err = limitValue(slct.Limit.Rowcount)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad LIMIT")
+		}
+
+		query.Offset, err = limitValue(slct.Limit.Offset)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad OFFSET")
+		}
+	}
+
 	return query, nil
 }
+
+// exprString returns the name of a column or the SQL of other expressions
+func exprString(expr sqlparser.Expr) string {
+	if col, ok := expr.(*sqlparser.ColName); ok {
+		return col.Name.String()
+	}
+
+	return sqlparser.String(expr)
+}
+
+func limitValue(expr sqlparser.Expr) (int64, error) {
+	if expr == nil {
+		return 0, nil
+	}
+
+	val, ok := expr.(*sqlparser.SQLVal)
+	if !ok || val.Type != sqlparser.IntVal {
+		return 0, fmt.Errorf("not an integer - %s", sqlparser.String(expr))
+	}
+
+	return strconv.ParseInt(string(val.Val), 10, 64)
+}
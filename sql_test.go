@@ -50,3 +50,61 @@ func TestSimple(t *testing.T) {
 		t.Fatalf("wrong result - %+v", query)
 	}
 }
+
+func TestAggregation(t *testing.T) {
+	sql := `SELECT host, avg(cpu), count(*), count(DISTINCT user) FROM metrics GROUP BY host`
+	query, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("error parsing - %s", err)
+	}
+
+	expected := []string{"host", "avg(cpu)", "count(*)", "count(distinct user)"}
+	if !reflect.DeepEqual(query.Columns, expected) {
+		t.Fatalf("wrong columns - %v", query.Columns)
+	}
+}
+
+func TestSelectExpressions(t *testing.T) {
+	sql := `
+	SELECT host AS h, cpu * 100 AS pct, upper(name)
+	FROM metrics
+	ORDER BY pct DESC, h
+	LIMIT 10 OFFSET 5
+	`
+	query, err := ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("error parsing - %s", err)
+	}
+
+	expected := &Query{
+		Table:   "metrics",
+		Columns: []string{"host", "cpu * 100", "upper(name)"},
+		Aliases: []string{"h", "pct", ""},
+		OrderBy: []OrderBy{
+			{Column: "pct", Descending: true},
+			{Column: "h"},
+		},
+		Limit:    10,
+		Offset:   5,
+		HasLimit: true,
+	}
+
+	if !reflect.DeepEqual(query, expected) {
+		t.Fatalf("wrong result - %+v", query)
+	}
+}
+
+func TestSelectAll(t *testing.T) {
+	query, err := ParseSQL("SELECT * FROM metrics LIMIT 3")
+	if err != nil {
+		t.Fatalf("error parsing - %s", err)
+	}
+
+	if query.Columns != nil || query.Limit != 3 {
+		t.Fatalf("wrong result - %+v", query)
+	}
+
+	if _, err := ParseSQL("SELECT *, host FROM metrics"); err == nil {
+		t.Fatal("no error on * with other columns")
+	}
+}
@@ -21,6 +21,7 @@ such restriction.
 package frames
 
 import (
+	"context"
 	"time"
 
 	"github.com/v3io/frames/pb"
@@ -53,6 +54,8 @@ type Column interface {
 	TimeAt(i int) (time.Time, error)          // time.Time value at index i
 	Bools() ([]bool, error)                   // Data as []bool
 	BoolAt(i int) (bool, error)               // bool value at index i
+	IsNull(i int) bool                        // Is value at index i null
+	NullCount() int                           // Number of null values
 	Slice(start int, end int) (Column, error) // Slice of data
 }
 
@@ -76,14 +79,41 @@ type RowIterator interface {
 	Err() error                      // Iteration error
 }
 
-// DataBackend is an interface for read/write on backend
+// DataBackend is an interface for read/write on backend. Iterators and
+// appenders returned from Read and Write stop when ctx is done, their Err (or
+// Add/WaitForComplete) will return ctx.Err()
 type DataBackend interface {
-	// TODO: Expose name, type, config ... ?
-	Read(request *ReadRequest) (FrameIterator, error)
-	Write(request *WriteRequest) (FrameAppender, error) // TODO: use Appender for write streaming
-	Create(request *CreateRequest) error
-	Delete(request *DeleteRequest) error
-	Exec(request *ExecRequest) error
+	Capabilities() *Capabilities
+	Read(ctx context.Context, request *ReadRequest) (FrameIterator, error)
+	Write(ctx context.Context, request *WriteRequest) (FrameAppender, error) // TODO: use Appender for write streaming
+	Create(ctx context.Context, request *CreateRequest) error
+	Delete(ctx context.Context, request *DeleteRequest) error
+	Exec(ctx context.Context, request *ExecRequest) (Frame, error) // Frame is the command result (or nil)
+	List(ctx context.Context, request *ListRequest) ([]string, error)
+	Describe(ctx context.Context, request *DescribeRequest) (*TableInfo, error)
+}
+
+// Pushdown describes the parts of a read request a backend handles natively,
+// the API layer handles the rest in process
+type Pushdown struct {
+	// Backend returns only the requested columns
+	Columns bool
+	// Filter operators and functions the backend evaluates natively
+	// (e.g. "=", "<", "and", "in")
+	FilterOperators []string
+	// Operator names in the backend filter syntax, if they are different from
+	// SQL (e.g. "=" -> "==")
+	OperatorNames map[string]string
+	// Backend gets the complete (SQL) filter when no part of it is pushed down
+	// and uses it only to skip data (e.g. by statistics), the filter is still
+	// evaluated in process
+	FilterHint bool
+}
+
+// PushdownBackend is a backend that can tell which parts of a read request it
+// handles natively
+type PushdownBackend interface {
+	Pushdown() *Pushdown
 }
 
 // FrameIterator iterates over frames
@@ -99,6 +129,13 @@ type FrameAppender interface {
 	WaitForComplete(timeout time.Duration) error
 }
 
+// ReportingAppender is an appender that reports the outcome of rows, Report
+// is valid after WaitForComplete
+type ReportingAppender interface {
+	FrameAppender
+	Report() *WriteReport
+}
+
 // ReadRequest is a read/query request
 type ReadRequest = pb.ReadRequest
 
@@ -114,12 +151,62 @@ type WriteRequest struct {
 	Table   string   `msgpack:"table"`   // Table name (path)
 	// Data message sent with the write request (in case of a stream multiple messages can follow)
 	ImmidiateData Frame `msgpack:"intermidate,omitempty"`
-	// Expression template, for update expressions generated from combining columns data with expression
+	// Expression template, for update expressions generated from combining columns data with expression.
+	// Placeholders are {name} of a column, index or label (e.g. "SET x = x + {y}")
 	Expression string `msgpack:"expression,omitempty"`
 	// Will we get more message chunks (in a stream), if not we can complete
 	HaveMore bool `msgpack:"more"`
+	// Format of the frames following the request (ProtobufFormat or ArrowFormat)
+	DataFormat string `msgpack:"data_format,omitempty"`
+	// How to handle an existing table, backends that can't append fail on
+	// append modes
+	SaveMode SaveMode `msgpack:"save_mode,omitempty"`
+	// Request timeout in seconds (0 = server default)
+	Timeout int64 `msgpack:"timeout,omitempty"`
+	// Per row condition template (same placeholders as Expression), rows
+	// failing the condition are not written
+	Condition string `msgpack:"condition,omitempty"`
+	// How rows are written to existing items
+	WriteMode WriteMode `msgpack:"write_mode,omitempty"`
+}
+
+// IsConditional returns true if rows of the write can be rejected by a
+// condition or the write mode, the outcome of such writes is reported per row
+func (r *WriteRequest) IsConditional() bool {
+	return r.Condition != "" || r.WriteMode != PutMode
 }
 
+// Data formats of frames sent over HTTP (ReadRequest.DataFormat and
+// WriteRequest.DataFormat), empty is ProtobufFormat
+const (
+	ProtobufFormat = "protobuf"
+	ArrowFormat    = "arrow"
+)
+
+// WriteMode is how rows are written to existing items
+type WriteMode = pb.WriteMode
+
+// Shortcut for write modes
+const (
+	PutMode    = pb.WriteMode_PUT
+	UpsertMode = pb.WriteMode_UPSERT
+	UpdateMode = pb.WriteMode_UPDATE
+	InsertMode = pb.WriteMode_INSERT
+)
+
+// WriteReport is the outcome of a conditional write
+type WriteReport = pb.WriteReport
+
+// RowOutcome is the outcome of a row that wasn't written
+type RowOutcome = pb.RowOutcome
+
+// Shortcut for row statuses
+const (
+	RowApplied         = pb.RowStatus_APPLIED
+	RowConditionFailed = pb.RowStatus_CONDITION_FAILED
+	RowFailed          = pb.RowStatus_FAILED
+)
+
 // CreateRequest is a table creation request
 type CreateRequest = pb.CreateRequest
 
@@ -144,5 +231,54 @@ const (
 	FailOnError = pb.ErrorOptions_FAIL
 )
 
+// SaveMode is write mode for existing tables
+type SaveMode = pb.SaveMode
+
+// Shortcut for save modes
+const (
+	OverwriteMode        = pb.SaveMode_OVERWRITE
+	AppendMode           = pb.SaveMode_APPEND
+	AppendNewColumnsMode = pb.SaveMode_APPEND_NEW_COLUMNS
+)
+
 // ExecRequest is execution request
 type ExecRequest = pb.ExecRequest
+
+// ListRequest is a table listing request
+type ListRequest = pb.ListRequest
+
+// DescribeRequest is a table description request
+type DescribeRequest = pb.DescribeRequest
+
+// TableInfo is a table schema and backend specific attributes
+type TableInfo = pb.TableInfo
+
+// Backend operations (used in Capabilities.Operations)
+const (
+	ReadOperation     = "read"
+	WriteOperation    = "write"
+	CreateOperation   = "create"
+	DeleteOperation   = "delete"
+	ExecOperation     = "exec"
+	ListOperation     = "list"
+	DescribeOperation = "describe"
+)
+
+// Capabilities are the operations, commands and data types a backend supports
+type Capabilities = pb.Capabilities
+
+// ExecCommandInfo describes an Exec command
+type ExecCommandInfo = pb.ExecCommandInfo
+
+// ArgumentInfo describes an Exec command argument or a Create attribute
+type ArgumentInfo = pb.ArgumentInfo
+
+// BackendInfo is backend name, type and capabilities
+type BackendInfo = pb.BackendInfo
+
+// AllDTypes returns all the data types, for backends that support all of them
+func AllDTypes() []pb.DType {
+	return []pb.DType{
+		pb.DType_INTEGER, pb.DType_FLOAT, pb.DType_STRING, pb.DType_TIME, pb.DType_BOOLEAN,
+	}
+}
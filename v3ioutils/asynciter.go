@@ -21,6 +21,7 @@ such restriction.
 package v3ioutils
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/nuclio/logger"
@@ -42,75 +43,88 @@ type AsyncItemsCursor struct {
 	currentError error
 	itemIndex    int
 	items        []v3io.Item
-	input        *v3io.GetItemsInput
+	nextMarker   string              // Marker after the current page
+	scan         *v3io.GetItemsInput // Scan of the current page
 	container    *v3io.Container
 	logger       logger.Logger
-
-	responseChan  chan *v3io.Response
-	workers       int
-	totalSegments int
-	lastShards    int
-	Cnt           int
-	limit         int
+	ctx          context.Context
+	retry        *RetryPolicy
+	attempts     map[*v3io.GetItemsInput]int // Attempts of failing requests
+
+	responseChan chan *v3io.Response
+	pending      int // Number of in-flight requests
+	released     bool
+	workers      int // Number of parallel scans
+	lastShards   int
+	Cnt          int
+	limit        int
 }
 
-// NewAsyncItemsCursor return new AsyncItemsCursor
+// NewAsyncItemsCursor return new AsyncItemsCursor, the cursor stops (and
+// releases in-flight requests) when ctx is done. Failed requests are sent
+// again according to retry (nil for no retries)
 func NewAsyncItemsCursor(
-	container *v3io.Container, input *v3io.GetItemsInput,
-	workers int, shardingKeys []string, logger logger.Logger, limit int) (*AsyncItemsCursor, error) {
+	ctx context.Context, container *v3io.Container, input *v3io.GetItemsInput,
+	workers int, shardingKeys []string, logger logger.Logger, limit int,
+	retry *RetryPolicy) (*AsyncItemsCursor, error) {
 
 	// TODO: use workers from Context.numWorkers (if no ShardingKey)
 	if workers == 0 || input.ShardingKey != "" {
 		workers = 1
 	}
 
-	newAsyncItemsCursor := &AsyncItemsCursor{
-		container:    container,
-		input:        input,
-		responseChan: make(chan *v3io.Response, 1000),
-		workers:      workers,
-		logger:       logger.GetChild("AsyncItemsCursor"),
-		limit:        limit,
-	}
-
+	var inputs []*v3io.GetItemsInput
 	if len(shardingKeys) > 0 {
-		newAsyncItemsCursor.workers = len(shardingKeys)
-
-		for i := 0; i < newAsyncItemsCursor.workers; i++ {
-			input := v3io.GetItemsInput{
+		for _, key := range shardingKeys {
+			inputs = append(inputs, &v3io.GetItemsInput{
 				Path:           input.Path,
 				AttributeNames: input.AttributeNames,
 				Filter:         input.Filter,
-				ShardingKey:    shardingKeys[i],
-			}
-			_, err := container.GetItems(&input, &input, newAsyncItemsCursor.responseChan)
-
-			if err != nil {
-				return nil, err
-			}
+				ShardingKey:    key,
+			})
+		}
+	} else {
+		for i := 0; i < workers; i++ {
+			inputs = append(inputs, &v3io.GetItemsInput{
+				Path:           input.Path,
+				AttributeNames: input.AttributeNames,
+				Filter:         input.Filter,
+				TotalSegments:  workers,
+				Segment:        i,
+			})
 		}
-
-		return newAsyncItemsCursor, nil
 	}
 
-	for i := 0; i < newAsyncItemsCursor.workers; i++ {
-		newAsyncItemsCursor.totalSegments = workers
-		input := v3io.GetItemsInput{
-			Path:           input.Path,
-			AttributeNames: input.AttributeNames,
-			Filter:         input.Filter,
-			TotalSegments:  newAsyncItemsCursor.totalSegments,
-			Segment:        i,
-		}
-		_, err := container.GetItems(&input, &input, newAsyncItemsCursor.responseChan)
+	return NewScanCursor(ctx, container, inputs, logger, limit, retry)
+}
 
-		if err != nil {
-			// TODO: proper exit, release requests which passed
+// NewScanCursor returns a cursor over parallel scans, one for every input
+// (e.g. segments or sharding keys). The cursor owns inputs, their markers are
+// updated as the scans progress
+func NewScanCursor(
+	ctx context.Context, container *v3io.Container, inputs []*v3io.GetItemsInput,
+	logger logger.Logger, limit int, retry *RetryPolicy) (*AsyncItemsCursor, error) {
+
+	ic := &AsyncItemsCursor{
+		container:    container,
+		responseChan: make(chan *v3io.Response, 1000),
+		workers:      len(inputs),
+		logger:       logger.GetChild("AsyncItemsCursor"),
+		ctx:          ctx,
+		limit:        limit,
+		retry:        retry,
+		attempts:     make(map[*v3io.GetItemsInput]int),
+	}
+
+	for _, input := range inputs {
+		if _, err := container.GetItems(input, input, ic.responseChan); err != nil {
+			ic.Release()
 			return nil, err
 		}
+		ic.pending++
 	}
 
-	return newAsyncItemsCursor, nil
+	return ic, nil
 }
 
 // Err returns the last error
@@ -118,9 +132,26 @@ func (ic *AsyncItemsCursor) Err() error {
 	return ic.currentError
 }
 
-// Release releases a cursor and its underlying resources
+// Release releases a cursor and its underlying resources. Responses to
+// in-flight requests are drained in the background
 func (ic *AsyncItemsCursor) Release() {
-	// TODO:
+	if ic.released {
+		return
+	}
+
+	ic.released = true
+	pending := ic.pending
+	ic.pending = 0
+	if pending == 0 {
+		return
+	}
+
+	go func() {
+		for i := 0; i < pending; i++ {
+			resp := <-ic.responseChan
+			resp.Release()
+		}
+	}()
 }
 
 // Next gets the next matching item. this may potentially block as this lazy loads items from the collection
@@ -155,6 +186,10 @@ func (ic *AsyncItemsCursor) NextItem() (v3io.Item, error) {
 		return ic.currentItem, nil
 	}
 
+	if ic.released {
+		return nil, ic.ctx.Err()
+	}
+
 	// are there any more items up stream? did all the shards complete ?
 	if ic.lastShards == ic.workers {
 		ic.currentError = nil
@@ -162,7 +197,15 @@ func (ic *AsyncItemsCursor) NextItem() (v3io.Item, error) {
 	}
 
 	// Read response from channel
-	resp := <-ic.responseChan
+	var resp *v3io.Response
+	select {
+	case resp = <-ic.responseChan:
+	case <-ic.ctx.Done():
+		ic.Release()
+		return nil, ic.ctx.Err()
+	}
+
+	ic.pending--
 	defer resp.Release()
 
 	// Ignore 404s
@@ -171,29 +214,55 @@ func (ic *AsyncItemsCursor) NextItem() (v3io.Item, error) {
 		ic.lastShards++
 		return ic.NextItem()
 	}
+
+	input := resp.Context.(*v3io.GetItemsInput)
 	if resp.Error != nil {
+		ic.attempts[input]++
+		if attempt := ic.attempts[input]; ic.retry.Retry(resp.Error, attempt) {
+			ic.logger.WarnWith("retrying items request", "attempt", attempt, "error", resp.Error)
+			if err := ic.retry.Wait(ic.ctx, attempt); err != nil {
+				ic.Release()
+				return nil, err
+			}
+
+			if err := Resend(ic.container, resp, input, ic.responseChan); err != nil {
+				return nil, errors.Wrap(err, "Failed to resend items request")
+			}
+			ic.pending++
+			return ic.NextItem()
+		}
+
 		ic.logger.Warn("error reading from response channel: %v, error: %v, request: %v", resp, resp.Error, resp.Request().Input)
 		return nil, errors.Wrap(resp.Error, "Failed to get next items")
 	}
+	delete(ic.attempts, input)
 
 	getItemsResp := resp.Output.(*v3io.GetItemsOutput)
 
 	// set the cursor items and reset the item index
 	ic.items = getItemsResp.Items
 	ic.itemIndex = 0
-
+	ic.scan = input
+	ic.nextMarker = ""
 	if !getItemsResp.Last {
+		ic.nextMarker = getItemsResp.NextMarker
+	}
 
-		// if not last, make a new request to that shard
-		input := resp.Context.(*v3io.GetItemsInput)
+	if !getItemsResp.Last {
 
-		// set next marker
+		// if not last, make a new request to that shard with the next marker
 		input.Marker = getItemsResp.NextMarker
 
+		if err := ic.ctx.Err(); err != nil {
+			ic.Release()
+			return nil, err
+		}
+
 		_, err := ic.container.GetItems(input, input, ic.responseChan)
 		if err != nil {
 			return nil, errors.Wrap(err, "Failed to request next items")
 		}
+		ic.pending++
 
 	} else {
 		// Mark one more shard as completed
@@ -204,6 +273,25 @@ func (ic *AsyncItemsCursor) NextItem() (v3io.Item, error) {
 	return ic.NextItem()
 }
 
+// PageEnd returns true if the current item is the last item of its response
+// page
+func (ic *AsyncItemsCursor) PageEnd() bool {
+	return ic.itemIndex == len(ic.items)
+}
+
+// NextMarker returns the marker to continue the scan of the current item after
+// its page, "" if it's the last page. Markers of cursors with more than one
+// scan are per scan
+func (ic *AsyncItemsCursor) NextMarker() string {
+	return ic.nextMarker
+}
+
+// Scan returns the input of the scan of the current item, which identifies the
+// scan (segment or sharding key) NextMarker continues. It must not be modified
+func (ic *AsyncItemsCursor) Scan() *v3io.GetItemsInput {
+	return ic.scan
+}
+
 // All returns all items
 func (ic *AsyncItemsCursor) All() ([]v3io.Item, error) {
 	var items []v3io.Item
@@ -21,7 +21,10 @@ such restriction.
 package v3ioutils
 
 import (
+	"context"
 	"encoding/binary"
+	"fmt"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -70,26 +73,30 @@ func AsInt64Array(val []byte) []uint64 {
 	return array
 }
 
-// DeleteTable deletes a table
-func DeleteTable(logger logger.Logger, container *v3io.Container, path, filter string, workers int) error {
+// DeleteTable deletes a table, or the table items matching filter. Failed
+// requests are sent again according to retry (nil for no retries)
+func DeleteTable(ctx context.Context, logger logger.Logger, container *v3io.Container, path, filter string, workers int, retry *RetryPolicy) error {
 
 	input := v3io.GetItemsInput{Path: path, AttributeNames: []string{"__name"}, Filter: filter}
-	iter, err := NewAsyncItemsCursor(container, &input, workers, []string{}, logger, 0)
+	iter, err := NewAsyncItemsCursor(ctx, container, &input, workers, []string{}, logger, 0, retry)
 	//iter, err := container.Sync.GetItemsCursor(&input)
 	if err != nil {
 		return err
 	}
 
+	defer iter.Release()
+
 	responseChan := make(chan *v3io.Response, 1000)
 	commChan := make(chan int, 2)
-	doneChan := respWaitLoop(logger, commChan, responseChan, 10*time.Second)
+	doneChan := respWaitLoop(ctx, logger, container, retry, commChan, responseChan)
 	reqMap := map[uint64]bool{}
 
 	i := 0
 	for iter.Next() {
 		name := iter.GetField("__name").(string)
+		// Request context is the attempt number
 		req, err := container.DeleteObject(&v3io.DeleteObjectInput{
-			Path: path + "/" + url.QueryEscape(name)}, nil, responseChan)
+			Path: path + "/" + url.QueryEscape(name)}, 1, responseChan)
 		if err != nil {
 			commChan <- i
 			return errors.Wrap(err, "failed to delete object "+name)
@@ -103,9 +110,13 @@ func DeleteTable(logger logger.Logger, container *v3io.Container, path, filter s
 		return errors.Wrap(iter.Err(), "failed to delete object ")
 	}
 
-	<-doneChan
+	if err := <-doneChan; err != nil {
+		return errors.Wrap(err, "failed to delete objects")
+	}
 
-	err = container.Sync.DeleteObject(&v3io.DeleteObjectInput{Path: path})
+	err = retry.Do(ctx, logger, func() error {
+		return container.Sync.DeleteObject(&v3io.DeleteObjectInput{Path: path})
+	})
 	if err != nil {
 		if !utils.IsNotExistsError(err) {
 			return errors.Wrapf(err, "Failed to delete table object '%s'.", path)
@@ -115,43 +126,75 @@ func DeleteTable(logger logger.Logger, container *v3io.Container, path, filter s
 	return nil
 }
 
-func respWaitLoop(logger logger.Logger, comm chan int, responseChan chan *v3io.Response, timeout time.Duration) chan bool {
+// respTimeout is the time to wait for a delete response before giving up
+const respTimeout = 10 * time.Second
+
+// respWaitLoop waits for the responses of delete requests, failed requests are
+// sent again according to retry. The number of requests is sent on comm, done
+// gets the last error once all responses arrived, ctx is done or no response
+// arrived for too long
+func respWaitLoop(ctx context.Context, logger logger.Logger, container *v3io.Container, retry *RetryPolicy, comm chan int, responseChan chan *v3io.Response) chan error {
 	responses := 0
 	requests := -1
-	done := make(chan bool)
+	// Buffered so the loop won't block if the caller returned on error
+	done := make(chan error, 1)
+
+	// Lost responses are detected by inactivity, resends may be delayed by up
+	// to the maximal backoff
+	timeout := respTimeout
+	if retry != nil {
+		timeout += retry.maxBackoff
+	}
 
 	go func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
 		active := false
+		var lastErr error
 		for {
 			select {
 
 			case resp := <-responseChan:
-				responses++
 				active = true
+				if attempt, _ := resp.Context.(int); retry.Retry(resp.Error, attempt) {
+					logger.WarnWith("retrying delete", "attempt", attempt, "error", resp.Error)
+					retry.ResendAfter(ctx, container, resp, attempt, attempt+1, responseChan)
+					continue
+				}
 
-				if resp.Error != nil {
+				responses++
+				// Deleted by someone else
+				if resp.Error != nil && StatusCode(resp.Error) != http.StatusNotFound {
 					logger.ErrorWith("failed Delete response", "error", resp.Error)
-					// TODO: signal done and return?
+					lastErr = resp.Error
 				}
 
 				if requests == responses {
-					done <- true
+					done <- lastErr
 					return
 				}
 
 			case requests = <-comm:
 				if requests <= responses {
-					done <- true
+					done <- lastErr
 					return
 				}
 
-			case <-time.After(timeout):
-				if !active {
-					logger.ErrorWith("Resp loop timed out!", "requests", requests, "response", responses)
-					done <- true
+			case <-timer.C:
+				// Requests are still sent while requests is unknown
+				if !active && requests >= 0 {
+					logger.ErrorWith("delete timed out", "requests", requests, "response", responses)
+					done <- fmt.Errorf("no delete response for %s", timeout)
 					return
 				}
 				active = false
+				timer.Reset(timeout)
+
+			case <-ctx.Done():
+				logger.ErrorWith("delete canceled", "requests", requests, "response", responses)
+				done <- ctx.Err()
+				return
 			}
 		}
 	}()
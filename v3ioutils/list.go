@@ -0,0 +1,93 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package v3ioutils
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	v3io "github.com/v3io/v3io-go-http"
+	"github.com/v3io/v3io-tsdb/pkg/utils"
+)
+
+// ListDir returns the objects and sub directories in path (without trailing /)
+func ListDir(container *v3io.Container, path string) ([]string, []string, error) {
+	if path != "" && !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	resp, err := container.Sync.ListBucket(&v3io.ListBucketInput{Path: path})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "can't list %q", path)
+	}
+
+	defer resp.Release()
+	output := resp.Output.(*v3io.ListBucketOutput)
+
+	var objects []string
+	for _, content := range output.Contents {
+		objects = append(objects, content.Key)
+	}
+
+	var dirs []string
+	for _, prefix := range output.CommonPrefixes {
+		dirs = append(dirs, strings.TrimSuffix(prefix.Prefix, "/"))
+	}
+
+	return objects, dirs, nil
+}
+
+// ObjectExists returns true if there's an object in path
+func ObjectExists(container *v3io.Container, path string) (bool, error) {
+	resp, err := container.Sync.GetObject(&v3io.GetObjectInput{Path: path})
+	if err != nil {
+		if utils.IsNotExistsError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	resp.Release()
+	return true, nil
+}
+
+// ListTables returns the sub directories of path that contain a marker object
+// (e.g. a schema file)
+func ListTables(container *v3io.Container, path, marker string) ([]string, error) {
+	_, dirs, err := ListDir(container, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []string
+	for _, dir := range dirs {
+		ok, err := ObjectExists(container, dir+"/"+marker)
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't check %q", dir)
+		}
+
+		if ok {
+			tables = append(tables, dir)
+		}
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package v3ioutils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/pkg/errors"
+	v3io "github.com/v3io/v3io-go-http"
+
+	"github.com/v3io/frames"
+)
+
+// Retry policy defaults
+const (
+	DefaultMaxAttempts = 3
+	DefaultBackoff     = 100 * time.Millisecond
+	DefaultMaxBackoff  = 5 * time.Second
+)
+
+// DefaultRetryStatusCodes are the status codes of throttled or temporary
+// unavailable requests
+var DefaultRetryStatusCodes = []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}
+
+// RetryPolicy decides which failed v3io requests are sent again and how long
+// to wait before each attempt. A nil policy doesn't retry
+type RetryPolicy struct {
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	statusCodes map[int]bool
+}
+
+// NewRetryPolicy returns a retry policy from configuration, cfg can be nil
+func NewRetryPolicy(cfg *frames.RetryConfig) *RetryPolicy {
+	if cfg == nil {
+		cfg = &frames.RetryConfig{}
+	}
+
+	policy := &RetryPolicy{
+		maxAttempts: cfg.MaxAttempts,
+		backoff:     time.Duration(cfg.Backoff) * time.Millisecond,
+		maxBackoff:  time.Duration(cfg.MaxBackoff) * time.Millisecond,
+		statusCodes: make(map[int]bool),
+	}
+
+	if policy.maxAttempts == 0 {
+		policy.maxAttempts = DefaultMaxAttempts
+	}
+
+	if policy.backoff == 0 {
+		policy.backoff = DefaultBackoff
+	}
+
+	if policy.maxBackoff == 0 {
+		policy.maxBackoff = DefaultMaxBackoff
+	}
+
+	statusCodes := cfg.StatusCodes
+	if len(statusCodes) == 0 {
+		statusCodes = DefaultRetryStatusCodes
+	}
+
+	for _, code := range statusCodes {
+		policy.statusCodes[code] = true
+	}
+
+	return policy
+}
+
+// Retry returns true if a request that failed with err after attempt attempts
+// should be sent again
+func (p *RetryPolicy) Retry(err error, attempt int) bool {
+	if p == nil || err == nil || attempt >= p.maxAttempts {
+		return false
+	}
+
+	return p.RetryStatus(StatusCode(err))
+}
+
+// RetryStatus returns true if failures with status code are retried
+func (p *RetryPolicy) RetryStatus(code int) bool {
+	return p != nil && p.statusCodes[code]
+}
+
+// Delay returns the delay after attempt attempts
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.backoff
+	for i := 1; i < attempt && delay < p.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > p.maxBackoff {
+		delay = p.maxBackoff
+	}
+
+	return delay
+}
+
+// Wait waits the delay after attempt attempts, it returns ctx.Err() if ctx is
+// done first
+func (p *RetryPolicy) Wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.Delay(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Do calls fn until it succeeds, fails with an error that isn't retried or ctx
+// is done
+func (p *RetryPolicy) Do(ctx context.Context, logger logger.Logger, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if !p.Retry(err, attempt) {
+			return err
+		}
+
+		logger.WarnWith("retrying v3io request", "attempt", attempt, "error", err)
+		if err := p.Wait(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+// StatusCode returns the HTTP status code of a failed v3io request, 0 if err
+// has no status code
+func StatusCode(err error) int {
+	switch e := errors.Cause(err).(type) {
+	case v3io.ErrorWithStatusCode:
+		return e.StatusCode()
+	case *v3io.ErrorWithStatusCode:
+		return e.StatusCode()
+	}
+
+	return 0
+}
+
+// Resend sends the request of a failed async response again, reqContext is the
+// context of the new request
+func Resend(container *v3io.Container, resp *v3io.Response, reqContext interface{}, responseChan chan *v3io.Response) error {
+	var err error
+	switch input := resp.Request().Input.(type) {
+	case *v3io.PutItemInput:
+		_, err = container.PutItem(input, reqContext, responseChan)
+	case *v3io.UpdateItemInput:
+		_, err = container.UpdateItem(input, reqContext, responseChan)
+	case *v3io.GetItemsInput:
+		_, err = container.GetItems(input, reqContext, responseChan)
+	case *v3io.DeleteObjectInput:
+		_, err = container.DeleteObject(input, reqContext, responseChan)
+	default:
+		err = fmt.Errorf("can't resend %T request", input)
+	}
+
+	return err
+}
+
+// ResendAfter sends the request of a failed async response again after the
+// delay of attempt attempts, without blocking the caller (which keeps reading
+// responseChan). If ctx is done first or the request can't be sent, a response
+// with the error and reqContext is delivered on responseChan instead
+func (p *RetryPolicy) ResendAfter(ctx context.Context, container *v3io.Container, resp *v3io.Response, attempt int, reqContext interface{}, responseChan chan *v3io.Response) {
+	time.AfterFunc(p.Delay(attempt), func() {
+		err := ctx.Err()
+		if err == nil {
+			err = Resend(container, resp, reqContext, responseChan)
+		}
+
+		if err == nil {
+			return
+		}
+
+		select {
+		case responseChan <- &v3io.Response{Error: err, Context: reqContext}:
+		case <-ctx.Done():
+		}
+	})
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package v3ioutils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	v3io "github.com/v3io/v3io-go-http"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils/v3iotest"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	cfg := &frames.RetryConfig{MaxAttempts: 4, Backoff: 10, MaxBackoff: 30, StatusCodes: []int{503}}
+	policy := NewRetryPolicy(cfg)
+
+	delays := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}
+	for i, expected := range delays {
+		if delay := policy.Delay(i + 1); delay != expected {
+			t.Fatalf("attempt %d: bad delay %s != %s", i+1, delay, expected)
+		}
+	}
+
+	unavailable := v3io.NewErrorWithStatusCode(503, "unavailable")
+	if !policy.Retry(unavailable, 3) || policy.Retry(unavailable, 4) {
+		t.Fatal("bad retry by attempt")
+	}
+
+	if policy.Retry(v3io.NewErrorWithStatusCode(400, "bad"), 1) || policy.Retry(fmt.Errorf("oops"), 1) {
+		t.Fatal("retry of non retryable error")
+	}
+
+	var nilPolicy *RetryPolicy
+	if nilPolicy.Retry(unavailable, 1) {
+		t.Fatal("nil policy retries")
+	}
+
+	logger, err := frames.NewLogger("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	err = policy.Do(context.Background(), logger, func() error {
+		calls++
+		if calls < 3 {
+			return unavailable
+		}
+		return nil
+	})
+
+	if err != nil || calls != 3 {
+		t.Fatalf("bad Do: %d calls (%v)", calls, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = policy.Do(ctx, logger, func() error { return unavailable })
+	if err != context.Canceled {
+		t.Fatalf("bad error on canceled Do: %v", err)
+	}
+}
+
+func TestRetryItems(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+
+	logger, err := frames.NewLogger("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	container, err := CreateContainer(logger, srv.Addr, "bigdata", "", "", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		input := &v3io.PutItemInput{Path: fmt.Sprintf("table/%d", i), Attributes: map[string]interface{}{"i": i}}
+		if err := container.Sync.PutItem(input); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	retry := NewRetryPolicy(&frames.RetryConfig{MaxAttempts: 3, Backoff: 1})
+	count := func(retry *RetryPolicy) (int, error) {
+		input := &v3io.GetItemsInput{Path: "table/", AttributeNames: []string{"__name"}}
+		iter, err := NewAsyncItemsCursor(context.Background(), container, input, 2, nil, logger, 0, retry)
+		if err != nil {
+			return 0, err
+		}
+		defer iter.Release()
+
+		n := 0
+		for iter.Next() {
+			n++
+		}
+		return n, iter.Err()
+	}
+
+	srv.Fail("GetItems", 2, http.StatusServiceUnavailable)
+	if n, err := count(retry); err != nil || n != 10 {
+		t.Fatalf("bad count with retries: %d (%v)", n, err)
+	}
+
+	srv.Fail("GetItems", 1, http.StatusServiceUnavailable)
+	if _, err := count(nil); StatusCode(err) != http.StatusServiceUnavailable {
+		t.Fatalf("bad error without retries: %v", err)
+	}
+	srv.Fail("GetItems", 0, 0)
+
+	srv.Fail("DeleteObject", 2, http.StatusTooManyRequests)
+	if err := DeleteTable(context.Background(), logger, container, "table/", "i < 5", 2, retry); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := count(nil); err != nil || n != 5 {
+		t.Fatalf("bad count after delete: %d (%v)", n, err)
+	}
+}
+
+func TestRetryConcurrentBackoff(t *testing.T) {
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+
+	logger, err := frames.NewLogger("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	container, err := CreateContainer(logger, srv.Addr, "bigdata", "", "", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size := 20
+	for i := 0; i < size; i++ {
+		input := &v3io.PutItemInput{Path: fmt.Sprintf("table/%d", i), Attributes: map[string]interface{}{"i": i}}
+		if err := container.Sync.PutItem(input); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Sequential backoffs take size*backoff
+	backoff := 100 * time.Millisecond
+	retry := NewRetryPolicy(&frames.RetryConfig{MaxAttempts: 2, Backoff: int(backoff / time.Millisecond)})
+	srv.Fail("DeleteObject", size, http.StatusServiceUnavailable)
+	start := time.Now()
+	if err := DeleteTable(context.Background(), logger, container, "table/", "", 2, retry); err != nil {
+		t.Fatal(err)
+	}
+
+	if duration := time.Since(start); duration >= time.Duration(size/2)*backoff {
+		t.Fatalf("backoffs are not concurrent: delete took %s", duration)
+	}
+}
@@ -23,12 +23,14 @@ package v3ioutils
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/pkg/errors"
 	v3io "github.com/v3io/v3io-go-http"
 
 	"github.com/v3io/frames"
+	"github.com/v3io/frames/pb"
 )
 
 const (
@@ -36,63 +38,143 @@ const (
 	doubleType = "double"
 	stringType = "string"
 	timeType   = "time"
-	// TODO: boolType?
+	boolType   = "boolean"
 )
 
+// SchemaVersion is the version of schemas written by frames, schemas without
+// a version are old schemas (see MigrateSchema)
+const SchemaVersion = 2
+
+// SchemaObject is the (URL encoded) schema object name in table directory
+const SchemaObject = ".%23schema"
+
+var (
+	typeDTypes = map[string]frames.DType{
+		longType:   frames.IntType,
+		doubleType: frames.FloatType,
+		stringType: frames.StringType,
+		timeType:   frames.TimeType,
+		boolType:   frames.BoolType,
+	}
+
+	dtypeTypes = map[frames.DType]string{
+		frames.IntType:    longType,
+		frames.FloatType:  doubleType,
+		frames.StringType: stringType,
+		frames.TimeType:   timeType,
+		frames.BoolType:   boolType,
+	}
+)
+
+// Schema is a table schema. The JSON format is a superset of the old schema
+// so other readers of the table schema keep working
+type Schema struct {
+	Version          int           `json:"version"`
+	Fields           []SchemaField `json:"fields"`
+	Key              string        `json:"key"`
+	HashingBucketNum int           `json:"hashingBucketNum"`
+}
+
+// SchemaField is a Schema field
+type SchemaField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // long, double, string, time or boolean
+	Nullable bool   `json:"nullable"`
+}
+
+// DType returns the field data type
+func (f *SchemaField) DType() (frames.DType, error) {
+	dtype, ok := typeDTypes[f.Type]
+	if !ok {
+		return 0, fmt.Errorf("%q - unknown type %q", f.Name, f.Type)
+	}
+
+	return dtype, nil
+}
+
 // NewSchema returns a new schema
-func NewSchema(key string) V3ioSchema {
-	return &OldV3ioSchema{Fields: []OldSchemaField{}, Key: key}
+func NewSchema(key string) *Schema {
+	return &Schema{Version: SchemaVersion, Fields: []SchemaField{}, Key: key}
 }
 
-// SchemaFromJSON return a schema from JSON data
-func SchemaFromJSON(data []byte) (V3ioSchema, error) {
-	var schema OldV3ioSchema
-	err := json.Unmarshal(data, &schema)
-	return &schema, err
+// SchemaFromJSON return a schema from JSON data, old schemas are migrated
+func SchemaFromJSON(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+
+	if schema.Version >= SchemaVersion {
+		if schema.Version > SchemaVersion {
+			return nil, fmt.Errorf("unsupported schema version %d", schema.Version)
+		}
+		return &schema, schema.validate()
+	}
+
+	var old OldV3ioSchema
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, err
+	}
+
+	return MigrateSchema(&old)
 }
 
-// V3ioSchema is schema for v3io
-type V3ioSchema interface {
-	AddColumn(name string, col frames.Column, nullable bool) error
-	AddField(name string, val interface{}, nullable bool) error
-	UpdateSchema(container *v3io.Container, tablePath string, newSchema V3ioSchema) error
+// ReadSchema reads the schema of the table at tablePath, the error of a
+// missing schema has http.StatusNotFound status code (see StatusCode)
+func ReadSchema(container *v3io.Container, tablePath string) (*Schema, error) {
+	resp, err := container.Sync.GetObject(&v3io.GetObjectInput{Path: tablePath + SchemaObject})
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read schema")
+	}
+	defer resp.Release()
+
+	schema, err := SchemaFromJSON(resp.Body())
+	if err != nil {
+		return nil, errors.Wrap(err, "bad schema")
+	}
+
+	return schema, nil
 }
 
-// OldV3ioSchema is old v3io schema
-type OldV3ioSchema struct {
-	Fields           []OldSchemaField `json:"fields"`
-	Key              string           `json:"key"`
-	HashingBucketNum int              `json:"hashingBucketNum"`
+// ReadSchemaOrNew reads the schema of the table at tablePath, a new schema
+// (with key) is returned if the table has no schema
+func ReadSchemaOrNew(container *v3io.Container, tablePath, key string) (*Schema, error) {
+	schema, err := ReadSchema(container, tablePath)
+	if StatusCode(err) == http.StatusNotFound {
+		return NewSchema(key), nil
+	}
+
+	return schema, err
 }
 
-// OldSchemaField is OldV3ioSchema field
-type OldSchemaField struct {
-	Name     string `json:"name"`
-	Type     string `json:"type"`
-	Nullable bool   `json:"nullable,omitempty"`
+// Field returns the field called name, nil if there's no such field
+func (s *Schema) Field(name string) *SchemaField {
+	for i := range s.Fields {
+		if s.Fields[i].Name == name {
+			return &s.Fields[i]
+		}
+	}
+
+	return nil
 }
 
 // AddColumn adds a column
-func (s *OldV3ioSchema) AddColumn(name string, col frames.Column, nullable bool) error {
-	var ftype string
-	switch col.DType() {
-	case frames.IntType:
-		ftype = longType
-	case frames.FloatType:
-		ftype = doubleType
-	case frames.StringType:
-		ftype = stringType
-	case frames.TimeType:
-		ftype = timeType
+func (s *Schema) AddColumn(name string, col frames.Column, nullable bool) error {
+	return s.AddDType(name, col.DType(), nullable)
+}
+
+// AddDType adds a field of type dtype
+func (s *Schema) AddDType(name string, dtype frames.DType, nullable bool) error {
+	ftype, ok := dtypeTypes[dtype]
+	if !ok {
+		return fmt.Errorf("%q - unsupported type %v", name, dtype)
 	}
 
-	field := OldSchemaField{Name: name, Type: ftype, Nullable: nullable}
-	s.Fields = append(s.Fields, field)
-	return nil
+	return s.addField(SchemaField{Name: name, Type: ftype, Nullable: nullable})
 }
 
 // AddField adds a field
-func (s *OldV3ioSchema) AddField(name string, val interface{}, nullable bool) error {
+func (s *Schema) AddField(name string, val interface{}, nullable bool) error {
 	var ftype string
 	switch val.(type) {
 	case int, int32, int64:
@@ -103,73 +185,137 @@ func (s *OldV3ioSchema) AddField(name string, val interface{}, nullable bool) er
 		ftype = stringType
 	case time.Time:
 		ftype = timeType
+	case bool:
+		ftype = boolType
+	default:
+		return fmt.Errorf("%q - unsupported value type %T", name, val)
 	}
 
-	field := OldSchemaField{Name: name, Type: ftype, Nullable: nullable}
+	return s.addField(SchemaField{Name: name, Type: ftype, Nullable: nullable})
+}
+
+// addField adds a field, duplicate fields are merged by Merge
+func (s *Schema) addField(field SchemaField) error {
 	s.Fields = append(s.Fields, field)
 	return nil
 }
 
-// toJSON retrun JSON representation of schema
-func (s *OldV3ioSchema) toJSON() ([]byte, error) {
-	return json.Marshal(s)
+// TableSchema returns the schema as frames.TableSchema
+func (s *Schema) TableSchema() *frames.TableSchema {
+	schema := &frames.TableSchema{}
+	for _, field := range s.Fields {
+		sfield := &frames.SchemaField{Name: field.Name, Type: field.Type}
+		if field.Nullable {
+			sfield.Properties = map[string]*pb.Value{
+				"nullable": &pb.Value{Value: &pb.Value_Bval{Bval: true}},
+			}
+		}
+		schema.Fields = append(schema.Fields, sfield)
+	}
+
+	if s.Key != "" {
+		schema.Key = &frames.SchemaKey{ShardingKey: []string{s.Key}}
+	}
+
+	return schema
 }
 
-func (s *OldV3ioSchema) merge(new *OldV3ioSchema) (bool, error) {
-	changed := false
-	for _, field := range new.Fields {
-		index := -1
-		for j := 0; j < len(s.Fields); j++ {
-			if s.Fields[j].Name == field.Name {
-				index = j
-			}
+func (s *Schema) validate() error {
+	names := make(map[string]bool, len(s.Fields))
+	for i := range s.Fields {
+		field := &s.Fields[i]
+		if names[field.Name] {
+			return fmt.Errorf("%q - duplicate field", field.Name)
 		}
+		names[field.Name] = true
 
-		if index >= 0 && field.Type != s.Fields[index].Type {
-			if field.Type == stringType {
-				s.Fields[index].Type = stringType
-				changed = true
-				continue
-			}
+		if _, err := field.DType(); err != nil {
+			return err
+		}
+	}
 
-			if field.Type == doubleType && s.Fields[index].Type == longType {
-				s.Fields[index].Type = doubleType
-				changed = true
-				continue
-			}
+	return nil
+}
 
-			if field.Type == timeType || s.Fields[index].Type == timeType {
-				return changed, fmt.Errorf(
-					"Schema change from %s to %s is not allowed", s.Fields[index].Type, field.Type)
-			}
-		} else {
+// mergeTypes returns the type of a field of type current after writing
+// values of type added to it. Widening the type (e.g. long to double) is an
+// error in strict mode
+func mergeTypes(current, added string, strict bool) (string, error) {
+	switch {
+	case current == added:
+		return current, nil
+	case current == doubleType && added == longType:
+		// Integers are read as doubles
+		return current, nil
+	case strict:
+		// Type change
+	case current == longType && added == doubleType:
+		return doubleType, nil
+	case current == stringType && added != timeType && added != boolType:
+		return current, nil
+	case added == stringType && current != timeType && current != boolType:
+		return stringType, nil
+	}
+
+	return "", fmt.Errorf("schema change from %s to %s is not allowed", current, added)
+}
+
+// Merge merges the fields of other into s and returns true if s has changed.
+// Type changes are errors in strict mode, otherwise types are widened
+// (long to double and numbers to string)
+func (s *Schema) Merge(other *Schema, strict bool) (bool, error) {
+	indices := make(map[string]int, len(s.Fields))
+	for i, field := range s.Fields {
+		indices[field.Name] = i
+	}
+
+	changed := false
+	for _, field := range other.Fields {
+		i, ok := indices[field.Name]
+		if !ok {
+			indices[field.Name] = len(s.Fields)
 			s.Fields = append(s.Fields, field)
 			changed = true
+			continue
+		}
+
+		current := &s.Fields[i]
+		ftype, err := mergeTypes(current.Type, field.Type, strict)
+		if err != nil {
+			return changed, errors.Wrapf(err, "%q", field.Name)
+		}
+
+		if ftype != current.Type || (field.Nullable && !current.Nullable) {
+			current.Type = ftype
+			current.Nullable = current.Nullable || field.Nullable
+			changed = true
 		}
 	}
 
-	if s.Key != new.Key && new.Key != "" {
-		s.Key = new.Key
+	if s.Key != other.Key && other.Key != "" {
+		s.Key = other.Key
 		changed = true
 	}
 
 	return changed, nil
 }
 
-// UpdateSchema updates the schema
-func (s *OldV3ioSchema) UpdateSchema(container *v3io.Container, tablePath string, newSchema V3ioSchema) error {
-	changed, err := s.merge(newSchema.(*OldV3ioSchema))
+// UpdateSchema merges newSchema into s and writes s to the table at tablePath
+// if it has changed (see Merge)
+func (s *Schema) UpdateSchema(container *v3io.Container, tablePath string, newSchema *Schema, strict bool) error {
+	changed, err := s.Merge(newSchema, strict)
 	if err != nil {
 		return errors.Wrap(err, "failed to merge schema")
 	}
 
 	if changed {
-		body, err := s.toJSON()
+		s.Version = SchemaVersion
+		body, err := json.Marshal(s)
 		if err != nil {
 			return errors.Wrap(err, "failed to marshal schema")
 		}
 		err = container.Sync.PutObject(&v3io.PutObjectInput{
-			Path: tablePath + ".%23schema", Body: body})
+			Path: tablePath + SchemaObject, Body: body})
 		if err != nil {
 			return errors.Wrap(err, "failed to update schema")
 		}
@@ -177,3 +323,43 @@ func (s *OldV3ioSchema) UpdateSchema(container *v3io.Container, tablePath string
 
 	return nil
 }
+
+// OldV3ioSchema is old v3io schema
+type OldV3ioSchema struct {
+	Fields           []OldSchemaField `json:"fields"`
+	Key              string           `json:"key"`
+	HashingBucketNum int              `json:"hashingBucketNum"`
+}
+
+// OldSchemaField is OldV3ioSchema field
+type OldSchemaField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable,omitempty"`
+}
+
+// MigrateSchema returns the schema of an old schema. Old schemas can have
+// duplicate fields, they are merged (widening the type), and fields without a
+// type (old schemas had no boolean type) or with a type frames doesn't know
+// (e.g. written by other tools), they are dropped. Dropped fields are read
+// with types inferred from their values
+func MigrateSchema(old *OldV3ioSchema) (*Schema, error) {
+	schema := NewSchema(old.Key)
+	schema.HashingBucketNum = old.HashingBucketNum
+
+	fields := &Schema{}
+	for _, field := range old.Fields {
+		if _, ok := typeDTypes[field.Type]; !ok {
+			continue
+		}
+
+		fields.Fields = append(fields.Fields, SchemaField(field))
+	}
+
+	// Merge also merges duplicates in fields
+	if _, err := schema.Merge(fields, false); err != nil {
+		return nil, errors.Wrap(err, "can't migrate schema")
+	}
+
+	return schema, nil
+}
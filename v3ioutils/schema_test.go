@@ -21,6 +21,9 @@ such restriction.
 package v3ioutils
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -129,12 +132,104 @@ func TestNewSchema(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	oldSchema, ok := schema.(*OldV3ioSchema)
-	if !ok {
-		t.Fatalf("can't get underlying schema object")
+	if nFields := len(schema.Fields); nFields != 18 {
+		t.Fatalf("wrong number of fields %d != %d", nFields, 18)
 	}
 
-	if nFields := len(oldSchema.Fields); nFields != 18 {
-		t.Fatalf("wrong number of fields %d != %d", nFields, 18)
+	if schema.Version != SchemaVersion || schema.Key != "id" {
+		t.Fatalf("bad migrated schema: %+v", schema)
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema2, err := SchemaFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(schema, schema2) {
+		t.Fatalf("schema changed after JSON round trip: %+v != %+v", schema, schema2)
+	}
+}
+
+func TestMigrateSchema(t *testing.T) {
+	old := &OldV3ioSchema{
+		Fields: []OldSchemaField{
+			{Name: "a", Type: longType},
+			{Name: "b", Type: stringType, Nullable: true},
+			{Name: "a", Type: longType, Nullable: true},
+			{Name: "c", Type: ""}, // Old boolean
+			{Name: "d", Type: "blob"},
+			{Name: "a", Type: doubleType},
+		},
+		Key: "k",
+	}
+
+	schema, err := MigrateSchema(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []SchemaField{
+		{Name: "a", Type: doubleType, Nullable: true},
+		{Name: "b", Type: stringType, Nullable: true},
+	}
+	if !reflect.DeepEqual(schema.Fields, expected) || schema.Key != "k" {
+		t.Fatalf("bad migrated schema: %+v", schema)
+	}
+
+	old.Fields = append(old.Fields, OldSchemaField{Name: "b", Type: timeType})
+	if _, err := MigrateSchema(old); err == nil {
+		t.Fatal("migrated string to time")
+	}
+}
+
+func TestSchemaMerge(t *testing.T) {
+	testCases := []struct {
+		current string
+		added   string
+		strict  bool
+		result  string // "" for error
+	}{
+		{longType, longType, true, longType},
+		{doubleType, longType, true, doubleType},
+		{longType, doubleType, true, ""},
+		{longType, doubleType, false, doubleType},
+		{longType, stringType, true, ""},
+		{longType, stringType, false, stringType},
+		{stringType, doubleType, false, stringType},
+		{boolType, boolType, true, boolType},
+		{boolType, longType, false, ""},
+		{timeType, stringType, false, ""},
+		{stringType, timeType, false, ""},
+	}
+
+	for _, tc := range testCases {
+		name := fmt.Sprintf("%s-%s-%v", tc.current, tc.added, tc.strict)
+		t.Run(name, func(t *testing.T) {
+			schema := NewSchema("")
+			schema.Fields = []SchemaField{{Name: "x", Type: tc.current}}
+			other := NewSchema("")
+			other.Fields = []SchemaField{{Name: "x", Type: tc.added}, {Name: "y", Type: tc.added}}
+
+			_, err := schema.Merge(other, tc.strict)
+			if tc.result == "" {
+				if err == nil {
+					t.Fatalf("no error, type is %s", schema.Fields[0].Type)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(schema.Fields) != 2 || schema.Fields[0].Type != tc.result || schema.Field("y") == nil {
+				t.Fatalf("bad merged schema: %+v", schema.Fields)
+			}
+		})
 	}
 }
@@ -0,0 +1,92 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package v3ioutils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/v3io/frames"
+)
+
+// StreamInfo is stream shard count and retention
+type StreamInfo struct {
+	ShardCount           int `json:"ShardCount"`
+	RetentionPeriodHours int `json:"RetentionPeriodHours"`
+}
+
+// describeTimeout is the timeout of DescribeStream requests (ctx can make it
+// shorter)
+const describeTimeout = 30 * time.Second
+
+var describeClient = &http.Client{Timeout: describeTimeout}
+
+// DescribeStream returns stream information. v3io-go-http doesn't support the
+// DescribeStream function so we call the web API directly
+func DescribeStream(ctx context.Context, session *frames.Session, path string) (*StreamInfo, error) {
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	addr := session.Url
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+
+	url := fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(addr, "/"), session.Container, path)
+	req, err := http.NewRequest("POST", url, bytes.NewBufferString("{}"))
+	if err != nil {
+		return nil, errors.Wrap(err, "can't create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-v3io-function", "DescribeStream")
+	switch {
+	case session.Token != "":
+		req.Header.Set("X-v3io-session-key", session.Token)
+	case session.User != "":
+		req.SetBasicAuth(session.User, session.Password)
+	}
+
+	resp, err := describeClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't describe stream %q", path)
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("can't describe stream %q - %s", path, resp.Status)
+	}
+
+	info := &StreamInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(info); err != nil {
+		return nil, errors.Wrap(err, "can't decode stream description")
+	}
+
+	return info, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package v3ioutils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/v3io/frames"
+)
+
+func TestDescribeStreamAuth(t *testing.T) {
+	var key, user string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("X-v3io-session-key")
+		user, _, _ = r.BasicAuth()
+		fmt.Fprint(w, `{"ShardCount": 3, "RetentionPeriodHours": 24}`)
+	}))
+	defer srv.Close()
+
+	session := &frames.Session{Url: srv.URL, Container: "bigdata", Token: "t0ken", User: "u"}
+	info, err := DescribeStream(context.Background(), session, "strm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.ShardCount != 3 || info.RetentionPeriodHours != 24 {
+		t.Fatalf("bad stream info: %+v", info)
+	}
+
+	if key != "t0ken" || user != "" {
+		t.Fatalf("bad auth - key=%q, user=%q", key, user)
+	}
+
+	session.Token = ""
+	if _, err := DescribeStream(context.Background(), session, "strm"); err != nil {
+		t.Fatal(err)
+	}
+
+	if key != "" || user != "u" {
+		t.Fatalf("bad auth - key=%q, user=%q", key, user)
+	}
+}
+
+func TestDescribeStreamContext(t *testing.T) {
+	release := make(chan bool)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	session := &frames.Session{Url: srv.URL, Container: "bigdata"}
+	if _, err := DescribeStream(ctx, session, "strm"); err == nil {
+		t.Fatal("no error on canceled request")
+	}
+
+	if duration := time.Since(start); duration > time.Second {
+		t.Fatalf("request wasn't canceled (took %s)", duration)
+	}
+}
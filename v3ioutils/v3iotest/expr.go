@@ -0,0 +1,1013 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package v3iotest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// This file implements the subset of the v3io expression language used by
+// filters, condition and update expressions. Values are int64, float64,
+// string, bool, []byte, []int64 and []float64. A missing attribute evaluates
+// to nil, comparisons with nil are false.
+
+type tokenKind int
+
+const (
+	eofToken tokenKind = iota
+	identToken
+	numberToken
+	stringToken
+	opToken
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func tokenize(text string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(text[i+1:], c)
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, token{stringToken, text[i+1 : i+1+end], i})
+			i += end + 2
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(text) && text[i+1] >= '0' && text[i+1] <= '9':
+			start := i
+			for i < len(text) && (text[i] >= '0' && text[i] <= '9' || text[i] == '.') {
+				i++
+			}
+			if i < len(text) && (text[i] == 'e' || text[i] == 'E') {
+				i++
+				if i < len(text) && (text[i] == '+' || text[i] == '-') {
+					i++
+				}
+				for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+					i++
+				}
+			}
+			tokens = append(tokens, token{numberToken, text[start:i], start})
+		case c == '_' || unicode.IsLetter(rune(c)):
+			start := i
+			for i < len(text) && (text[i] == '_' || unicode.IsLetter(rune(text[i])) || unicode.IsDigit(rune(text[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{identToken, text[start:i], start})
+		default:
+			op := string(c)
+			if i+1 < len(text) {
+				switch two := text[i : i+2]; two {
+				case "==", "!=", "<=", ">=", "<>":
+					op = two
+				}
+			}
+			if !strings.Contains("=!<>+-*/()[],;", op[:1]) || op == "!" {
+				return nil, fmt.Errorf("unexpected %q at %d", op, i)
+			}
+			tokens = append(tokens, token{opToken, op, i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{eofToken, "", len(text)}), nil
+}
+
+type node interface {
+	eval(item map[string]interface{}) (interface{}, error)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func newParser(text string) (*parser, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parser{tokens: tokens}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != eofToken {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it's the operator (or keyword) op
+func (p *parser) accept(op string) bool {
+	tok := p.peek()
+	if (tok.kind == opToken && tok.value == op) || (tok.kind == identToken && strings.EqualFold(tok.value, op)) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		tok := p.peek()
+		return fmt.Errorf("expected %q at %d, got %q", op, tok.pos, tok.value)
+	}
+	return nil
+}
+
+// parseCondition parses a filter or condition expression
+func parseCondition(text string) (node, error) {
+	p, err := newParser(text)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != eofToken {
+		return nil, fmt.Errorf("unexpected %q at %d", tok.value, tok.pos)
+	}
+
+	return expr, nil
+}
+
+func (p *parser) parseExpr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "or", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "and", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.accept("not") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{expr}, nil
+	}
+
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.accept("in") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+
+		in := &inNode{expr: left}
+		for {
+			value, err := p.parseAdd()
+			if err != nil {
+				return nil, err
+			}
+			in.values = append(in.values, value)
+			if !p.accept(",") {
+				break
+			}
+		}
+
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return in, nil
+	}
+
+	tok := p.peek()
+	if tok.kind != opToken {
+		return left, nil
+	}
+
+	switch tok.value {
+	case "=", "==", "!=", "<>", "<", "<=", ">", ">=":
+		p.next()
+		right, err := p.parseAdd()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: tok.value, left: left, right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAdd() (node, error) {
+	left, err := p.parseMul()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.kind != opToken || (tok.value != "+" && tok.value != "-") {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseMul()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithNode{op: tok.value, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMul() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok.kind != opToken || (tok.value != "*" && tok.value != "/") {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithNode{op: tok.value, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("-") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &arithNode{op: "-", left: &literalNode{int64(0)}, right: expr}, nil
+	}
+
+	if p.accept("+") {
+		return p.parseUnary()
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case numberToken:
+		if i, err := strconv.ParseInt(tok.value, 10, 64); err == nil {
+			return &literalNode{i}, nil
+		}
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q at %d", tok.value, tok.pos)
+		}
+		return &literalNode{f}, nil
+	case stringToken:
+		return &literalNode{tok.value}, nil
+	case opToken:
+		if tok.value != "(" {
+			break
+		}
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case identToken:
+		if p.accept("(") {
+			return p.parseCall(tok)
+		}
+
+		switch strings.ToLower(tok.value) {
+		case "true":
+			return &literalNode{true}, nil
+		case "false":
+			return &literalNode{false}, nil
+		case "inf":
+			return &literalNode{math.Inf(1)}, nil
+		case "nan":
+			return &literalNode{math.NaN()}, nil
+		}
+
+		if p.accept("[") {
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			return &indexNode{name: tok.value, index: index}, nil
+		}
+
+		return &attrNode{tok.value}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q at %d", tok.value, tok.pos)
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	call := &callNode{name: strings.ToLower(name.value)}
+	if !p.accept(")") {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if !p.accept(",") {
+				break
+			}
+		}
+
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	nargs, ok := functions[call.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at %d", name.value, name.pos)
+	}
+
+	if len(call.args) < nargs[0] || len(call.args) > nargs[1] {
+		return nil, fmt.Errorf("wrong number of arguments to %s at %d", call.name, name.pos)
+	}
+
+	return call, nil
+}
+
+// functions are the supported functions and their min/max number of arguments
+var functions = map[string][2]int{
+	"if_not_exists": {2, 2},
+	"exists":        {1, 1},
+	"starts":        {2, 2},
+	"ends":          {2, 2},
+	"contains":      {2, 2},
+	"length":        {1, 1},
+	"blob":          {1, 1},
+	"init_array":    {2, 3},
+	"min":           {2, 2},
+	"max":           {2, 2},
+}
+
+// assignment is a single "name = expr" or "name[index] = expr" in an update
+// expression. A nil value removes the attribute
+type assignment struct {
+	name  string
+	index node
+	value node
+}
+
+// parseUpdate parses an update expression. Statements are separated by ";"
+// and may start with SET (assignments separated by ",") or REMOVE (attribute
+// names separated by ",")
+func parseUpdate(text string) ([]*assignment, error) {
+	p, err := newParser(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments []*assignment
+	for p.peek().kind != eofToken {
+		if p.accept(";") {
+			continue
+		}
+
+		if p.accept("remove") {
+			for {
+				tok := p.next()
+				if tok.kind != identToken {
+					return nil, fmt.Errorf("expected attribute name at %d", tok.pos)
+				}
+				assignments = append(assignments, &assignment{name: tok.value})
+				if !p.accept(",") {
+					break
+				}
+			}
+		} else {
+			// SET is optional, but "SET = 1" sets an attribute named SET
+			if tok := p.tokens[p.pos+1]; !(tok.kind == opToken && (tok.value == "=" || tok.value == "[")) {
+				p.accept("set")
+			}
+
+			for {
+				asgn, err := p.parseAssignment()
+				if err != nil {
+					return nil, err
+				}
+				assignments = append(assignments, asgn)
+				if !p.accept(",") {
+					break
+				}
+			}
+		}
+
+		if tok := p.peek(); tok.kind != eofToken && !p.accept(";") {
+			return nil, fmt.Errorf("unexpected %q at %d", tok.value, tok.pos)
+		}
+	}
+
+	return assignments, nil
+}
+
+func (p *parser) parseAssignment() (*assignment, error) {
+	tok := p.next()
+	if tok.kind != identToken {
+		return nil, fmt.Errorf("expected attribute name at %d, got %q", tok.pos, tok.value)
+	}
+
+	asgn := &assignment{name: tok.value}
+	if p.accept("[") {
+		index, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		asgn.index = index
+	}
+
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+
+	value, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	asgn.value = value
+
+	return asgn, nil
+}
+
+// apply applies update assignments to item. All values are evaluated against
+// the item state before the update
+func apply(assignments []*assignment, item map[string]interface{}) error {
+	type update struct {
+		*assignment
+		index int64
+		value interface{}
+	}
+
+	updates := make([]update, len(assignments))
+	for i, asgn := range assignments {
+		updates[i].assignment = asgn
+		if asgn.value == nil {
+			continue
+		}
+
+		value, err := asgn.value.eval(item)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			return fmt.Errorf("%s: value is missing", asgn.name)
+		}
+		updates[i].value = value
+
+		if asgn.index != nil {
+			index, err := asgn.index.eval(item)
+			if err != nil {
+				return err
+			}
+			ival, ok := index.(int64)
+			if !ok {
+				return fmt.Errorf("%s: index is not an integer", asgn.name)
+			}
+			updates[i].index = ival
+		}
+	}
+
+	for _, u := range updates {
+		switch {
+		case u.assignment.value == nil:
+			delete(item, u.name)
+		case u.assignment.index == nil:
+			item[u.name] = u.value
+		default:
+			if err := setElement(item, u.name, u.index, u.value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func setElement(item map[string]interface{}, name string, index int64, value interface{}) error {
+	switch array := item[name].(type) {
+	case []int64:
+		if index < 0 || index >= int64(len(array)) {
+			return fmt.Errorf("%s: index %d out of range", name, index)
+		}
+		f, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("%s: can't set %T in an int array", name, value)
+		}
+		array[index] = int64(f)
+	case []float64:
+		if index < 0 || index >= int64(len(array)) {
+			return fmt.Errorf("%s: index %d out of range", name, index)
+		}
+		f, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("%s: can't set %T in a double array", name, value)
+		}
+		array[index] = f
+	case nil:
+		return fmt.Errorf("%s: array is missing", name)
+	default:
+		return fmt.Errorf("%s: not an array (%T)", name, array)
+	}
+
+	return nil
+}
+
+// match returns true if condition evaluates to true on item
+func match(condition node, item map[string]interface{}) (bool, error) {
+	value, err := condition.eval(item)
+	if err != nil {
+		return false, err
+	}
+
+	bval, ok := value.(bool)
+	return ok && bval, nil
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(item map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type attrNode struct {
+	name string
+}
+
+func (n *attrNode) eval(item map[string]interface{}) (interface{}, error) {
+	return item[n.name], nil
+}
+
+type indexNode struct {
+	name  string
+	index node
+}
+
+func (n *indexNode) eval(item map[string]interface{}) (interface{}, error) {
+	index, err := n.index.eval(item)
+	if err != nil {
+		return nil, err
+	}
+
+	i, ok := index.(int64)
+	if !ok {
+		return nil, fmt.Errorf("%s: index is not an integer", n.name)
+	}
+
+	switch array := item[n.name].(type) {
+	case []int64:
+		if i < 0 || i >= int64(len(array)) {
+			return nil, fmt.Errorf("%s: index %d out of range", n.name, i)
+		}
+		return array[i], nil
+	case []float64:
+		if i < 0 || i >= int64(len(array)) {
+			return nil, fmt.Errorf("%s: index %d out of range", n.name, i)
+		}
+		return array[i], nil
+	case nil:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("%s: not an array", n.name)
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicalNode) eval(item map[string]interface{}) (interface{}, error) {
+	left, err := match(n.left, item)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "and" && !left {
+		return false, nil
+	}
+
+	if n.op == "or" && left {
+		return true, nil
+	}
+
+	return match(n.right, item)
+}
+
+type notNode struct {
+	expr node
+}
+
+func (n *notNode) eval(item map[string]interface{}) (interface{}, error) {
+	value, err := match(n.expr, item)
+	if err != nil {
+		return nil, err
+	}
+
+	return !value, nil
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(item map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(item)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := n.right.eval(item)
+	if err != nil {
+		return nil, err
+	}
+
+	cmp, ok := compare(left, right)
+	if !ok {
+		return false, nil
+	}
+
+	switch n.op {
+	case "=", "==":
+		return cmp == 0, nil
+	case "!=", "<>":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator %q", n.op)
+}
+
+// compare returns -1, 0 or 1, false if the values are not comparable
+func compare(left, right interface{}) (int, bool) {
+	if lf, ok := toFloat(left); ok {
+		rf, ok := toFloat(right)
+		if !ok {
+			return 0, false
+		}
+
+		// Compare integers exactly
+		li, lok := left.(int64)
+		ri, rok := right.(int64)
+		switch {
+		case lok && rok && li < ri, !(lok && rok) && lf < rf:
+			return -1, true
+		case lok && rok && li > ri, !(lok && rok) && lf > rf:
+			return 1, true
+		case lf == rf:
+			return 0, true
+		}
+		return 0, false // NaN
+	}
+
+	switch lval := left.(type) {
+	case string:
+		rval, ok := right.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(lval, rval), true
+	case bool:
+		rval, ok := right.(bool)
+		if !ok || lval == rval {
+			return 0, ok
+		}
+		if !lval {
+			return -1, true
+		}
+		return 1, true
+	case []byte:
+		rval, ok := right.([]byte)
+		if !ok {
+			return 0, false
+		}
+		return bytes.Compare(lval, rval), true
+	}
+
+	return 0, false
+}
+
+type inNode struct {
+	expr   node
+	values []node
+}
+
+func (n *inNode) eval(item map[string]interface{}) (interface{}, error) {
+	value, err := n.expr.eval(item)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vnode := range n.values {
+		other, err := vnode.eval(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp, ok := compare(value, other); ok && cmp == 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type arithNode struct {
+	op          string
+	left, right node
+}
+
+func (n *arithNode) eval(item map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(item)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := n.right.eval(item)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil || right == nil {
+		return nil, fmt.Errorf("missing operand for %q", n.op)
+	}
+
+	if n.op == "+" {
+		switch lval := left.(type) {
+		case string:
+			if rval, ok := right.(string); ok {
+				return lval + rval, nil
+			}
+		case []byte:
+			if rval, ok := right.([]byte); ok {
+				out := make([]byte, 0, len(lval)+len(rval))
+				return append(append(out, lval...), rval...), nil
+			}
+		}
+	}
+
+	li, lok := left.(int64)
+	ri, rok := right.(int64)
+	if lok && rok {
+		switch n.op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		case "/":
+			if ri == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return li / ri, nil
+		}
+	}
+
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("can't apply %q to %T and %T", n.op, left, right)
+	}
+
+	switch n.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		return lf / rf, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator %q", n.op)
+}
+
+type callNode struct {
+	name string
+	args []node
+}
+
+func (n *callNode) eval(item map[string]interface{}) (interface{}, error) {
+	// Functions that get an attribute, not a value
+	switch n.name {
+	case "if_not_exists", "exists":
+		value, err := n.args[0].eval(item)
+		if err != nil {
+			return nil, err
+		}
+
+		if n.name == "exists" {
+			return value != nil, nil
+		}
+
+		if value != nil {
+			return value, nil
+		}
+		return n.args[1].eval(item)
+	}
+
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(item)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+
+	switch n.name {
+	case "starts", "ends", "contains":
+		s, ok1 := args[0].(string)
+		sub, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return false, nil
+		}
+		switch n.name {
+		case "starts":
+			return strings.HasPrefix(s, sub), nil
+		case "ends":
+			return strings.HasSuffix(s, sub), nil
+		}
+		return strings.Contains(s, sub), nil
+	case "length":
+		switch value := args[0].(type) {
+		case string:
+			return int64(len(value)), nil
+		case []byte:
+			return int64(len(value)), nil
+		case []int64:
+			return int64(len(value)), nil
+		case []float64:
+			return int64(len(value)), nil
+		}
+		return nil, fmt.Errorf("length: bad argument type %T", args[0])
+	case "blob":
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("blob: argument must be a string")
+		}
+		data, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, errors.Wrap(err, "blob: bad base64 value")
+		}
+		return data, nil
+	case "init_array":
+		return initArray(args)
+	case "min", "max":
+		lf, lok := toFloat(args[0])
+		rf, rok := toFloat(args[1])
+		if !lok || !rok {
+			return nil, fmt.Errorf("%s: arguments must be numbers", n.name)
+		}
+		if n.name == "min" {
+			return math.Min(lf, rf), nil
+		}
+		return math.Max(lf, rf), nil
+	}
+
+	return nil, fmt.Errorf("unknown function %q", n.name)
+}
+
+func initArray(args []interface{}) (interface{}, error) {
+	size, ok := args[0].(int64)
+	if !ok || size < 0 {
+		return nil, fmt.Errorf("init_array: bad size %v", args[0])
+	}
+
+	init := 0.0
+	if len(args) == 3 {
+		if init, ok = toFloat(args[2]); !ok {
+			return nil, fmt.Errorf("init_array: bad initial value %v", args[2])
+		}
+	}
+
+	switch args[1] {
+	case "int":
+		array := make([]int64, size)
+		for i := range array {
+			array[i] = int64(init)
+		}
+		return array, nil
+	case "double":
+		array := make([]float64, size)
+		for i := range array {
+			array[i] = init
+		}
+		return array, nil
+	}
+
+	return nil, fmt.Errorf("init_array: unknown type %v", args[1])
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	}
+
+	return 0, false
+}
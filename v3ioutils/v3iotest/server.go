@@ -0,0 +1,889 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+/*
+Package v3iotest provides an in-memory stand-in for the v3io web API, for
+testing the kv, stream and tsdb backends without a cluster.
+
+	srv := v3iotest.NewServer()
+	defer srv.Close()
+	session := &frames.Session{Url: srv.Addr, Container: "bigdata"}
+
+The server supports object (GetObject, PutObject, DeleteObject, ListBucket),
+item (GetItem, GetItems, PutItem, UpdateItem) and stream (CreateStream,
+DescribeStream, PutRecords, SeekShard, GetRecords) operations. Containers are
+created on first use and credentials are not checked. Stream retention is not
+enforced. Use Fail to simulate failing (e.g. throttled) requests.
+*/
+package v3iotest
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	v3io "github.com/v3io/v3io-go-http"
+)
+
+const (
+	// DefaultPageSize is the default maximal number of items in a GetItems response
+	DefaultPageSize = 100
+
+	functionHeader = "X-v3io-function"
+)
+
+// Server is an in-memory v3io web API server
+type Server struct {
+	// Addr is the server host:port, use it as the session URL
+	Addr string
+	// URL is the server base URL (http://host:port)
+	URL string
+
+	server   *httptest.Server
+	lock     sync.Mutex
+	pageSize int
+	objects  map[string]*object  // container/path -> object
+	dirs     map[string]bool     // container/path
+	streams  map[string]*stream  // container/path -> stream
+	failures map[string]*failure // operation -> failure
+}
+
+// failure is a simulated failure of the next count requests
+type failure struct {
+	count  int
+	status int
+}
+
+// object is an object with attributes (an item). Stream shards are objects
+// with a shard
+type object struct {
+	attrs map[string]interface{}
+	body  []byte
+	ctime time.Time
+	mtime time.Time
+	shard *shard
+}
+
+// NewServer starts and returns a new server, the caller should call Close
+// when done
+func NewServer() *Server {
+	srv := &Server{
+		pageSize: DefaultPageSize,
+		objects:  make(map[string]*object),
+		dirs:     make(map[string]bool),
+		streams:  make(map[string]*stream),
+		failures: make(map[string]*failure),
+	}
+
+	srv.server = httptest.NewServer(srv)
+	srv.URL = srv.server.URL
+	srv.Addr = strings.TrimPrefix(srv.URL, "http://")
+	return srv
+}
+
+// Close shuts down the server
+func (srv *Server) Close() {
+	srv.server.Close()
+}
+
+// SetPageSize sets the maximal number of items in a GetItems response
+func (srv *Server) SetPageSize(size int) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	srv.pageSize = size
+}
+
+// Fail makes the next count requests of operation (e.g. "PutItem",
+// "DeleteObject") fail with status, without changing any data
+func (srv *Server) Fail(operation string, count int, status int) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	if count <= 0 {
+		delete(srv.failures, operation)
+		return
+	}
+	srv.failures[operation] = &failure{count: count, status: status}
+}
+
+// httpError is an error with an HTTP status code
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func newError(status int, format string, args ...interface{}) error {
+	return &httpError{status, fmt.Sprintf(format, args...)}
+}
+
+func notFound(key string) error {
+	return newError(http.StatusNotFound, "%q not found", key)
+}
+
+func badRequest(err error) error {
+	return newError(http.StatusBadRequest, "%s", err)
+}
+
+// request is a parsed request
+type request struct {
+	method    string
+	function  string
+	container string
+	path      string // relative to container, without trailing /
+	isDir     bool   // path ends with /
+	body      []byte
+	query     map[string][]string
+}
+
+// operation returns the name of the request operation
+func (r *request) operation() string {
+	if r.function != "" {
+		return r.function
+	}
+
+	switch r.method {
+	case http.MethodGet:
+		if _, ok := r.query["prefix"]; ok || r.path == "" {
+			return "ListBucket"
+		}
+		return "GetObject"
+	case http.MethodPut:
+		return "PutObject"
+	case http.MethodDelete:
+		return "DeleteObject"
+	}
+
+	return r.method
+}
+
+// key returns the store key of the request path
+func (r *request) key() string {
+	if r.path == "" {
+		return r.container
+	}
+	return r.container + "/" + r.path
+}
+
+// ServeHTTP implements http.Handler
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		srv.writeError(w, badRequest(err))
+		return
+	}
+
+	req := &request{
+		method:   r.Method,
+		function: r.Header.Get(functionHeader),
+		body:     body,
+		query:    r.URL.Query(),
+	}
+
+	urlPath := strings.TrimPrefix(r.URL.Path, "/")
+	if i := strings.IndexByte(urlPath, '/'); i == -1 {
+		req.container = urlPath
+	} else {
+		req.container = urlPath[:i]
+		req.path = strings.TrimPrefix(path.Clean("/"+urlPath[i+1:]), "/")
+		req.isDir = strings.HasSuffix(urlPath, "/")
+	}
+
+	out, err := srv.handle(req)
+	if err != nil {
+		srv.writeError(w, err)
+		return
+	}
+
+	switch out := out.(type) {
+	case nil:
+		w.WriteHeader(http.StatusOK)
+	case []byte:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(out)
+	case xmlResponse:
+		w.Header().Set("Content-Type", "application/xml")
+		if err := xml.NewEncoder(w).Encode(out.value); err != nil {
+			srv.writeError(w, err)
+		}
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(out); err != nil {
+			srv.writeError(w, err)
+		}
+	}
+}
+
+// xmlResponse marks a response value that should be XML encoded
+type xmlResponse struct {
+	value interface{}
+}
+
+func (srv *Server) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if herr, ok := err.(*httpError); ok {
+		status = herr.status
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ErrorCode":    status,
+		"ErrorMessage": err.Error(),
+	})
+}
+
+func (srv *Server) handle(req *request) (interface{}, error) {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	if req.container == "" {
+		if req.method != http.MethodGet {
+			return nil, newError(http.StatusMethodNotAllowed, "%s not allowed", req.method)
+		}
+		return srv.listAll(), nil
+	}
+
+	if fail, ok := srv.failures[req.operation()]; ok {
+		fail.count--
+		if fail.count == 0 {
+			delete(srv.failures, req.operation())
+		}
+		return nil, newError(fail.status, "simulated %s failure", req.operation())
+	}
+
+	switch req.function {
+	case "":
+		// Object operations
+	case "GetItem":
+		return srv.getItem(req)
+	case "GetItems":
+		return srv.getItems(req)
+	case "PutItem":
+		return nil, srv.putItem(req)
+	case "UpdateItem":
+		return nil, srv.updateItem(req)
+	case "CreateStream":
+		return nil, srv.createStream(req)
+	case "DescribeStream":
+		return srv.describeStream(req)
+	case "PutRecords":
+		return srv.putRecords(req)
+	case "SeekShard":
+		return srv.seekShard(req)
+	case "GetRecords":
+		return srv.getRecords(req)
+	default:
+		return nil, newError(http.StatusBadRequest, "unknown function %q", req.function)
+	}
+
+	switch req.operation() {
+	case "ListBucket":
+		return srv.listBucket(req)
+	case "GetObject":
+		return srv.getObject(req)
+	case "PutObject":
+		return nil, srv.putObject(req)
+	case "DeleteObject":
+		return nil, srv.deleteObject(req)
+	}
+
+	return nil, newError(http.StatusMethodNotAllowed, "%s not allowed", req.method)
+}
+
+// mkdirs creates the parent directories of key
+func (srv *Server) mkdirs(key string) {
+	for dir := path.Dir(key); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		srv.dirs[dir] = true
+	}
+}
+
+// object returns the object at key, creating it if create is true
+func (srv *Server) object(key string, create bool) (*object, error) {
+	if srv.dirs[key] {
+		return nil, newError(http.StatusConflict, "%q is a directory", key)
+	}
+
+	obj, ok := srv.objects[key]
+	if ok {
+		return obj, nil
+	}
+
+	if !create {
+		return nil, notFound(key)
+	}
+
+	now := time.Now()
+	obj = &object{
+		attrs: make(map[string]interface{}),
+		ctime: now,
+		mtime: now,
+	}
+	srv.objects[key] = obj
+	srv.mkdirs(key)
+	return obj, nil
+}
+
+// children returns the names (sorted) of the objects and sub directories
+// directly under the directory dir
+func (srv *Server) children(dir string) ([]string, []string) {
+	prefix := dir + "/"
+	var objects, dirs []string
+	for key := range srv.objects {
+		if strings.HasPrefix(key, prefix) && !strings.Contains(key[len(prefix):], "/") {
+			objects = append(objects, key[len(prefix):])
+		}
+	}
+
+	for key := range srv.dirs {
+		if strings.HasPrefix(key, prefix) && !strings.Contains(key[len(prefix):], "/") {
+			dirs = append(dirs, key[len(prefix):])
+		}
+	}
+
+	sort.Strings(objects)
+	sort.Strings(dirs)
+	return objects, dirs
+}
+
+func (srv *Server) listAll() interface{} {
+	containers := make(map[string]bool)
+	for key := range srv.dirs {
+		containers[strings.SplitN(key, "/", 2)[0]] = true
+	}
+
+	out := &v3io.ListAllOutput{}
+	for name := range containers {
+		out.Buckets.Bucket = append(out.Buckets.Bucket, v3io.Bucket{Name: name})
+	}
+	sort.Slice(out.Buckets.Bucket, func(i, j int) bool {
+		return out.Buckets.Bucket[i].Name < out.Buckets.Bucket[j].Name
+	})
+
+	return xmlResponse{out}
+}
+
+// listBucket lists the objects and sub directories that match the prefix.
+// The part of the prefix after the last / is a name prefix
+func (srv *Server) listBucket(req *request) (interface{}, error) {
+	prefix := ""
+	if values := req.query["prefix"]; len(values) > 0 {
+		prefix = strings.TrimPrefix(values[0], "/")
+	}
+
+	dir, namePrefix := req.container, prefix
+	if i := strings.LastIndexByte(prefix, '/'); i != -1 {
+		dir = req.container + "/" + strings.TrimSuffix(path.Clean(prefix[:i]), "/")
+		namePrefix = prefix[i+1:]
+	}
+
+	out := &v3io.ListBucketOutput{Name: req.container}
+	relDir := strings.TrimPrefix(strings.TrimPrefix(dir, req.container), "/")
+	if relDir != "" {
+		relDir += "/"
+	}
+
+	objects, dirs := srv.children(dir)
+	for _, name := range objects {
+		if !strings.HasPrefix(name, namePrefix) {
+			continue
+		}
+		obj := srv.objects[dir+"/"+name]
+		out.Contents = append(out.Contents, v3io.Content{
+			Key:          relDir + name,
+			Size:         len(obj.body),
+			LastModified: obj.mtime.UTC().Format(time.RFC3339),
+		})
+	}
+
+	for _, name := range dirs {
+		if strings.HasPrefix(name, namePrefix) {
+			out.CommonPrefixes = append(out.CommonPrefixes, v3io.CommonPrefix{Prefix: relDir + name + "/"})
+		}
+	}
+
+	return xmlResponse{out}, nil
+}
+
+func (srv *Server) getObject(req *request) (interface{}, error) {
+	obj, err := srv.object(req.key(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{}, obj.body...), nil
+}
+
+func (srv *Server) putObject(req *request) error {
+	if req.isDir {
+		srv.dirs[req.key()] = true
+		srv.mkdirs(req.key())
+		return nil
+	}
+
+	obj, err := srv.object(req.key(), true)
+	if err != nil {
+		return err
+	}
+
+	obj.body = req.body
+	obj.mtime = time.Now()
+	return nil
+}
+
+// deleteObject deletes an object or an empty directory. Deleting a missing
+// or non empty directory is a no-op (DeleteTable deletes the table directory
+// after a filtered delete)
+func (srv *Server) deleteObject(req *request) error {
+	key := req.key()
+	if _, ok := srv.objects[key]; ok && !req.isDir {
+		delete(srv.objects, key)
+		return nil
+	}
+
+	if !srv.dirs[key] {
+		if req.isDir {
+			return nil
+		}
+		return notFound(key)
+	}
+
+	if objects, dirs := srv.children(key); len(objects) > 0 || len(dirs) > 0 {
+		return nil
+	}
+
+	delete(srv.dirs, key)
+	delete(srv.streams, key)
+	return nil
+}
+
+// view returns the item attributes including system attributes
+func (obj *object) view(name string) map[string]interface{} {
+	item := make(map[string]interface{}, len(obj.attrs)+6)
+	for key, value := range obj.attrs {
+		item[key] = value
+	}
+
+	item["__name"] = name
+	item["__size"] = int64(len(obj.body))
+	item["__ctime_secs"] = obj.ctime.Unix()
+	item["__ctime_nsecs"] = int64(obj.ctime.Nanosecond())
+	item["__mtime_secs"] = obj.mtime.Unix()
+	item["__mtime_nsecs"] = int64(obj.mtime.Nanosecond())
+	return item
+}
+
+// selectAttributes returns the typed requested attributes of item. "*" is
+// all user attributes and the item name, "**" is all attributes
+func selectAttributes(item map[string]interface{}, names string) (map[string]map[string]string, error) {
+	out := make(map[string]map[string]string)
+	add := func(name string) error {
+		value, ok := item[name]
+		if !ok {
+			return nil
+		}
+
+		typed, err := encodeValue(value)
+		if err != nil {
+			return errors.Wrap(err, name)
+		}
+		out[name] = typed
+		return nil
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "*", "**":
+			for key := range item {
+				if name == "*" && strings.HasPrefix(key, "__") && key != "__name" {
+					continue
+				}
+				if err := add(key); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			if err := add(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// encodeValue encodes a value in the v3io typed format ({"N": "1"})
+func encodeValue(value interface{}) (map[string]string, error) {
+	switch value := value.(type) {
+	case int64:
+		return map[string]string{"N": strconv.FormatInt(value, 10)}, nil
+	case float64:
+		s := strconv.FormatFloat(value, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eIN") {
+			s += ".0" // Keep it a float on decode
+		}
+		return map[string]string{"N": s}, nil
+	case string:
+		return map[string]string{"S": value}, nil
+	case bool:
+		return map[string]string{"BOOL": strconv.FormatBool(value)}, nil
+	case []byte:
+		return map[string]string{"B": base64.StdEncoding.EncodeToString(value)}, nil
+	case []int64:
+		return map[string]string{"B": base64.StdEncoding.EncodeToString(encodeArray(len(value), func(i int) uint64 {
+			return uint64(value[i])
+		}))}, nil
+	case []float64:
+		return map[string]string{"B": base64.StdEncoding.EncodeToString(encodeArray(len(value), func(i int) uint64 {
+			return math.Float64bits(value[i])
+		}))}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type - %T", value)
+}
+
+// encodeArray encodes an array as a v3io array blob - 16 bytes header
+// followed by little endian 64 bit values
+func encodeArray(size int, at func(int) uint64) []byte {
+	data := make([]byte, 16+8*size)
+	binary.LittleEndian.PutUint64(data, uint64(size))
+	for i := 0; i < size; i++ {
+		binary.LittleEndian.PutUint64(data[16+8*i:], at(i))
+	}
+	return data
+}
+
+// decodeValue decodes a v3io typed value
+func decodeValue(typed map[string]string) (interface{}, error) {
+	for typ, value := range typed {
+		switch typ {
+		case "N":
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return i, nil
+			}
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad number - %q", value)
+			}
+			return f, nil
+		case "S":
+			return value, nil
+		case "BOOL":
+			return strconv.ParseBool(value)
+		case "B":
+			return base64.StdEncoding.DecodeString(value)
+		default:
+			return nil, fmt.Errorf("unknown type - %q", typ)
+		}
+	}
+
+	return nil, fmt.Errorf("empty value")
+}
+
+func decodeBody(req *request, out interface{}) error {
+	if len(req.body) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(req.body, out); err != nil {
+		return badRequest(errors.Wrap(err, "bad request body"))
+	}
+
+	return nil
+}
+
+func (srv *Server) getItem(req *request) (interface{}, error) {
+	var body struct {
+		AttributesToGet string
+	}
+	if err := decodeBody(req, &body); err != nil {
+		return nil, err
+	}
+
+	obj, err := srv.object(req.key(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := selectAttributes(obj.view(path.Base(req.path)), body.AttributesToGet)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"Item": item}, nil
+}
+
+// checkCondition returns a precondition failed error if condition doesn't
+// match obj (which can be nil)
+func checkCondition(condition, key string, obj *object) error {
+	if condition == "" {
+		return nil
+	}
+
+	expr, err := parseCondition(condition)
+	if err != nil {
+		return badRequest(errors.Wrap(err, "bad condition"))
+	}
+
+	item := map[string]interface{}{}
+	if obj != nil {
+		item = obj.view(path.Base(key))
+	}
+
+	ok, err := match(expr, item)
+	if err != nil {
+		return badRequest(err)
+	}
+
+	if !ok {
+		return newError(http.StatusPreconditionFailed, "condition not met for %q", key)
+	}
+
+	return nil
+}
+
+func (srv *Server) putItem(req *request) error {
+	var body struct {
+		Item                map[string]map[string]string
+		ConditionExpression string
+		UpdateMode          string
+	}
+	if err := decodeBody(req, &body); err != nil {
+		return err
+	}
+
+	attrs := make(map[string]interface{}, len(body.Item))
+	for name, typed := range body.Item {
+		if strings.HasPrefix(name, "__") {
+			return newError(http.StatusBadRequest, "can't set system attribute %q", name)
+		}
+
+		value, err := decodeValue(typed)
+		if err != nil {
+			return badRequest(errors.Wrap(err, name))
+		}
+		attrs[name] = value
+	}
+
+	key := req.key()
+	if err := checkCondition(body.ConditionExpression, key, srv.objects[key]); err != nil {
+		return err
+	}
+
+	obj, err := srv.object(key, true)
+	if err != nil {
+		return err
+	}
+
+	if body.UpdateMode == "CreateOrReplaceAttributes" {
+		for name, value := range attrs {
+			obj.attrs[name] = value
+		}
+	} else {
+		obj.attrs = attrs
+	}
+
+	obj.mtime = time.Now()
+	return nil
+}
+
+func (srv *Server) updateItem(req *request) error {
+	var body struct {
+		UpdateExpression    string
+		ConditionExpression string
+	}
+	if err := decodeBody(req, &body); err != nil {
+		return err
+	}
+
+	assignments, err := parseUpdate(body.UpdateExpression)
+	if err != nil {
+		return badRequest(errors.Wrap(err, "bad update expression"))
+	}
+
+	for _, asgn := range assignments {
+		if strings.HasPrefix(asgn.name, "__") {
+			return newError(http.StatusBadRequest, "can't set system attribute %q", asgn.name)
+		}
+	}
+
+	key := req.key()
+	if err := checkCondition(body.ConditionExpression, key, srv.objects[key]); err != nil {
+		return err
+	}
+
+	_, existed := srv.objects[key]
+	obj, err := srv.object(key, true)
+	if err != nil {
+		return err
+	}
+
+	// Evaluate on a copy with system attributes so a failed update won't
+	// leave a partially updated item
+	item := obj.view(path.Base(req.path))
+	for name, value := range item {
+		switch value := value.(type) {
+		case []int64:
+			item[name] = append([]int64{}, value...)
+		case []float64:
+			item[name] = append([]float64{}, value...)
+		}
+	}
+
+	if err := apply(assignments, item); err != nil {
+		if !existed {
+			delete(srv.objects, key) // Don't leave an empty item we created
+		}
+		return badRequest(err)
+	}
+
+	for name := range item {
+		if strings.HasPrefix(name, "__") {
+			delete(item, name)
+		}
+	}
+
+	obj.attrs = item
+	obj.mtime = time.Now()
+	return nil
+}
+
+func (srv *Server) getItems(req *request) (interface{}, error) {
+	var body struct {
+		AttributesToGet  string
+		FilterExpression string
+		Marker           string
+		ShardingKey      string
+		Limit            int
+		Segment          int
+		TotalSegment     int
+	}
+	if err := decodeBody(req, &body); err != nil {
+		return nil, err
+	}
+
+	dir := req.key()
+	if !srv.dirs[dir] {
+		return nil, notFound(dir)
+	}
+
+	var filter node
+	if body.FilterExpression != "" {
+		var err error
+		filter, err = parseCondition(body.FilterExpression)
+		if err != nil {
+			return nil, badRequest(errors.Wrap(err, "bad filter"))
+		}
+	}
+
+	if body.TotalSegment > 0 && (body.Segment < 0 || body.Segment >= body.TotalSegment) {
+		return nil, newError(http.StatusBadRequest, "bad segment %d/%d", body.Segment, body.TotalSegment)
+	}
+
+	limit := srv.pageSize
+	if body.Limit > 0 && body.Limit < limit {
+		limit = body.Limit
+	}
+
+	// Sharding key (with optional trailing ".") selects items named key or
+	// key.<sorting key>
+	shardingKey := strings.TrimSuffix(body.ShardingKey, ".")
+
+	names, _ := srv.children(dir)
+	items := []map[string]map[string]string{}
+	last, lastName := true, ""
+	for _, name := range names {
+		if body.Marker != "" && name <= body.Marker {
+			continue
+		}
+
+		if shardingKey != "" && name != shardingKey && !strings.HasPrefix(name, shardingKey+".") {
+			continue
+		}
+
+		if body.TotalSegment > 0 && segmentOf(name, body.TotalSegment) != body.Segment {
+			continue
+		}
+
+		item := srv.objects[dir+"/"+name].view(name)
+		if filter != nil {
+			ok, err := match(filter, item)
+			if err != nil {
+				return nil, badRequest(err)
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		if len(items) == limit {
+			last = false
+			break
+		}
+
+		typed, err := selectAttributes(item, body.AttributesToGet)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, typed)
+		lastName = name
+	}
+
+	out := map[string]interface{}{
+		"Items":            items,
+		"LastItemIncluded": "TRUE",
+	}
+
+	if !last {
+		out["LastItemIncluded"] = "FALSE"
+		out["NextMarker"] = lastName
+	}
+
+	return out, nil
+}
+
+// segmentOf returns the segment of an item name in a segmented scan
+func segmentOf(name string, total int) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return int(h.Sum32() % uint32(total))
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package v3iotest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	v3io "github.com/v3io/v3io-go-http"
+
+	"github.com/v3io/frames"
+	"github.com/v3io/frames/v3ioutils"
+)
+
+func newContainer(t *testing.T, srv *Server) *v3io.Container {
+	logger, err := frames.NewLogger("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	container, err := v3ioutils.CreateContainer(logger, srv.Addr, "bigdata", "user", "secret", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return container
+}
+
+func TestItems(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.SetPageSize(3)
+	container := newContainer(t, srv)
+
+	for i := 0; i < 10; i++ {
+		input := &v3io.PutItemInput{
+			Path: fmt.Sprintf("table/item%d", i),
+			Attributes: map[string]interface{}{
+				"i":    i,
+				"f":    float64(i) / 2,
+				"name": fmt.Sprintf("n%d", i),
+			},
+		}
+		if err := container.Sync.PutItem(input); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := container.Sync.PutObject(&v3io.PutObjectInput{Path: "table/.%23schema", Body: []byte("{}")}); err != nil {
+		t.Fatal(err)
+	}
+
+	logger, _ := frames.NewLogger("error")
+	read := func(filter string, workers int) []string {
+		input := &v3io.GetItemsInput{Path: "table/", AttributeNames: []string{"*"}, Filter: filter}
+		iter, err := v3ioutils.NewAsyncItemsCursor(context.Background(), container, input, workers, nil, logger, 0, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer iter.Release()
+
+		var names []string
+		for iter.Next() {
+			names = append(names, iter.GetField("__name").(string))
+		}
+		if err := iter.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		sort.Strings(names)
+		return names
+	}
+
+	// Segmented scan with paging
+	if names := read("", 4); len(names) != 11 || names[0] != ".#schema" {
+		t.Fatalf("bad names: %v", names)
+	}
+
+	names := read("i >= 3 AND f < 4.0 and starts(name, 'n') and not (i in (5, 6))", 2)
+	if fmt.Sprint(names) != "[item3 item4 item7]" {
+		t.Fatalf("bad filtered names: %v", names)
+	}
+
+	expr := "SET i = i + 10; f = if_not_exists(g, 1.5) * 2; arr = init_array(3, 'double', -Inf)"
+	if err := container.Sync.UpdateItem(&v3io.UpdateItemInput{Path: "table/item1", Expression: &expr}); err != nil {
+		t.Fatal(err)
+	}
+
+	expr = "arr[1] = max(arr[1], 7.5); REMOVE name"
+	if err := container.Sync.UpdateItem(&v3io.UpdateItemInput{Path: "table/item1", Expression: &expr}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := container.Sync.GetItem(&v3io.GetItemInput{Path: "table/item1", AttributeNames: []string{"*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := resp.Output.(*v3io.GetItemOutput).Item
+	resp.Release()
+
+	if item["i"] != 11 || item["f"] != 3.0 || item["name"] != nil {
+		t.Fatalf("bad item: %v", item)
+	}
+
+	arr := v3ioutils.AsInt64Array(item["arr"].([]byte))
+	if len(arr) != 3 || math.Float64frombits(arr[1]) != 7.5 || !math.IsInf(math.Float64frombits(arr[0]), -1) {
+		t.Fatalf("bad array: %v", arr)
+	}
+
+	cond := &v3io.UpdateItemInput{Path: "table/item1", Expression: &expr, Condition: "i > 100"}
+	if err := container.Sync.UpdateItem(cond); err == nil {
+		t.Fatal("no error on false condition")
+	}
+
+	if err := v3ioutils.DeleteTable(context.Background(), logger, container, "table/", "i < 5", 2, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if names := read("", 1); fmt.Sprint(names) != "[.#schema item1 item5 item6 item7 item8 item9]" {
+		t.Fatalf("bad names after delete: %v", names)
+	}
+
+	objects, dirs, err := v3ioutils.ListDir(container, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(objects) != 0 || fmt.Sprint(dirs) != "[table]" {
+		t.Fatalf("bad listing: %v %v", objects, dirs)
+	}
+}
+
+func TestStream(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	container := newContainer(t, srv)
+
+	err := container.Sync.CreateStream(&v3io.CreateStreamInput{Path: "strm/", ShardCount: 2, RetentionPeriodHours: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := v3ioutils.DescribeStream(context.Background(), &frames.Session{Url: srv.Addr, Container: "bigdata"}, "strm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.ShardCount != 2 || info.RetentionPeriodHours != 1 {
+		t.Fatalf("bad stream info: %+v", info)
+	}
+
+	shard := 1
+	var records []*v3io.StreamRecord
+	for i := 0; i < 5; i++ {
+		records = append(records, &v3io.StreamRecord{ShardID: &shard, Data: []byte(fmt.Sprintf("r%d", i))})
+	}
+
+	resp, err := container.Sync.PutRecords(&v3io.PutRecordsInput{Path: "strm/", Records: records})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := resp.Output.(*v3io.PutRecordsOutput); out.FailedRecordCount != 0 {
+		t.Fatalf("failed records: %+v", out)
+	}
+	resp.Release()
+
+	resp, err = container.Sync.SeekShard(&v3io.SeekShardInput{Path: "strm/1", Type: v3io.SeekShardInputTypeEarliest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	location := resp.Output.(*v3io.SeekShardOutput).Location
+	resp.Release()
+
+	var data []string
+	for {
+		resp, err := container.Sync.GetRecords(&v3io.GetRecordsInput{Path: "strm/1", Location: location, Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := resp.Output.(*v3io.GetRecordsOutput)
+		for _, rec := range out.Records {
+			data = append(data, string(rec.Data))
+		}
+		location = out.NextLocation
+		resp.Release()
+
+		if out.RecordsBehindLatest == 0 {
+			break
+		}
+	}
+
+	if fmt.Sprint(data) != "[r0 r1 r2 r3 r4]" {
+		t.Fatalf("bad records: %v", data)
+	}
+
+	if err := container.Sync.DeleteStream(&v3io.DeleteStreamInput{Path: "strm/"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, dirs, err := v3ioutils.ListDir(container, ""); err != nil || len(dirs) != 0 {
+		t.Fatalf("stream not deleted: %v (%v)", dirs, err)
+	}
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2018 Iguazio Systems Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License") with
+an addition restriction as set forth herein. You may not use this
+file except in compliance with the License. You may obtain a copy of
+the License at http://www.apache.org/licenses/LICENSE-2.0.
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+implied. See the License for the specific language governing
+permissions and limitations under the License.
+
+In addition, you may not use the software for any purposes that are
+illegal under applicable law, and the grant of the foregoing license
+under the Apache 2.0 license is conditioned upon your compliance with
+such restriction.
+*/
+
+package v3iotest
+
+import (
+	"encoding/base64"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	v3io "github.com/v3io/v3io-go-http"
+)
+
+const defaultRecordsLimit = 1000
+
+type stream struct {
+	shardCount     int
+	retentionHours int
+	nextShard      int // Round robin for records without shard and partition key
+}
+
+type shard struct {
+	records []*v3io.GetRecordsResult
+	nextSeq int
+}
+
+func (srv *Server) stream(key string) (*stream, error) {
+	strm, ok := srv.streams[key]
+	if !ok {
+		return nil, notFound(key)
+	}
+
+	return strm, nil
+}
+
+func (srv *Server) shard(key string) (*shard, error) {
+	obj, ok := srv.objects[key]
+	if !ok || obj.shard == nil {
+		return nil, notFound(key)
+	}
+
+	return obj.shard, nil
+}
+
+func (srv *Server) createStream(req *request) error {
+	var body struct {
+		ShardCount           int
+		RetentionPeriodHours int
+	}
+	if err := decodeBody(req, &body); err != nil {
+		return err
+	}
+
+	if body.ShardCount < 1 {
+		return newError(http.StatusBadRequest, "bad shard count - %d", body.ShardCount)
+	}
+
+	key := req.key()
+	if srv.dirs[key] {
+		return newError(http.StatusConflict, "%q already exists", key)
+	}
+
+	if _, ok := srv.objects[key]; ok {
+		return newError(http.StatusConflict, "%q is an object", key)
+	}
+
+	srv.dirs[key] = true
+	srv.mkdirs(key)
+	srv.streams[key] = &stream{
+		shardCount:     body.ShardCount,
+		retentionHours: body.RetentionPeriodHours,
+	}
+
+	now := time.Now()
+	for i := 0; i < body.ShardCount; i++ {
+		srv.objects[key+"/"+strconv.Itoa(i)] = &object{
+			attrs: make(map[string]interface{}),
+			ctime: now,
+			mtime: now,
+			shard: &shard{nextSeq: 1},
+		}
+	}
+
+	return nil
+}
+
+func (srv *Server) describeStream(req *request) (interface{}, error) {
+	strm, err := srv.stream(req.key())
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]interface{}{
+		"ShardCount":           strm.shardCount,
+		"RetentionPeriodHours": strm.retentionHours,
+	}
+	return out, nil
+}
+
+func (srv *Server) putRecords(req *request) (interface{}, error) {
+	var body struct {
+		Records []struct {
+			Data         []byte
+			ClientInfo   []byte
+			ShardID      *int `json:"ShardId"`
+			PartitionKey string
+		}
+	}
+	if err := decodeBody(req, &body); err != nil {
+		return nil, err
+	}
+
+	key := req.key()
+	strm, err := srv.stream(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &v3io.PutRecordsOutput{}
+	now := time.Now()
+	for _, rec := range body.Records {
+		var shardID int
+		switch {
+		case rec.ShardID != nil:
+			shardID = *rec.ShardID
+		case rec.PartitionKey != "":
+			h := fnv.New32a()
+			h.Write([]byte(rec.PartitionKey))
+			shardID = int(h.Sum32() % uint32(strm.shardCount))
+		default:
+			shardID = strm.nextShard
+			strm.nextShard = (strm.nextShard + 1) % strm.shardCount
+		}
+
+		shardKey := key + "/" + strconv.Itoa(shardID)
+		shrd, err := srv.shard(shardKey)
+		if err != nil {
+			out.FailedRecordCount++
+			out.Records = append(out.Records, v3io.PutRecordResult{
+				ShardID:      shardID,
+				ErrorCode:    http.StatusNotFound,
+				ErrorMessage: err.Error(),
+			})
+			continue
+		}
+
+		shrd.records = append(shrd.records, &v3io.GetRecordsResult{
+			ArrivalTimeSec:  int(now.Unix()),
+			ArrivalTimeNSec: now.Nanosecond(),
+			SequenceNumber:  shrd.nextSeq,
+			ClientInfo:      rec.ClientInfo,
+			PartitionKey:    rec.PartitionKey,
+			Data:            rec.Data,
+		})
+		out.Records = append(out.Records, v3io.PutRecordResult{
+			SequenceNumber: shrd.nextSeq,
+			ShardID:        shardID,
+		})
+		shrd.nextSeq++
+		srv.objects[shardKey].mtime = now
+	}
+
+	return out, nil
+}
+
+// Locations are opaque to clients, we encode the sequence number
+func encodeLocation(seq int) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(seq)))
+}
+
+func decodeLocation(location string) (int, error) {
+	data, err := base64.StdEncoding.DecodeString(location)
+	if err != nil {
+		return 0, newError(http.StatusBadRequest, "bad location %q", location)
+	}
+
+	seq, err := strconv.Atoi(string(data))
+	if err != nil {
+		return 0, newError(http.StatusBadRequest, "bad location %q", location)
+	}
+
+	return seq, nil
+}
+
+func (srv *Server) seekShard(req *request) (interface{}, error) {
+	var body struct {
+		Type                   string
+		StartingSequenceNumber int
+		TimestampSec           int
+		TimestampNSec          int
+	}
+	if err := decodeBody(req, &body); err != nil {
+		return nil, err
+	}
+
+	shrd, err := srv.shard(req.key())
+	if err != nil {
+		return nil, err
+	}
+
+	var seq int
+	switch body.Type {
+	case "EARLIEST":
+		seq = shrd.nextSeq
+		if len(shrd.records) > 0 {
+			seq = shrd.records[0].SequenceNumber
+		}
+	case "LATEST":
+		seq = shrd.nextSeq
+	case "SEQUENCE":
+		seq = body.StartingSequenceNumber
+	case "TIME":
+		ts := time.Unix(int64(body.TimestampSec), int64(body.TimestampNSec))
+		i := sort.Search(len(shrd.records), func(i int) bool {
+			rec := shrd.records[i]
+			return !time.Unix(int64(rec.ArrivalTimeSec), int64(rec.ArrivalTimeNSec)).Before(ts)
+		})
+		seq = shrd.nextSeq
+		if i < len(shrd.records) {
+			seq = shrd.records[i].SequenceNumber
+		}
+	default:
+		return nil, newError(http.StatusBadRequest, "unknown seek type %q", body.Type)
+	}
+
+	return &v3io.SeekShardOutput{Location: encodeLocation(seq)}, nil
+}
+
+func (srv *Server) getRecords(req *request) (interface{}, error) {
+	var body struct {
+		Location string
+		Limit    int
+	}
+	if err := decodeBody(req, &body); err != nil {
+		return nil, err
+	}
+
+	shrd, err := srv.shard(req.key())
+	if err != nil {
+		return nil, err
+	}
+
+	seq, err := decodeLocation(body.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := body.Limit
+	if limit <= 0 {
+		limit = defaultRecordsLimit
+	}
+
+	start := sort.Search(len(shrd.records), func(i int) bool {
+		return shrd.records[i].SequenceNumber >= seq
+	})
+	end := start + limit
+	if end > len(shrd.records) {
+		end = len(shrd.records)
+	}
+
+	out := &v3io.GetRecordsOutput{
+		NextLocation:        encodeLocation(seq),
+		RecordsBehindLatest: len(shrd.records) - end,
+		Records:             []v3io.GetRecordsResult{},
+	}
+
+	for _, rec := range shrd.records[start:end] {
+		out.Records = append(out.Records, *rec)
+	}
+
+	if end > start {
+		out.NextLocation = encodeLocation(shrd.records[end-1].SequenceNumber + 1)
+	}
+
+	if end < len(shrd.records) {
+		rec := shrd.records[end]
+		behind := time.Since(time.Unix(int64(rec.ArrivalTimeSec), int64(rec.ArrivalTimeNSec)))
+		out.MSecBehindLatest = int(behind / time.Millisecond)
+	}
+
+	return out, nil
+}